@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/konveyor/analyzer-lsp/engine"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/spf13/pflag"
+)
+
+// defaultEngine evaluates rules in process with the analyzer-lsp rule engine
+const defaultEngine = "analyzer-lsp"
+
+// analysisEngine evaluates the parsed rulesets of an analysis with the
+// providers started by kantra
+type analysisEngine interface {
+	RunRules(ctx context.Context, ruleSets []engine.RuleSet, selectors ...engine.RuleSelector) []outputv1.RuleSet
+	Stop()
+}
+
+// engineOptions are the settings of the analysis passed to the engine
+type engineOptions struct {
+	log              logr.Logger
+	contextLines     int
+	incidentSelector string
+	locationPrefixes []string
+	settings         engineSettings
+}
+
+// engineSettings are the options of the analyzer-lsp engine set with flags
+// of analyze, given to the engine in process and as arguments to the
+// analyzer in containers. Options of the engine added to analyzer-lsp are
+// added here with their flag.
+type engineSettings struct {
+	incidentLimit    int
+	codeSnipLimit    int
+	noCodeSnips      bool
+	locationPrefixes []string
+}
+
+// defaults of the analyzer, 0 means no limit
+const (
+	defaultIncidentLimit = 1500
+	defaultCodeSnipLimit = 20
+)
+
+func (s *engineSettings) addFlags(flags *pflag.FlagSet) {
+	flags.IntVar(&s.incidentLimit, "incident-limit", defaultIncidentLimit, "maximum number of incidents of a rule, 0 means no limit")
+	flags.IntVar(&s.codeSnipLimit, "code-snip-limit", defaultCodeSnipLimit, "maximum number of code snippets of a rule per file, 0 means no limit")
+	flags.BoolVar(&s.noCodeSnips, "no-code-snips", false, "leave the code snippets out of the incidents, e.g. when the output must not contain source code")
+	flags.StringArrayVar(&s.locationPrefixes, "location-prefix", []string{}, "path prefix of files whose incident URIs are written relative to it, in addition to the locations of the providers. Requires --run-local")
+}
+
+// validate checks the values of the engine flags
+func (s *engineSettings) validate(runLocal bool) error {
+	if s.incidentLimit < 0 {
+		return fmt.Errorf("incident-limit must not be negative")
+	}
+	if s.codeSnipLimit < 0 {
+		return fmt.Errorf("code-snip-limit must not be negative")
+	}
+	if len(s.locationPrefixes) > 0 && !runLocal {
+		return fmt.Errorf("location-prefix requires containerless mode, set --run-local")
+	}
+	for _, prefix := range s.locationPrefixes {
+		if prefix == "" {
+			return fmt.Errorf("location-prefix must not be empty")
+		}
+	}
+	return nil
+}
+
+// options returns the options of the engine running in process
+func (s *engineSettings) options() []engine.Option {
+	return []engine.Option{
+		engine.WithIncidentLimit(s.incidentLimit),
+		engine.WithCodeSnipLimit(s.codeSnipLimit),
+	}
+}
+
+// analyzerArgs returns the arguments of the analyzer running in a container
+func (s *engineSettings) analyzerArgs() []string {
+	return []string{
+		fmt.Sprintf("--limit-incidents=%d", s.incidentLimit),
+		fmt.Sprintf("--limit-code-snips=%d", s.codeSnipLimit),
+	}
+}
+
+// removeCodeSnips removes the code snippets of the incidents with
+// --no-code-snips, the engine has no option to skip them
+func removeCodeSnips(rulesets []outputv1.RuleSet) {
+	for _, rs := range rulesets {
+		for _, v := range rs.Violations {
+			for i := range v.Incidents {
+				v.Incidents[i].CodeSnip = ""
+			}
+		}
+	}
+}
+
+// analysisEngines creates the engines selected with --engine, by name
+var analysisEngines = map[string]func(ctx context.Context, opts engineOptions) (analysisEngine, error){
+	defaultEngine: newAnalyzerLSPEngine,
+}
+
+// newAnalyzerLSPEngine creates the rule engine of analyzer-lsp
+func newAnalyzerLSPEngine(ctx context.Context, opts engineOptions) (analysisEngine, error) {
+	options := append([]engine.Option{
+		engine.WithContextLines(opts.contextLines),
+		engine.WithIncidentSelector(opts.incidentSelector),
+		engine.WithLocationPrefixes(opts.locationPrefixes),
+	}, opts.settings.options()...)
+	return engine.CreateRuleEngine(ctx, 10, opts.log, options...), nil
+}
+
+// validateEngine checks the engine of --engine exists, engines other than the
+// default one evaluate rules in process and need containerless mode
+func (a *analyzeCommand) validateEngine() error {
+	if err := a.engineSettings.validate(a.runLocal); err != nil {
+		return err
+	}
+	if _, ok := analysisEngines[a.engineName]; !ok {
+		return fmt.Errorf("unknown engine %s, must be one of %s", a.engineName, strings.Join(sortedMapKeys(analysisEngines), ", "))
+	}
+	if a.engineName != defaultEngine && !a.runLocal {
+		return fmt.Errorf("engine %s requires containerless mode, set --run-local", a.engineName)
+	}
+	return nil
+}
+
+// createEngine creates the engine of --engine for the analysis
+func (a *analyzeCommand) createEngine(ctx context.Context, log logr.Logger, locationPrefixes []string) (analysisEngine, error) {
+	newEngine, ok := analysisEngines[a.engineName]
+	if !ok {
+		return nil, fmt.Errorf("unknown engine %s", a.engineName)
+	}
+	a.log.V(5).Info("creating analysis engine", "engine", a.engineName)
+	return newEngine(ctx, engineOptions{
+		log:              log,
+		contextLines:     a.contextLines,
+		incidentSelector: a.engineIncidentSelector(),
+		locationPrefixes: append(locationPrefixes, a.engineSettings.locationPrefixes...),
+		settings:         a.engineSettings,
+	})
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/konveyor/analyzer-lsp/engine"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+type fakeEngine struct {
+	opts engineOptions
+}
+
+func (f *fakeEngine) RunRules(ctx context.Context, ruleSets []engine.RuleSet, selectors ...engine.RuleSelector) []outputv1.RuleSet {
+	return []outputv1.RuleSet{{Name: "fake"}}
+}
+
+func (f *fakeEngine) Stop() {}
+
+func Test_analyzeCommand_createEngine(t *testing.T) {
+	analysisEngines["fake"] = func(ctx context.Context, opts engineOptions) (analysisEngine, error) {
+		return &fakeEngine{opts: opts}, nil
+	}
+	defer delete(analysisEngines, "fake")
+
+	tests := []struct {
+		name     string
+		engine   string
+		runLocal bool
+		wantErr  bool
+	}{
+		{name: "default", engine: defaultEngine, runLocal: true},
+		{name: "default in container mode", engine: defaultEngine},
+		{name: "alternate", engine: "fake", runLocal: true},
+		{name: "alternate in container mode", engine: "fake", wantErr: true},
+		{name: "unknown", engine: "remote", runLocal: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &analyzeCommand{engineName: tt.engine, runLocal: tt.runLocal}
+			if err := a.validateEngine(); (err != nil) != tt.wantErr {
+				t.Errorf("validateEngine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	a := &analyzeCommand{engineName: "fake", runLocal: true, contextLines: 5, log: logr.Discard()}
+	eng, err := a.createEngine(context.Background(), logr.Discard(), []string{"/opt/input"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake, ok := eng.(*fakeEngine)
+	if !ok {
+		t.Fatalf("createEngine() = %T, want *fakeEngine", eng)
+	}
+	if fake.opts.contextLines != 5 || len(fake.opts.locationPrefixes) != 1 {
+		t.Errorf("createEngine() options = %+v", fake.opts)
+	}
+	if rulesets := eng.RunRules(context.Background(), nil); len(rulesets) != 1 || rulesets[0].Name != "fake" {
+		t.Errorf("RunRules() = %v", rulesets)
+	}
+}
+
+func Test_engineSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings engineSettings
+		runLocal bool
+		wantErr  bool
+	}{
+		{name: "defaults", settings: engineSettings{incidentLimit: defaultIncidentLimit, codeSnipLimit: defaultCodeSnipLimit}},
+		{name: "no limits", settings: engineSettings{}},
+		{name: "negative incident limit", settings: engineSettings{incidentLimit: -1}, wantErr: true},
+		{name: "negative code snip limit", settings: engineSettings{codeSnipLimit: -1}, wantErr: true},
+		{name: "location prefix", settings: engineSettings{locationPrefixes: []string{"/src"}}, runLocal: true},
+		{name: "location prefix in container mode", settings: engineSettings{locationPrefixes: []string{"/src"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.settings.validate(tt.runLocal); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	settings := engineSettings{incidentLimit: 50, codeSnipLimit: 0, locationPrefixes: []string{"/src"}}
+	want := []string{"--limit-incidents=50", "--limit-code-snips=0"}
+	if got := settings.analyzerArgs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("analyzerArgs() = %v, want %v", got, want)
+	}
+
+	analysisEngines["fake"] = func(ctx context.Context, opts engineOptions) (analysisEngine, error) {
+		return &fakeEngine{opts: opts}, nil
+	}
+	defer delete(analysisEngines, "fake")
+	a := &analyzeCommand{engineName: "fake", runLocal: true, engineSettings: settings, log: logr.Discard()}
+	eng, err := a.createEngine(context.Background(), logr.Discard(), []string{"/opt/input"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := eng.(*fakeEngine).opts
+	if !reflect.DeepEqual(opts.locationPrefixes, []string{"/opt/input", "/src"}) || opts.settings.incidentLimit != 50 {
+		t.Errorf("createEngine() options = %+v, want the engine settings and location prefixes", opts)
+	}
+}
+
+func Test_removeCodeSnips(t *testing.T) {
+	rulesets := []outputv1.RuleSet{{
+		Name: "eap8",
+		Violations: map[string]outputv1.Violation{
+			"session-00010": {Incidents: []outputv1.Incident{{URI: "file:///src/Cart.java", CodeSnip: "1  class Cart {}"}}},
+		},
+	}}
+	removeCodeSnips(rulesets)
+	if snip := rulesets[0].Violations["session-00010"].Incidents[0].CodeSnip; snip != "" {
+		t.Errorf("removeCodeSnips() left code snip %q", snip)
+	}
+}
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -11,7 +10,6 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
-	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -19,6 +17,8 @@ import (
 
 	"github.com/bombsimon/logrusr/v3"
 	"github.com/go-logr/logr"
+	"github.com/konveyor-ecosystem/kantra/cmd/internal/i18n"
+	kantraprovider "github.com/konveyor-ecosystem/kantra/pkg/provider"
 	"github.com/konveyor/analyzer-lsp/engine"
 	"github.com/konveyor/analyzer-lsp/engine/labels"
 	java "github.com/konveyor/analyzer-lsp/external-providers/java-external-provider/pkg/java_external_provider"
@@ -29,7 +29,6 @@ import (
 	"github.com/konveyor/analyzer-lsp/provider/lib"
 	"github.com/konveyor/analyzer-lsp/tracing"
 	"github.com/sirupsen/logrus"
-	"go.lsp.dev/uri"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"gopkg.in/yaml.v2"
@@ -46,8 +45,6 @@ func (a *analyzeCommand) RunAnalysisContainerless(ctx context.Context) error {
 		a.reqMap = make(map[string]string)
 	}
 
-	defer os.Remove(filepath.Join(a.output, "settings.json"))
-
 	analysisLogFilePath := filepath.Join(a.output, "analysis.log")
 	analysisLog, err := os.Create(analysisLogFilePath)
 	if err != nil {
@@ -109,10 +106,12 @@ func (a *analyzeCommand) RunAnalysisContainerless(ctx context.Context) error {
 		}
 	}
 
-	err = a.setBinMapContainerless()
-	if err != nil {
-		a.log.Error(err, "unable to find kantra dependencies")
-		os.Exit(1)
+	if !a.builtinOnly() && a.replayRecording == nil {
+		err = a.setBinMapContainerless()
+		if err != nil {
+			a.log.Error(err, "unable to find kantra dependencies")
+			os.Exit(1)
+		}
 	}
 
 	// Get the configs
@@ -127,13 +126,12 @@ func (a *analyzeCommand) RunAnalysisContainerless(ctx context.Context) error {
 
 	engineCtx, engineSpan := tracing.StartNewSpan(ctx, "rule-engine")
 	//start up the rule eng
-	eng := engine.CreateRuleEngine(engineCtx,
-		10,
-		analyzeLog,
-		engine.WithContextLines(a.contextLines),
-		engine.WithIncidentSelector(a.incidentSelector),
-		engine.WithLocationPrefixes(providerLocations),
-	)
+	rules := &ruleProgress{}
+	eng, err := a.createEngine(engineCtx, ruleProgressLogger(analyzeLog, rules), providerLocations)
+	if err != nil {
+		a.log.Error(err, "failed to create analysis engine", "engine", a.engineName)
+		return err
+	}
 
 	parser := parser.RuleParser{
 		ProviderNameToClient: providers,
@@ -141,11 +139,8 @@ func (a *analyzeCommand) RunAnalysisContainerless(ctx context.Context) error {
 		DepLabelSelector:     dependencyLabelSelector,
 	}
 
-	ruleSets := []engine.RuleSet{}
-	needProviders := map[string]provider.InternalProviderClient{}
-
 	if a.enableDefaultRulesets {
-		a.rules = append(a.rules, filepath.Join(a.kantraDir, RulesetsLocation))
+		a.rules = append(a.rules, a.defaultRulesetsContainerless())
 	}
 	if !xmlDirEmpty {
 		a.rules = append(a.rules, xmlTempDir)
@@ -153,15 +148,11 @@ func (a *analyzeCommand) RunAnalysisContainerless(ctx context.Context) error {
 
 	for _, f := range a.rules {
 		a.log.Info("parsing rules for analysis", "rules", f)
-
-		internRuleSet, internNeedProviders, err := parser.LoadRules(f)
-		if err != nil {
-			a.log.Error(err, "unable to parse all the rules for ruleset", "file", f)
-		}
-		ruleSets = append(ruleSets, internRuleSet...)
-		for k, v := range internNeedProviders {
-			needProviders[k] = v
-		}
+	}
+	ruleSets, needProviders := parseRules(a.log, &parser, a.rules)
+	if a.tagsOnly {
+		ruleSets = tagRules(ruleSets)
+		a.log.Info("running tagging rules only", "rulesets", len(ruleSets))
 	}
 	err = a.startProvidersContainerless(ctx, needProviders)
 	if err != nil {
@@ -182,7 +173,14 @@ func (a *analyzeCommand) RunAnalysisContainerless(ctx context.Context) error {
 
 	// This will already wait
 	a.log.Info("evaluating rules for violations. see analysis.log for more info")
-	rulesets := eng.RunRules(ctx, ruleSets, selectors...)
+	a.providerStatus.setAll(providerAnalyzing)
+	numRules := 0
+	for _, rs := range ruleSets {
+		numRules += len(rs.Rules)
+	}
+	stopProgress := a.startRuleProgress(i18n.Sprintf("evaluating %d rules for violations", numRules), rules)
+	rulesets := a.runRules(ctx, eng, ruleSets, selectors...)
+	stopProgress()
 	engineSpan.End()
 	wg.Wait()
 	if depSpan != nil {
@@ -193,10 +191,19 @@ func (a *analyzeCommand) RunAnalysisContainerless(ctx context.Context) error {
 	for _, provider := range needProviders {
 		provider.Stop()
 	}
+	if err := a.writeRecording(); err != nil {
+		a.log.Error(err, "failed to write provider recording")
+		return err
+	}
 
 	sort.SliceStable(rulesets, func(i, j int) bool {
 		return rulesets[i].Name < rulesets[j].Name
 	})
+	rulesets, err = a.postProcessRuleSets(rulesets)
+	if err != nil {
+		a.log.Error(err, "failed to process analysis results")
+		return err
+	}
 
 	// Write results out to CLI
 	a.log.Info("writing analysis results to output", "output", a.output)
@@ -210,22 +217,18 @@ func (a *analyzeCommand) RunAnalysisContainerless(ctx context.Context) error {
 		os.Exit(1) // Treat the error as a fatal error
 	}
 
-	err = a.CreateJSONOutput()
-	if err != nil {
-		a.log.Error(err, "failed to create json output file")
-		return err
-	}
-
-	err = a.GenerateStaticReportContainerless(ctx)
-	if err != nil {
-		a.log.Error(err, "failed to generate static report")
-		return err
-	}
-
-	return nil
+	return a.writeAnalysisOutputs(ctx, a.GenerateStaticReportContainerless)
 }
 
 func (a *analyzeCommand) ValidateContainerless(ctx context.Context) error {
+	// the builtin provider runs in kantra and replayed providers do not run,
+	// only the rulesets are needed
+	if a.builtinOnly() || a.replayRecording != nil {
+		if _, err := os.Stat(filepath.Join(a.kantraDir, RulesetsLocation)); a.enableDefaultRulesets && os.IsNotExist(err) {
+			return fmt.Errorf("%w; run 'kantra bootstrap' to install container-less dependencies", err)
+		}
+		return nil
+	}
 	// validate mvn and openjdk install
 	_, mvnErr := exec.LookPath("mvn")
 	if mvnErr != nil {
@@ -259,7 +262,7 @@ func (a *analyzeCommand) ValidateContainerless(ctx context.Context) error {
 	for _, path := range requiredDirs {
 		if _, err := os.Stat(path); os.IsNotExist(err) {
 			a.log.Error(err, "cannot open required path, ensure that container-less dependencies are installed")
-			return err
+			return fmt.Errorf("%w; run 'kantra bootstrap' to install container-less dependencies", err)
 		}
 	}
 
@@ -281,8 +284,7 @@ func (a *analyzeCommand) fetchLabelsContainerless(ctx context.Context, listSourc
 			a.log.Error(err, "failed to read rule labels")
 			return err
 		}
-		listOptionsFromLabels(sourceSlice, sourceLabel, out)
-		return nil
+		return listOptionsFromLabels(sourceSlice, sourceLabel, a.listFormat, out)
 	}
 	if listTargets {
 		targetsSlice, err := a.walkRuleFilesForLabelsContainerless(targetLabel)
@@ -290,8 +292,7 @@ func (a *analyzeCommand) fetchLabelsContainerless(ctx context.Context, listSourc
 			a.log.Error(err, "failed to read rule labels")
 			return err
 		}
-		listOptionsFromLabels(targetsSlice, targetLabel, out)
-		return nil
+		return listOptionsFromLabels(targetsSlice, targetLabel, a.listFormat, out)
 	}
 
 	return nil
@@ -299,7 +300,7 @@ func (a *analyzeCommand) fetchLabelsContainerless(ctx context.Context, listSourc
 
 func (a *analyzeCommand) walkRuleFilesForLabelsContainerless(label string) ([]string, error) {
 	labelsSlice := []string{}
-	path := filepath.Join(a.kantraDir, RulesetsLocation)
+	path := a.defaultRulesetsContainerless()
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		a.log.Error(err, "cannot open provided path")
 		return nil, err
@@ -339,19 +340,8 @@ func (a *analyzeCommand) setKantraDir() error {
 		return nil
 	}
 	// fall back to $HOME/.kantra
-	ops := runtime.GOOS
-	if ops == "linux" {
-		dir, set = os.LookupEnv("XDG_CONFIG_HOME")
-	}
-	if ops != "linux" || dir == "" || !set {
-		// on Unix, including macOS, this returns the $HOME environment variable. On Windows, it returns %USERPROFILE%
-		dir, err = os.UserHomeDir()
-		if err != nil {
-			return err
-		}
-	}
-	a.kantraDir = filepath.Join(dir, ".kantra")
-	return nil
+	a.kantraDir, err = kantraHomeDir()
+	return err
 }
 
 func (a *analyzeCommand) setBinMapContainerless() error {
@@ -371,66 +361,39 @@ func (a *analyzeCommand) setBinMapContainerless() error {
 }
 
 func (a *analyzeCommand) createProviderConfigsContainerless() ([]provider.Config, error) {
-	javaConfig := provider.Config{
-		Name:       javaProvider,
-		BinaryPath: a.reqMap["jdtls"],
-		InitConfig: []provider.InitConfig{
-			{
-				Location:     a.input,
-				AnalysisMode: provider.AnalysisMode(a.mode),
-				ProviderSpecificConfig: map[string]interface{}{
-					"fernFlowerPath":                filepath.Join(a.kantraDir, "fernflower.jar"),
-					"lspServerName":                 javaProvider,
-					"bundles":                       a.reqMap["bundle"],
-					provider.LspServerPathConfigKey: a.reqMap["jdtls"],
-					"depOpenSourceLabelsFile":       filepath.Join(a.kantraDir, "maven.default.index"),
-				},
-			},
-		},
-	}
-	if a.mavenSettingsFile != "" {
-		javaConfig.InitConfig[0].ProviderSpecificConfig["mavenSettingsFile"] = a.mavenSettingsFile
-	}
-	if Settings.JvmMaxMem != "" {
-		javaConfig.InitConfig[0].ProviderSpecificConfig["jvmMaxMem"] = Settings.JvmMaxMem
-	}
-
 	provConfig := []provider.Config{
-		{
-			Name: "builtin",
-			InitConfig: []provider.InitConfig{
-				{
-					Location:     a.input,
-					AnalysisMode: provider.AnalysisMode(a.mode),
-				},
-			},
-		},
-	}
-	provConfig = append(provConfig, javaConfig)
+		kantraprovider.BuiltinConfig(kantraprovider.Options{
+			Mode:          kantraprovider.ContainerlessMode,
+			Location:      a.input,
+			AnalysisMode:  provider.AnalysisMode(a.mode),
+			IncludedPaths: a.builtinIncludedPaths(),
+		}),
+	}
+	if !a.builtinOnly() {
+		provConfig = append(provConfig, kantraprovider.JavaConfig(kantraprovider.Options{
+			Mode:              kantraprovider.ContainerlessMode,
+			Location:          a.input,
+			AnalysisMode:      provider.AnalysisMode(a.mode),
+			IncludedPaths:     a.includedPaths,
+			MavenSettingsFile: a.mavenSettingsFile,
+			JVMMaxMemory:      a.jvmMaxMemory(),
+			JDTLSPath:         a.reqMap["jdtls"],
+			JavaBundlePath:    a.reqMap["bundle"],
+			KantraDir:         a.kantraDir,
+			Workspace:         a.jdtlsWorkspaceDir(),
+		}))
+	}
+	if err := a.setJavaOptionsContainerless(); err != nil {
+		return nil, err
+	}
 
+	// Set proxy to providers
+	kantraprovider.SetProxy(provConfig, a.httpProxy, a.httpsProxy, a.noProxy)
 	for i := range provConfig {
-		// Set proxy to providers
-		if a.httpProxy != "" || a.httpsProxy != "" {
-			proxy := provider.Proxy{
-				HTTPProxy:  a.httpProxy,
-				HTTPSProxy: a.httpsProxy,
-				NoProxy:    a.noProxy,
-			}
-
-			provConfig[i].Proxy = &proxy
-		}
 		provConfig[i].ContextLines = a.contextLines
 	}
 
-	jsonData, err := json.MarshalIndent(&provConfig, "", "	")
-	if err != nil {
-		a.log.V(1).Error(err, "failed to marshal provider config")
-		return nil, err
-	}
-	err = os.WriteFile(filepath.Join(a.output, "settings.json"), jsonData, os.ModePerm)
-	if err != nil {
-		a.log.V(1).Error(err,
-			"failed to write provider config", "dir", a.output, "file", "settings.json")
+	if err := a.writeDebugSettings(provConfig); err != nil {
 		return nil, err
 	}
 	configs := a.setConfigsContainerless(provConfig)
@@ -492,6 +455,13 @@ func (a *analyzeCommand) setInternalProviders(finalConfigs []provider.Config, an
 			}
 			config.InitConfig = inits
 		}
+		if a.replayRecording != nil {
+			if _, ok := a.replayRecording.Providers[config.Name]; !ok {
+				a.log.Info("provider not in recording, its rules are skipped", "provider", config.Name)
+			}
+			providers[config.Name] = a.replayRecording.replay(config.Name)
+			continue
+		}
 		var prov provider.InternalProviderClient
 		var err error
 		// only create java and builtin providers
@@ -505,6 +475,9 @@ func (a *analyzeCommand) setInternalProviders(finalConfigs []provider.Config, an
 				os.Exit(1)
 			}
 		}
+		if a.recording != nil && prov != nil {
+			prov = a.recording.record(config.Name, prov)
+		}
 		providers[config.Name] = prov
 	}
 	return providers, providerLocations
@@ -513,7 +486,7 @@ func (a *analyzeCommand) setInternalProviders(finalConfigs []provider.Config, an
 func (a *analyzeCommand) startProvidersContainerless(ctx context.Context, needProviders map[string]provider.InternalProviderClient) error {
 	// Now that we have all the providers, we need to start them.
 	additionalBuiltinConfigs := []provider.InitConfig{}
-	for name, provider := range needProviders {
+	for name, client := range needProviders {
 		a.log.Info("starting provider", "provider", name)
 		switch name {
 		// other providers can return additional configs for the builtin provider
@@ -523,11 +496,16 @@ func (a *analyzeCommand) startProvidersContainerless(ctx context.Context, needPr
 		default:
 			initCtx, initSpan := tracing.StartNewSpan(ctx, "init",
 				attribute.Key("provider").String(name))
-			additionalBuiltinConfs, err := provider.ProviderInit(initCtx, nil)
+			a.providerStatus.set(name, providerInitializing, "")
+			additionalBuiltinConfs, err := initWithTimeout(name, a.providerInitTimeout, func() ([]provider.InitConfig, error) {
+				return client.ProviderInit(initCtx, nil)
+			})
 			if err != nil {
+				a.providerStatus.set(name, providerFailed, err.Error())
 				a.log.Error(err, "unable to init the providers", "provider", name)
 				os.Exit(1)
 			}
+			a.providerStatus.set(name, providerReady, "")
 			if additionalBuiltinConfs != nil {
 				additionalBuiltinConfigs = append(additionalBuiltinConfigs, additionalBuiltinConfs...)
 			}
@@ -536,9 +514,15 @@ func (a *analyzeCommand) startProvidersContainerless(ctx context.Context, needPr
 	}
 
 	if builtinClient, ok := needProviders["builtin"]; ok {
-		if _, err := builtinClient.ProviderInit(ctx, additionalBuiltinConfigs); err != nil {
+		a.providerStatus.set("builtin", providerInitializing, "")
+		_, err := initWithTimeout("builtin", a.providerInitTimeout, func() ([]provider.InitConfig, error) {
+			return builtinClient.ProviderInit(ctx, additionalBuiltinConfigs)
+		})
+		if err != nil {
+			a.providerStatus.set("builtin", providerFailed, err.Error())
 			return err
 		}
+		a.providerStatus.set("builtin", providerReady, "")
 	}
 	return nil
 }
@@ -590,6 +574,11 @@ func (a *analyzeCommand) DependencyOutputContainerless(ctx context.Context, prov
 		a.log.Error(err, "failed to write dependencies to output file", "file", depOutputFile)
 		return
 	}
+	if a.depsTree {
+		if err := a.writeDepsTree(ctx, providers); err != nil {
+			a.log.Error(err, "failed to write dependency tree to output file", "file", depsTreeFile)
+		}
+	}
 
 }
 
@@ -605,23 +594,16 @@ func (a *analyzeCommand) buildStaticReportFile(ctx context.Context, staticReport
 		applicationNames = nil
 		outputAnalyses = nil
 		outputDeps = nil
-		outputFiles, err := filepath.Glob(filepath.Join(a.output, "output.yaml.*"))
+		bulkApps, err := listBulkApps(a.output)
 		if err != nil {
 			return err
 		}
-		for i := range outputFiles {
-			outputName := filepath.Base(outputFiles[i])
-			applicationName := strings.SplitN(outputName, "output.yaml.", 2)[1]
-			applicationNames = append(applicationNames, applicationName)
-			outputAnalyses = append(outputAnalyses, outputFiles[i])
-			deps := fmt.Sprintf("%s.%s", filepath.Join(a.output, "dependencies.yaml"), applicationName)
-			// If deps for given application are missing, empty the deps path allowing skip it in static-report
-			if _, err := os.Stat(deps); errors.Is(err, os.ErrNotExist) {
-				deps = ""
-			}
-			outputDeps = append(outputDeps, deps)
+		for _, app := range bulkApps {
+			applicationNames = append(applicationNames, app.name)
+			outputAnalyses = append(outputAnalyses, app.analysis)
+			// If deps for given application are missing, the empty deps path allows skipping it in static-report
+			outputDeps = append(outputDeps, app.deps)
 		}
-
 	}
 
 	if depsErr {
@@ -682,7 +664,9 @@ func (a *analyzeCommand) GenerateStaticReportContainerless(ctx context.Context)
 	}
 
 	if a.bulk {
-		a.moveResults()
+		if err := a.moveResults(); err != nil {
+			return err
+		}
 	}
 
 	staticReportAnalyzePath := filepath.Join(a.kantraDir, "static-report")
@@ -694,8 +678,5 @@ func (a *analyzeCommand) GenerateStaticReportContainerless(ctx context.Context)
 	if err != nil {
 		return err
 	}
-	uri := uri.File(filepath.Join(a.output, "static-report", "index.html"))
-	a.log.Info("Static report created. Access it at this URL:", "URL", string(uri))
-
 	return nil
 }
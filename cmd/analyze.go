@@ -17,17 +17,19 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/devfile/alizer/pkg/apis/model"
 	"github.com/devfile/alizer/pkg/apis/recognizer"
 	"github.com/go-logr/logr"
 	"github.com/konveyor-ecosystem/kantra/cmd/internal/hiddenfile"
+	"github.com/konveyor-ecosystem/kantra/cmd/internal/hostpath"
+	"github.com/konveyor-ecosystem/kantra/cmd/internal/i18n"
 	"github.com/konveyor-ecosystem/kantra/pkg/container"
+	kantraprovider "github.com/konveyor-ecosystem/kantra/pkg/provider"
 	"github.com/konveyor/analyzer-lsp/engine"
 	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
 	"github.com/konveyor/analyzer-lsp/provider"
-	"github.com/phayes/freeport"
-	"go.lsp.dev/uri"
 	"gopkg.in/yaml.v2"
 
 	"github.com/spf13/cobra"
@@ -87,6 +89,8 @@ const (
 	nodeJSProvider          = "nodejs"
 	dotnetProvider          = "dotnet"
 	dotnetFrameworkProvider = "dotnetframework"
+	// the builtin provider runs with all others, alone when requested
+	builtinProvider = "builtin"
 )
 
 // valid java file extensions
@@ -111,7 +115,10 @@ type ProviderInit struct {
 type analyzeCommand struct {
 	listSources              bool
 	listTargets              bool
+	listRulesets             bool
 	listProviders            bool
+	listLanguages            bool
+	listFormat               string
 	skipStaticReport         bool
 	analyzeKnownLibraries    bool
 	jsonOutput               bool
@@ -123,6 +130,7 @@ type analyzeCommand struct {
 	labelSelector            string
 	input                    string
 	output                   string
+	outputPrefix             string
 	mode                     string
 	rules                    []string
 	jaegerEndpoint           string
@@ -131,9 +139,11 @@ type analyzeCommand struct {
 	httpsProxy               string
 	noProxy                  string
 	contextLines             int
+	engineSettings           engineSettings
 	incidentSelector         string
 	depFolders               []string
 	overrideProviderSettings string
+	debugSettings            bool
 	provider                 []string
 	providersMap             map[string]ProviderInit
 
@@ -150,6 +160,186 @@ type analyzeCommand struct {
 	providerContainerNames []string
 	cleanup                bool
 	runLocal               bool
+	quiet                  bool
+	summaryOnly            bool
+	summaryColumns         []string
+	progressInterval       time.Duration
+	progressStyle          string
+	// grouping of the incidents of output.json and the console summary
+	groupBy string
+	// paths to analyze derived from .kantraignore, relative to input
+	includedPaths        []string
+	followSymlinks       bool
+	ignoreBrokenSymlinks bool
+	// targets of symlinks outside of input mounted into containers
+	symlinkVolumes map[string]string
+	// links by the mounts of their targets relative to the input
+	symlinkMounts   map[string]string
+	maxFileSize     string
+	skipBinaryFiles bool
+	// paths searched by the builtin provider when files are skipped
+	builtinPaths []string
+	skippedFiles int
+	// files skipped by providers, written to skipped.yaml
+	skipped    []skippedFile
+	snapshot   bool
+	customVars map[string]string
+	// business metadata of the application added to summary and report
+	appMetadataFile string
+	appMetadata     map[string]string
+	// weights used to compute readiness score and incident priorities
+	scoringModelFile string
+	scoringModel     scoringModel
+	failOnScore      int
+	// named policy of the quality gates file the results must pass
+	gate             string
+	qualityGatesFile string
+	qualityGate      *qualityGate
+	// rule engine evaluating the rules in containerless mode
+	engineName string
+	// category of the rules run first, the others are skipped when they
+	// exceed stopAfterIncidents
+	stopAfter          string
+	stopAfterIncidents int
+	// incompatibilities of the input with the Java versions of the targets
+	compatibilityIssues []string
+	platformChecks      bool
+	serverConfig        bool
+	// temp dirs of the rules bundled with kantra added to the rules, e.g.
+	// by --platform-checks
+	bundledRulesDirs    []string
+	defaultRulesetsPath string
+	rulesetsChannel     string
+	// dir of the default rulesets replacing the ones shipped with kantra
+	defaultRulesetsDir string
+	// content addressed copy of the input that is analyzed instead
+	snapshotDir string
+	// input given by the user when a snapshot of it is analyzed
+	originalInput string
+	// set for binaries analyzed in source-only mode, only their
+	// descriptors are extracted to descriptorsDir and analyzed
+	descriptorsOnly bool
+	descriptorsDir  string
+	// temp dir of the input read from stdin with --input -
+	stdinDir string
+	// analyze a git ref of the input in a temp worktree
+	gitRef         string
+	gitWorktree    *gitWorktree
+	gitWorktreeDir string
+	// write incident URIs relative to the input
+	relativePaths bool
+	// links incidents to the source hosting of the input
+	sourceURLTemplate string
+	sourceLinks       *sourceLinks
+	// annotate incidents with the last change of their line
+	gitBlame       bool
+	gitBlameBudget time.Duration
+	// license report of the dependencies
+	licenseReport     bool
+	licenseAllow      []string
+	licenseDeny       []string
+	failOnLicense     bool
+	licenseViolations int
+	// maven repository shared with provider containers
+	m2Dir string
+	// platforms images run with, set for images emulated on the host
+	imagePlatforms map[string]string
+	// stream a copy of the input into the container volume instead of bind mounting it
+	streamInput bool
+	// exclusions of .kantraignore and the symlink policy
+	inputIgnore *kantraIgnore
+	// dirs excluded from analysis in addition to those of .kantraignore
+	excludeDirs []string
+	// isolate the input from writes of the providers
+	readOnlyInput bool
+	// maven settings generated from a mirror
+	mavenMirror        string
+	mavenMirrorOf      string
+	mavenCredentialsID string
+	mavenSettingsDir   string
+	// private registry credentials of the providers
+	registryAuthFiles []string
+	registryAuthDir   string
+	// heap, garbage collector and workspace of the jvm running jdtls
+	jvmMaxMem           string
+	jvmGC               string
+	jdtlsWorkspace      string
+	reuseJdtlsWorkspace bool
+	jvmHeapSized        bool
+	// analyze the input once per profile of the profiles dir
+	allProfiles      bool
+	profileNames     []string
+	profilesDir      string
+	analysisProfiles []analysisProfile
+	// network and security options of provider containers
+	providerNetwork      string
+	providerSecurityOpts []string
+	// SELinux labeling of the volumes of provider containers, resolved into
+	// the volume label option
+	selinuxLabel string
+	volumeLabel  string
+	// user namespace of provider containers, resolved for the host
+	providerUserNS  string
+	containerUserNS string
+	// detail level of the search index of the static report
+	reportIndex string
+	// inputs analyzed in bulk one after the other, bulkSequence is set for
+	// the analysis of each of them
+	bulkInputs   []string
+	bulkSequence bool
+	// run the rules tagging the input only, for a technology inventory
+	tagsOnly bool
+	// fail on problems of the rule files before the analysis
+	strictRules bool
+	// location of the static report, in the output dir by default
+	staticReportDir string
+	reportName      string
+	// formats of the analysis output besides yaml
+	outputFormats []string
+	exporters     map[string]outputExporter
+	// analyze only the files changed since the git ref
+	changedSince string
+	changedFiles []string
+	// gzip output.yaml and dependencies.yaml
+	compressOutput bool
+	// write incidents.jsonl with one incident per line
+	incidentStore bool
+	// write the digests of the outputs, signed with cosign with signWith
+	signOutput bool
+	signWith   string
+	// prometheus metrics file of the analyses
+	metricsFile      string
+	providerRestarts int
+	// remove the lock of the output dir of another analysis
+	forceUnlock bool
+	// ID of the analysis holding the lock of the output dir
+	runID string
+	// record the provider responses to a file, or replay them from one
+	record          string
+	replay          string
+	recording       *providerRecording
+	replayRecording *providerRecording
+	// errors and warnings of the analysis printed at its end
+	problems *problemCollector
+	// host paths mounted in the containers of providers
+	providerVolumeArgs []string
+	providerVolumes    []providerVolume
+	// write the dependency tree next to the flat dependencies
+	depsTree bool
+	// run the analysis as a kubernetes job
+	kube          bool
+	kubeNamespace string
+	kubeContext   string
+	kubeInputPVC  string
+	kubeTimeout   time.Duration
+	// range of the ports of the providers of the run, e.g. 30000-30999
+	providerPorts string
+	// time providers may take to initialize in containerless mode, and
+	// provider containers are checked for after they start
+	providerInitTimeout   time.Duration
+	providerHealthTimeout time.Duration
+	// reports provider state changes to the user
+	providerStatus *providerStatus
 
 	// for containerless cmd
 	reqMap    map[string]string
@@ -159,20 +349,36 @@ type analyzeCommand struct {
 // analyzeCmd represents the analyze command
 func NewAnalyzeCmd(log logr.Logger) *cobra.Command {
 	analyzeCmd := &analyzeCommand{
-		log:     log,
-		cleanup: true,
+		log:            log,
+		cleanup:        true,
+		providerStatus: newProviderStatus(os.Stdout),
 	}
 
 	analyzeCommand := &cobra.Command{
 		Use:   "analyze",
 		Short: "Analyze application source code",
 		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if analyzeCmd.quiet && analyzeCmd.summaryOnly {
+				return i18n.Errorf("must not specify both quiet and summary-only")
+			}
+			if analyzeCmd.quiet || analyzeCmd.summaryOnly {
+				logrusLog.SetOutput(io.Discard)
+				analyzeCmd.providerStatus = newProviderStatus(nil)
+			}
+			if analyzeCmd.quiet {
+				cmd.SilenceErrors = true
+			}
 			// TODO (pgaikwad): this is nasty
 			if !cmd.Flags().Lookup("list-sources").Changed &&
 				!cmd.Flags().Lookup("list-targets").Changed &&
-				!cmd.Flags().Lookup("list-providers").Changed {
-				cmd.MarkFlagRequired("input")
-				cmd.MarkFlagRequired("output")
+				!cmd.Flags().Lookup("list-providers").Changed &&
+				!cmd.Flags().Lookup("list-rulesets").Changed {
+				if len(analyzeCmd.bulkInputs) == 0 {
+					cmd.MarkFlagRequired("input")
+				}
+				if !analyzeCmd.listLanguages {
+					cmd.MarkFlagRequired("output")
+				}
 				if err := cmd.ValidateRequiredFlags(); err != nil {
 					return err
 				}
@@ -184,26 +390,87 @@ func NewAnalyzeCmd(log logr.Logger) *cobra.Command {
 					return err
 				}
 			}
+			if val, err := cmd.Flags().GetBool(noCleanupFlag); err == nil {
+				analyzeCmd.cleanup = !val
+			}
 			err := analyzeCmd.Validate(cmd.Context())
 			if err != nil {
+				// RunE removing them does not run
+				analyzeCmd.removeValidateResources()
 				log.Error(err, "failed to validate flags")
 				return err
 			}
 			return nil
 		},
-		RunE: func(cmd *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) (runErr error) {
 			if val, err := cmd.Flags().GetUint32(logLevelFlag); err == nil {
 				analyzeCmd.logLevel = &val
 			}
-			if val, err := cmd.Flags().GetBool(noCleanupFlag); err == nil {
-				analyzeCmd.cleanup = !val
-			}
+			// printed last, after the problems of the deferred steps
+			defer analyzeCmd.collectProblems()()
+			defer analyzeCmd.removeValidateResources()
 			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 			defer stop()
 
 			if analyzeCmd.listProviders {
-				analyzeCmd.ListAllProviders()
-				return nil
+				return analyzeCmd.ListAllProviders(os.Stdout)
+			}
+			if analyzeCmd.listLanguages {
+				return analyzeCmd.ListLanguages(os.Stdout)
+			}
+			if analyzeCmd.listRulesets {
+				return analyzeCmd.ListRuleSets(ctx, os.Stdout)
+			}
+			if analyzeCmd.allProfiles || len(analyzeCmd.profileNames) > 0 {
+				return analyzeCmd.RunProfiles(ctx, cmd.Flags())
+			}
+			// kube jobs write the output dir only when copying it back
+			if !analyzeCmd.kube && !analyzeCmd.listSources && !analyzeCmd.listTargets {
+				if err := analyzeCmd.lockOutput(); err != nil {
+					return err
+				}
+				defer func() {
+					if err := analyzeCmd.unlockOutput(); err != nil {
+						log.Error(err, "failed to unlock output dir", "output", analyzeCmd.output)
+					}
+				}()
+			}
+			// outputs are renamed once all steps reading them are done
+			if analyzeCmd.outputPrefix != "" && !analyzeCmd.listSources && !analyzeCmd.listTargets {
+				defer func() {
+					if err := analyzeCmd.prefixOutputs(); err != nil {
+						log.Error(err, "failed to prefix outputs", "prefix", analyzeCmd.outputPrefix)
+					}
+				}()
+			}
+			// the analysis of each bulk input adds its own metrics
+			if analyzeCmd.metricsFile != "" && len(analyzeCmd.bulkInputs) == 0 && !analyzeCmd.listSources && !analyzeCmd.listTargets {
+				started := time.Now()
+				defer func() {
+					if err := analyzeCmd.writeMetrics(started, runErr); err != nil {
+						log.Error(err, "failed to write metrics", "file", analyzeCmd.metricsFile)
+					}
+				}()
+			}
+			if analyzeCmd.kube {
+				return analyzeCmd.RunKube(ctx, cmd.Flags())
+			}
+			if len(analyzeCmd.bulkInputs) > 0 {
+				return analyzeCmd.RunBulk(ctx, cmd.Flags())
+			}
+			if analyzeCmd.bulk && !analyzeCmd.listSources && !analyzeCmd.listTargets {
+				started := time.Now()
+				defer func() {
+					statuses, err := analyzeCmd.recordBulkStatus(started, runErr)
+					if err != nil {
+						log.Error(err, "failed to record bulk status")
+						return
+					}
+					// the bulk sequence prints the status once all inputs are analyzed
+					if !analyzeCmd.quiet && !analyzeCmd.bulkSequence {
+						writeBulkStatus(os.Stdout, statuses)
+					}
+				}()
 			}
 
 			// ***** RUN CONTAINERLESS MODE *****
@@ -218,6 +485,12 @@ func NewAnalyzeCmd(log logr.Logger) *cobra.Command {
 					}
 					return nil
 				}
+				// providers run on the host, only the analyzed ref is recorded
+				if analyzeCmd.gitWorktree != nil {
+					if err := analyzeCmd.writeRunInfo(nil); err != nil {
+						log.Error(err, "failed to write run info")
+					}
+				}
 				err := analyzeCmd.RunAnalysisContainerless(cmd.Context())
 				if err != nil {
 					return err
@@ -246,8 +519,11 @@ func NewAnalyzeCmd(log logr.Logger) *cobra.Command {
 					return err
 				}
 				foundProviders := []string{}
-				// file input means a binary was given which only the java provider can use
-				if analyzeCmd.isFileInput {
+				// descriptors extracted from a binary only need the builtin provider
+				if analyzeCmd.builtinOnly() {
+					log.V(1).Info("running builtin provider only")
+				} else if analyzeCmd.isFileInput {
+					// file input means a binary was given which only the java provider can use
 					foundProviders = append(foundProviders, javaProvider)
 				} else {
 					foundProviders, err = analyzeCmd.setProviders(languages, foundProviders)
@@ -266,8 +542,8 @@ func NewAnalyzeCmd(log logr.Logger) *cobra.Command {
 
 				// default rulesets are only java rules
 				// may want to change this in the future
-				if len(foundProviders) > 0 && len(analyzeCmd.rules) == 0 && !slices.Contains(foundProviders, javaProvider) {
-					return fmt.Errorf("No providers found with default rules. Use --rules option")
+				if len(foundProviders) > 0 && !analyzeCmd.hasUserRules() && !slices.Contains(foundProviders, javaProvider) {
+					return i18n.Errorf("No providers found with default rules. Use --rules option")
 				}
 
 				xmlOutputDir, err := analyzeCmd.ConvertXML(ctx)
@@ -279,23 +555,20 @@ func NewAnalyzeCmd(log logr.Logger) *cobra.Command {
 				// in this case, we can first check for a java project
 				// if not found, only start builtin provider
 				if len(foundProviders) == 0 {
-					foundJava, err := analyzeCmd.detectJavaProviderFallback()
-					if err != nil {
-						return err
+					foundJava := false
+					if !analyzeCmd.builtinOnly() {
+						foundJava, err = analyzeCmd.detectJavaProviderFallback()
+						if err != nil {
+							return err
+						}
 					}
 					if foundJava {
 						foundProviders = append(foundProviders, javaProvider)
 					} else {
 						analyzeCmd.needsBuiltin = true
-						return analyzeCmd.RunAnalysis(ctx, xmlOutputDir, analyzeCmd.input)
 					}
 				}
 
-				err = analyzeCmd.setProviderInitInfo(foundProviders)
-				if err != nil {
-					log.Error(err, "failed to set provider init info")
-					return err
-				}
 				// defer cleaning created resources here instead of PostRun
 				// if Run returns an error, PostRun does not run
 				defer func() {
@@ -304,26 +577,7 @@ func NewAnalyzeCmd(log logr.Logger) *cobra.Command {
 						log.Error(err, "failed to clean temporary directories")
 					}
 				}()
-				containerNetworkName, err := analyzeCmd.createContainerNetwork()
-				if err != nil {
-					log.Error(err, "failed to create container network")
-					return err
-				}
-				// share source app with provider and engine containers
-				containerVolName, err := analyzeCmd.createContainerVolume()
-				if err != nil {
-					log.Error(err, "failed to create container volume")
-					return err
-				}
-				// allow for 5 retries of running provider in the case of port in use
-				err = analyzeCmd.RunProviders(ctx, containerNetworkName, containerVolName, 5)
-				if err != nil {
-					log.Error(err, "failed to run provider")
-					return err
-				}
-				err = analyzeCmd.RunAnalysis(ctx, xmlOutputDir, containerVolName)
-				if err != nil {
-					log.Error(err, "failed to run analysis")
+				if err := analyzeCmd.runContainerAnalysis(ctx, xmlOutputDir, foundProviders); err != nil {
 					return err
 				}
 			} else {
@@ -333,58 +587,169 @@ func NewAnalyzeCmd(log logr.Logger) *cobra.Command {
 					return err
 				}
 			}
-			err := analyzeCmd.CreateJSONOutput()
-			if err != nil {
-				log.Error(err, "failed to create json output file")
-				return err
-			}
-
-			err = analyzeCmd.GenerateStaticReport(ctx)
-			if err != nil {
-				log.Error(err, "failed to generate static report")
-				return err
-			}
-
-			return nil
+			return analyzeCmd.writeAnalysisOutputs(ctx, analyzeCmd.GenerateStaticReport)
 		},
 	}
 	analyzeCommand.Flags().BoolVar(&analyzeCmd.listSources, "list-sources", false, "list rules for available migration sources")
 	analyzeCommand.Flags().BoolVar(&analyzeCmd.listTargets, "list-targets", false, "list rules for available migration targets")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.listRulesets, "list-rulesets", false, "list the rulesets the analysis would load, default, --rules, bundled and of the profiles, with their rules and the rules selected by the sources, targets or label selector")
 	analyzeCommand.Flags().BoolVar(&analyzeCmd.listProviders, "list-providers", false, "list available supported providers")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.listLanguages, "list-languages", false, "list languages, frameworks and tools detected in the input and the targets suggested for them")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.listFormat, "format", listFormatText, "output format of --list-sources, --list-targets, --list-rulesets, --list-providers and --list-languages. Must be one of 'text', 'json' or 'yaml'")
 	analyzeCommand.Flags().StringArrayVarP(&analyzeCmd.sources, "source", "s", []string{}, "source technology to consider for analysis. Use multiple times for additional sources: --source <source1> --source <source2> ...")
 	analyzeCommand.Flags().StringArrayVarP(&analyzeCmd.targets, "target", "t", []string{}, "target technology to consider for analysis. Use multiple times for additional targets: --target <target1> --target <target2> ...")
 	analyzeCommand.Flags().StringVarP(&analyzeCmd.labelSelector, "label-selector", "l", "", "run rules based on specified label selector expression")
 	analyzeCommand.Flags().StringArrayVar(&analyzeCmd.rules, "rules", []string{}, "filename or directory containing rule files. Use multiple times for additional rules: --rules <rule1> --rules <rule2> ...")
-	analyzeCommand.Flags().StringVarP(&analyzeCmd.input, "input", "i", "", "path to application source code or a binary")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.strictRules, "strict-rules", false, "fail before starting the providers when yaml rule files of --rules fail to parse, listing all their problems")
+	analyzeCommand.Flags().StringVarP(&analyzeCmd.input, "input", "i", "", "path to application source code or a binary, - reads a tar stream of the input from stdin, e.g. from git archive")
 	analyzeCommand.Flags().StringVarP(&analyzeCmd.output, "output", "o", "", "path to the directory for analysis output")
 	analyzeCommand.Flags().BoolVar(&analyzeCmd.skipStaticReport, "skip-static-report", false, "do not generate static report")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.tagsOnly, "tags-only", false, "run only the rules tagging the input, e.g. with the technologies it uses, for a quick technology inventory without violations. Requires containerless mode")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.staticReportDir, "static-report-dir", "", "directory to write the static report to, e.g. of a docs site, instead of static-report in the output dir")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.reportName, "report-name", defaultReportName, "file name of the static report page, its data files are named after it so that several reports can share a static report dir")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.reportIndex, "report-index", reportIndexFull, fmt.Sprintf("detail level of the search index of the static report, one of %s. Lower levels make smaller indexes of large reports", strings.Join(reportIndexLevels, ", ")))
 	analyzeCommand.Flags().BoolVar(&analyzeCmd.analyzeKnownLibraries, "analyze-known-libraries", false, "analyze known open-source libraries")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.depsTree, "deps-tree", false, "also write the hierarchical dependency tree of each module to dependencies-tree.yaml and show it in the static report, containerless mode only")
 	analyzeCommand.Flags().StringVar(&analyzeCmd.mavenSettingsFile, "maven-settings", "", "path to a custom maven settings file to use")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.mavenMirror, "maven-mirror", "", "URL of a maven repository mirror to resolve dependencies from, generates maven settings instead of --maven-settings. Can also be set with KANTRA_MAVEN_MIRROR")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.mavenMirrorOf, "maven-mirror-of", "*", "repositories the maven mirror replaces")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.mavenCredentialsID, "maven-credentials-id", "", "id of the maven mirror credentials, read from KANTRA_MAVEN_USERNAME and KANTRA_MAVEN_PASSWORD")
+	analyzeCommand.Flags().StringArrayVar(&analyzeCmd.registryAuthFiles, "registry-auth-file", []string{}, "path to private registry credentials of the providers, one of settings.xml (maven), .npmrc or pip.conf. Can be repeated")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.jvmMaxMem, "jvm-max-mem", "", "max heap of the jvm of the java provider, e.g. 8g. Can also be set with JVM_MAX_MEM, sized by the lines of java code of the input in containerless mode by default")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.jvmGC, "jvm-gc", "", "garbage collector of the jvm of the java provider, one of g1, parallel, serial, z or shenandoah")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.jdtlsWorkspace, "jdtls-workspace", "", "path to a directory to keep the jdtls workspace cache in between containerless runs")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.reuseJdtlsWorkspace, "reuse-jdtls-workspace", true, "reuse the jdtls workspace of the input from previous containerless runs, cached in the kantra dir")
 	analyzeCommand.Flags().StringVarP(&analyzeCmd.mode, "mode", "m", string(provider.FullAnalysisMode), "analysis mode. Must be one of 'full' (source + dependencies) or 'source-only'")
 	analyzeCommand.Flags().BoolVar(&analyzeCmd.jsonOutput, "json-output", false, "create analysis and dependency output as json")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.metricsFile, "metrics-file", "", "file to add the metrics of the analysis to in the prometheus text format, e.g. for the textfile collector of the node exporter")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.compressOutput, "compress-output", false, "compress output.yaml and dependencies.yaml with gzip once the other outputs are generated, kantra commands read compressed outputs")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.signOutput, "sign-output", false, "write the SHA-256 digests of the analysis outputs to output.sha256, to verify them with 'sha256sum -c'")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.signWith, "sign-with", "", "sign output.sha256 with cosign into output.sha256.bundle, with a cosign key, e.g. cosign.key or a KMS URI, or 'keyless' for the Sigstore identity of the user or CI job. Implies --sign-output")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.incidentStore, "incident-store", false, "also write the incidents one per line to incidents.jsonl, which kantra query --stream reads without loading the whole output")
+	analyzeCommand.Flags().StringSliceVar(&analyzeCmd.outputFormats, "output-format", []string{}, fmt.Sprintf("formats of the analysis output besides yaml, one or more of %s or of an exporter of the exporters dir of the kantra dir. junit writes the violations as failed test cases to junit.xml for the test reports of CI servers", strings.Join(outputFormats, ", ")))
 	analyzeCommand.Flags().BoolVar(&analyzeCmd.overwrite, "overwrite", false, "overwrite output directory")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.outputPrefix, "output-prefix", "", "prefix of the output files and of the static report dir, e.g. payments-api-, so that analyses with different prefixes share the output dir")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.forceUnlock, "force-unlock", false, "remove the lock of the output dir left by an analysis that is not running anymore, e.g. one killed on another host")
 	analyzeCommand.Flags().BoolVar(&analyzeCmd.bulk, "bulk", false, "running multiple analyze commands in bulk will result to combined static report")
+	analyzeCommand.Flags().StringArrayVar(&analyzeCmd.bulkInputs, "bulk-input", []string{}, "input to analyze in bulk into the output dir, continuing with the next inputs when one fails. Use multiple times for additional inputs")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.bulkSequence, "bulk-sequence", false, "")
+	analyzeCommand.Flags().MarkHidden("bulk-sequence")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.kube, "kube", false, "run the analysis as a kubernetes job with kubectl and copy its output to the output dir. The input is a git URL cloned in the job or a path in the volume of --kube-input-pvc")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.kubeNamespace, "kube-namespace", "", "namespace of the kube job, defaults to the namespace of the kubectl context")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.kubeContext, "kube-context", "", "kubectl context of the cluster of the kube job, defaults to the current context")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.kubeInputPVC, "kube-input-pvc", "", "persistent volume claim holding the input of the kube job, the input is a path relative to the root of the volume")
+	analyzeCommand.Flags().DurationVar(&analyzeCmd.kubeTimeout, "kube-timeout", 2*time.Hour, "time the kube job may take, including the wait for its pod to be scheduled")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.providerPorts, "provider-ports", "", "range of the ports of the provider containers, e.g. 30000-30999, so that users of a shared host get their own ranges, defaults to KANTRA_PROVIDER_PORTS or any free port")
+	analyzeCommand.Flags().DurationVar(&analyzeCmd.providerInitTimeout, "provider-init-timeout", 0, "time providers may take to initialize in containerless mode, e.g. 10m, jdtls takes minutes on large binaries, 0 for no limit")
+	analyzeCommand.Flags().DurationVar(&analyzeCmd.providerHealthTimeout, "provider-health-timeout", 0, "time provider containers are checked for after they start, failing the analysis when one stops, 0 checks them once")
 	analyzeCommand.Flags().StringVar(&analyzeCmd.jaegerEndpoint, "jaeger-endpoint", "", "jaeger endpoint to collect traces")
 	analyzeCommand.Flags().BoolVar(&analyzeCmd.enableDefaultRulesets, "enable-default-rulesets", true, "run default rulesets with analysis")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.allProfiles, "all-profiles", false, "analyze the input once per profile of the profiles dir, writing each to a subdir of the output and comparing the results")
+	analyzeCommand.Flags().StringArrayVar(&analyzeCmd.profileNames, "profile", []string{}, "name of a profile of the profiles dir to analyze the input with. Use multiple times for additional profiles")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.profilesDir, "profiles-dir", defaultProfilesDir, "path to the directory of analysis profiles, yaml files with targets, sources, labelSelector, rules, mode and enableDefaultRulesets")
 	analyzeCommand.Flags().StringVar(&analyzeCmd.httpProxy, "http-proxy", loadEnvInsensitive("http_proxy"), "HTTP proxy string URL")
 	analyzeCommand.Flags().StringVar(&analyzeCmd.httpsProxy, "https-proxy", loadEnvInsensitive("https_proxy"), "HTTPS proxy string URL")
 	analyzeCommand.Flags().StringVar(&analyzeCmd.noProxy, "no-proxy", loadEnvInsensitive("no_proxy"), "proxy excluded URLs (relevant only with proxy)")
 	analyzeCommand.Flags().IntVar(&analyzeCmd.contextLines, "context-lines", 100, "number of lines of source code to include in the output for each incident")
+	analyzeCmd.engineSettings.addFlags(analyzeCommand.Flags())
 	analyzeCommand.Flags().StringVar(&analyzeCmd.incidentSelector, "incident-selector", "", "an expression to select incidents based on custom variables. ex: (!package=io.konveyor.demo.config-utils)")
+	analyzeCommand.Flags().StringToStringVar(&analyzeCmd.customVars, "custom-var", nil, "custom variable added to all incidents that the incident selector can reference, ex: --custom-var team=payments")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.appMetadataFile, "app-metadata", "", "YAML file with business metadata of the application such as criticality, owner and lifecycle, added to summary.json and the static report data")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.scoringModelFile, "scoring-model", "", "YAML file with weights of the scoring model used to compute the readiness score")
+	analyzeCommand.Flags().IntVar(&analyzeCmd.failOnScore, "fail-on-score", 0, "exit with an error when the readiness score of the application is below this value (0-100)")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.gate, "gate", "", "name of the quality gate the results must pass, exit with an error when they exceed its thresholds")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.qualityGatesFile, "quality-gates", "", "YAML file with the named quality gates selected by --gate, defaults to "+defaultQualityGatesFile+" in the working dir")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.platformChecks, "platform-checks", false, "also check Dockerfiles, helm charts and Kubernetes manifests for OpenShift compatibility")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.serverConfig, "server-config", false, "also inventory the datasources, JMS queues and security domains of JBoss, WebLogic, WebSphere and Liberty server configurations into server-config.yaml")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.defaultRulesetsPath, "default-rulesets-path", "", "local dir or URL of a .tar.gz or .zip bundle with rulesets to use instead of the default rulesets")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.rulesetsChannel, "rulesets-channel", "", "channel of the default rulesets bundle to use, e.g. community, a subdir of the bundle")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.relativePaths, "relative-paths", false, "write URIs of incidents in the input relative to it, making results independent of the machine they were created on")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.sourceURLTemplate, "source-url-template", "", "link incidents to the source hosting with a template using {ref}, {path} and {line}, ex: https://github.com/org/app/blob/{ref}/{path}#L{line}, 'auto' derives it from the git remote of the input")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.gitBlame, "git-blame", false, "annotate incidents with the author and date of the last change of their line from git blame")
+	analyzeCommand.Flags().DurationVar(&analyzeCmd.gitBlameBudget, "git-blame-budget", 2*time.Minute, "maximum time spent running git blame, 0 for no limit")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.licenseReport, "license-report", false, "write the licenses of the dependencies to licenses.json")
+	analyzeCommand.Flags().StringArrayVar(&analyzeCmd.licenseAllow, "license-allow", []string{}, "license allowed for dependencies, wildcards are supported, ex: 'Apache*'. Dependencies without an allowed license are reported as not allowed")
+	analyzeCommand.Flags().StringArrayVar(&analyzeCmd.licenseDeny, "license-deny", []string{}, "license denied for dependencies, wildcards are supported, ex: 'GPL*'")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.failOnLicense, "fail-on-license", false, "exit with an error when a dependency has a denied or not allowed license")
 	analyzeCommand.Flags().StringArrayVarP(&analyzeCmd.depFolders, "dependency-folders", "d", []string{}, "directory for dependencies")
 	analyzeCommand.Flags().StringVar(&analyzeCmd.overrideProviderSettings, "override-provider-settings", "", "override the provider settings, the analysis pod will be run on the host network and no providers will be started up")
-	analyzeCommand.Flags().StringArrayVar(&analyzeCmd.provider, "provider", []string{}, "specify which provider(s) to run")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.debugSettings, "debug-settings", false, "write the provider settings to settings.json in the output dir, with credentials, ports and host paths redacted")
+	analyzeCommand.Flags().StringArrayVar(&analyzeCmd.provider, "provider", []string{}, "specify which provider(s) to run, builtin runs the builtin provider only, e.g. for a fast scan of XML, properties and other config files")
 	analyzeCommand.Flags().BoolVar(&analyzeCmd.runLocal, "run-local", true, "run Java analysis in containerless mode")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.engineName, "engine", defaultEngine, fmt.Sprintf("rule engine evaluating the rules, one of %s, engines other than %s need containerless mode", strings.Join(sortedMapKeys(analysisEngines), ", "), defaultEngine))
+	analyzeCommand.Flags().StringVar(&analyzeCmd.record, "record", "", "record the provider responses of the analysis to this file, e.g. providers.rec, to reproduce it with --replay. Requires containerless mode")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.replay, "replay", "", "run the rules against the provider responses recorded with --record instead of starting providers. Requires containerless mode")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.stopAfter, "stop-after", "", "run the rules of this category first, mandatory, optional or potential, and skip the other rules when they find more incidents than --stop-after-incidents, e.g. to fail CI early")
+	analyzeCommand.Flags().IntVar(&analyzeCmd.stopAfterIncidents, "stop-after-incidents", 0, "incidents of the --stop-after rules above which the other rules are skipped")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.quiet, "quiet", false, "do not print anything to the console, rely on the exit code")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.summaryOnly, "summary-only", false, "only print a one-line summary of the analysis results to the console")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.groupBy, "group-by", "", fmt.Sprintf("group the incidents of output.json by %s, by rule as output.yaml by default. The table of the grouping is printed first after the analysis unless --summary-columns is set", strings.Join(groupByValues, ", ")))
+	analyzeCommand.Flags().StringSliceVar(&analyzeCmd.summaryColumns, "summary-columns", defaultSummaryColumns, "tables of incidents printed after the analysis, of rules, categories, files, packages or label=<key> for the values of a label, e.g. label=konveyor.io/target")
+	analyzeCommand.Flags().DurationVar(&analyzeCmd.progressInterval, "progress-interval", 30*time.Second, "interval between progress lines when output is not an interactive terminal, 0 disables progress output")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.progressStyle, "progress-style", progressStyleAuto, "progress output style. Must be one of 'auto' or 'plain' (without escape codes)")
+	analyzeCommand.Flags().StringArrayVar(&analyzeCmd.excludeDirs, "exclude-dir", []string{}, "dir of the input to exclude from analysis, in addition to those of .kantraignore, e.g. 'node_modules' for dirs of any name or 'src/test' relative to the input. Use multiple times for additional dirs")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.followSymlinks, "follow-symlinks", false, "analyze targets of symlinks pointing outside of the input, they are excluded by default")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.ignoreBrokenSymlinks, "ignore-broken-symlinks", false, "exclude broken symlinks in the input from analysis instead of failing")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.maxFileSize, "max-file-size", "", "skip files larger than this size in the builtin provider, e.g. 2MB")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.skipBinaryFiles, "skip-binary-files", false, "skip binary files in the builtin provider")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.gitRef, "git-ref", "", "analyze a git ref of the input, e.g. a tag, checked out into a temp worktree leaving the checkout of the input untouched")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.changedSince, "changed-since", "", "analyze only the files of the input changed since a git ref, e.g. the target branch of a pull request, and the java files referencing them")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.providerNetwork, "provider-network", "", "network of provider containers instead of a network created for the analysis, 'none' runs them without network access and maven offline")
+	analyzeCommand.Flags().StringArrayVar(&analyzeCmd.providerSecurityOpts, "provider-security-opt", []string{}, "security option of provider containers, e.g. seccomp=<profile.json> or apparmor=<profile>. Use multiple times for additional options")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.selinuxLabel, "selinux-label", selinuxLabelAuto, "SELinux label of the volumes of provider containers, one of auto (shared on SELinux hosts), shared (:z), private (:Z, with a common level of the containers) or none")
+	analyzeCommand.Flags().StringVar(&analyzeCmd.providerUserNS, "provider-userns", usernsAuto, "user namespace of provider containers, e.g. keep-id to run them as the host user with rootless podman, auto uses keep-id with rootless podman on SELinux hosts, none keeps the default of the container tool")
+	analyzeCommand.Flags().StringArrayVar(&analyzeCmd.providerVolumeArgs, "provider-volume", []string{}, "host path mounted in the container of a provider as <provider>=<host path>:<container path>[:ro], e.g. java=/etc/pki/ca.crt:/etc/pki/extra/ca.crt:ro. Use multiple times for additional volumes")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.readOnlyInput, "read-only-input", false, "never write into the input: providers get an overlay of the input with podman, a read-only mount with docker and a snapshot in containerless mode")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.streamInput, "stream-input", false, "copy the input into the container volume as a tar stream instead of bind mounting it, faster for large inputs in podman machines. Paths excluded by .kantraignore are not copied")
+	analyzeCommand.Flags().BoolVar(&analyzeCmd.snapshot, "snapshot-input", false, "analyze a read-only snapshot of the input so that changes made during analysis do not affect it")
 
 	return analyzeCommand
 }
 
 func (a *analyzeCommand) Validate(ctx context.Context) error {
+	if err := a.resolveDefaultRulesets(); err != nil {
+		return err
+	}
+	switch a.listFormat {
+	case "", listFormatText, listFormatJSON, listFormatYAML:
+	default:
+		return fmt.Errorf("format must be one of 'text', 'json' or 'yaml'")
+	}
 	if a.listSources || a.listTargets || a.listProviders {
 		return nil
 	}
+	if a.listRulesets {
+		return a.validateListRuleSets()
+	}
+	if a.listLanguages {
+		if _, err := os.Stat(a.input); err != nil {
+			return fmt.Errorf("%w failed to stat input path %s", err, a.input)
+		}
+		return nil
+	}
+	if err := a.validateOutputPrefix(); err != nil {
+		return err
+	}
+	if a.gitRef != "" && (a.kube || len(a.bulkInputs) > 0) {
+		return fmt.Errorf("git-ref is not supported with kube and bulk inputs")
+	}
+	if a.kube {
+		return a.validateKube()
+	}
+	if len(a.bulkInputs) > 0 {
+		return a.validateBulkInputs()
+	}
+	if a.allProfiles || len(a.profileNames) > 0 {
+		return a.validateProfiles()
+	}
+	if a.listFormat != "" && a.listFormat != listFormatText {
+		return fmt.Errorf("format is only supported with --list-sources, --list-targets, --list-rulesets, --list-providers and --list-languages")
+	}
+	if a.progressStyle != progressStyleAuto && a.progressStyle != progressStylePlain {
+		return i18n.Errorf("progress-style must be one of 'auto' or 'plain'")
+	}
 	if a.labelSelector != "" && (len(a.sources) > 0 || len(a.targets) > 0) {
-		return fmt.Errorf("must not specify label-selector and sources or targets")
+		return i18n.Errorf("must not specify label-selector and sources or targets")
 	}
 	// Validate source labels
 	if len(a.sources) > 0 {
@@ -403,7 +768,7 @@ func (a *analyzeCommand) Validate(ctx context.Context) error {
 				}
 			}
 			if !found {
-				return fmt.Errorf("unknown source: \"%s\"", source)
+				return i18n.Errorf("unknown source: \"%s\"", source)
 			}
 		}
 	}
@@ -424,11 +789,19 @@ func (a *analyzeCommand) Validate(ctx context.Context) error {
 				}
 			}
 			if !found {
-				return fmt.Errorf("unknown target: \"%s\"", source)
+				return i18n.Errorf("unknown target: \"%s\"", source)
 			}
 		}
 	}
 
+	if err := a.validateGitRef(); err != nil {
+		return err
+	}
+	if a.input == stdinInput {
+		if err := a.readStdinInput(); err != nil {
+			return err
+		}
+	}
 	if a.overrideProviderSettings != "" {
 		stat, err := os.Stat(a.overrideProviderSettings)
 		if err != nil {
@@ -448,13 +821,13 @@ func (a *analyzeCommand) Validate(ctx context.Context) error {
 			if arg == "-i" || strings.Contains(arg, "--input") {
 				inputNum += 1
 				if inputNum > 1 {
-					return fmt.Errorf("must specify only one input source")
+					return i18n.Errorf("must specify only one input source")
 				}
 			}
 		}
 		stat, err := os.Stat(a.input)
 		if err != nil {
-			return fmt.Errorf("%w failed to stat input path %s", err, a.input)
+			return i18n.Errorf("%w failed to stat input path %s", err, a.input)
 		}
 		// when input isn't a dir, it's pointing to a binary
 		// we need abs path to mount the file correctly
@@ -465,14 +838,12 @@ func (a *analyzeCommand) Validate(ctx context.Context) error {
 			case JavaArchive, WebArchive, EnterpriseArchive, ClassFile:
 				a.log.V(5).Info("valid java file found")
 			default:
-				return fmt.Errorf("invalid file type %v", fileExt)
+				return i18n.Errorf("invalid file type %v", fileExt)
 			}
 			a.input, err = filepath.Abs(a.input)
 			if err != nil {
 				return fmt.Errorf("%w failed to get absolute path for input file %s", err, a.input)
 			}
-			// make sure we mount a file and not a dir
-			SourceMountPath = path.Join(SourceMountPath, filepath.Base(a.input))
 			a.isFileInput = true
 		}
 	}
@@ -492,26 +863,56 @@ func (a *analyzeCommand) Validate(ctx context.Context) error {
 		}
 	}
 	if stat != nil && !stat.IsDir() {
-		return fmt.Errorf("output path %s is not a directory", a.output)
+		return i18n.Errorf("output path %s is not a directory", a.output)
 	}
-	if len(a.depFolders) != 0 {
-		for i := range a.depFolders {
-			stat, err := os.Stat(a.depFolders[i])
-			if err != nil {
-				return fmt.Errorf("%w failed to stat dependency folder %v", err, a.depFolders[i])
-			}
-			if stat != nil && !stat.IsDir() {
-				return fmt.Errorf("depdendecy folder %v is not a directory", a.depFolders[i])
-			}
-		}
+	if err := a.validateDepFolders(); err != nil {
+		return err
 	}
 	if a.mode != string(provider.FullAnalysisMode) &&
 		a.mode != string(provider.SourceOnlyAnalysisMode) {
-		return fmt.Errorf("mode must be one of 'full' or 'source-only'")
+		return i18n.Errorf("mode must be one of 'full' or 'source-only'")
 	}
 	if _, err := os.Stat(a.mavenSettingsFile); a.mavenSettingsFile != "" && err != nil {
 		return fmt.Errorf("%w failed to stat maven settings file at path %s", err, a.mavenSettingsFile)
 	}
+	// proxy URLs may hold credentials
+	for _, proxy := range []*string{&a.httpProxy, &a.httpsProxy} {
+		value, err := resolveSecret(ctx, *proxy)
+		if err != nil {
+			return err
+		}
+		*proxy = value
+	}
+	if err := a.writeMavenSettings(); err != nil {
+		return err
+	}
+	if err := a.writeRegistryAuth(); err != nil {
+		return err
+	}
+	if err := a.validateJvm(); err != nil {
+		return err
+	}
+	if err := a.validateTagsOnly(); err != nil {
+		return err
+	}
+	if err := a.validateGroupBy(); err != nil {
+		return err
+	}
+	if err := validateSummaryColumns(a.summaryColumns); err != nil {
+		return err
+	}
+	if err := a.validateOutputFormats(); err != nil {
+		return err
+	}
+	if !slices.Contains(reportIndexLevels, a.reportIndex) {
+		return fmt.Errorf("report index must be one of %s", strings.Join(reportIndexLevels, ", "))
+	}
+	if err := a.validateReportLocation(); err != nil {
+		return err
+	}
+	if absPath, err := filepath.Abs(a.metricsFile); a.metricsFile != "" && err == nil {
+		a.metricsFile = absPath
+	}
 	// try to get abs path, if not, continue with relative path
 	if absPath, err := filepath.Abs(a.output); err == nil {
 		a.output = absPath
@@ -519,11 +920,151 @@ func (a *analyzeCommand) Validate(ctx context.Context) error {
 	if absPath, err := filepath.Abs(a.input); err == nil {
 		a.input = absPath
 	}
+	if a.input != "" {
+		if err := a.resolveSourceLinks(); err != nil {
+			return err
+		}
+		if err := a.validateGitBlame(); err != nil {
+			return err
+		}
+	}
+	if err := a.validateSandbox(); err != nil {
+		return err
+	}
+	if err := a.validateSELinux(); err != nil {
+		return err
+	}
+	if err := a.validateProviderVolumes(); err != nil {
+		return err
+	}
+	if err := a.validateDepsTree(); err != nil {
+		return err
+	}
+	if err := a.validateProviders(a.provider); err != nil {
+		return err
+	}
+	// providers run on the host in containerless mode, only a snapshot
+	// isolates the input from their writes
+	if a.readOnlyInput && a.runLocal {
+		a.snapshot = true
+	}
+	if a.input != "" && a.changedSince != "" {
+		if a.isFileInput {
+			return fmt.Errorf("changed-since requires a source code input in a git repository")
+		}
+		// before the snapshot of the input, which is not a git repository
+		if a.changedFiles, err = changedFiles(a.input, a.changedSince); err != nil {
+			return err
+		}
+	}
+	if a.input != "" && a.snapshot {
+		if err := a.snapshotInput(); err != nil {
+			return err
+		}
+	}
+	if a.input != "" && !a.isFileInput {
+		ignore, err := loadKantraIgnore(a.input)
+		if err != nil {
+			return fmt.Errorf("%w failed to read %s", err, kantraIgnoreFile)
+		}
+		ignore = excludeDirs(ignore, a.excludeDirs)
+		ignore, err = a.applySymlinkPolicy(ignore)
+		if err != nil {
+			return err
+		}
+		a.inputIgnore = ignore
+		a.includedPaths, err = ignore.IncludedPaths(a.input)
+		if err != nil {
+			return fmt.Errorf("%w failed to apply %s", err, kantraIgnoreFile)
+		}
+		if a.includedPaths != nil {
+			if len(a.includedPaths) == 0 {
+				return fmt.Errorf("all files of the input are excluded from analysis")
+			}
+			// targets of followed symlinks are mounted into the input
+			a.includedPaths = append(a.includedPaths, a.symlinkMountPaths()...)
+			a.log.Info("excluding paths from analysis", "includedPaths", len(a.includedPaths))
+		}
+		gaps, err := findCoverageGaps(a.input, ignore)
+		if err != nil {
+			return fmt.Errorf("%w failed to check input for skipped files", err)
+		}
+		a.addSkipped(gaps...)
+		if err := a.applyFilePolicy(ignore); err != nil {
+			return err
+		}
+		if err := a.applyChangedSince(ignore); err != nil {
+			return err
+		}
+	}
 	if absPath, err := filepath.Abs(a.mavenSettingsFile); a.mavenSettingsFile != "" && err == nil {
 		a.mavenSettingsFile = absPath
 	}
+	if a.platformChecks {
+		if err := a.addPlatformRules(); err != nil {
+			return fmt.Errorf("%w failed to add platform rules", err)
+		}
+	}
+	if a.serverConfig {
+		if err := a.addServerConfigRules(); err != nil {
+			return fmt.Errorf("%w failed to add server config rules", err)
+		}
+	}
 	if !a.enableDefaultRulesets && len(a.rules) == 0 {
-		return fmt.Errorf("must specify rules if default rulesets are not enabled")
+		return i18n.Errorf("must specify rules if default rulesets are not enabled")
+	}
+	if err := a.checkStrictRules(); err != nil {
+		return err
+	}
+	if err := a.validateCustomVars(); err != nil {
+		return err
+	}
+	a.scoringModel, err = loadScoringModel(a.scoringModelFile)
+	if err != nil {
+		return fmt.Errorf("%w failed to load scoring model from %s", err, a.scoringModelFile)
+	}
+	if a.failOnLicense || len(a.licenseAllow) > 0 || len(a.licenseDeny) > 0 {
+		a.licenseReport = true
+	}
+	if err := a.validateQualityGate(); err != nil {
+		return err
+	}
+	if err := a.validateEngine(); err != nil {
+		return err
+	}
+	if err := a.validateStopAfter(); err != nil {
+		return err
+	}
+	if err := a.validateRecording(); err != nil {
+		return err
+	}
+	if err := a.validateSignOutput(); err != nil {
+		return err
+	}
+	if err := a.validateProviderPorts(); err != nil {
+		return err
+	}
+	if a.providerInitTimeout < 0 || a.providerHealthTimeout < 0 {
+		return fmt.Errorf("provider-init-timeout and provider-health-timeout must not be negative")
+	}
+	if a.failOnScore < 0 || a.failOnScore > 100 {
+		return fmt.Errorf("fail-on-score must be between 0 and 100")
+	}
+	a.compatibilityIssues = a.checkJavaCompatibility()
+	for _, issue := range a.compatibilityIssues {
+		a.log.Info("incompatible with target runtime", "issue", issue)
+	}
+	// the builtin provider can only analyze the descriptors of binaries
+	if a.isFileInput && (a.mode == string(provider.SourceOnlyAnalysisMode) || a.builtinOnly()) {
+		if err := a.extractDescriptors(); err != nil {
+			return err
+		}
+	}
+	if a.appMetadataFile != "" {
+		a.appMetadata, err = loadAppMetadata(a.appMetadataFile)
+		if err != nil {
+			return fmt.Errorf("%w failed to load app metadata from %s", err, a.appMetadataFile)
+		}
 	}
 	return nil
 }
@@ -536,18 +1077,27 @@ func (a *analyzeCommand) CheckOverwriteOutput() error {
 			return err
 		}
 	}
+	// before --overwrite removes the output dir of a running analysis
+	if err := a.checkOutputLock(); err != nil {
+		return err
+	}
+	// analyses with an output prefix share the output dir
+	if a.outputPrefix != "" {
+		return a.checkPrefixedOutputs()
+	}
 	if a.bulk {
+		// analyses in bulk move their log to the application dir, running
+		// ones hold the lock of the output dir
 		lockStat, _ := os.Stat(filepath.Join(a.output, "analysis.log"))
 		if lockStat != nil {
-			return fmt.Errorf("output dir %v already contains 'analysis.log', it was used for single application analysis or there is running --bulk analysis, try another output dir", a.output)
+			return fmt.Errorf("output dir %v already contains 'analysis.log', it was used for single application analysis, try another output dir", a.output)
 		}
-		sameInputStat, _ := os.Stat(fmt.Sprintf("%s.%s", filepath.Join(a.output, "output.yaml"), a.inputShortName()))
-		if sameInputStat != nil {
+		if a.analyzedInBulk() {
 			return fmt.Errorf("output dir %v already contains analysis report for provided input '%v', try another input or change output dir", a.output, a.inputShortName())
 		}
 	} else {
 		if !a.overwrite && stat != nil {
-			return fmt.Errorf("output dir %v already exists and --overwrite not set", a.output)
+			return i18n.Errorf("output dir %v already exists and --overwrite not set", a.output)
 		}
 	}
 	if a.overwrite && stat != nil {
@@ -601,11 +1151,12 @@ func (a *analyzeCommand) setProviders(languages []model.Language, foundProviders
 }
 
 func (a *analyzeCommand) setProviderInitInfo(foundProviders []string) error {
-	for _, prov := range foundProviders {
-		port, err := freeport.GetFreePort()
-		if err != nil {
-			return err
-		}
+	ports, err := a.allocateProviderPorts(len(foundProviders))
+	if err != nil {
+		return err
+	}
+	for i, prov := range foundProviders {
+		port := ports[i]
 		switch prov {
 		case javaProvider:
 			a.providersMap[javaProvider] = ProviderInit{
@@ -650,35 +1201,54 @@ func (a *analyzeCommand) validateProviders(providers []string) error {
 		nodeJSProvider,
 		dotnetProvider,
 		dotnetFrameworkProvider,
+		builtinProvider,
 	}
 	for _, prov := range providers {
 		//validate other providers
 		if !slices.Contains(validProvs, prov) {
-			return fmt.Errorf("provider %v not supported. Use --providerOverride or --provider option", prov)
+			return i18n.Errorf("provider %v not supported. Use --providerOverride or --provider option", prov)
 		}
 	}
+	if slices.Contains(providers, builtinProvider) && len(providers) > 1 {
+		return fmt.Errorf("provider builtin runs with all other providers, set it alone to run it only")
+	}
 	return nil
 }
 
-func (a *analyzeCommand) ListAllProviders() {
+// builtinOnly reports whether only the builtin provider runs, skipping the
+// language providers, e.g. for a fast scan of the config files of the input
+func (a *analyzeCommand) builtinOnly() bool {
+	return a.descriptorsOnly || slices.Equal(a.provider, []string{builtinProvider})
+}
+
+func (a *analyzeCommand) ListAllProviders(out io.Writer) error {
 	supportedProvsContainer := []string{
 		"java",
 		"python",
 		"go",
 		"dotnet",
 		"nodejs",
+		"builtin",
 	}
 	supportedProvsContainerless := []string{
 		"java",
+		"builtin",
+	}
+	if a.listFormat != "" && a.listFormat != listFormatText {
+		return writeListOutput(out, a.listFormat, struct {
+			Container     []string `json:"container" yaml:"container"`
+			Containerless []string `json:"containerless" yaml:"containerless"`
+		}{supportedProvsContainer, supportedProvsContainerless})
 	}
-	fmt.Println("container analysis supported providers:")
+	fmt.Fprintln(out, "container analysis supported providers:")
 	for _, prov := range supportedProvsContainer {
-		fmt.Fprintln(os.Stdout, prov)
+		fmt.Fprintln(out, prov)
 	}
-	fmt.Println("containerless analysis supported providers (default):")
+	fmt.Fprintln(out, "containerless analysis supported providers (default):")
 	for _, prov := range supportedProvsContainerless {
-		fmt.Fprintln(os.Stdout, prov)
+		fmt.Fprintln(out, prov)
 	}
+	return nil
 }
 
 func (a *analyzeCommand) ListLabels(ctx context.Context) error {
@@ -698,8 +1268,7 @@ func (a *analyzeCommand) fetchLabels(ctx context.Context, listSources, listTarge
 				a.log.Error(err, "failed to read rule labels")
 				return err
 			}
-			listOptionsFromLabels(sourceSlice, sourceLabel, out)
-			return nil
+			return listOptionsFromLabels(sourceSlice, sourceLabel, a.listFormat, out)
 		}
 		if listTargets {
 			targetsSlice, err := a.readRuleFilesForLabels(targetLabel)
@@ -707,8 +1276,7 @@ func (a *analyzeCommand) fetchLabels(ctx context.Context, listSources, listTarge
 				a.log.Error(err, "failed to read rule labels")
 				return err
 			}
-			listOptionsFromLabels(targetsSlice, targetLabel, out)
-			return nil
+			return listOptionsFromLabels(targetsSlice, targetLabel, a.listFormat, out)
 		}
 	} else {
 		volumes, err := a.getRulesVolumes()
@@ -716,15 +1284,29 @@ func (a *analyzeCommand) fetchLabels(ctx context.Context, listSources, listTarge
 			a.log.Error(err, "failed getting rules volumes")
 			return err
 		}
+		rulesetsVol, err := a.getDefaultRulesetsVolume()
+		if err != nil {
+			a.log.Error(err, "failed getting default rulesets volume")
+			return err
+		}
+		maps.Copy(volumes, rulesetsVol)
 		args := []string{"analyze", "--run-local=false"}
 		if listSources {
 			args = append(args, "--list-sources")
 		} else {
 			args = append(args, "--list-targets")
 		}
+		if a.listFormat != "" {
+			args = append(args, fmt.Sprintf("--format=%s", a.listFormat))
+		}
+		volumes, err = hostVolumes(volumes)
+		if err != nil {
+			return err
+		}
 		err = container.NewContainer().Run(
 			ctx,
 			container.WithImage(Settings.RunnerImage),
+			container.WithPlatform(a.imagePlatform(ctx, Settings.RunnerImage)),
 			container.WithLog(a.log.V(1)),
 			container.WithEnv(runMode, runModeContainer),
 			container.WithVolumes(volumes),
@@ -798,16 +1380,7 @@ func (a *analyzeCommand) getConfigVolumes() (map[string]string, error) {
 		}
 
 		// Set proxy to providers
-		if a.httpProxy != "" || a.httpsProxy != "" {
-			proxy := provider.Proxy{
-				HTTPProxy:  a.httpProxy,
-				HTTPSProxy: a.httpsProxy,
-				NoProxy:    a.noProxy,
-			}
-			for i := range provConfig {
-				provConfig[i].Proxy = &proxy
-			}
-		}
+		kantraprovider.SetProxy(provConfig, a.httpProxy, a.httpsProxy, a.noProxy)
 
 		for prov := range a.providersMap {
 			err = a.getProviderOptions(tempDir, provConfig, prov)
@@ -828,6 +1401,9 @@ func (a *analyzeCommand) getConfigVolumes() (map[string]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := checkDepFolderSettings(filepath.Join(tempDir, "settings.json"), settingsVols); err != nil {
+		return nil, err
+	}
 
 	// attempt to create a .m2 directory we can use to speed things a bit
 	// this will be shared between analyze and dep command containers
@@ -838,6 +1414,7 @@ func (a *analyzeCommand) getConfigVolumes() (map[string]string, error) {
 			a.log.V(1).Error(err, "failed to create m2 repo", "dir", m2Dir)
 		} else {
 			settingsVols[m2Dir] = M2Dir
+			a.m2Dir = m2Dir
 			a.log.V(1).Info("created directory for maven repo", "dir", m2Dir)
 			a.tempDirs = append(a.tempDirs, m2Dir)
 		}
@@ -887,6 +1464,9 @@ func (a *analyzeCommand) getRulesVolumes() (map[string]string, error) {
 				if path == r {
 					return nil
 				}
+				if d.Type()&fs.ModeSymlink != 0 {
+					return a.copyRulesSymlink(path, tempDir, r)
+				}
 				if d.IsDir() {
 					// This will create the new dir
 					a.handleDir(path, tempDir, r)
@@ -1021,7 +1601,11 @@ func (a *analyzeCommand) createTempRuleSet(path string, name string) error {
 }
 
 func (a *analyzeCommand) createContainerNetwork() (string, error) {
-	networkName := fmt.Sprintf("network-%v", container.RandomName())
+	if a.providerNetwork != "" {
+		a.log.V(1).Info("running providers in network", "network", a.providerNetwork)
+		return a.providerNetwork, nil
+	}
+	networkName := a.containerName("network")
 	args := []string{
 		"network",
 		"create",
@@ -1029,7 +1613,7 @@ func (a *analyzeCommand) createContainerNetwork() (string, error) {
 	}
 
 	cmd := exec.Command(Settings.ContainerBinary, args...)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = a.consoleWriter()
 	cmd.Stderr = os.Stderr
 	err := cmd.Run()
 	if err != nil {
@@ -1041,9 +1625,8 @@ func (a *analyzeCommand) createContainerNetwork() (string, error) {
 	return networkName, nil
 }
 
-// TODO: create for each source input once accepting multiple apps is completed
-func (a *analyzeCommand) createContainerVolume() (string, error) {
-	volName := fmt.Sprintf("volume-%v", container.RandomName())
+// inputMountDir returns the directory of the input mounted into containers
+func (a *analyzeCommand) inputMountDir() (string, error) {
 	input, err := filepath.Abs(a.input)
 	if err != nil {
 		return "", err
@@ -1051,19 +1634,53 @@ func (a *analyzeCommand) createContainerVolume() (string, error) {
 	if a.isFileInput {
 		input = filepath.Dir(input)
 	}
-	if runtime.GOOS == "windows" {
-		// TODO(djzager): Thank ChatGPT
-		// Extract the volume name (e.g., "C:")
-		// Remove the volume name from the path to get the remaining part
-		// Convert backslashes to forward slashes
-		// Remove the colon from the volume name and convert to lowercase
-		volumeName := filepath.VolumeName(input)
-		remainingPath := input[len(volumeName):]
-		remainingPath = filepath.ToSlash(remainingPath)
-		driveLetter := strings.ToLower(strings.TrimSuffix(volumeName, ":"))
+	// bind mounts need the real path of the input
+	return resolveSymlinkPath(input)
+}
 
-		// Construct the Linux-style path
-		input = fmt.Sprintf("/mnt/%s%s", driveLetter, remainingPath)
+// sourceMountPath returns the path the input is mounted at in containers,
+// the dir of a file input is mounted at a path named after the file
+func (a *analyzeCommand) sourceMountPath() string {
+	if a.isFileInput {
+		return path.Join(SourceMountPath, filepath.Base(a.input))
+	}
+	return SourceMountPath
+}
+
+// hostVolumes returns volumes with their host paths converted for bind
+// mounts, named volumes are kept as is
+func hostVolumes(volumes map[string]string) (map[string]string, error) {
+	mounts := map[string]string{}
+	for src, dst := range volumes {
+		if filepath.IsAbs(src) || strings.ContainsAny(src, `/\`) {
+			mount, err := hostpath.Mount(src)
+			if err != nil {
+				return nil, err
+			}
+			src = mount
+		}
+		mounts[src] = dst
+	}
+	return mounts, nil
+}
+
+// TODO: create for each source input once accepting multiple apps is completed
+func (a *analyzeCommand) createContainerVolume() (string, error) {
+	volName := a.containerName("volume")
+	if a.streamInput {
+		if err := a.createStreamedVolume(context.TODO(), volName); err != nil {
+			return "", err
+		}
+		return volName, nil
+	}
+	input, err := a.inputMountDir()
+	if err != nil {
+		return "", err
+	}
+	// podman machine mounts drives of windows hosts under /mnt
+	input, err = hostpath.MachinePath(input)
+	if err != nil {
+		return "", err
 	}
 
 	args := []string{
@@ -1078,7 +1695,7 @@ func (a *analyzeCommand) createContainerVolume() (string, error) {
 		volName,
 	}
 	cmd := exec.Command(Settings.ContainerBinary, args...)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = a.consoleWriter()
 	cmd.Stderr = os.Stderr
 	err = cmd.Run()
 	if err != nil {
@@ -1095,6 +1712,7 @@ func (a *analyzeCommand) retryProviderContainer(ctx context.Context, networkName
 		return fmt.Errorf("too many provider container retry attempts")
 	}
 	retry--
+	a.providerRestarts++
 
 	err := a.RunProviders(ctx, networkName, volName, retry)
 	if err != nil {
@@ -1104,9 +1722,10 @@ func (a *analyzeCommand) retryProviderContainer(ctx context.Context, networkName
 }
 
 func (a *analyzeCommand) RunProviders(ctx context.Context, networkName string, volName string, retry int) error {
+	sourceVolume, volumeOptions := a.sourceVolume(volName)
 	volumes := map[string]string{
 		// application source code
-		volName: SourceMountPath,
+		sourceVolume: a.sourceMountPath(),
 	}
 	if a.mavenSettingsFile != "" {
 		configVols, err := a.getConfigVolumes()
@@ -1120,6 +1739,14 @@ func (a *analyzeCommand) RunProviders(ctx context.Context, networkName string, v
 	if len(vols) != 0 {
 		maps.Copy(volumes, vols)
 	}
+	maps.Copy(volumes, a.symlinkVolumes)
+	authVolumes, authEnv := a.registryAuthVolumes()
+	if len(authVolumes) != 0 {
+		maps.Copy(volumes, authVolumes)
+		options := map[string]string{RegistryAuthMountPath: readOnlyMount}
+		maps.Copy(options, volumeOptions)
+		volumeOptions = options
+	}
 	firstProvRun := false
 	for prov, init := range a.providersMap {
 		// if retrying provider, skip providers already running
@@ -1127,31 +1754,48 @@ func (a *analyzeCommand) RunProviders(ctx context.Context, networkName string, v
 			continue
 		}
 		args := []string{fmt.Sprintf("--port=%v", init.port)}
+		provVolumes, provVolumeOptions := a.providerContainerVolumes(prov, volumes, volumeOptions)
+		provVolumes, err := hostVolumes(provVolumes)
+		if err != nil {
+			return err
+		}
 		// we have to start the fist provider separately to create the shared
 		// container network to then add other providers to the network
+		a.providerStatus.set(prov, providerInitializing, "")
 		if !firstProvRun {
 			a.log.Info("starting first provider", "provider", prov)
 			con := container.NewContainer()
 			err := con.Run(
 				ctx,
 				container.WithImage(init.image),
+				container.WithPlatform(a.imagePlatform(ctx, init.image)),
 				container.WithLog(a.log.V(1)),
-				container.WithVolumes(volumes),
+				container.WithVolumes(provVolumes),
+				container.WithVolumeOptions(provVolumeOptions),
+				container.WithEnvs(authEnv),
+				container.WithEnvs(a.javaProviderEnv(prov)),
 				container.WithContainerToolBin(Settings.ContainerBinary),
 				container.WithEntrypointArgs(args...),
 				container.WithDetachedMode(true),
 				container.WithCleanup(a.cleanup),
-				container.WithName(fmt.Sprintf("provider-%v", container.RandomName())),
+				container.WithStdout(a.consoleWriter()),
+				container.WithName(a.containerName("provider")),
 				container.WithNetwork(networkName),
+				container.WithSecurityOpts(a.providerSecurityOpts...),
+				container.WithSELinuxLabel(a.volumeLabel),
+				container.WithUserNS(a.containerUserNS),
 			)
 			if err != nil {
 				err := a.retryProviderContainer(ctx, networkName, volName, retry)
 				if err != nil {
+					a.providerStatus.set(prov, providerFailed, err.Error())
 					return err
 				}
 			}
 			a.providerContainerNames = append(a.providerContainerNames, con.Name)
 			init.isRunning = true
+			init.containerName = con.Name
+			a.providersMap[prov] = init
 		}
 		// start additional providers
 		if firstProvRun && len(a.providersMap) > 1 {
@@ -1160,23 +1804,34 @@ func (a *analyzeCommand) RunProviders(ctx context.Context, networkName string, v
 			err := con.Run(
 				ctx,
 				container.WithImage(init.image),
+				container.WithPlatform(a.imagePlatform(ctx, init.image)),
 				container.WithLog(a.log.V(1)),
-				container.WithVolumes(volumes),
+				container.WithVolumes(provVolumes),
+				container.WithVolumeOptions(provVolumeOptions),
+				container.WithEnvs(authEnv),
+				container.WithEnvs(a.javaProviderEnv(prov)),
 				container.WithContainerToolBin(Settings.ContainerBinary),
 				container.WithEntrypointArgs(args...),
 				container.WithDetachedMode(true),
 				container.WithCleanup(a.cleanup),
-				container.WithName(fmt.Sprintf("provider-%v", container.RandomName())),
+				container.WithStdout(a.consoleWriter()),
+				container.WithName(a.containerName("provider")),
 				container.WithNetwork(fmt.Sprintf("container:%v", a.providerContainerNames[0])),
+				container.WithSecurityOpts(a.providerSecurityOpts...),
+				container.WithSELinuxLabel(a.volumeLabel),
+				container.WithUserNS(a.containerUserNS),
 			)
 			if err != nil {
 				err := a.retryProviderContainer(ctx, networkName, volName, retry)
 				if err != nil {
+					a.providerStatus.set(prov, providerFailed, err.Error())
 					return err
 				}
 			}
 			a.providerContainerNames = append(a.providerContainerNames, con.Name)
 			init.isRunning = true
+			init.containerName = con.Name
+			a.providersMap[prov] = init
 		}
 		firstProvRun = true
 	}
@@ -1205,15 +1860,21 @@ func (a *analyzeCommand) RunAnalysisOverrideProviderSettings(ctx context.Context
 		fmt.Sprintf("--output-file=%s", AnalysisOutputMountPath),
 		fmt.Sprintf("--context-lines=%d", a.contextLines),
 	}
+	args = append(args, a.engineSettings.analyzerArgs()...)
 
 	if a.enableDefaultRulesets {
 		args = append(args,
 			fmt.Sprintf("--rules=%s/", RulesetPath))
+		rulesetsVol, err := a.getDefaultRulesetsVolume()
+		if err != nil {
+			return err
+		}
+		maps.Copy(volumes, rulesetsVol)
 	}
 
-	if a.incidentSelector != "" {
+	if incidentSelector := a.engineIncidentSelector(); incidentSelector != "" {
 		args = append(args,
-			fmt.Sprintf("--incident-selector=%s", a.incidentSelector))
+			fmt.Sprintf("--incident-selector=%s", incidentSelector))
 	}
 
 	if len(a.rules) > 0 {
@@ -1253,18 +1914,23 @@ func (a *analyzeCommand) RunAnalysisOverrideProviderSettings(ctx context.Context
 	a.log.Info("running source code analysis", "log", analysisLogFilePath,
 		"input", a.input, "output", a.output, "args", strings.Join(args, " "), "volumes", volumes)
 	a.log.Info("generating analysis log in file", "file", analysisLogFilePath)
+	volumes, err = hostVolumes(volumes)
+	if err != nil {
+		return err
+	}
 	// TODO (pgaikwad): run analysis & deps in parallel
 
 	c := container.NewContainer()
 	err = c.Run(
 		ctx,
 		container.WithImage(Settings.RunnerImage),
+		container.WithPlatform(a.imagePlatform(ctx, Settings.RunnerImage)),
 		container.WithLog(a.log.V(1)),
 		container.WithVolumes(volumes),
 		container.WithStdout(analysisLog),
 		container.WithStderr(analysisLog),
 		container.WithEntrypointArgs(args...),
-		container.WithName(fmt.Sprintf("analyzer-%v", container.RandomName())),
+		container.WithName(a.containerName("analyzer")),
 		container.WithEntrypointBin("/usr/local/bin/konveyor-analyzer"),
 		container.WithNetwork("host"),
 		container.WithContainerToolBin(Settings.ContainerBinary),
@@ -1278,13 +1944,130 @@ func (a *analyzeCommand) RunAnalysisOverrideProviderSettings(ctx context.Context
 		a.log.Error(err, "failed to get provider container logs")
 	}
 
+	return a.postProcessOutput()
+}
+
+// runContainerAnalysis runs the found providers and the analyzer in
+// containers sharing a volume of the input, the analyzer runs the builtin
+// provider only when no providers are needed
+func (a *analyzeCommand) runContainerAnalysis(ctx context.Context, xmlOutputDir string, foundProviders []string) error {
+	// share source app with provider and engine containers
+	containerVolName, err := a.createContainerVolume()
+	if err != nil {
+		a.log.Error(err, "failed to create container volume")
+		return err
+	}
+	if !a.needsBuiltin {
+		err = a.setProviderInitInfo(foundProviders)
+		if err != nil {
+			a.log.Error(err, "failed to set provider init info")
+			return err
+		}
+		containerNetworkName, err := a.createContainerNetwork()
+		if err != nil {
+			a.log.Error(err, "failed to create container network")
+			return err
+		}
+		// allow for 5 retries of running provider in the case of port in use
+		err = a.RunProviders(ctx, containerNetworkName, containerVolName, 5)
+		if err != nil {
+			a.log.Error(err, "failed to run provider")
+			return err
+		}
+		err = a.checkProviderContainers(ctx)
+		if err != nil {
+			a.log.Error(err, "provider health check failed")
+			return err
+		}
+	}
+	if err := a.writeRunInfo(a.providerPortMap()); err != nil {
+		a.log.Error(err, "failed to write run info")
+	}
+	err = a.RunAnalysis(ctx, xmlOutputDir, containerVolName)
+	if err != nil {
+		a.log.Error(err, "failed to run analysis")
+		return err
+	}
 	return nil
 }
 
+// writeAnalysisOutputs writes the outputs and reports of the analysis output
+// and checks the gates of the analysis, in container and containerless mode
+// with the static report generator of the mode
+func (a *analyzeCommand) writeAnalysisOutputs(ctx context.Context, generateStaticReport func(context.Context) error) error {
+	err := a.CreateJSONOutput()
+	if err != nil {
+		a.log.Error(err, "failed to create json output file")
+		return err
+	}
+	if err := a.writeExports(); err != nil {
+		a.log.Error(err, "failed to export analysis output")
+		return err
+	}
+
+	err = generateStaticReport(ctx)
+	if err != nil {
+		a.log.Error(err, "failed to generate static report")
+		return err
+	}
+
+	if err := a.annotateStaticReport(); err != nil {
+		a.log.Error(err, "failed to add app metadata to static report")
+	}
+	if err := a.writeSearchIndex(); err != nil {
+		a.log.Error(err, "failed to write static report search index")
+	}
+	if err := a.writeDepsTreeReport(); err != nil {
+		a.log.Error(err, "failed to write static report dependency trees")
+	}
+	if err := a.writeLicenseReport(); err != nil {
+		a.log.Error(err, "failed to write license report")
+		return err
+	}
+	if err := a.writeLicensesPage(); err != nil {
+		a.log.Error(err, "failed to write static report licenses")
+	}
+	if err := a.publishStaticReport(); err != nil {
+		a.log.Error(err, "failed to publish static report")
+		return err
+	}
+	if err := a.writeServerConfigReport(); err != nil {
+		a.log.Error(err, "failed to write server config report")
+		return err
+	}
+	if err := a.writeSkippedReport(); err != nil {
+		a.log.Error(err, "failed to write skipped files report")
+	}
+	if err := a.writeIncidentStoreReport(); err != nil {
+		a.log.Error(err, "failed to write incident store")
+		return err
+	}
+	summary, err := a.printSummary()
+	if err != nil {
+		a.log.Error(err, "failed to summarize analysis output")
+	}
+	if err := a.compressOutputs(); err != nil {
+		a.log.Error(err, "failed to compress analysis output")
+		return err
+	}
+	if err := a.signOutputs(); err != nil {
+		a.log.Error(err, "failed to sign analysis output")
+		return err
+	}
+	if err := a.checkLicenses(); err != nil {
+		return err
+	}
+	if err := a.checkScore(summary); err != nil {
+		return err
+	}
+	return a.checkQualityGate(summary)
+}
+
 func (a *analyzeCommand) RunAnalysis(ctx context.Context, xmlOutputDir string, volName string) error {
+	sourceVolume, volumeOptions := a.sourceVolume(volName)
 	volumes := map[string]string{
 		// application source code
-		volName: SourceMountPath,
+		sourceVolume: a.sourceMountPath(),
 		// output directory
 		a.output: OutputPath,
 	}
@@ -1305,6 +2088,7 @@ func (a *analyzeCommand) RunAnalysis(ctx context.Context, xmlOutputDir string, v
 		return err
 	}
 	maps.Copy(volumes, configVols)
+	maps.Copy(volumes, a.symlinkVolumes)
 
 	if len(a.rules) > 0 {
 		ruleVols, err := a.getRulesVolumes()
@@ -1319,13 +2103,19 @@ func (a *analyzeCommand) RunAnalysis(ctx context.Context, xmlOutputDir string, v
 		fmt.Sprintf("--output-file=%s", AnalysisOutputMountPath),
 		fmt.Sprintf("--context-lines=%d", a.contextLines),
 	}
+	args = append(args, a.engineSettings.analyzerArgs()...)
 	if a.enableDefaultRulesets {
 		args = append(args,
 			fmt.Sprintf("--rules=%s/", RulesetPath))
+		rulesetsVol, err := a.getDefaultRulesetsVolume()
+		if err != nil {
+			return err
+		}
+		maps.Copy(volumes, rulesetsVol)
 	}
-	if a.incidentSelector != "" {
+	if incidentSelector := a.engineIncidentSelector(); incidentSelector != "" {
 		args = append(args,
-			fmt.Sprintf("--incident-selector=%s", a.incidentSelector))
+			fmt.Sprintf("--incident-selector=%s", incidentSelector))
 	}
 	if len(a.rules) > 0 {
 		args = append(args,
@@ -1377,22 +2167,34 @@ func (a *analyzeCommand) RunAnalysis(ctx context.Context, xmlOutputDir string, v
 	} else {
 		networkName = "none"
 	}
+	volumes, err = hostVolumes(volumes)
+	if err != nil {
+		return err
+	}
+	a.providerStatus.setAll(providerAnalyzing)
+	stopProgress := a.startProgress(i18n.T("evaluating rules for violations"))
 	c := container.NewContainer()
 	// TODO (pgaikwad): run analysis & deps in parallel
 	err = c.Run(
 		ctx,
 		container.WithImage(Settings.RunnerImage),
+		container.WithPlatform(a.imagePlatform(ctx, Settings.RunnerImage)),
 		container.WithLog(a.log.V(1)),
 		container.WithVolumes(volumes),
+		container.WithVolumeOptions(volumeOptions),
 		container.WithStdout(analysisLog),
 		container.WithStderr(analysisLog),
-		container.WithName(fmt.Sprintf("analyzer-%v", container.RandomName())),
+		container.WithName(a.containerName("analyzer")),
 		container.WithEntrypointArgs(args...),
 		container.WithEntrypointBin("/usr/local/bin/konveyor-analyzer"),
 		container.WithNetwork(networkName),
+		container.WithSecurityOpts(a.providerSecurityOpts...),
+		container.WithSELinuxLabel(a.volumeLabel),
+		container.WithUserNS(a.containerUserNS),
 		container.WithContainerToolBin(Settings.ContainerBinary),
 		container.WithCleanup(a.cleanup),
 	)
+	stopProgress()
 	if err != nil {
 		return err
 	}
@@ -1401,7 +2203,7 @@ func (a *analyzeCommand) RunAnalysis(ctx context.Context, xmlOutputDir string, v
 		a.log.Error(err, "failed to get provider container logs")
 	}
 
-	return nil
+	return a.postProcessOutput()
 }
 
 func (a *analyzeCommand) CreateJSONOutput() error {
@@ -1412,7 +2214,7 @@ func (a *analyzeCommand) CreateJSONOutput() error {
 	outputPath := filepath.Join(a.output, "output.yaml")
 	depPath := filepath.Join(a.output, "dependencies.yaml")
 
-	data, err := os.ReadFile(outputPath)
+	data, err := readOutputFile(outputPath)
 	if err != nil {
 		return err
 	}
@@ -1423,7 +2225,11 @@ func (a *analyzeCommand) CreateJSONOutput() error {
 		return err
 	}
 
-	jsonData, err := json.MarshalIndent(ruleOutput, "", "	")
+	var jsonOutput interface{} = ruleOutput
+	if a.groupBy == groupByFile || a.groupBy == groupByPackage {
+		jsonOutput = groupIncidents(*ruleOutput, a.groupBy)
+	}
+	jsonData, err := json.MarshalIndent(jsonOutput, "", "	")
 	if err != nil {
 		a.log.V(1).Error(err, "failed to marshal output file to json")
 		return err
@@ -1435,12 +2241,12 @@ func (a *analyzeCommand) CreateJSONOutput() error {
 	}
 
 	// in case of no dep output
-	_, noDepFileErr := os.Stat(filepath.Join(a.output, "dependencies.yaml"))
+	_, noDepFileErr := os.Stat(outputFile(depPath))
 	if errors.Is(noDepFileErr, os.ErrNotExist) || a.mode == string(provider.SourceOnlyAnalysisMode) {
 		a.log.Info("skipping dependency output for json output")
 		return nil
 	}
-	depData, err := os.ReadFile(depPath)
+	depData, err := readOutputFile(depPath)
 	if err != nil {
 		return err
 	}
@@ -1479,7 +2285,7 @@ func (a *analyzeCommand) GenerateStaticReport(ctx context.Context) error {
 	}
 
 	volumes := map[string]string{
-		a.input:  SourceMountPath,
+		a.input:  a.sourceMountPath(),
 		a.output: OutputPath,
 	}
 
@@ -1492,33 +2298,40 @@ func (a *analyzeCommand) GenerateStaticReport(ctx context.Context) error {
 	outputDeps := []string{DepsOutputMountPath}
 
 	if a.bulk {
-		a.moveResults()
+		if err := a.moveResults(); err != nil {
+			return err
+		}
 		// Scan all available analysis output files to be reported
 		applicationNames = nil
 		outputAnalyses = nil
 		outputDeps = nil
-		outputFiles, err := filepath.Glob(filepath.Join(a.output, "output.yaml.*"))
-		// optional
-		depFiles, _ := filepath.Glob(filepath.Join(a.output, "dependencies.yaml.*"))
+		apps, err := listBulkApps(a.output)
 		if err != nil {
 			return err
 		}
-		for i := range outputFiles {
-			outputName := filepath.Base(outputFiles[i])
-			applicationName := strings.SplitN(outputName, "output.yaml.", 2)[1]
-			applicationNames = append(applicationNames, applicationName)
-			outputAnalyses = append(outputAnalyses, strings.ReplaceAll(outputFiles[i], a.output, OutputPath)) // re-map paths to container mounts
-			outputDeps = append(outputDeps, fmt.Sprintf("%s.%s", DepsOutputMountPath, applicationName))
-		}
-
-		if !errors.Is(noDepFileErr, os.ErrNotExist) {
-			for i := range depFiles {
-				_, depErr := os.Stat(depFiles[i])
-				if a.mode != string(provider.FullAnalysisMode) || depErr != nil {
-					// Remove not existing dependency files from statis report generator list
-					outputDeps[i] = ""
+		hasDeps := false
+		// the generator reads decompressed outputs only
+		defer os.RemoveAll(filepath.Join(a.output, reportInputsDir))
+		for _, app := range apps {
+			analysis, err := a.reportInput(app.analysis)
+			if err != nil {
+				return err
+			}
+			applicationNames = append(applicationNames, app.name)
+			outputAnalyses = append(outputAnalyses, a.outputMountPath(analysis))
+			deps := ""
+			// Remove not existing dependency files from static report generator list
+			if app.deps != "" && a.mode == string(provider.FullAnalysisMode) {
+				appDeps, err := a.reportInput(app.deps)
+				if err != nil {
+					return err
 				}
+				deps = a.outputMountPath(appDeps)
+				hasDeps = true
 			}
+			outputDeps = append(outputDeps, deps)
+		}
+		if hasDeps {
 			staticReportArgs = append(staticReportArgs,
 				fmt.Sprintf("--deps-output-list=%s", strings.Join(outputDeps, ",")))
 		}
@@ -1547,57 +2360,39 @@ func (a *analyzeCommand) GenerateStaticReport(ctx context.Context) error {
 	joinedArgs := strings.Join(args, " ")
 	staticReportCmd := []string{joinedArgs}
 
+	volumes, err := hostVolumes(volumes)
+	if err != nil {
+		return err
+	}
 	c := container.NewContainer()
 	a.log.Info("generating static report",
 		"output", a.output, "args", strings.Join(staticReportCmd, " "))
-	err := c.Run(
+	err = c.Run(
 		ctx,
 		container.WithImage(Settings.RunnerImage),
+		container.WithPlatform(a.imagePlatform(ctx, Settings.RunnerImage)),
 		container.WithLog(a.log.V(1)),
 		container.WithEntrypointBin("/bin/sh"),
 		container.WithContainerToolBin(Settings.ContainerBinary),
 		container.WithEntrypointArgs(staticReportCmd...),
 		container.WithVolumes(volumes),
+		container.WithStdout(a.consoleWriter()),
 		container.WithcFlag(true),
 		container.WithCleanup(a.cleanup),
 	)
 	if err != nil {
 		return err
 	}
-	uri := uri.File(filepath.Join(a.output, "static-report", "index.html"))
-	a.log.Info("Static report created. Access it at this URL:", "URL", string(uri))
-
 	return nil
 }
 
-func (a *analyzeCommand) moveResults() error {
-	outputPath := filepath.Join(a.output, "output.yaml")
-	analysisLogFilePath := filepath.Join(a.output, "analysis.log")
-	depsPath := filepath.Join(a.output, "dependencies.yaml")
-	err := copyFileContents(outputPath, fmt.Sprintf("%s.%s", outputPath, a.inputShortName()))
-	if err != nil {
-		return err
-	}
-	err = os.Remove(outputPath)
+// outputMountPath re-maps a path in the output dir to the container mount
+func (a *analyzeCommand) outputMountPath(file string) string {
+	rel, err := filepath.Rel(a.output, file)
 	if err != nil {
-		return err
-	}
-	err = copyFileContents(analysisLogFilePath, fmt.Sprintf("%s.%s", analysisLogFilePath, a.inputShortName()))
-	if err != nil {
-		return err
-	}
-	err = os.Remove(analysisLogFilePath)
-	if err != nil {
-		return err
+		return file
 	}
-	err = copyFileContents(depsPath, fmt.Sprintf("%s.%s", depsPath, a.inputShortName()))
-	if err == nil { // dependencies file presence is optional
-		err = os.Remove(depsPath)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return path.Join(OutputPath, filepath.ToSlash(rel))
 }
 
 func (a *analyzeCommand) inputShortName() string {
@@ -1614,6 +2409,12 @@ func (a *analyzeCommand) getLabelSelector() string {
 	}
 	// default labels are applied everytime either a source or target is specified
 	defaultLabels := []string{"discovery"}
+	if a.platformChecks {
+		defaultLabels = append(defaultLabels, platformLabel)
+	}
+	if a.serverConfig {
+		defaultLabels = append(defaultLabels, serverConfigLabel)
+	}
 	targets := []string{}
 	for _, target := range a.targets {
 		targets = append(targets,
@@ -1653,7 +2454,7 @@ func (a *analyzeCommand) getLabelSelector() string {
 }
 
 func isXMLFile(rule string) bool {
-	return path.Ext(rule) == ".xml"
+	return filepath.Ext(rule) == ".xml"
 }
 
 func loadEnvInsensitive(variableName string) string {
@@ -1742,9 +2543,14 @@ func (a *analyzeCommand) ConvertXML(ctx context.Context) (string, error) {
 	a.log.Info("running windup shim",
 		"output", a.output, "args", strings.Join(args, " "), "volumes", volumes)
 	a.log.Info("generating shim log in file", "file", shimLogPath)
+	volumes, err = hostVolumes(volumes)
+	if err != nil {
+		return "", err
+	}
 	err = container.NewContainer().Run(
 		ctx,
 		container.WithImage(Settings.RunnerImage),
+		container.WithPlatform(a.imagePlatform(ctx, Settings.RunnerImage)),
 		container.WithLog(a.log.V(1)),
 		container.WithStdout(shimLog),
 		container.WithStderr(shimLog),
@@ -1773,26 +2579,16 @@ func (a *analyzeCommand) writeProvConfig(tempDir string, config []provider.Confi
 			"failed to write provider config", "dir", tempDir, "file", "settings.json")
 		return err
 	}
-	return nil
+	return a.writeDebugSettings(config)
 }
 
 func (a *analyzeCommand) getProviderOptions(tempDir string, provConfig []provider.Config, prov string) error {
-	var confDir string
-	var set bool
-	ops := runtime.GOOS
-	if ops == "linux" {
-		confDir, set = os.LookupEnv("XDG_CONFIG_HOME")
-	}
-	if ops != "linux" || confDir == "" || !set {
-		// on Unix, including macOS, this returns the $HOME environment variable. On Windows, it returns %USERPROFILE%
-		var err error
-		confDir, err = os.UserHomeDir()
-		if err != nil {
-			return err
-		}
+	kantraDir, err := kantraHomeDir()
+	if err != nil {
+		return err
 	}
 	// get provider options from provider settings file
-	data, err := os.ReadFile(filepath.Join(confDir, ".kantra", fmt.Sprintf("%v.json", prov)))
+	data, err := os.ReadFile(filepath.Join(kantraDir, fmt.Sprintf("%v.json", prov)))
 	if err != nil {
 		return err
 	}
@@ -1954,7 +2750,7 @@ func (a *analyzeCommand) analyzeDotnetFramework(ctx context.Context) error {
 	}
 
 	// Create network
-	networkName := container.RandomName()
+	networkName := a.containerName("network")
 	cmd = exec.Command(Settings.ContainerBinary, []string{"network", "create", "-d", "nat", networkName}...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -1985,23 +2781,25 @@ func (a *analyzeCommand) analyzeDotnetFramework(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	port, err := freeport.GetFreePort()
+	ports, err := a.allocateProviderPorts(1)
 	if err != nil {
 		return err
 	}
+	port := ports[0]
 	a.log.V(1).Info("Starting dotnet-external-provider")
 	providerContainer := container.NewContainer()
 	err = providerContainer.Run(
 		ctx,
 		container.WithImage(Settings.DotnetProviderImage),
+		container.WithPlatform(a.imagePlatform(ctx, Settings.DotnetProviderImage)),
 		container.WithLog(a.log.V(1)),
 		container.WithVolumes(map[string]string{
-			input: "C:" + filepath.FromSlash(SourceMountPath),
+			input: "C:" + filepath.FromSlash(a.sourceMountPath()),
 		}),
 		container.WithContainerToolBin(Settings.ContainerBinary),
 		container.WithEntrypointArgs([]string{fmt.Sprintf("--port=%v", port)}...),
 		container.WithDetachedMode(true),
-		container.WithName(fmt.Sprintf("provider-%v", container.RandomName())),
+		container.WithName(a.containerName("provider")),
 		container.WithCleanup(a.cleanup),
 		container.WithNetwork(networkName),
 	)
@@ -2009,6 +2807,9 @@ func (a *analyzeCommand) analyzeDotnetFramework(ctx context.Context) error {
 		return err
 	}
 	a.providerContainerNames = append(a.providerContainerNames, providerContainer.Name)
+	if err := a.writeRunInfo(map[string]int{dotnetProvider: port}); err != nil {
+		a.log.Error(err, "failed to write run info")
+	}
 	a.log.V(1).Info("Provider started")
 	// end run provider
 
@@ -2032,7 +2833,7 @@ func (a *analyzeCommand) analyzeDotnetFramework(ctx context.Context) error {
 			Name: "builtin",
 			InitConfig: []provider.InitConfig{
 				{
-					Location:     "C:" + filepath.FromSlash(SourceMountPath),
+					Location:     "C:" + filepath.FromSlash(a.sourceMountPath()),
 					AnalysisMode: provider.AnalysisMode(a.mode),
 				},
 			},
@@ -2042,7 +2843,7 @@ func (a *analyzeCommand) analyzeDotnetFramework(ctx context.Context) error {
 			Address: fmt.Sprintf("%v:%v", providerContainer.Name, port),
 			InitConfig: []provider.InitConfig{
 				{
-					Location:     "C:" + filepath.FromSlash(SourceMountPath),
+					Location:     "C:" + filepath.FromSlash(a.sourceMountPath()),
 					AnalysisMode: provider.AnalysisMode(a.mode),
 					ProviderSpecificConfig: map[string]interface{}{
 						provider.LspServerPathConfigKey: "C:/Users/ContainerAdministrator/.dotnet/tools/csharp-ls.exe",
@@ -2066,7 +2867,7 @@ func (a *analyzeCommand) analyzeDotnetFramework(ctx context.Context) error {
 
 	volumes := map[string]string{
 		tempDir:  "C:" + filepath.FromSlash(ConfigMountPath),
-		input:    "C:" + filepath.FromSlash(SourceMountPath),
+		input:    "C:" + filepath.FromSlash(a.sourceMountPath()),
 		a.output: "C:" + filepath.FromSlash(OutputPath),
 	}
 
@@ -2075,6 +2876,7 @@ func (a *analyzeCommand) analyzeDotnetFramework(ctx context.Context) error {
 		fmt.Sprintf("--output-file=%s", "C:"+filepath.FromSlash(AnalysisOutputMountPath)),
 		fmt.Sprintf("--context-lines=%d", a.contextLines),
 	}
+	args = append(args, a.engineSettings.analyzerArgs()...)
 
 	if a.enableDefaultRulesets {
 		args = append(args, fmt.Sprintf("--rules=C:%s", filepath.FromSlash(RulesetPath)))
@@ -2123,9 +2925,10 @@ func (a *analyzeCommand) analyzeDotnetFramework(ctx context.Context) error {
 	err = c.Run(
 		ctx,
 		container.WithImage(Settings.RunnerImage),
+		container.WithPlatform(a.imagePlatform(ctx, Settings.RunnerImage)),
 		container.WithLog(a.log.V(1)),
 		container.WithVolumes(volumes),
-		container.WithName(fmt.Sprintf("analyzer-%v", container.RandomName())),
+		container.WithName(a.containerName("analyzer")),
 		container.WithStdout(analysisLog),
 		container.WithStderr(analysisLog),
 		container.WithEntrypointArgs(args...),
@@ -2143,21 +2946,25 @@ func (a *analyzeCommand) analyzeDotnetFramework(ctx context.Context) error {
 	}
 	// end run analysis
 
-	// Create json output
-	err = a.CreateJSONOutput()
-	if err != nil {
-		a.log.Error(err, "failed to create json output file")
+	if err := a.postProcessOutput(); err != nil {
 		return err
 	}
+	return a.writeAnalysisOutputs(ctx, func(ctx context.Context) error {
+		return a.generateDotnetFrameworkStaticReport(ctx, volumes)
+	})
+}
 
-	// Generate Static Report
+// generateDotnetFrameworkStaticReport generates the static report of a .NET
+// Framework analysis with the Windows runner image
+func (a *analyzeCommand) generateDotnetFrameworkStaticReport(ctx context.Context, volumes map[string]string) error {
 	if a.skipStaticReport {
 		return nil
 	}
 
-	err = container.NewContainer().Run(
+	err := container.NewContainer().Run(
 		ctx,
 		container.WithImage(Settings.RunnerImage),
+		container.WithPlatform(a.imagePlatform(ctx, Settings.RunnerImage)),
 		container.WithLog(a.log.V(1)),
 		container.WithContainerToolBin(Settings.ContainerBinary),
 		container.WithEntrypointBin("powershell"),
@@ -2180,6 +2987,7 @@ func (a *analyzeCommand) analyzeDotnetFramework(ctx context.Context) error {
 	err = container.NewContainer().Run(
 		ctx,
 		container.WithImage(Settings.RunnerImage),
+		container.WithPlatform(a.imagePlatform(ctx, Settings.RunnerImage)),
 		container.WithLog(a.log.V(1)),
 		container.WithContainerToolBin(Settings.ContainerBinary),
 		container.WithEntrypointBin(`C:\app\js-bundle-generator`),
@@ -2190,10 +2998,6 @@ func (a *analyzeCommand) analyzeDotnetFramework(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-
-	uri := uri.File(filepath.Join(a.output, "static-report", "index.html"))
-	a.log.Info("Static report created. Access it at this URL:", "URL", string(uri))
-
 	return nil
 }
 
@@ -1,8 +1,15 @@
 package cmd
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/go-logr/logr"
 )
 
 func Test_analyzeCommand_getLabelSelectorArgs(t *testing.T) {
@@ -81,3 +88,132 @@ func Test_analyzeCommand_getLabelSelectorArgs(t *testing.T) {
 		})
 	}
 }
+
+func Test_analyzeCommand_validateProviders(t *testing.T) {
+	tests := []struct {
+		providers   []string
+		wantErr     bool
+		builtinOnly bool
+	}{
+		{providers: []string{"java", "go"}},
+		{providers: []string{"builtin"}, builtinOnly: true},
+		{providers: []string{"java", "builtin"}, wantErr: true},
+		{providers: []string{"cobol"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		a := &analyzeCommand{provider: tt.providers}
+		if err := a.validateProviders(tt.providers); (err != nil) != tt.wantErr {
+			t.Errorf("validateProviders(%v) = %v, want error %v", tt.providers, err, tt.wantErr)
+		}
+		if got := a.builtinOnly(); got != tt.builtinOnly {
+			t.Errorf("builtinOnly() with providers %v = %v, want %v", tt.providers, got, tt.builtinOnly)
+		}
+	}
+}
+
+func Test_analyzeCommand_builtinOnlyContainerAnalysis(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake container tool is a shell script")
+	}
+	dir := t.TempDir()
+	// fake container tool writing the analyzer output and the static report
+	// to the dir mounted at the output path
+	tool := filepath.Join(dir, "podman")
+	writeTestFile(t, tool, `#!/bin/sh
+echo "$@" >> "$FAKE_LOG"
+[ "$1" = run ] || exit 0
+prev=""
+for arg in "$@"; do
+	if [ "$prev" = "-v" ]; then
+		case "$arg" in
+		*:/opt/output | *:/opt/output:*) out="${arg%%:/opt/output*}" ;;
+		esac
+	fi
+	prev="$arg"
+done
+case "$*" in
+*konveyor-analyzer*) cp "$FAKE_OUTPUT" "$out/output.yaml" ;;
+*js-bundle-generator*) mkdir -p "$out/static-report" && echo report > "$out/static-report/index.html" ;;
+esac
+`)
+	if err := os.Chmod(tool, 0755); err != nil {
+		t.Fatal(err)
+	}
+	toolLog := filepath.Join(dir, "podman.log")
+	analyzerOutput := filepath.Join(dir, "analyzer-output.yaml")
+	writeTestFile(t, analyzerOutput, `- name: custom
+  violations:
+    xml-rule:
+      description: xml rule
+      category: mandatory
+      effort: 1
+      incidents:
+      - uri: file:///opt/input/source/pom.xml
+        message: found
+`)
+	t.Setenv("FAKE_LOG", toolLog)
+	t.Setenv("FAKE_OUTPUT", analyzerOutput)
+	binary, platform, image := Settings.ContainerBinary, Settings.ImagePlatform, Settings.RunnerImage
+	defer func() {
+		Settings.ContainerBinary, Settings.ImagePlatform, Settings.RunnerImage = binary, platform, image
+	}()
+	Settings.ContainerBinary = tool
+	Settings.ImagePlatform = "linux/amd64"
+	Settings.RunnerImage = "quay.io/konveyor/kantra:latest"
+
+	input := filepath.Join(dir, "input")
+	writeTestFile(t, filepath.Join(input, "pom.xml"), "<project/>")
+	output := filepath.Join(dir, "output")
+	if err := os.Mkdir(output, 0755); err != nil {
+		t.Fatal(err)
+	}
+	a := &analyzeCommand{
+		log:          logr.Discard(),
+		input:        input,
+		output:       output,
+		provider:     []string{builtinProvider},
+		needsBuiltin: true,
+		jsonOutput:   true,
+		cleanup:      true,
+		reportName:   defaultReportName,
+	}
+	if err := a.runContainerAnalysis(context.Background(), "", nil); err != nil {
+		t.Fatalf("runContainerAnalysis() error = %v", err)
+	}
+	if err := a.writeAnalysisOutputs(context.Background(), a.GenerateStaticReport); err != nil {
+		t.Fatalf("writeAnalysisOutputs() error = %v", err)
+	}
+	if err := a.CleanAnalysisResources(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"output.json", filepath.Join("static-report", "index.html")} {
+		if _, err := os.Stat(filepath.Join(output, file)); err != nil {
+			t.Errorf("builtin only analysis did not write %s: %v", file, err)
+		}
+	}
+	calls := mustReadFile(t, toolLog)
+	if a.volumeName == "" || !strings.Contains(calls, "volume create") {
+		t.Errorf("builtin only analysis did not create the source volume:\n%s", calls)
+	}
+	if !strings.Contains(calls, a.volumeName+":"+SourceMountPath) {
+		t.Errorf("analyzer does not mount the source volume %s:\n%s", a.volumeName, calls)
+	}
+	if !strings.Contains(calls, "volume rm "+a.volumeName) {
+		t.Errorf("builtin only analysis did not remove the source volume:\n%s", calls)
+	}
+}
+
+func Test_hostVolumes(t *testing.T) {
+	dir := t.TempDir()
+	got, err := hostVolumes(map[string]string{
+		"volume-abc":                           SourceMountPath,
+		filepath.Join(dir, "out", "..", "out"): OutputPath,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"volume-abc": SourceMountPath, filepath.Join(dir, "out"): OutputPath}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("hostVolumes() = %v, want %v", got, want)
+	}
+}
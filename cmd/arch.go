@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// images set from the environment, with per architecture overrides named
+// <variable>_<ARCH>, e.g. JAVA_PROVIDER_IMG_ARM64
+var imageVariables = []string{"RUNNER_IMG", "JAVA_PROVIDER_IMG", "GENERIC_PROVIDER_IMG", "DOTNET_PROVIDER_IMG"}
+
+// platform of images without a variant for the host architecture
+const fallbackImagePlatform = "linux/amd64"
+
+// hostArch returns the architecture containers run natively on, podman
+// machines on mac and windows share the architecture of the host
+func hostArch() string {
+	return runtime.GOARCH
+}
+
+// archOverrideVariable returns the variable overriding an image on the
+// host architecture
+func archOverrideVariable(name string) string {
+	return fmt.Sprintf("%s_%s", name, strings.ToUpper(hostArch()))
+}
+
+// imageVariable returns the variable an image is set with
+func imageVariable(image string) string {
+	switch image {
+	case Settings.JavaProviderImage:
+		return "JAVA_PROVIDER_IMG"
+	case Settings.GenericProviderImage:
+		return "GENERIC_PROVIDER_IMG"
+	case Settings.DotnetProviderImage:
+		return "DOTNET_PROVIDER_IMG"
+	}
+	return "RUNNER_IMG"
+}
+
+func imageArch(ctx context.Context, image string) (string, error) {
+	cmd := exec.CommandContext(ctx, Settings.ContainerBinary,
+		"image", "inspect", "--format", "{{.Architecture}}", image)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// pullProgressWriter returns where the container tool prints the progress
+// of image pulls, stderr to keep the output of list options parsable
+func (a *analyzeCommand) pullProgressWriter() io.Writer {
+	if a.quiet || a.summaryOnly {
+		return io.Discard
+	}
+	return os.Stderr
+}
+
+// imagePlatform returns the platform to run an image with. Images are
+// pulled for the host architecture, when they have no such variant the
+// amd64 variant is used and runs emulated, which is slow.
+func (a *analyzeCommand) imagePlatform(ctx context.Context, image string) string {
+	if Settings.ImagePlatform != "" {
+		return Settings.ImagePlatform
+	}
+	if platform, ok := a.imagePlatforms[image]; ok {
+		return platform
+	}
+	if a.imagePlatforms == nil {
+		a.imagePlatforms = map[string]string{}
+	}
+	arch, err := imageArch(ctx, image)
+	if err != nil {
+		a.log.Info("pulling image", "image", image)
+		if err := pullImage(ctx, a.log, a.pullProgressWriter(), image, ""); err != nil {
+			a.log.V(1).Info("failed to pull image for host architecture", "image", image, "arch", hostArch(), "error", err)
+			if err := pullImage(ctx, a.log, a.pullProgressWriter(), image, fallbackImagePlatform); err != nil {
+				// running the container reports the error too
+				a.log.Info(fmt.Sprintf("warning: %s", err))
+				a.imagePlatforms[image] = ""
+				return ""
+			}
+		}
+		arch, _ = imageArch(ctx, image)
+	}
+	platform := ""
+	if arch != "" && arch != hostArch() {
+		platform = "linux/" + arch
+		a.log.Info(fmt.Sprintf("warning: image %s is built for %s and runs emulated on this %s host, which is slow. Set %s to an image built for %s or use containerless mode",
+			image, arch, hostArch(), archOverrideVariable(imageVariable(image)), hostArch()))
+	}
+	a.imagePlatforms[image] = platform
+	return platform
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_analyzeCommand_imagePlatform(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake container tool is a shell script")
+	}
+	// fake container tool reporting the architecture of images in $FAKE_ARCH
+	tool := filepath.Join(t.TempDir(), "podman")
+	writeTestFile(t, tool, "#!/bin/sh\necho \"$FAKE_ARCH\"\n")
+	if err := os.Chmod(tool, 0755); err != nil {
+		t.Fatal(err)
+	}
+	binary, platform := Settings.ContainerBinary, Settings.ImagePlatform
+	defer func() { Settings.ContainerBinary, Settings.ImagePlatform = binary, platform }()
+	Settings.ContainerBinary = tool
+	Settings.ImagePlatform = ""
+
+	emulated := "arm64"
+	if runtime.GOARCH == "arm64" {
+		emulated = "amd64"
+	}
+	tests := []struct {
+		arch string
+		want string
+	}{
+		{runtime.GOARCH, ""},
+		{emulated, "linux/" + emulated},
+	}
+	for _, tt := range tests {
+		t.Setenv("FAKE_ARCH", tt.arch)
+		a := &analyzeCommand{log: logr.Discard()}
+		if got := a.imagePlatform(context.Background(), "quay.io/konveyor/kantra:latest"); got != tt.want {
+			t.Errorf("imagePlatform() for %s image = %q, want %q", tt.arch, got, tt.want)
+		}
+	}
+
+	Settings.ImagePlatform = "linux/amd64"
+	a := &analyzeCommand{log: logr.Discard()}
+	if got := a.imagePlatform(context.Background(), "quay.io/konveyor/kantra:latest"); got != "linux/amd64" {
+		t.Errorf("imagePlatform() = %q, want KANTRA_IMAGE_PLATFORM", got)
+	}
+}
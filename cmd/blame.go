@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+const (
+	// incident variables set by --git-blame
+	authorVariable     = "kantra.io/author"
+	authorDateVariable = "kantra.io/authorDate"
+)
+
+// blameLine is the last change of a line
+type blameLine struct {
+	author string
+	date   string
+}
+
+// blameFile returns the last change of every line of a file, indexed by
+// line number starting at 1
+func blameFile(dir string, relPath string) (map[int]blameLine, error) {
+	cmd := exec.Command("git", "-C", dir, "blame", "--line-porcelain", "--", relPath)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	lines := map[int]blameLine{}
+	current := blameLine{}
+	lineNumber := 0
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			// content of the line ends its entry
+			lines[lineNumber] = current
+			current = blameLine{}
+		case strings.HasPrefix(line, "author "):
+			current.author = strings.TrimPrefix(line, "author ")
+		case strings.HasPrefix(line, "author-time "):
+			seconds, err := strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64)
+			if err == nil {
+				current.date = time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+			}
+		default:
+			// header of an entry: <sha> <original line> <final line> [<group size>]
+			fields := strings.Fields(line)
+			if len(fields) >= 3 && len(fields[0]) == 40 {
+				if n, err := strconv.Atoi(fields[2]); err == nil {
+					lineNumber = n
+				}
+			}
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// validateGitBlame checks that the input of --git-blame is in a git repository
+func (a *analyzeCommand) validateGitBlame() error {
+	if !a.gitBlame {
+		return nil
+	}
+	if a.isFileInput {
+		return fmt.Errorf("git-blame requires a source code input")
+	}
+	if _, err := gitOutput(a.input, "rev-parse", "--show-toplevel"); err != nil {
+		return fmt.Errorf("%w git-blame requires the input to be in a git repository", err)
+	}
+	if a.gitBlameBudget < 0 {
+		return fmt.Errorf("git-blame-budget must not be negative")
+	}
+	return nil
+}
+
+// addBlame annotates incidents in the input with the author and date of
+// the last change of their line. Files are blamed until the budget is
+// spent, incidents of the remaining files are not annotated.
+func (a *analyzeCommand) addBlame(rulesets []outputv1.RuleSet) {
+	if !a.gitBlame {
+		return
+	}
+	roots := a.inputRoots()
+	deadline := time.Now().Add(a.gitBlameBudget)
+	blamed := map[string]map[int]blameLine{}
+	exceeded := false
+	for i := range rulesets {
+		for ruleID, violation := range rulesets[i].Violations {
+			for j, incident := range violation.Incidents {
+				if incident.LineNumber == nil {
+					continue
+				}
+				rel := relativeURI(incident.URI, roots)
+				if rel == incident.URI || rel == "." {
+					continue
+				}
+				lines, ok := blamed[string(rel)]
+				if !ok {
+					if a.gitBlameBudget > 0 && time.Now().After(deadline) {
+						exceeded = true
+						continue
+					}
+					var err error
+					lines, err = blameFile(a.input, string(rel))
+					if err != nil {
+						// e.g. files not tracked by git
+						a.log.V(3).Info("failed to blame file", "file", rel, "error", err)
+					}
+					blamed[string(rel)] = lines
+				}
+				blame, ok := lines[*incident.LineNumber]
+				if !ok {
+					continue
+				}
+				variables := map[string]interface{}{}
+				for k, v := range incident.Variables {
+					variables[k] = v
+				}
+				variables[authorVariable] = blame.author
+				variables[authorDateVariable] = blame.date
+				violation.Incidents[j].Variables = variables
+			}
+			rulesets[i].Violations[ruleID] = violation
+		}
+	}
+	if exceeded {
+		a.log.Info("git blame budget exceeded, some incidents have no author", "budget", a.gitBlameBudget, "files", len(blamed))
+	}
+}
+
+// authorSummary is the number of incidents on lines last changed by an author
+type authorSummary struct {
+	Author    string `yaml:"author" json:"author"`
+	Incidents int    `yaml:"incidents" json:"incidents"`
+	// date of the most recent change of the author with an incident
+	LastChange string `yaml:"lastChange,omitempty" json:"lastChange,omitempty"`
+}
+
+// summarizeAuthors aggregates incidents annotated by --git-blame by author
+func summarizeAuthors(rulesets []outputv1.RuleSet) []authorSummary {
+	byAuthor := map[string]*authorSummary{}
+	for _, rs := range rulesets {
+		for _, violation := range rs.Violations {
+			for _, incident := range violation.Incidents {
+				author, ok := incident.Variables[authorVariable].(string)
+				if !ok {
+					continue
+				}
+				s, ok := byAuthor[author]
+				if !ok {
+					s = &authorSummary{Author: author}
+					byAuthor[author] = s
+				}
+				s.Incidents++
+				// RFC 3339 dates in UTC compare lexically
+				if date, ok := incident.Variables[authorDateVariable].(string); ok && date > s.LastChange {
+					s.LastChange = date
+				}
+			}
+		}
+	}
+	authors := []authorSummary{}
+	for _, s := range byAuthor {
+		authors = append(authors, *s)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Incidents != authors[j].Incidents {
+			return authors[i].Incidents > authors[j].Incidents
+		}
+		return authors[i].Author < authors[j].Author
+	})
+	return authors
+}
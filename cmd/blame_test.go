@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+)
+
+func Test_analyzeCommand_addBlame(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	input := t.TempDir()
+	if err := os.WriteFile(filepath.Join(input, "App.java"), []byte("class App {\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "App.java"},
+		{"-c", "user.name=Jane Doe", "-c", "user.email=jane@example.com", "commit", "-q", "-m", "init", "--date", "2024-01-02T03:04:05Z"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", input}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_COMMITTER_DATE=2024-01-02T03:04:05Z")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	line := func(n int) *int { return &n }
+	a := &analyzeCommand{log: logr.Discard(), input: input, gitBlame: true, gitBlameBudget: time.Minute}
+	if err := a.validateGitBlame(); err != nil {
+		t.Fatal(err)
+	}
+	rulesets := []outputv1.RuleSet{
+		{
+			Name: "test",
+			Violations: map[string]outputv1.Violation{
+				"rule-00010": {
+					Incidents: []outputv1.Incident{
+						{URI: uri.File(filepath.Join(input, "App.java")), LineNumber: line(2)},
+						{URI: uri.File(filepath.Join(input, "App.java"))},
+					},
+				},
+			},
+		},
+	}
+	a.addBlame(rulesets)
+	incidents := rulesets[0].Violations["rule-00010"].Incidents
+	if got := incidents[0].Variables[authorVariable]; got != "Jane Doe" {
+		t.Errorf("author of incident = %v, want Jane Doe", got)
+	}
+	if got := incidents[0].Variables[authorDateVariable]; got != "2024-01-02T03:04:05Z" {
+		t.Errorf("author date of incident = %v, want 2024-01-02T03:04:05Z", got)
+	}
+	if incidents[1].Variables != nil {
+		t.Errorf("incident without line must not be annotated")
+	}
+	authors := summarizeAuthors(rulesets)
+	if len(authors) != 1 || authors[0].Author != "Jane Doe" || authors[0].Incidents != 1 || authors[0].LastChange != "2024-01-02T03:04:05Z" {
+		t.Errorf("summarizeAuthors() = %+v", authors)
+	}
+}
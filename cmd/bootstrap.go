@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+)
+
+const releaseDownloadURL = "https://github.com/konveyor/kantra/releases"
+
+// containerlessRequisites are the files containerless analysis needs in
+// the kantra dir, relative to it
+var containerlessRequisites = []string{
+	RulesetsLocation,
+	JavaBundlesLocation,
+	JDTLSBinLocation,
+	"fernflower.jar",
+	"maven.default.index",
+	"static-report",
+}
+
+type bootstrapCommand struct {
+	log     logr.Logger
+	from    string
+	url     string
+	sha256  string
+	noCheck bool
+	force   bool
+}
+
+func NewBootstrapCommand(log logr.Logger) *cobra.Command {
+	bootstrapCmd := &bootstrapCommand{
+		log: log,
+	}
+	bootstrapCommand := &cobra.Command{
+		Use:   "bootstrap",
+		Short: "Install the requirements of containerless analysis",
+		Long: "Download the release archive of this kantra version for the current platform, " +
+			"verify its checksum and install jdtls, fernflower, the java bundle, the maven index, " +
+			"the static report and the default rulesets into the kantra dir",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := bootstrapCmd.Run()
+			if err != nil {
+				log.Error(err, "failed to install containerless requirements")
+				return err
+			}
+			return nil
+		},
+	}
+	bootstrapCommand.Flags().StringVar(&bootstrapCmd.from, "from", "", "install from a local release archive (.zip or .tar.gz) instead of downloading it")
+	bootstrapCommand.Flags().StringVar(&bootstrapCmd.url, "url", "", "URL of the release archive, defaults to the GitHub release of this version")
+	bootstrapCommand.Flags().StringVar(&bootstrapCmd.sha256, "sha256", "", "expected SHA-256 checksum of the archive, defaults to the .sha256 file published next to it")
+	bootstrapCommand.Flags().BoolVar(&bootstrapCmd.noCheck, "skip-checksum", false, "do not verify the checksum of the archive")
+	bootstrapCommand.Flags().BoolVar(&bootstrapCmd.force, "force", false, "replace requirements that are already installed")
+	return bootstrapCommand
+}
+
+// releaseArchiveURL returns the URL of the release archive of this version
+func releaseArchiveURL() string {
+	name := fmt.Sprintf("kantra.%s.%s.zip", runtime.GOOS, runtime.GOARCH)
+	if Version == "latest" {
+		return fmt.Sprintf("%s/latest/download/%s", releaseDownloadURL, name)
+	}
+	return fmt.Sprintf("%s/download/%s/%s", releaseDownloadURL, Version, name)
+}
+
+func (b *bootstrapCommand) Run() error {
+	kantraDir, err := kantraHomeDir()
+	if err != nil {
+		return err
+	}
+	if !b.force && missingRequisites(kantraDir) == nil {
+		fmt.Printf("containerless requirements are already installed in %s, use --force to replace them\n", kantraDir)
+		return nil
+	}
+	if err := os.MkdirAll(kantraDir, 0755); err != nil {
+		return err
+	}
+	tempDir, err := os.MkdirTemp(kantraDir, ".bootstrap-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	archive := b.from
+	if archive == "" {
+		url := b.url
+		if url == "" {
+			url = releaseArchiveURL()
+		}
+		archive = filepath.Join(tempDir, filepath.Base(url))
+		b.log.Info("downloading containerless requirements", "url", url)
+		if err := downloadFile(b.log, url, archive); err != nil {
+			return err
+		}
+		if !b.noCheck && b.sha256 == "" {
+			b.sha256, err = downloadChecksum(b.log, url+".sha256")
+			if err != nil {
+				return fmt.Errorf("%w failed to get checksum of %s, pass --sha256 or --skip-checksum", err, url)
+			}
+		}
+	} else if !b.noCheck && b.sha256 == "" {
+		// offline installs verify against a checksum file next to the archive
+		content, err := os.ReadFile(b.from + ".sha256")
+		if err != nil {
+			return fmt.Errorf("%w failed to read checksum of %s, pass --sha256 or --skip-checksum", err, b.from)
+		}
+		b.sha256 = parseChecksum(string(content))
+	}
+	if !b.noCheck {
+		if err := verifyChecksum(archive, b.sha256); err != nil {
+			return err
+		}
+	}
+
+	extractDir := filepath.Join(tempDir, "extract")
+	if err := extractArchive(archive, extractDir); err != nil {
+		return err
+	}
+	root, err := requisitesRoot(extractDir)
+	if err != nil {
+		return err
+	}
+	for _, name := range []string{RulesetsLocation, "jdtls", "fernflower.jar", "maven.default.index", "static-report"} {
+		dest := filepath.Join(kantraDir, name)
+		if err := os.RemoveAll(dest); err != nil {
+			return err
+		}
+		if err := os.Rename(filepath.Join(root, name), dest); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("containerless requirements installed in %s\n", kantraDir)
+	return nil
+}
+
+// missingRequisites returns the requirements missing in dir
+func missingRequisites(dir string) []string {
+	var missing []string
+	for _, req := range containerlessRequisites {
+		if _, err := os.Stat(filepath.Join(dir, req)); err != nil {
+			missing = append(missing, req)
+		}
+	}
+	return missing
+}
+
+// requisitesRoot returns the dir of an extracted archive holding the
+// requirements, archives may wrap them in a single top level dir
+func requisitesRoot(dir string) (string, error) {
+	if missingRequisites(dir) == nil {
+		return dir, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		nested := filepath.Join(dir, entries[0].Name())
+		if missingRequisites(nested) == nil {
+			return nested, nil
+		}
+	}
+	return "", fmt.Errorf("archive is missing containerless requirements %s", strings.Join(missingRequisites(dir), ", "))
+}
+
+func downloadChecksum(log logr.Logger, url string) (string, error) {
+	file, err := os.CreateTemp("", "kantra-*.sha256")
+	if err != nil {
+		return "", err
+	}
+	file.Close()
+	defer os.Remove(file.Name())
+	if err := downloadFile(log, url, file.Name()); err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	return parseChecksum(string(content)), nil
+}
+
+// parseChecksum accepts a bare checksum or the output of sha256sum
+func parseChecksum(content string) string {
+	fields := strings.Fields(content)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(fields[0])
+}
+
+func verifyChecksum(path string, want string) error {
+	if want == "" {
+		return errors.New("checksum is empty")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	digest := sha256.New()
+	if _, err := io.Copy(digest, file); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(digest.Sum(nil)); got != strings.ToLower(want) {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", filepath.Base(path), got, want)
+	}
+	return nil
+}
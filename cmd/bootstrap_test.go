@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func writeRequisitesZip(t *testing.T, path string) string {
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	w := zip.NewWriter(file)
+	// release zips wrap the requirements in a kantra.<os>.<arch> dir
+	for _, name := range []string{"darwin-kantra", RulesetsLocation + "/ruleset.yaml", JavaBundlesLocation, JDTLSBinLocation,
+		"fernflower.jar", "maven.default.index", "static-report/index.html"} {
+		f, err := w.Create(filepath.ToSlash(filepath.Join("kantra.darwin.amd64", name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func Test_bootstrapCommand_Run(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("HOME", home)
+	archive := filepath.Join(t.TempDir(), "kantra.darwin.amd64.zip")
+	checksum := writeRequisitesZip(t, archive)
+
+	b := &bootstrapCommand{log: logr.Discard(), from: archive, sha256: strings.Repeat("0", 64)}
+	if err := b.Run(); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Run() with wrong checksum error = %v, want checksum mismatch", err)
+	}
+
+	if err := os.WriteFile(archive+".sha256", []byte(checksum+"  kantra.darwin.amd64.zip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	b = &bootstrapCommand{log: logr.Discard(), from: archive}
+	if err := b.Run(); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	kantraDir, err := kantraHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing := missingRequisites(kantraDir); missing != nil {
+		t.Errorf("requirements %v are missing after bootstrap", missing)
+	}
+	if _, err := os.Stat(filepath.Join(kantraDir, "darwin-kantra")); err == nil {
+		t.Errorf("kantra binary must not be installed into the kantra dir")
+	}
+}
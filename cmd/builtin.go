@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"github.com/konveyor/analyzer-lsp/provider"
+
+	kantraprovider "github.com/konveyor-ecosystem/kantra/pkg/provider"
 )
 
 type BuiltinProvider struct {
@@ -9,14 +11,11 @@ type BuiltinProvider struct {
 }
 
 func (p *BuiltinProvider) GetConfigVolume(a *analyzeCommand, tmpDir string) (provider.Config, error) {
-	p.config = provider.Config{
-		Name: "builtin",
-		InitConfig: []provider.InitConfig{
-			{
-				Location:     SourceMountPath,
-				AnalysisMode: provider.AnalysisMode(a.mode),
-			},
-		},
-	}
+	p.config = kantraprovider.BuiltinConfig(kantraprovider.Options{
+		Mode:          kantraprovider.ContainerMode,
+		Location:      a.sourceMountPath(),
+		AnalysisMode:  provider.AnalysisMode(a.mode),
+		IncludedPaths: a.builtinIncludedPaths(),
+	})
 	return p.config, nil
 }
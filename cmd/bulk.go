@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+// dir of the per application results of bulk analysis in the output dir
+const bulkAppsDir = "apps"
+
+// results moved to the application dir after each bulk analysis
+var bulkResultFiles = []string{"output.yaml", "output.json", "dependencies.yaml", "dependencies.json", depsTreeFile, junitOutputFile, "analysis.log"}
+
+// file in the output dir with the status of each application analyzed in bulk
+const bulkStatusFile = "bulk-status.yaml"
+
+// status of an application analyzed in bulk
+const (
+	bulkSucceeded = "succeeded"
+	bulkFailed    = "failed"
+)
+
+// bulkAppStatus is the outcome of the bulk analysis of an application
+type bulkAppStatus struct {
+	App       string    `yaml:"app"`
+	Status    string    `yaml:"status"`
+	Error     string    `yaml:"error,omitempty"`
+	Started   time.Time `yaml:"started"`
+	Duration  string    `yaml:"duration"`
+	Rules     int       `yaml:"rules"`
+	Incidents int       `yaml:"incidents"`
+	Output    string    `yaml:"output,omitempty"`
+	Log       string    `yaml:"log,omitempty"`
+}
+
+// exit code of a bulk sequence in which some applications failed
+const bulkFailedExitCode = 3
+
+// flags of the bulk sequence, not passed to the analysis of each input
+var bulkSequenceFlags = []string{"bulk-input", "input", "bulk", "bulk-sequence"}
+
+// bulkApp is the results of an application analyzed in bulk
+type bulkApp struct {
+	name     string
+	analysis string
+	// empty when the application has no dependency output
+	deps string
+}
+
+func bulkAppDir(output, name string) string {
+	return filepath.Join(output, bulkAppsDir, name)
+}
+
+// listBulkApps returns the applications analyzed in bulk into the output
+// dir, sorted by name. Results of older kantra versions, in files suffixed
+// with the application name, are still read.
+func listBulkApps(output string) ([]bulkApp, error) {
+	apps := map[string]bulkApp{}
+	appDirs, err := filepath.Glob(filepath.Join(output, bulkAppsDir, "*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range appDirs {
+		// outputs may be compressed
+		analysis := existingFile(outputFile(filepath.Join(dir, "output.yaml")))
+		if analysis == "" {
+			continue
+		}
+		apps[filepath.Base(dir)] = bulkApp{
+			name:     filepath.Base(dir),
+			analysis: analysis,
+			deps:     existingFile(outputFile(filepath.Join(dir, "dependencies.yaml"))),
+		}
+	}
+	legacyFiles, err := filepath.Glob(filepath.Join(output, "output.yaml.*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, outputFile := range legacyFiles {
+		name := strings.TrimPrefix(filepath.Base(outputFile), "output.yaml.")
+		if _, ok := apps[name]; ok {
+			continue
+		}
+		apps[name] = bulkApp{
+			name:     name,
+			analysis: outputFile,
+			deps:     existingFile(filepath.Join(output, "dependencies.yaml."+name)),
+		}
+	}
+	list := []bulkApp{}
+	for _, app := range apps {
+		list = append(list, app)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].name < list[j].name })
+	return list, nil
+}
+
+func existingFile(path string) string {
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// bulkResultPath returns the path of a result file of the input in bulk
+// analysis, once moved to the application dir
+func (a *analyzeCommand) bulkResultPath(file string) string {
+	return filepath.Join(bulkAppDir(a.output, a.inputShortName()), file)
+}
+
+// analyzedInBulk reports whether the output dir has results of the input
+func (a *analyzeCommand) analyzedInBulk() bool {
+	if _, err := os.Stat(outputFile(a.bulkResultPath("output.yaml"))); err == nil {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(a.output, "output.yaml."+a.inputShortName()))
+	return err == nil
+}
+
+// moveResults moves the results of the input to its application dir, so
+// that the next bulk analysis into the output dir does not overwrite them
+func (a *analyzeCommand) moveResults() error {
+	appDir := bulkAppDir(a.output, a.inputShortName())
+	if err := os.MkdirAll(appDir, 0755); err != nil {
+		return err
+	}
+	for _, file := range bulkResultFiles {
+		err := os.Rename(filepath.Join(a.output, file), filepath.Join(appDir, file))
+		// only the analysis output is always present
+		if errors.Is(err, os.ErrNotExist) && file != "output.yaml" {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readBulkStatus(path string) ([]bulkAppStatus, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []bulkAppStatus{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	statuses := []bulkAppStatus{}
+	if err := yaml.Unmarshal(data, &statuses); err != nil {
+		return nil, fmt.Errorf("%w failed to parse %s", err, path)
+	}
+	return statuses, nil
+}
+
+// recordBulkStatus adds the outcome of the analysis of the input to the
+// bulk status of the output dir, replacing the one of a previous run, and
+// returns the status of all applications
+func (a *analyzeCommand) recordBulkStatus(started time.Time, runErr error) ([]bulkAppStatus, error) {
+	status := bulkAppStatus{
+		App:      a.inputShortName(),
+		Status:   bulkSucceeded,
+		Started:  started.UTC().Truncate(time.Second),
+		Duration: time.Since(started).Round(time.Second).String(),
+	}
+	if runErr != nil {
+		status.Status, status.Error = bulkFailed, runErr.Error()
+		// results of a failed analysis must neither block the next ones nor
+		// be moved to the dir of the next application
+		if err := os.MkdirAll(bulkAppDir(a.output, status.App), 0755); err != nil {
+			return nil, err
+		}
+		for _, file := range bulkResultFiles {
+			err := os.Rename(filepath.Join(a.output, file), a.bulkResultPath(file))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return nil, err
+			}
+		}
+	}
+	status.Output = existingFile(outputFile(a.bulkResultPath("output.yaml")))
+	status.Log = existingFile(a.bulkResultPath("analysis.log"))
+	if data, err := os.ReadFile(a.bulkResultPath("summary.json")); err == nil {
+		summary := analysisSummary{}
+		if err := json.Unmarshal(data, &summary); err == nil {
+			status.Rules, status.Incidents = summary.Rules, summary.Incidents
+		}
+	}
+
+	path := filepath.Join(a.output, bulkStatusFile)
+	statuses, err := readBulkStatus(path)
+	if err != nil {
+		return nil, err
+	}
+	replaced := false
+	for i := range statuses {
+		if statuses[i].App == status.App {
+			statuses[i], replaced = status, true
+		}
+	}
+	if !replaced {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].App < statuses[j].App })
+	data, err := yaml.Marshal(statuses)
+	if err != nil {
+		return nil, err
+	}
+	return statuses, os.WriteFile(path, data, 0644)
+}
+
+func writeBulkStatus(out io.Writer, statuses []bulkAppStatus) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "APP\tSTATUS\tDURATION\tRULES\tINCIDENTS\tLOG")
+	failed := 0
+	for _, s := range statuses {
+		log := s.Log
+		if log == "" {
+			log = "-"
+		}
+		if s.Status == bulkFailed {
+			failed++
+			fmt.Fprintf(w, "%s\t%s\t%s\t-\t-\t%s\n", s.App, s.Status, s.Duration, log)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\n", s.App, s.Status, s.Duration, s.Rules, s.Incidents, log)
+	}
+	fmt.Fprintf(w, "%d applications, %d failed\n", len(statuses), failed)
+	return w.Flush()
+}
+
+// validateBulkInputs checks the flags of the bulk sequence, the analysis of
+// each input validates the other flags
+func (a *analyzeCommand) validateBulkInputs() error {
+	if a.input != "" {
+		return fmt.Errorf("must not specify both input and bulk-input")
+	}
+	if a.allProfiles || len(a.profileNames) > 0 {
+		return fmt.Errorf("must not specify both profiles and bulk-input")
+	}
+	names := map[string]string{}
+	for _, input := range a.bulkInputs {
+		if _, err := os.Stat(input); err != nil {
+			return fmt.Errorf("%w failed to stat input path %s", err, input)
+		}
+		name := filepath.Base(input)
+		if other, ok := names[name]; ok {
+			return fmt.Errorf("inputs %s and %s have the same application name %s", other, input, name)
+		}
+		names[name] = input
+	}
+	a.bulk = true
+	if err := a.checkOutputLock(); err != nil {
+		return err
+	}
+	return os.MkdirAll(a.output, os.ModePerm)
+}
+
+// RunBulk analyzes the bulk inputs into the output dir one after the other,
+// continuing when the analysis of an input fails, e.g. when its provider
+// crashes, so that the combined report holds all the other applications
+func (a *analyzeCommand) RunBulk(ctx context.Context, flags *pflag.FlagSet) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	failed := []string{}
+	for _, input := range a.bulkInputs {
+		started := time.Now()
+		args := append([]string{"analyze", "--bulk", "--bulk-sequence", "--input", input}, flagArgs(flags, bulkSequenceFlags)...)
+		a.log.Info("analyzing input in bulk", "input", input, "output", a.output)
+		cmd := exec.CommandContext(ctx, executable, args...)
+		cmd.Stdout = a.consoleWriter()
+		cmd.Stderr = os.Stderr
+		runErr := cmd.Run()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if runErr == nil {
+			continue
+		}
+		a.log.Error(runErr, "failed to analyze input, continuing with the next inputs", "input", input)
+		failed = append(failed, filepath.Base(input))
+		// the analysis did not record its failure when it exited early
+		app := *a
+		app.input = input
+		if _, err := app.recordFailedBulkStatus(started, runErr); err != nil {
+			return err
+		}
+	}
+	statuses, err := readBulkStatus(filepath.Join(a.output, bulkStatusFile))
+	if err != nil {
+		return err
+	}
+	if !a.quiet {
+		if err := writeBulkStatus(os.Stdout, statuses); err != nil {
+			return err
+		}
+	}
+	if len(failed) > 0 {
+		return &exitError{
+			code: bulkFailedExitCode,
+			err:  fmt.Errorf("analysis failed for applications %s, see %s", strings.Join(failed, ", "), bulkStatusFile),
+		}
+	}
+	return nil
+}
+
+// recordFailedBulkStatus records the failure of the input unless its
+// analysis recorded it
+func (a *analyzeCommand) recordFailedBulkStatus(started time.Time, runErr error) ([]bulkAppStatus, error) {
+	statuses, err := readBulkStatus(filepath.Join(a.output, bulkStatusFile))
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range statuses {
+		if s.App == a.inputShortName() && s.Status == bulkFailed && !s.Started.Before(started.UTC().Truncate(time.Second)) {
+			return statuses, nil
+		}
+	}
+	return a.recordBulkStatus(started, runErr)
+}
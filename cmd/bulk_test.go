@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_analyzeCommand_moveResults(t *testing.T) {
+	output := t.TempDir()
+	writeTestFile(t, filepath.Join(output, "output.yaml"), "[]\n")
+	writeTestFile(t, filepath.Join(output, "analysis.log"), "")
+	a := &analyzeCommand{input: "/src/coolstore", output: output, bulk: true}
+	if a.analyzedInBulk() {
+		t.Fatal("analyzedInBulk() = true before the results are moved")
+	}
+	if err := a.moveResults(); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"output.yaml", "analysis.log"} {
+		if _, err := os.Stat(filepath.Join(output, "apps", "coolstore", file)); err != nil {
+			t.Errorf("moveResults() did not move %s: %v", file, err)
+		}
+		if _, err := os.Stat(filepath.Join(output, file)); err == nil {
+			t.Errorf("moveResults() left %s in the output dir", file)
+		}
+	}
+	if !a.analyzedInBulk() {
+		t.Error("analyzedInBulk() = false after the results are moved")
+	}
+}
+
+func Test_listBulkApps(t *testing.T) {
+	output := t.TempDir()
+	writeTestFile(t, filepath.Join(output, "apps", "b", "output.yaml"), "[]\n")
+	writeTestFile(t, filepath.Join(output, "apps", "b", "dependencies.yaml"), "[]\n")
+	writeTestFile(t, filepath.Join(output, "apps", "c", "output.yaml"), "[]\n")
+	// results of older versions
+	writeTestFile(t, filepath.Join(output, "output.yaml.a"), "[]\n")
+	writeTestFile(t, filepath.Join(output, "dependencies.yaml.a"), "[]\n")
+	writeTestFile(t, filepath.Join(output, "output.yaml.c"), "[]\n")
+
+	apps, err := listBulkApps(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []bulkApp{
+		{name: "a", analysis: filepath.Join(output, "output.yaml.a"), deps: filepath.Join(output, "dependencies.yaml.a")},
+		{name: "b", analysis: filepath.Join(output, "apps", "b", "output.yaml"), deps: filepath.Join(output, "apps", "b", "dependencies.yaml")},
+		{name: "c", analysis: filepath.Join(output, "apps", "c", "output.yaml")},
+	}
+	if !reflect.DeepEqual(apps, want) {
+		t.Errorf("listBulkApps() = %v, want %v", apps, want)
+	}
+}
+
+func Test_analyzeCommand_recordBulkStatus(t *testing.T) {
+	output := t.TempDir()
+	writeTestFile(t, filepath.Join(output, "apps", "a", "output.yaml"), "[]\n")
+	writeTestFile(t, filepath.Join(output, "apps", "a", "summary.json"), `{"rules":2,"incidents":5}`)
+	// failed analysis of b leaves its log in the output dir
+	writeTestFile(t, filepath.Join(output, "analysis.log"), "")
+
+	started := time.Now()
+	a := &analyzeCommand{input: "/src/b", output: output, quiet: true}
+	if _, err := a.recordBulkStatus(started, errors.New("provider failed")); err != nil {
+		t.Fatal(err)
+	}
+	a.input = "/src/a"
+	if _, err := a.recordBulkStatus(started, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := readBulkStatus(filepath.Join(output, bulkStatusFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("recordBulkStatus() statuses = %v, want 2", statuses)
+	}
+	if s := statuses[0]; s.App != "a" || s.Status != bulkSucceeded || s.Rules != 2 || s.Incidents != 5 {
+		t.Errorf("recordBulkStatus() status of a = %v", s)
+	}
+	wantLog := filepath.Join(output, "apps", "b", "analysis.log")
+	if s := statuses[1]; s.App != "b" || s.Status != bulkFailed || s.Error != "provider failed" || s.Log != wantLog {
+		t.Errorf("recordBulkStatus() status of b = %v, want failed with log %s", s, wantLog)
+	}
+	if _, err := os.Stat(filepath.Join(output, "analysis.log")); err == nil {
+		t.Error("recordBulkStatus() left the log of the failed analysis in the output dir")
+	}
+
+	out := &bytes.Buffer{}
+	if err := writeBulkStatus(out, statuses); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "2 applications, 1 failed") {
+		t.Errorf("writeBulkStatus() = %s", out)
+	}
+}
+
+func Test_analyzeCommand_validateBulkInputs(t *testing.T) {
+	dir := t.TempDir()
+	for _, app := range []string{"a/coolstore", "b/coolstore", "petclinic"} {
+		if err := os.MkdirAll(filepath.Join(dir, app), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a := &analyzeCommand{output: filepath.Join(dir, "out"),
+		bulkInputs: []string{filepath.Join(dir, "a/coolstore"), filepath.Join(dir, "petclinic")}}
+	if err := a.validateBulkInputs(); err != nil {
+		t.Fatal(err)
+	}
+	if !a.bulk {
+		t.Error("validateBulkInputs() did not set bulk")
+	}
+	a.bulkInputs = append(a.bulkInputs, filepath.Join(dir, "b/coolstore"))
+	if err := a.validateBulkInputs(); err == nil {
+		t.Error("validateBulkInputs() accepted inputs with the same application name")
+	}
+}
+
+func Test_analyzeCommand_recordFailedBulkStatus(t *testing.T) {
+	output := t.TempDir()
+	a := &analyzeCommand{input: "/src/a", output: output}
+	started := time.Now()
+	// the analysis recorded its failure
+	if _, err := a.recordBulkStatus(started, errors.New("provider failed")); err != nil {
+		t.Fatal(err)
+	}
+	statuses, err := a.recordFailedBulkStatus(started, errors.New("exit status 1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 1 || statuses[0].Error != "provider failed" {
+		t.Errorf("recordFailedBulkStatus() = %v, want the recorded failure", statuses)
+	}
+	// the analysis exited before recording it
+	a.input = "/src/b"
+	statuses, err = a.recordFailedBulkStatus(started, errors.New("exit status 1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 2 || statuses[1].App != "b" || statuses[1].Status != bulkFailed {
+		t.Errorf("recordFailedBulkStatus() = %v, want the failure of b", statuses)
+	}
+}
@@ -0,0 +1,458 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// file in a cache entry holding the checksum of its content
+	cacheChecksumFile = ".kantra-checksum"
+	// file in a cache entry identifying the last process using it, entries
+	// used by running analyses are not pruned
+	cacheUseFile = ".kantra-in-use"
+	// cache entries not used for this long are removed by cache prune
+	defaultCacheMaxAge = 30 * 24 * time.Hour
+)
+
+// kantraHomeDir returns $XDG_CONFIG_HOME/.kantra on linux, $HOME/.kantra otherwise
+func kantraHomeDir() (string, error) {
+	var dir string
+	set := false
+	if runtime.GOOS == "linux" {
+		dir, set = os.LookupEnv("XDG_CONFIG_HOME")
+	}
+	if runtime.GOOS != "linux" || dir == "" || !set {
+		// on Unix, including macOS, this returns the $HOME environment variable. On Windows, it returns %USERPROFILE%
+		var err error
+		dir, err = os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, ".kantra"), nil
+}
+
+func rulesetsCacheDir() (string, error) {
+	dir, err := kantraHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache", "rulesets"), nil
+}
+
+// checksumDir hashes the paths and content of all files in dir
+func checksumDir(dir string) (string, error) {
+	digest := sha256.New()
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || (filepath.Dir(p) == dir && (d.Name() == cacheChecksumFile || d.Name() == cacheUseFile)) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(digest, "%s\x00", filepath.ToSlash(rel))
+		file, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(digest, file)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+// verifyCacheEntry returns true when the content of a cache entry matches
+// the checksum recorded when it was created
+func verifyCacheEntry(dir string) bool {
+	want, err := os.ReadFile(filepath.Join(dir, cacheChecksumFile))
+	if err != nil {
+		return false
+	}
+	got, err := checksumDir(dir)
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(want)) == got
+}
+
+// markCacheEntryUsed marks a cache entry as recently used, and used by this
+// process until it exits
+func markCacheEntryUsed(dir string) {
+	now := time.Now()
+	host, _ := os.Hostname()
+	if data, err := json.Marshal(outputLock{PID: os.Getpid(), Host: host, Started: now}); err == nil {
+		os.WriteFile(filepath.Join(dir, cacheUseFile), data, 0644)
+	}
+	os.Chtimes(dir, now, now)
+}
+
+// cacheEntryInUse returns true when another running process of this host
+// uses the cache entry
+func cacheEntryInUse(dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, cacheUseFile))
+	if err != nil {
+		return false
+	}
+	use := outputLock{}
+	if err := json.Unmarshal(data, &use); err != nil {
+		return false
+	}
+	host, _ := os.Hostname()
+	return use.Host == host && use.PID != os.Getpid() && processRunning(use.PID)
+}
+
+// cachedRulesetsBundle returns the dir of a downloaded rulesets bundle,
+// shared across runs. Entries are keyed by kantra version and URL and
+// downloaded again when their content does not match the checksum.
+func cachedRulesetsBundle(log logr.Logger, url string) (string, error) {
+	cacheDir, err := rulesetsCacheDir()
+	if err != nil {
+		return "", err
+	}
+	key := sha256.Sum256([]byte(url))
+	dir := filepath.Join(cacheDir, fmt.Sprintf("%s-%s", Version, hex.EncodeToString(key[:])[:16]))
+	if verifyCacheEntry(dir) {
+		log.V(1).Info("using cached rulesets", "url", url, "path", dir)
+		markCacheEntryUsed(dir)
+		return dir, nil
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", err
+	}
+	// extract next to the entry and move it in place once complete
+	tempDir, err := os.MkdirTemp(cacheDir, ".download-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tempDir)
+	log.Info("downloading default rulesets", "url", url)
+	err = downloadRulesetsBundle(log, url, tempDir)
+	if err != nil {
+		return "", err
+	}
+	checksum, err := checksumDir(tempDir)
+	if err != nil {
+		return "", err
+	}
+	err = os.WriteFile(filepath.Join(tempDir, cacheChecksumFile), []byte(checksum), 0644)
+	if err != nil {
+		return "", err
+	}
+	err = os.Rename(tempDir, dir)
+	if err != nil {
+		return "", err
+	}
+	markCacheEntryUsed(dir)
+	return dir, nil
+}
+
+func workspacesCacheDir() (string, error) {
+	dir, err := kantraHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "cache", "workspaces"), nil
+}
+
+// cachedJdtlsWorkspace returns the jdtls workspace of input, reused across
+// containerless runs. Entries are keyed by kantra version and input path,
+// as the index of the workspace is only valid for the jdtls it was built with.
+func cachedJdtlsWorkspace(input string) (string, error) {
+	cacheDir, err := workspacesCacheDir()
+	if err != nil {
+		return "", err
+	}
+	key := sha256.Sum256([]byte(input))
+	dir := filepath.Join(cacheDir, fmt.Sprintf("%s-%s", Version, hex.EncodeToString(key[:])[:16]))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	markCacheEntryUsed(dir)
+	return dir, nil
+}
+
+// pruneCache removes cache entries not used since maxAge, all when maxAge is 0
+func pruneCache(log logr.Logger, cacheDir string, maxAge time.Duration) (int, error) {
+	return pruneEntries(log, cacheDir, maxAge, verifyCacheEntry)
+}
+
+// pruneWorkspaces removes jdtls workspaces not used since maxAge, all when
+// maxAge is 0
+func pruneWorkspaces(log logr.Logger, cacheDir string, maxAge time.Duration) (int, error) {
+	return pruneEntries(log, cacheDir, maxAge, func(string) bool { return true })
+}
+
+// pruneEntries removes the entries of cacheDir not used since maxAge or
+// failing verify, entries used by running analyses are kept
+func pruneEntries(log logr.Logger, cacheDir string, maxAge time.Duration, verify func(string) bool) (int, error) {
+	entries, err := os.ReadDir(cacheDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			return removed, err
+		}
+		if maxAge > 0 && time.Since(info.ModTime()) < maxAge && verify(filepath.Join(cacheDir, entry.Name())) {
+			continue
+		}
+		if cacheEntryInUse(filepath.Join(cacheDir, entry.Name())) {
+			log.V(1).Info("keeping cache entry used by a running analysis", "path", filepath.Join(cacheDir, entry.Name()))
+			continue
+		}
+		log.V(1).Info("removing cache entry", "path", filepath.Join(cacheDir, entry.Name()))
+		if err := os.RemoveAll(filepath.Join(cacheDir, entry.Name())); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// cacheEntry is an entry of a cache of the kantra dir
+type cacheEntry struct {
+	path     string
+	size     int64
+	lastUsed time.Time
+	inUse    bool
+}
+
+// dirSize returns the size of the files of dir
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// listCacheEntries returns the entries of cacheDir with their size
+func listCacheEntries(cacheDir string) ([]cacheEntry, error) {
+	dirEntries, err := os.ReadDir(cacheDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries := []cacheEntry{}
+	for _, d := range dirEntries {
+		info, err := d.Info()
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(cacheDir, d.Name())
+		size, err := dirSize(path)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, cacheEntry{path: path, size: size, lastUsed: info.ModTime(), inUse: cacheEntryInUse(path)})
+	}
+	return entries, nil
+}
+
+// evictCacheEntries removes the least recently used entries until the
+// entries fit in maxSize, entries used by running analyses are kept. It
+// returns the number of entries removed and their size.
+func evictCacheEntries(log logr.Logger, entries []cacheEntry, maxSize int64) (int, int64, error) {
+	var size int64
+	for _, entry := range entries {
+		size += entry.size
+	}
+	sorted := slices.Clone(entries)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].lastUsed.Before(sorted[j].lastUsed) })
+	removed := 0
+	var freed int64
+	for _, entry := range sorted {
+		if size-freed <= maxSize {
+			break
+		}
+		if entry.inUse {
+			continue
+		}
+		log.V(1).Info("removing least recently used cache entry", "path", entry.path, "size", entry.size)
+		if err := os.RemoveAll(entry.path); err != nil {
+			return removed, freed, err
+		}
+		removed++
+		freed += entry.size
+	}
+	return removed, freed, nil
+}
+
+// kantraCaches are the caches of the kantra dir
+var kantraCaches = []struct {
+	name  string
+	dir   func() (string, error)
+	prune func(logr.Logger, string, time.Duration) (int, error)
+}{
+	{name: "rulesets", dir: rulesetsCacheDir, prune: pruneCache},
+	{name: "workspaces", dir: workspacesCacheDir, prune: pruneWorkspaces},
+}
+
+func NewCacheCommand(log logr.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage cached rulesets and jdtls workspaces",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+	cmd.AddCommand(newCacheStatusCommand(log))
+	cmd.AddCommand(newCachePruneCommand(log))
+	return cmd
+}
+
+func newCacheStatusCommand(log logr.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the size of the cached rulesets and jdtls workspaces",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := writeCacheStatus(os.Stdout); err != nil {
+				log.Error(err, "failed to get cache status")
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+// writeCacheStatus writes the entries, size and last use of each cache
+func writeCacheStatus(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CACHE\tENTRIES\tIN USE\tSIZE\tLAST USED\tDIR")
+	var total int64
+	for _, c := range kantraCaches {
+		cacheDir, err := c.dir()
+		if err != nil {
+			return err
+		}
+		entries, err := listCacheEntries(cacheDir)
+		if err != nil {
+			return err
+		}
+		var size int64
+		inUse := 0
+		lastUsed := "-"
+		var last time.Time
+		for _, entry := range entries {
+			size += entry.size
+			if entry.inUse {
+				inUse++
+			}
+			if entry.lastUsed.After(last) {
+				last = entry.lastUsed
+				lastUsed = last.Format(time.DateTime)
+			}
+		}
+		total += size
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\n", c.name, len(entries), inUse, formatByteSize(size), lastUsed, cacheDir)
+	}
+	fmt.Fprintf(w, "total\t\t\t%s\t\t\n", formatByteSize(total))
+	return w.Flush()
+}
+
+func newCachePruneCommand(log logr.Logger) *cobra.Command {
+	var all bool
+	var olderThan time.Duration
+	var maxSize string
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove cached rulesets that are unused or corrupted and unused jdtls workspaces",
+		Long: "Remove cached rulesets that are unused or corrupted and unused jdtls workspaces. With --max-size the\n" +
+			"least recently used entries are removed until the caches fit in the size, e.g. --max-size 10GB.\n" +
+			"Entries used by running analyses are kept.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			maxAge := olderThan
+			if all {
+				maxAge = 0
+			}
+			var budget int64 = -1
+			if maxSize != "" {
+				var err error
+				if budget, err = parseByteSize(maxSize); err != nil {
+					return fmt.Errorf("%w max-size must be a size like 500MB or 10GB", err)
+				}
+			}
+			entries := []cacheEntry{}
+			for _, c := range kantraCaches {
+				cacheDir, err := c.dir()
+				if err != nil {
+					return err
+				}
+				removed, err := c.prune(log, cacheDir, maxAge)
+				if err != nil {
+					log.Error(err, "failed to prune cache")
+					return err
+				}
+				fmt.Printf("removed %d cache entries from %s\n", removed, cacheDir)
+				if budget < 0 {
+					continue
+				}
+				cacheEntries, err := listCacheEntries(cacheDir)
+				if err != nil {
+					return err
+				}
+				entries = append(entries, cacheEntries...)
+			}
+			if budget < 0 {
+				return nil
+			}
+			removed, freed, err := evictCacheEntries(log, entries, budget)
+			if err != nil {
+				log.Error(err, "failed to prune cache")
+				return err
+			}
+			fmt.Printf("removed %d least recently used cache entries, freed %s\n", removed, formatByteSize(freed))
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "remove all cached rulesets and jdtls workspaces")
+	cmd.Flags().DurationVar(&olderThan, "older-than", defaultCacheMaxAge, "remove cache entries not used for this long")
+	cmd.Flags().StringVar(&maxSize, "max-size", "", "remove the least recently used cache entries until the caches fit in this size, e.g. 10GB")
+	return cmd
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_pruneCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	newEntry := func(name string, age time.Duration) string {
+		dir := filepath.Join(cacheDir, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "ruleset.yaml"), []byte("name: test\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		checksum, err := checksumDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, cacheChecksumFile), []byte(checksum), 0644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := time.Now().Add(-age)
+		if err := os.Chtimes(dir, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	recent := newEntry("recent", time.Hour)
+	newEntry("unused", 60*24*time.Hour)
+	corrupted := newEntry("corrupted", time.Hour)
+	if err := os.WriteFile(filepath.Join(corrupted, "ruleset.yaml"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := pruneCache(logr.Discard(), cacheDir, defaultCacheMaxAge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 {
+		t.Errorf("pruneCache() removed %d entries, want 2", removed)
+	}
+	if !verifyCacheEntry(recent) {
+		t.Errorf("recently used entry was removed or corrupted")
+	}
+
+	removed, err = pruneCache(logr.Discard(), cacheDir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 {
+		t.Errorf("pruneCache() with no max age removed %d entries, want 1", removed)
+	}
+}
+
+func Test_cachedJdtlsWorkspace(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("HOME", home)
+	first, err := cachedJdtlsWorkspace("/apps/first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := cachedJdtlsWorkspace("/apps/first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := cachedJdtlsWorkspace("/apps/second")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != again || first == second {
+		t.Errorf("cachedJdtlsWorkspace() = %s, %s, %s, want one workspace per input", first, again, second)
+	}
+	modTime := time.Now().Add(-60 * 24 * time.Hour)
+	if err := os.Chtimes(second, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	removed, err := pruneWorkspaces(logr.Discard(), filepath.Dir(first), defaultCacheMaxAge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(first); removed != 1 || err != nil {
+		t.Errorf("pruneWorkspaces() removed %d entries, want only the unused workspace", removed)
+	}
+}
+
+func Test_evictCacheEntries(t *testing.T) {
+	cacheDir := t.TempDir()
+	newEntry := func(name string, size int, age time.Duration) string {
+		dir := filepath.Join(cacheDir, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "data"), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+		modTime := time.Now().Add(-age)
+		if err := os.Chtimes(dir, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	oldest := newEntry("oldest", 400, 72*time.Hour)
+	inUse := newEntry("in-use", 300, 48*time.Hour)
+	older := newEntry("older", 200, 24*time.Hour)
+	recent := newEntry("recent", 100, time.Hour)
+	// the parent of the test is a running process of this host
+	host, _ := os.Hostname()
+	use := fmt.Sprintf(`{"pid": %d, "host": %q}`, os.Getppid(), host)
+	if err := os.WriteFile(filepath.Join(inUse, cacheUseFile), []byte(use), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := listCacheEntries(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("listCacheEntries() = %d entries, want 4", len(entries))
+	}
+	removed, freed, err := evictCacheEntries(logr.Discard(), entries, 450)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 || freed != 600 {
+		t.Errorf("evictCacheEntries() removed %d entries of %d bytes, want 2 of 600", removed, freed)
+	}
+	for path, want := range map[string]bool{oldest: false, inUse: true, older: false, recent: true} {
+		if _, err := os.Stat(path); (err == nil) != want {
+			t.Errorf("entry %s kept = %v, want %v", filepath.Base(path), err == nil, want)
+		}
+	}
+}
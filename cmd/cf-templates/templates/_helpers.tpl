@@ -0,0 +1,8 @@
+{{- define "app.name" -}}
+{{ kubeName .Values.name }}
+{{- end -}}
+
+{{- define "app.labels" -}}
+app.kubernetes.io/name: {{ include "app.name" . }}
+app.kubernetes.io/managed-by: kantra
+{{- end -}}
@@ -0,0 +1,419 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// cfTemplates is the template pack used without --templates
+//
+//go:embed all:cf-templates
+var cfTemplates embed.FS
+
+// template packs in images are read from this dir of the image
+const templatePackImagePath = "/pack"
+
+// prefix of the template packs of --templates pulled from a registry
+const ociPrefix = "oci://"
+
+type cfToK8sCommand struct {
+	input     string
+	templates string
+	output    string
+	log       logr.Logger
+}
+
+func NewCFToK8sCommand(log logr.Logger) *cobra.Command {
+	cfToK8sCmd := &cfToK8sCommand{log: log}
+	cmd := &cobra.Command{
+		Use:   "cf-to-k8s",
+		Short: "Convert Cloud Foundry manifests to Kubernetes manifests with a template pack",
+		Long: "Render the templates of a template pack for each application of Cloud Foundry manifests. " +
+			"A template pack is a dir with Go templates in templates/, helpers in templates/_*.tpl as in Helm charts, " +
+			"and an optional schema.yaml the attributes of each application are validated against. " +
+			"Without --templates a pack with a deployment, service and ingress of each application is used.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := cfToK8sCmd.Run(cmd.Context(), os.Stdout); err != nil {
+				log.Error(err, "failed to convert cloud foundry manifests")
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&cfToK8sCmd.input, "input", "i", "", "path to a Cloud Foundry manifest or a directory of manifests")
+	cmd.Flags().StringVar(&cfToK8sCmd.templates, "templates", "", "template pack, a dir or an image of a registry as oci://<image> with the pack in "+templatePackImagePath)
+	cmd.Flags().StringVarP(&cfToK8sCmd.output, "output", "o", "", "dir to write the Kubernetes manifests to, one dir per application")
+	cmd.MarkFlagRequired("input")
+	cmd.MarkFlagRequired("output")
+	return cmd
+}
+
+// templatePack renders the kubernetes manifests of an application
+type templatePack struct {
+	schema    *openapi3.Schema
+	templates *template.Template
+	// templates rendered for each application, helpers are not
+	names []string
+}
+
+// templateData is the data of the templates of a pack, the attributes of
+// the application are its values as in Helm charts
+type templateData struct {
+	Values   map[string]interface{}
+	Manifest string
+}
+
+// kubeName returns a name valid for kubernetes resources, e.g. of an
+// application named My_App
+func kubeName(name string) string {
+	name = strings.Trim(kubeNameInvalid.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(name) > 63 {
+		name = strings.TrimRight(name[:63], "-")
+	}
+	return name
+}
+
+// cfSize converts a size of a manifest, e.g. 512M, to a kubernetes
+// quantity, e.g. 512Mi
+func cfSize(size interface{}) string {
+	s := strings.ToUpper(strings.ReplaceAll(fmt.Sprint(size), " ", ""))
+	s = strings.TrimSuffix(s, "B")
+	for _, unit := range []string{"K", "M", "G", "T"} {
+		if strings.HasSuffix(s, unit) {
+			return s + "i"
+		}
+	}
+	return s
+}
+
+// routeHost returns the host of a route, without its port and path
+func routeHost(route string) string {
+	host, _, _ := strings.Cut(route, "/")
+	host, _, _ = strings.Cut(host, ":")
+	return host
+}
+
+// routePath returns the path of a route, / for routes without one
+func routePath(route string) string {
+	_, p, _ := strings.Cut(route, "/")
+	return "/" + p
+}
+
+// tcpRoute reports whether a route of the manifest is a tcp route, which
+// has no ingress
+func tcpRoute(route map[string]interface{}) bool {
+	host, _ := route["route"].(string)
+	protocol, _ := route["protocol"].(string)
+	hostname, _, _ := strings.Cut(host, "/")
+	return protocol == "tcp" || strings.Contains(hostname, ":")
+}
+
+// empty reports whether a value is the zero value of its type, as Helm
+// does for default
+func empty(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// templateFuncs are the functions of the templates of packs, a subset of
+// those of Helm charts
+func templateFuncs(t *template.Template) template.FuncMap {
+	return template.FuncMap{
+		"include": func(name string, data interface{}) (string, error) {
+			buf := &bytes.Buffer{}
+			err := t.ExecuteTemplate(buf, name, data)
+			return buf.String(), err
+		},
+		"required": func(message string, v interface{}) (interface{}, error) {
+			if empty(v) {
+				return nil, errors.New(message)
+			}
+			return v, nil
+		},
+		"default": func(def interface{}, v ...interface{}) interface{} {
+			if len(v) == 0 || empty(v[0]) {
+				return def
+			}
+			return v[0]
+		},
+		"toYaml": func(v interface{}) (string, error) {
+			data, err := yaml.Marshal(v)
+			return strings.TrimSuffix(string(data), "\n"), err
+		},
+		"indent": func(n int, s string) string {
+			pad := strings.Repeat(" ", n)
+			return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+		},
+		"nindent": func(n int, s string) string {
+			pad := strings.Repeat(" ", n)
+			return "\n" + pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+		},
+		"quote": func(v interface{}) string {
+			return strconv.Quote(fmt.Sprint(v))
+		},
+		"toString": func(v interface{}) string {
+			return fmt.Sprint(v)
+		},
+		"lower":      strings.ToLower,
+		"upper":      strings.ToUpper,
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"list":       func(v ...interface{}) []interface{} { return v },
+		"append":     func(l []interface{}, v interface{}) []interface{} { return append(l[:len(l):len(l)], v) },
+		"kubeName":   kubeName,
+		"cfSize":     cfSize,
+		"routeHost":  routeHost,
+		"routePath":  routePath,
+		"tcpRoute":   tcpRoute,
+	}
+}
+
+// yamlToJSON converts yaml to json, e.g. for the schema of a pack
+func yamlToJSON(data []byte) ([]byte, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// loadTemplatePack reads a template pack from its files
+func loadTemplatePack(pack fs.FS) (*templatePack, error) {
+	p := &templatePack{}
+	schema, err := fs.ReadFile(pack, "schema.yaml")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		data, err := yamlToJSON(schema)
+		if err != nil {
+			return nil, fmt.Errorf("%w failed to parse schema.yaml", err)
+		}
+		p.schema = &openapi3.Schema{}
+		if err := json.Unmarshal(data, p.schema); err != nil {
+			return nil, fmt.Errorf("%w failed to parse schema.yaml", err)
+		}
+	}
+	files, err := fs.Glob(pack, "templates/*")
+	if err != nil {
+		return nil, err
+	}
+	p.templates = template.New("pack").Option("missingkey=zero")
+	p.templates.Funcs(templateFuncs(p.templates))
+	for _, file := range files {
+		content, err := fs.ReadFile(pack, file)
+		if err != nil {
+			return nil, err
+		}
+		name := path.Base(file)
+		if _, err := p.templates.New(name).Parse(string(content)); err != nil {
+			return nil, fmt.Errorf("%w failed to parse template %s", err, name)
+		}
+		if !strings.HasPrefix(name, "_") {
+			p.names = append(p.names, name)
+		}
+	}
+	if len(p.names) == 0 {
+		return nil, fmt.Errorf("template pack has no templates in templates/")
+	}
+	sort.Strings(p.names)
+	return p, nil
+}
+
+// jsonValues returns the attributes of an application as decoded from json,
+// the types the schema validates
+func jsonValues(app map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(app)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{}
+	return values, json.Unmarshal(data, &values)
+}
+
+// render returns the kubernetes manifests of an application by template,
+// templates rendering nothing are left out
+func (p *templatePack) render(manifest string, app map[string]interface{}) (map[string][]byte, error) {
+	values, err := jsonValues(app)
+	if err != nil {
+		return nil, err
+	}
+	if p.schema != nil {
+		if err := p.schema.VisitJSON(values, openapi3.MultiErrors()); err != nil {
+			return nil, fmt.Errorf("attributes do not match the schema of the template pack: %v", err)
+		}
+	}
+	rendered := map[string][]byte{}
+	for _, name := range p.names {
+		buf := &bytes.Buffer{}
+		if err := p.templates.ExecuteTemplate(buf, name, templateData{Values: values, Manifest: manifest}); err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(buf.Bytes())) == 0 {
+			continue
+		}
+		// the output of a template must be yaml documents
+		decoder := yaml.NewDecoder(bytes.NewReader(buf.Bytes()))
+		for {
+			var doc interface{}
+			err := decoder.Decode(&doc)
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("%w template %s rendered invalid yaml", err, name)
+			}
+		}
+		rendered[name] = buf.Bytes()
+	}
+	return rendered, nil
+}
+
+// manifestApps returns the applications of a manifest
+func manifestApps(content []byte) ([]map[string]interface{}, error) {
+	manifest := struct {
+		Applications []map[string]interface{} `yaml:"applications"`
+	}{}
+	if err := yaml.Unmarshal(content, &manifest); err != nil {
+		return nil, err
+	}
+	return manifest.Applications, nil
+}
+
+// pullTemplatePack copies the template pack of an image to a temp dir
+func (c *cfToK8sCommand) pullTemplatePack(ctx context.Context, image string) (string, error) {
+	if err := pullImage(ctx, c.log, io.Discard, image, ""); err != nil {
+		return "", err
+	}
+	dir, err := os.MkdirTemp("", "kantra-template-pack-")
+	if err != nil {
+		return "", err
+	}
+	out, err := exec.CommandContext(ctx, Settings.ContainerBinary, "create", image).Output()
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("%w failed to create container of %s", err, image)
+	}
+	id := strings.TrimSpace(string(out))
+	defer exec.Command(Settings.ContainerBinary, "rm", id).Run()
+	if out, err := exec.CommandContext(ctx, Settings.ContainerBinary, "cp", id+":"+templatePackImagePath+"/.", dir).CombinedOutput(); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("%w failed to copy %s of %s: %s", err, templatePackImagePath, image, out)
+	}
+	return dir, nil
+}
+
+// templatePack returns the template pack of --templates
+func (c *cfToK8sCommand) templatePack(ctx context.Context) (*templatePack, error) {
+	switch {
+	case c.templates == "":
+		pack, err := fs.Sub(cfTemplates, "cf-templates")
+		if err != nil {
+			return nil, err
+		}
+		return loadTemplatePack(pack)
+	case strings.HasPrefix(c.templates, ociPrefix):
+		dir, err := c.pullTemplatePack(ctx, strings.TrimPrefix(c.templates, ociPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("%w failed to pull template pack %s", err, c.templates)
+		}
+		defer os.RemoveAll(dir)
+		return loadTemplatePack(os.DirFS(dir))
+	default:
+		if _, err := os.Stat(c.templates); err != nil {
+			return nil, err
+		}
+		return loadTemplatePack(os.DirFS(c.templates))
+	}
+}
+
+// Run renders the template pack for each application of the manifests of
+// the input, failing on problems of the manifests blocking the conversion
+func (c *cfToK8sCommand) Run(ctx context.Context, out io.Writer) error {
+	manifests, err := findManifests(c.input)
+	if err != nil {
+		return fmt.Errorf("%w failed to find manifests in %s", err, c.input)
+	}
+	if len(manifests) == 0 {
+		return fmt.Errorf("no cloud foundry manifests found in %s", c.input)
+	}
+	pack, err := c.templatePack(ctx)
+	if err != nil {
+		return fmt.Errorf("%w failed to load template pack", err)
+	}
+	converted := 0
+	for _, manifest := range manifests {
+		content, err := os.ReadFile(manifest)
+		if err != nil {
+			return err
+		}
+		blocking := []string{}
+		for _, p := range validateManifest(manifest, content) {
+			if p.Severity == manifestError {
+				blocking = append(blocking, p.String())
+			}
+		}
+		if len(blocking) > 0 {
+			return fmt.Errorf("problems of %s block the conversion to kubernetes: %s", manifest, strings.Join(blocking, "; "))
+		}
+		apps, err := manifestApps(content)
+		if err != nil {
+			return fmt.Errorf("%w failed to parse %s", err, manifest)
+		}
+		for _, app := range apps {
+			name, _ := app["name"].(string)
+			rendered, err := pack.render(filepath.Base(manifest), app)
+			if err != nil {
+				return fmt.Errorf("%w failed to convert application %s of %s", err, name, manifest)
+			}
+			dir := filepath.Join(c.output, kubeName(name))
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return err
+			}
+			for _, file := range sortedMapKeys(rendered) {
+				if err := os.WriteFile(filepath.Join(dir, file), rendered[file], 0644); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintf(out, "%s: %s\n", name, strings.Join(sortedMapKeys(rendered), ", "))
+			converted++
+		}
+	}
+	fmt.Fprintf(out, "%d applications converted to %s\n", converted, c.output)
+	return nil
+}
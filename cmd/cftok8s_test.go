@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+const cfToK8sManifest = `applications:
+- name: My_App
+  instances: 2
+  memory: 512M
+  health-check-type: http
+  health-check-http-endpoint: /health
+  env:
+    RETRIES: 3
+  routes:
+  - route: myapp.example.com/api
+  - route: tcp.example.com:1024
+- name: worker
+  no-route: true
+  docker:
+    image: quay.io/org/worker:1.0
+`
+
+func Test_cfToK8sCommand_Run(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "manifest.yml"), cfToK8sManifest)
+	c := &cfToK8sCommand{input: filepath.Join(dir, "manifest.yml"), output: filepath.Join(dir, "k8s"), log: logr.Discard()}
+	out := &bytes.Buffer{}
+	if err := c.Run(context.Background(), out); err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string][]string{
+		"my-app/deployment.yaml": {"replicas: 2", "memory: 512Mi", "path: /health", `value: "3"`, `image: "my-app:latest"`},
+		"my-app/service.yaml":    {"name: my-app"},
+		"my-app/ingress.yaml":    {`host: "myapp.example.com"`, `path: "/api"`},
+		"worker/deployment.yaml": {"replicas: 1", `image: "quay.io/org/worker:1.0"`, "tcpSocket"},
+	} {
+		content, err := os.ReadFile(filepath.Join(c.output, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range want {
+			if !strings.Contains(string(content), w) {
+				t.Errorf("%s does not contain %s:\n%s", file, w, content)
+			}
+		}
+	}
+	if strings.Contains(mustReadFile(t, filepath.Join(c.output, "my-app/ingress.yaml")), "tcp.example.com") {
+		t.Error("ingress.yaml has the tcp route")
+	}
+	for _, file := range []string{"worker/service.yaml", "worker/ingress.yaml"} {
+		if _, err := os.Stat(filepath.Join(c.output, file)); !os.IsNotExist(err) {
+			t.Errorf("%s written for an application without routes", file)
+		}
+	}
+	if !strings.Contains(out.String(), "2 applications converted") {
+		t.Errorf("Run() printed %s", out.String())
+	}
+}
+
+func mustReadFile(t *testing.T, path string) string {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func Test_cfToK8sCommand_Run_templatePack(t *testing.T) {
+	dir := t.TempDir()
+	pack := filepath.Join(dir, "pack")
+	writeTestFile(t, filepath.Join(pack, "schema.yaml"), `type: object
+required: [docker]
+`)
+	writeTestFile(t, filepath.Join(pack, "templates", "_helpers.tpl"), `{{ define "image" }}{{ .Values.docker.image }}{{ end }}`)
+	writeTestFile(t, filepath.Join(pack, "templates", "knative.yaml"), `apiVersion: serving.knative.dev/v1
+kind: Service
+metadata:
+  name: {{ kubeName .Values.name }}
+spec:
+  image: {{ include "image" . }}
+`)
+
+	writeTestFile(t, filepath.Join(dir, "manifest.yml"), cfToK8sManifest)
+	c := &cfToK8sCommand{input: filepath.Join(dir, "manifest.yml"), templates: pack, output: filepath.Join(dir, "k8s"), log: logr.Discard()}
+	err := c.Run(context.Background(), &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "schema") {
+		t.Errorf("Run() error = %v, want the application without docker image to fail the schema", err)
+	}
+
+	writeTestFile(t, filepath.Join(dir, "manifest.yml"), `applications:
+- name: worker
+  docker:
+    image: quay.io/org/worker:1.0
+`)
+	if err := c.Run(context.Background(), &bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := mustReadFile(t, filepath.Join(c.output, "worker", "knative.yaml")); !strings.Contains(got, "image: quay.io/org/worker:1.0") {
+		t.Errorf("knative.yaml = %s, want the image of the application", got)
+	}
+}
+
+func Test_cfToK8sCommand_Run_blockingProblems(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "manifest.yml"), `applications:
+- name: app
+  host: app.example.com
+`)
+	c := &cfToK8sCommand{input: filepath.Join(dir, "manifest.yml"), output: filepath.Join(dir, "k8s"), log: logr.Discard()}
+	if err := c.Run(context.Background(), &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "host is not supported") {
+		t.Errorf("Run() error = %v, want the problems blocking the conversion", err)
+	}
+}
+
+func Test_cfSize(t *testing.T) {
+	for size, want := range map[string]string{"512M": "512Mi", "1G": "1Gi", "2 GB": "2Gi", "100": "100"} {
+		if got := cfSize(size); got != want {
+			t.Errorf("cfSize(%s) = %s, want %s", size, got, want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// changedFiles returns the files of the input dir changed since ref,
+// including changes of the working tree and untracked files, relative to
+// dir. Deleted files are left out.
+func changedFiles(dir string, ref string) ([]string, error) {
+	files := []string{}
+	for _, args := range [][]string{
+		{"diff", "--name-only", "--relative", "--no-renames", "--diff-filter=d", ref, "--", "."},
+		{"ls-files", "--others", "--exclude-standard", "--", "."},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		stderr := &bytes.Buffer{}
+		cmd.Stderr = stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("%w failed to list files changed since %s: %s", err, ref, strings.TrimSpace(stderr.String()))
+		}
+		for _, file := range strings.Split(string(out), "\n") {
+			if file != "" {
+				files = append(files, file)
+			}
+		}
+	}
+	return uniqueStrings(files), nil
+}
+
+// javaTypeReference matches the names of the given java types as words
+func javaTypeReference(names []string) *regexp.Regexp {
+	quoted := []string{}
+	for _, name := range names {
+		quoted = append(quoted, regexp.QuoteMeta(name))
+	}
+	return regexp.MustCompile(fmt.Sprintf(`\b(%s)\b`, strings.Join(quoted, "|")))
+}
+
+// javaDependents returns the java files of root referencing the types of
+// the changed java files by their simple name, e.g. classes calling a
+// changed class, which rules may match because of the change
+func javaDependents(root string, changed []string, ignore *kantraIgnore) ([]string, error) {
+	names := []string{}
+	changedSet := map[string]bool{}
+	for _, file := range changed {
+		changedSet[file] = true
+		if path.Ext(file) == ".java" {
+			names = append(names, strings.TrimSuffix(path.Base(file), ".java"))
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+	reference := javaTypeReference(names)
+	dependents := []string{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel != "." && (d.Name() == ".git" || ignore.Ignored(rel, true)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(p) != ".java" || changedSet[rel] || ignore.Ignored(rel, false) {
+			return nil
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if reference.Match(content) {
+			dependents = append(dependents, rel)
+		}
+		return nil
+	})
+	return dependents, err
+}
+
+// withinPaths reports whether the relative path is one of paths or in one
+// of them, nil paths include everything
+func withinPaths(rel string, paths []string) bool {
+	if paths == nil {
+		return true
+	}
+	for _, p := range paths {
+		if rel == p || strings.HasPrefix(rel, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func filterPaths(files []string, paths []string) []string {
+	kept := []string{}
+	for _, file := range files {
+		if withinPaths(file, paths) {
+			kept = append(kept, file)
+		}
+	}
+	return kept
+}
+
+// applyChangedSince limits the paths the providers analyze to the files
+// changed since the ref and the java files depending on them, so that the
+// analysis of a pull request does not analyze the whole input
+func (a *analyzeCommand) applyChangedSince(ignore *kantraIgnore) error {
+	if a.changedSince == "" {
+		return nil
+	}
+	changed := a.changedFiles
+	dependents, err := javaDependents(a.input, changed, ignore)
+	if err != nil {
+		return fmt.Errorf("%w failed to find files depending on changed files", err)
+	}
+	files := []string{}
+	for _, file := range append(changed, dependents...) {
+		if !ignore.Ignored(file, false) {
+			files = append(files, file)
+		}
+	}
+	files = filterPaths(files, a.includedPaths)
+	sort.Strings(files)
+	if len(files) == 0 {
+		return fmt.Errorf("no files of the input changed since %s", a.changedSince)
+	}
+	if a.builtinPaths != nil {
+		a.builtinPaths = filterPaths(files, a.builtinPaths)
+		if len(a.builtinPaths) == 0 {
+			return fmt.Errorf("all files of the input changed since %s are skipped by --max-file-size or --skip-binary-files", a.changedSince)
+		}
+	}
+	a.includedPaths = files
+	a.log.Info("analyzing files changed since ref", "ref", a.changedSince, "changed", len(changed), "dependents", len(dependents))
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_analyzeCommand_applyChangedSince(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	input := filepath.Join(repo, "app")
+	writeTestFile(t, filepath.Join(input, "src", "Cart.java"), "class Cart {}\n")
+	writeTestFile(t, filepath.Join(input, "src", "Checkout.java"), "class Checkout { Cart cart; }\n")
+	writeTestFile(t, filepath.Join(input, "src", "CartItem.java"), "class CartItem {}\n")
+	writeTestFile(t, filepath.Join(input, "src", "Removed.java"), "class Removed {}\n")
+	writeTestFile(t, filepath.Join(input, "generated", "CartProxy.java"), "class CartProxy { Cart cart; }\n")
+	writeTestFile(t, filepath.Join(input, kantraIgnoreFile), "generated/\n")
+	writeTestFile(t, filepath.Join(repo, "other", "Other.java"), "class Other {}\n")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=Jane Doe", "-c", "user.email=jane@example.com", "commit", "-q", "-m", "init"},
+		{"rm", "-q", "app/src/Removed.java"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	// changes of the pull request
+	writeTestFile(t, filepath.Join(input, "src", "Cart.java"), "class Cart { int items; }\n")
+	writeTestFile(t, filepath.Join(input, "src", "Order.java"), "class Order {}\n")
+	writeTestFile(t, filepath.Join(repo, "other", "Other.java"), "class Other { int changed; }\n")
+
+	changed, err := changedFiles(input, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ignore, err := loadKantraIgnore(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := &analyzeCommand{log: logr.Discard(), input: input, changedSince: "HEAD", changedFiles: changed}
+	if a.includedPaths, err = ignore.IncludedPaths(input); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.applyChangedSince(ignore); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"src/Cart.java", "src/Checkout.java", "src/Order.java"}
+	if !reflect.DeepEqual(a.includedPaths, want) {
+		t.Errorf("applyChangedSince() included paths = %v, want %v", a.includedPaths, want)
+	}
+
+	a = &analyzeCommand{log: logr.Discard(), input: input, changedSince: "HEAD"}
+	if err := a.applyChangedSince(nil); err == nil {
+		t.Errorf("applyChangedSince() without changed files error = nil, want error")
+	}
+}
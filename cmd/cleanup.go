@@ -8,7 +8,9 @@ import (
 )
 
 func (a *analyzeCommand) CleanAnalysisResources(ctx context.Context) error {
-	if !a.cleanup || a.needsBuiltin {
+	// created in the input, they are removed with --no-cleanup too
+	a.removeSymlinkMounts()
+	if !a.cleanup {
 		return nil
 	}
 	a.log.V(1).Info("removing temp dirs")
@@ -34,6 +36,36 @@ func (a *analyzeCommand) CleanAnalysisResources(ctx context.Context) error {
 	return nil
 }
 
+// removeValidateResources removes the temp resources Validate creates for
+// the input, e.g. its snapshot, and for bundled rules, and the files holding
+// secrets, which are removed with --no-cleanup too
+func (a *analyzeCommand) removeValidateResources() {
+	if a.mavenSettingsDir != "" {
+		os.RemoveAll(a.mavenSettingsDir)
+	}
+	if a.registryAuthDir != "" {
+		os.RemoveAll(a.registryAuthDir)
+	}
+	if !a.cleanup {
+		return
+	}
+	if a.gitWorktree != nil {
+		if err := a.removeGitWorktree(); err != nil {
+			a.log.Error(err, "failed to remove git worktree")
+		}
+	}
+	if a.snapshotDir != "" {
+		if err := removeSnapshot(a.snapshotDir); err != nil {
+			a.log.Error(err, "failed to remove input snapshot", "dir", a.snapshotDir)
+		}
+	}
+	for _, dir := range append([]string{a.descriptorsDir, a.stdinDir}, a.bundledRulesDirs...) {
+		if dir != "" {
+			os.RemoveAll(dir)
+		}
+	}
+}
+
 func (a *analyzeCommand) RmNetwork(ctx context.Context) error {
 	if a.networkName == "" {
 		return nil
@@ -0,0 +1,378 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// severities of the problems of cloud foundry manifests, errors block the
+// conversion of the applications to kubernetes
+const (
+	manifestError   = "error"
+	manifestWarning = "warning"
+)
+
+// attributes of the applications of version 1 manifests
+var manifestAppAttributes = []string{
+	"name", "buildpacks", "command", "disk_quota", "docker", "env",
+	"health-check-type", "health-check-http-endpoint", "health-check-invocation-timeout",
+	"health-check-interval", "readiness-health-check-type", "readiness-health-check-http-endpoint",
+	"readiness-health-check-invocation-timeout", "readiness-health-check-interval",
+	"instances", "lifecycle", "log-rate-limit-per-second", "memory", "metadata",
+	"no-route", "default-route", "path", "processes", "random-route", "routes",
+	"services", "sidecars", "stack", "timeout",
+}
+
+// attributes of applications removed from manifests, by their replacement
+var removedAppAttributes = map[string]string{
+	"host":        "routes",
+	"hosts":       "routes",
+	"domain":      "routes",
+	"domains":     "routes",
+	"no-hostname": "routes",
+}
+
+var (
+	healthCheckTypes = []string{"port", "process", "http"}
+	routeProtocols   = []string{"http1", "http2", "tcp"}
+	// sizes of memory, disk and log rate limits, e.g. 512M or 1G
+	manifestSize = regexp.MustCompile(`(?i)^\d+\s?(B|K|KB|M|MB|G|GB|T|TB)$`)
+	// routes are hosts with an optional port or path
+	manifestRoute = regexp.MustCompile(`^[a-zA-Z0-9*]([a-zA-Z0-9.*-]*[a-zA-Z0-9])?(:\d+)?(/\S*)?$`)
+)
+
+// manifestProblem is a construct of a cloud foundry manifest blocking or
+// to check in its conversion to kubernetes
+type manifestProblem struct {
+	File     string `json:"file" yaml:"file"`
+	App      string `json:"app,omitempty" yaml:"app,omitempty"`
+	Severity string `json:"severity" yaml:"severity"`
+	Problem  string `json:"problem" yaml:"problem"`
+}
+
+func (p manifestProblem) String() string {
+	if p.App == "" {
+		return fmt.Sprintf("%s: %s: %s", p.File, p.Severity, p.Problem)
+	}
+	return fmt.Sprintf("%s:%s: %s: %s", p.File, p.App, p.Severity, p.Problem)
+}
+
+// version of the schema of the outputs of discover commands, raised on
+// incompatible changes so that tools reading them can tell them apart
+const discoverSchemaVersion = "v1"
+
+// manifestValidation is the output of the validation of manifests
+type manifestValidation struct {
+	Version  string            `json:"version" yaml:"version"`
+	Problems []manifestProblem `json:"problems" yaml:"problems"`
+}
+
+type cfValidateCommand struct {
+	input  string
+	format string
+	log    logr.Logger
+}
+
+func NewDiscoverCommand(log logr.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "discover",
+		Short: "Discover the platform configuration of applications",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+	cfCmd := &cobra.Command{
+		Use:   "cloud-foundry",
+		Short: "Discover the configuration of Cloud Foundry applications",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+	cfCmd.AddCommand(newCFValidateCommand(log))
+	cmd.AddCommand(cfCmd)
+	return cmd
+}
+
+func newCFValidateCommand(log logr.Logger) *cobra.Command {
+	validateCmd := &cfValidateCommand{log: log}
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check Cloud Foundry manifests for constructs blocking the conversion to Kubernetes",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			switch validateCmd.format {
+			case listFormatText, listFormatJSON, listFormatYAML:
+			default:
+				return fmt.Errorf("format must be one of 'text', 'json' or 'yaml'")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateCmd.Run(os.Stdout); err != nil {
+				log.Error(err, "failed to validate cloud foundry manifests")
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&validateCmd.input, "input", "i", "", "path to a Cloud Foundry manifest or a directory of manifests")
+	cmd.Flags().StringVar(&validateCmd.format, "format", listFormatText, "output format. Must be one of 'text', 'json' or 'yaml'")
+	cmd.MarkFlagRequired("input")
+	return cmd
+}
+
+func isManifestFile(name string) bool {
+	ext := filepath.Ext(name)
+	return strings.Contains(strings.ToLower(name), "manifest") && (ext == ".yml" || ext == ".yaml")
+}
+
+// findManifests returns the manifest files of the input, files with
+// manifest in their name when the input is a dir
+func findManifests(input string) ([]string, error) {
+	stat, err := os.Stat(input)
+	if err != nil {
+		return nil, err
+	}
+	if !stat.IsDir() {
+		return []string{input}, nil
+	}
+	manifests := []string{}
+	err = filepath.WalkDir(input, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != input && (d.Name() == ".git" || d.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isManifestFile(d.Name()) {
+			manifests = append(manifests, path)
+		}
+		return nil
+	})
+	return manifests, err
+}
+
+// validateManifest returns the problems of a manifest
+func validateManifest(file string, content []byte) []manifestProblem {
+	problems := []manifestProblem{}
+	report := func(app, severity, format string, args ...interface{}) {
+		problems = append(problems, manifestProblem{File: file, App: app, Severity: severity, Problem: fmt.Sprintf(format, args...)})
+	}
+	manifest := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &manifest); err != nil {
+		report("", manifestError, "not a valid yaml file: %v", err)
+		return problems
+	}
+	keys := sortedMapKeys(manifest)
+	for _, key := range keys {
+		switch {
+		case key == "applications":
+		case key == "version":
+			if v, ok := manifest[key].(int); !ok || v != 1 {
+				report("", manifestError, "version %v is not supported, only version 1 is", manifest[key])
+			}
+		case key == "inherit":
+			report("", manifestError, "inherit is not supported anymore, merge the inherited manifest")
+		case slices.Contains(manifestAppAttributes, key) || removedAppAttributes[key] != "":
+			report("", manifestError, "%s must be set on each application, attributes at the top level are not supported anymore", key)
+		default:
+			report("", manifestWarning, "unknown attribute %s", key)
+		}
+	}
+	apps, ok := manifest["applications"].([]interface{})
+	if !ok {
+		report("", manifestError, "applications must be a list of applications")
+		return problems
+	}
+	for i, a := range apps {
+		app, ok := a.(map[string]interface{})
+		if !ok {
+			report("", manifestError, "application %d must be a map of attributes", i+1)
+			continue
+		}
+		name, _ := app["name"].(string)
+		if name == "" {
+			name = fmt.Sprintf("application %d", i+1)
+			report(name, manifestError, "name is missing")
+		}
+		for _, key := range sortedMapKeys(app) {
+			if replacement := removedAppAttributes[key]; replacement != "" {
+				report(name, manifestError, "%s is not supported anymore, use %s", key, replacement)
+			} else if key == "buildpack" {
+				report(name, manifestWarning, "buildpack is deprecated, use buildpacks")
+			} else if !slices.Contains(manifestAppAttributes, key) {
+				report(name, manifestWarning, "unknown attribute %s", key)
+			}
+		}
+		problems = append(problems, validateProcess(file, name, app)...)
+		for _, key := range []string{"instances", "timeout"} {
+			if v, ok := app[key]; ok {
+				if n, ok := v.(int); !ok || n < 0 {
+					report(name, manifestError, "%s %v must be a positive number", key, v)
+				}
+			}
+		}
+		if stack, _ := app["stack"].(string); strings.HasPrefix(stack, "windows") {
+			report(name, manifestError, "stack %s runs on windows cells, which have no equivalent in linux containers", stack)
+		}
+		if random, _ := app["random-route"].(bool); random {
+			report(name, manifestWarning, "random-route has no equivalent on kubernetes, set a route")
+		}
+		if routes, ok := app["routes"]; ok {
+			problems = append(problems, validateRoutes(file, name, routes)...)
+		}
+		if docker, ok := app["docker"].(map[string]interface{}); ok && docker["username"] != nil {
+			report(name, manifestWarning, "the credentials of the docker registry must be set as an image pull secret")
+		}
+		if _, ok := app["services"]; ok {
+			report(name, manifestWarning, "service bindings must be converted to secrets or config maps")
+		}
+		if _, ok := app["sidecars"]; ok {
+			report(name, manifestWarning, "sidecars must be converted to containers of the pod, check their processes and memory")
+		}
+		if processes, ok := app["processes"].([]interface{}); ok {
+			for j, p := range processes {
+				process, ok := p.(map[string]interface{})
+				if !ok {
+					report(name, manifestError, "process %d must be a map of attributes", j+1)
+					continue
+				}
+				if processType, _ := process["type"].(string); processType == "" {
+					report(name, manifestError, "process %d has no type", j+1)
+				}
+				problems = append(problems, validateProcess(file, name, process)...)
+			}
+		} else if _, ok := app["processes"]; ok {
+			report(name, manifestError, "processes must be a list of processes")
+		}
+	}
+	return problems
+}
+
+// validateProcess returns the problems of the attributes shared by
+// applications and their processes
+func validateProcess(file string, app string, attributes map[string]interface{}) []manifestProblem {
+	problems := []manifestProblem{}
+	report := func(severity, format string, args ...interface{}) {
+		problems = append(problems, manifestProblem{File: file, App: app, Severity: severity, Problem: fmt.Sprintf(format, args...)})
+	}
+	for _, key := range []string{"health-check-type", "readiness-health-check-type"} {
+		v, ok := attributes[key]
+		if !ok {
+			continue
+		}
+		healthCheck, _ := v.(string)
+		switch {
+		case healthCheck == "none":
+			report(manifestWarning, "%s none is deprecated, use process", key)
+		case !slices.Contains(healthCheckTypes, healthCheck):
+			report(manifestError, "%s %v must be one of %s", key, v, strings.Join(healthCheckTypes, ", "))
+		}
+	}
+	for _, key := range []string{"memory", "disk_quota", "log-rate-limit-per-second"} {
+		v, ok := attributes[key]
+		if !ok {
+			continue
+		}
+		// unlimited log rates are -1
+		if key == "log-rate-limit-per-second" && fmt.Sprint(v) == "-1" {
+			continue
+		}
+		if size, _ := v.(string); !manifestSize.MatchString(size) {
+			report(manifestError, "%s %v must be a size with a unit, e.g. 512M or 1G", key, v)
+		}
+	}
+	return problems
+}
+
+// validateRoutes returns the problems of the routes of an application
+func validateRoutes(file string, app string, v interface{}) []manifestProblem {
+	problems := []manifestProblem{}
+	report := func(severity, format string, args ...interface{}) {
+		problems = append(problems, manifestProblem{File: file, App: app, Severity: severity, Problem: fmt.Sprintf(format, args...)})
+	}
+	routes, ok := v.([]interface{})
+	if !ok {
+		report(manifestError, "routes must be a list of routes")
+		return problems
+	}
+	for i, r := range routes {
+		route, _ := r.(map[string]interface{})
+		host, _ := route["route"].(string)
+		if host == "" {
+			report(manifestError, "route %d has no route", i+1)
+			continue
+		}
+		if !manifestRoute.MatchString(host) {
+			report(manifestError, "route %s is not a valid route", host)
+			continue
+		}
+		protocol, hasProtocol := route["protocol"].(string)
+		if hasProtocol && !slices.Contains(routeProtocols, protocol) {
+			report(manifestError, "protocol %s of route %s must be one of %s", protocol, host, strings.Join(routeProtocols, ", "))
+		}
+		hostname, _, _ := strings.Cut(host, "/")
+		switch {
+		case protocol == "tcp" || strings.Contains(hostname, ":"):
+			report(manifestWarning, "route %s is a tcp route, which needs a service of type LoadBalancer instead of an ingress", host)
+		case protocol == "http2":
+			report(manifestWarning, "route %s uses http2, which depends on the ingress controller", host)
+		}
+		if strings.HasPrefix(hostname, "*.") {
+			report(manifestWarning, "route %s is a wildcard route, which depends on the ingress controller", host)
+		}
+	}
+	return problems
+}
+
+// Run validates the manifests of the input, failing when a manifest has
+// errors
+func (c *cfValidateCommand) Run(out io.Writer) error {
+	manifests, err := findManifests(c.input)
+	if err != nil {
+		return fmt.Errorf("%w failed to find manifests in %s", err, c.input)
+	}
+	if len(manifests) == 0 {
+		return fmt.Errorf("no cloud foundry manifests found in %s", c.input)
+	}
+	problems := []manifestProblem{}
+	for _, manifest := range manifests {
+		content, err := os.ReadFile(manifest)
+		if err != nil {
+			return err
+		}
+		problems = append(problems, validateManifest(manifest, content)...)
+	}
+	errors := 0
+	for _, p := range problems {
+		if p.Severity == manifestError {
+			errors++
+		}
+	}
+	if c.format != listFormatText {
+		if err := writeListOutput(out, c.format, manifestValidation{Version: discoverSchemaVersion, Problems: problems}); err != nil {
+			return err
+		}
+	} else {
+		for _, p := range problems {
+			fmt.Fprintln(out, p)
+		}
+		fmt.Fprintf(out, "%d manifests checked: %d errors, %d warnings\n", len(manifests), errors, len(problems)-errors)
+	}
+	if errors > 0 {
+		return fmt.Errorf("%d problems of the manifests block the conversion to kubernetes", errors)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_validateManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+	}{
+		{
+			name: "valid manifest",
+			manifest: `version: 1
+applications:
+- name: cart
+  memory: 1G
+  instances: 2
+  health-check-type: http
+  health-check-http-endpoint: /health
+  routes:
+  - route: cart.example.com/api
+`,
+			want: []string{},
+		},
+		{
+			name: "removed and deprecated attributes",
+			manifest: `memory: 512M
+applications:
+- name: cart
+  buildpack: java_buildpack
+  host: cart
+  health-check-type: none
+`,
+			want: []string{
+				"manifest.yml: error: memory must be set on each application, attributes at the top level are not supported anymore",
+				"manifest.yml:cart: warning: buildpack is deprecated, use buildpacks",
+				"manifest.yml:cart: error: host is not supported anymore, use routes",
+				"manifest.yml:cart: warning: health-check-type none is deprecated, use process",
+			},
+		},
+		{
+			name: "invalid values",
+			manifest: `applications:
+- memory: 512
+  instances: -1
+  stack: windows2016
+  processes:
+  - health-check-type: tcp
+`,
+			want: []string{
+				"manifest.yml:application 1: error: name is missing",
+				"manifest.yml:application 1: error: memory 512 must be a size with a unit, e.g. 512M or 1G",
+				"manifest.yml:application 1: error: instances -1 must be a positive number",
+				"manifest.yml:application 1: error: stack windows2016 runs on windows cells, which have no equivalent in linux containers",
+				"manifest.yml:application 1: error: process 1 has no type",
+				"manifest.yml:application 1: error: health-check-type tcp must be one of port, process, http",
+			},
+		},
+		{
+			name: "route features",
+			manifest: `applications:
+- name: cart
+  random-route: true
+  services: [db]
+  routes:
+  - route: tcp.example.com:1024
+  - route: "*.example.com"
+    protocol: http2
+  - route: cart.example.com
+    protocol: udp
+`,
+			want: []string{
+				"manifest.yml:cart: warning: random-route has no equivalent on kubernetes, set a route",
+				"manifest.yml:cart: warning: route tcp.example.com:1024 is a tcp route, which needs a service of type LoadBalancer instead of an ingress",
+				"manifest.yml:cart: warning: route *.example.com uses http2, which depends on the ingress controller",
+				"manifest.yml:cart: warning: route *.example.com is a wildcard route, which depends on the ingress controller",
+				"manifest.yml:cart: error: protocol udp of route cart.example.com must be one of http1, http2, tcp",
+				"manifest.yml:cart: warning: service bindings must be converted to secrets or config maps",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, p := range validateManifest("manifest.yml", []byte(tt.manifest)) {
+				got = append(got, p.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validateManifest() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func Test_cfValidateCommand_Run(t *testing.T) {
+	input := t.TempDir()
+	writeTestFile(t, filepath.Join(input, "cart", "manifest.yml"), "applications:\n- name: cart\n  memory: 1G\n")
+	writeTestFile(t, filepath.Join(input, "orders", "manifest-prod.yaml"), "applications:\n- name: orders\n  hosts: [orders]\n")
+	writeTestFile(t, filepath.Join(input, "orders", "config.yaml"), "not: a manifest\n")
+	c := &cfValidateCommand{input: input, format: listFormatText, log: logr.Discard()}
+	out := &bytes.Buffer{}
+	if err := c.Run(out); err == nil {
+		t.Error("Run() succeeded with a manifest with errors")
+	}
+	want := filepath.Join(input, "orders", "manifest-prod.yaml") + ":orders: error: hosts is not supported anymore, use routes\n" +
+		"2 manifests checked: 1 errors, 0 warnings\n"
+	if out.String() != want {
+		t.Errorf("Run() printed %q, want %q", out.String(), want)
+	}
+	c.input = filepath.Join(input, "cart", "manifest.yml")
+	if err := c.Run(&bytes.Buffer{}); err != nil {
+		t.Errorf("Run() of a valid manifest: %v", err)
+	}
+}
+
+func Test_cfValidateCommand_Run_format(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "manifest.yml")
+	writeTestFile(t, input, "applications:\n- name: cart\n  buildpack: java_buildpack\n")
+	c := &cfValidateCommand{input: input, format: listFormatYAML, log: logr.Discard()}
+	out := &bytes.Buffer{}
+	if err := c.Run(out); err != nil {
+		t.Fatal(err)
+	}
+	want := "version: v1\nproblems:\n- file: " + input + "\n  app: cart\n  severity: warning\n  problem: buildpack is deprecated, use buildpacks\n"
+	if out.String() != want {
+		t.Errorf("Run() printed %q, want %q", out.String(), want)
+	}
+}
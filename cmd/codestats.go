@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// codeSyntax is what the code counter needs to know of a language
+type codeSyntax struct {
+	lineComments []string
+	blockStart   string
+	blockEnd     string
+	// matches the decision points of a line, e.g. if or &&
+	decisions *regexp.Regexp
+}
+
+var (
+	cLikeSyntax = codeSyntax{
+		lineComments: []string{"//"},
+		blockStart:   "/*",
+		blockEnd:     "*/",
+		decisions:    regexp.MustCompile(`\b(if|for|while|case|catch)\b|&&|\|\|`),
+	}
+	pythonSyntax = codeSyntax{
+		lineComments: []string{"#"},
+		decisions:    regexp.MustCompile(`\b(if|elif|for|while|except|and|or)\b`),
+	}
+)
+
+// languages of the code counted by the extension of their files
+var codeLanguages = map[string]struct {
+	name   string
+	syntax codeSyntax
+}{
+	".java":   {"Java", cLikeSyntax},
+	".kt":     {"Kotlin", cLikeSyntax},
+	".scala":  {"Scala", cLikeSyntax},
+	".groovy": {"Groovy", cLikeSyntax},
+	".go":     {"Go", cLikeSyntax},
+	".cs":     {"C#", cLikeSyntax},
+	".js":     {"JavaScript", cLikeSyntax},
+	".jsx":    {"JavaScript", cLikeSyntax},
+	".ts":     {"TypeScript", cLikeSyntax},
+	".tsx":    {"TypeScript", cLikeSyntax},
+	".py":     {"Python", pythonSyntax},
+}
+
+// build files making their dir a module of the input
+var moduleBuildFiles = []string{"pom.xml", "build.gradle", "build.gradle.kts", "package.json", "go.mod", "pyproject.toml", "setup.py"}
+
+// dirs of dependencies and build outputs, which are not code of the input
+var codeSkippedDirs = []string{".git", "node_modules", "target", "vendor"}
+
+// languageStats are the lines of code of a language
+type languageStats struct {
+	Language string `yaml:"language" json:"language"`
+	Files    int    `yaml:"files" json:"files"`
+	Code     int    `yaml:"code" json:"code"`
+	Comments int    `yaml:"comments" json:"comments"`
+	Blank    int    `yaml:"blank" json:"blank"`
+	// decision points of the code, e.g. if, for, case, catch, && and ||,
+	// approximating its cyclomatic complexity
+	Complexity int `yaml:"complexity" json:"complexity"`
+}
+
+func (s *languageStats) add(o languageStats) {
+	s.Files += o.Files
+	s.Code += o.Code
+	s.Comments += o.Comments
+	s.Blank += o.Blank
+	s.Complexity += o.Complexity
+}
+
+// moduleStats are the lines of code of a module, a dir of the input with a
+// build file
+type moduleStats struct {
+	Path      string          `yaml:"path" json:"path"`
+	Languages []languageStats `yaml:"languages" json:"languages"`
+}
+
+// codeStats are the size and complexity of the code of the input, the
+// denominators of effort estimates
+type codeStats struct {
+	Languages []languageStats `yaml:"languages" json:"languages"`
+	Modules   []moduleStats   `yaml:"modules,omitempty" json:"modules,omitempty"`
+}
+
+// countFileLines counts the lines of a file of the language
+func countFileLines(content []byte, language string, syntax codeSyntax) languageStats {
+	stats := languageStats{Language: language, Files: 1}
+	inBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !inBlock && syntax.blockStart != "" && strings.HasPrefix(line, syntax.blockStart) {
+			inBlock = true
+			line = line[len(syntax.blockStart):]
+		}
+		if inBlock {
+			i := strings.Index(line, syntax.blockEnd)
+			if i < 0 {
+				stats.Comments++
+				continue
+			}
+			inBlock = false
+			// code after the end of the comment
+			line = strings.TrimSpace(line[i+len(syntax.blockEnd):])
+			if line == "" {
+				stats.Comments++
+				continue
+			}
+		}
+		switch {
+		case line == "":
+			stats.Blank++
+		case hasAnyPrefix(line, syntax.lineComments):
+			stats.Comments++
+		default:
+			stats.Code++
+			stats.Complexity += len(syntax.decisions.FindAllString(line, -1))
+		}
+	}
+	return stats
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedLanguageStats returns the stats sorted by lines of code
+func sortedLanguageStats(byLanguage map[string]*languageStats) []languageStats {
+	stats := []languageStats{}
+	for _, s := range byLanguage {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Code != stats[j].Code {
+			return stats[i].Code > stats[j].Code
+		}
+		return stats[i].Language < stats[j].Language
+	})
+	return stats
+}
+
+// countCode counts the lines of code of the input dir by language and by
+// module, files of the module are in its dir but not in a nested module
+func countCode(input string, ignore *kantraIgnore) (*codeStats, error) {
+	total := map[string]*languageStats{}
+	modules := map[string]map[string]*languageStats{}
+	// module of each dir, by slash separated path relative to the input
+	moduleOf := map[string]string{}
+	err := filepath.WalkDir(input, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(input, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel != "." && (ignore.Ignored(rel, true) || slices.Contains(codeSkippedDirs, d.Name())) {
+				return filepath.SkipDir
+			}
+			moduleOf[rel] = moduleOf[path.Dir(rel)]
+			for _, name := range moduleBuildFiles {
+				if _, err := os.Stat(filepath.Join(p, name)); err == nil {
+					moduleOf[rel] = rel
+					break
+				}
+			}
+			return nil
+		}
+		language, ok := codeLanguages[strings.ToLower(filepath.Ext(p))]
+		if !ok || ignore.Ignored(rel, false) {
+			return nil
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			// broken symlinks are not code
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		stats := countFileLines(content, language.name, language.syntax)
+		if total[language.name] == nil {
+			total[language.name] = &languageStats{Language: language.name}
+		}
+		total[language.name].add(stats)
+		module := moduleOf[path.Dir(rel)]
+		if modules[module] == nil {
+			modules[module] = map[string]*languageStats{}
+		}
+		if modules[module][language.name] == nil {
+			modules[module][language.name] = &languageStats{Language: language.name}
+		}
+		modules[module][language.name].add(stats)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	stats := &codeStats{Languages: sortedLanguageStats(total)}
+	// the input itself is no module without a build file
+	for _, module := range sortedMapKeys(modules) {
+		if module == "" {
+			continue
+		}
+		stats.Modules = append(stats.Modules, moduleStats{Path: module, Languages: sortedLanguageStats(modules[module])})
+	}
+	return stats, nil
+}
+
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// countInputCode counts the code of the input for summary.json, binary
+// inputs are not counted
+func (a *analyzeCommand) countInputCode() *codeStats {
+	if a.isFileInput || a.input == "" {
+		return nil
+	}
+	stats, err := countCode(a.input, a.inputIgnore)
+	if err != nil {
+		a.log.V(1).Error(err, "failed to count lines of code of the input")
+		return nil
+	}
+	return stats
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_countFileLines(t *testing.T) {
+	java := `package cart;
+
+/*
+ * Cart of the store
+ */
+public class Cart {
+    // items of the cart
+    /* inline */ int items;
+
+    int add(int n) {
+        if (n > 0 && items < 10) {
+            items += n;
+        }
+        /* bound
+         */ for (int i = 0; i < n; i++) {}
+        return items;
+    }
+}
+`
+	got := countFileLines([]byte(java), "Java", cLikeSyntax)
+	want := languageStats{Language: "Java", Files: 1, Code: 11, Comments: 5, Blank: 2, Complexity: 3}
+	if got != want {
+		t.Errorf("countFileLines() = %+v, want %+v", got, want)
+	}
+	python := "# cart\nif a and b:\n    pass\n\n"
+	got = countFileLines([]byte(python), "Python", pythonSyntax)
+	want = languageStats{Language: "Python", Files: 1, Code: 2, Comments: 1, Blank: 1, Complexity: 2}
+	if got != want {
+		t.Errorf("countFileLines() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_countCode(t *testing.T) {
+	input := t.TempDir()
+	writeTestFile(t, filepath.Join(input, "pom.xml"), "<project/>\n")
+	writeTestFile(t, filepath.Join(input, "src/main/java/App.java"), "class App {\n  void run() { if (a) {} }\n}\n")
+	writeTestFile(t, filepath.Join(input, "web/package.json"), "{}\n")
+	writeTestFile(t, filepath.Join(input, "web/src/app.js"), "// app\nrun()\n")
+	writeTestFile(t, filepath.Join(input, "web/node_modules/lib/index.js"), "lib()\n")
+	writeTestFile(t, filepath.Join(input, "generated/Gen.java"), "class Gen {}\n")
+	writeTestFile(t, filepath.Join(input, kantraIgnoreFile), "generated/\n")
+	ignore, err := loadKantraIgnore(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := countCode(input, ignore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	java := languageStats{Language: "Java", Files: 1, Code: 3, Complexity: 1}
+	javaScript := languageStats{Language: "JavaScript", Files: 1, Code: 1, Comments: 1}
+	want := &codeStats{
+		Languages: []languageStats{java, javaScript},
+		Modules: []moduleStats{
+			{Path: ".", Languages: []languageStats{java}},
+			{Path: "web", Languages: []languageStats{javaScript}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("countCode() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// extension of compressed output files
+const gzipExt = ".gz"
+
+// outputs compressed by --compress-output
+var compressedOutputs = []string{"output.yaml", "dependencies.yaml", incidentStoreFile}
+
+// dir in the output dir of the decompressed outputs read by the static
+// report generator of the container, removed once the report is generated
+const reportInputsDir = ".report-inputs"
+
+// outputFile returns the path of an output file, or the path of its
+// compressed copy when only that one exists
+func outputFile(path string) string {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if _, err := os.Stat(path + gzipExt); err == nil {
+			return path + gzipExt
+		}
+	}
+	return path
+}
+
+// readOutputFile reads an output file, decompressing it when it is
+// compressed or when only its compressed copy exists
+func readOutputFile(path string) ([]byte, error) {
+	path = outputFile(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, gzipExt) {
+		return data, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w failed to decompress %s", err, path)
+	}
+	defer r.Close()
+	data, err = io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w failed to decompress %s", err, path)
+	}
+	return data, nil
+}
+
+// compressFile replaces the file at path by its gzip compressed copy
+func compressFile(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(path + gzipExt)
+	if err != nil {
+		return err
+	}
+	w := gzip.NewWriter(out)
+	if _, err := io.Copy(w, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	in.Close()
+	return os.Remove(path)
+}
+
+// compressOutputs compresses the analysis and dependency outputs of the
+// input once all the other outputs are generated from them
+func (a *analyzeCommand) compressOutputs() error {
+	if !a.compressOutput {
+		return nil
+	}
+	for _, file := range compressedOutputs {
+		path := filepath.Join(a.output, file)
+		// bulk analysis moves results to the application dir
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) && a.bulk {
+			path = a.bulkResultPath(file)
+		}
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err := compressFile(path); err != nil {
+			return fmt.Errorf("%w failed to compress %s", err, path)
+		}
+		a.log.V(1).Info("compressed output", "file", path+gzipExt)
+	}
+	return nil
+}
+
+// reportInput returns the path of an output file the static report
+// generator of the container can read, a decompressed copy in the report
+// inputs dir of the output dir for a compressed file
+func (a *analyzeCommand) reportInput(file string) (string, error) {
+	if !strings.HasSuffix(file, gzipExt) {
+		return file, nil
+	}
+	rel, err := filepath.Rel(a.output, strings.TrimSuffix(file, gzipExt))
+	if err != nil {
+		return "", err
+	}
+	data, err := readOutputFile(file)
+	if err != nil {
+		return "", err
+	}
+	decompressed := filepath.Join(a.output, reportInputsDir, rel)
+	if err := os.MkdirAll(filepath.Dir(decompressed), 0755); err != nil {
+		return "", err
+	}
+	return decompressed, os.WriteFile(decompressed, data, 0644)
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_analyzeCommand_compressOutputs(t *testing.T) {
+	output := t.TempDir()
+	writeTestFile(t, filepath.Join(output, "output.yaml"), `- name: eap8
+  violations:
+    session-00010:
+      description: Stateful session EJB
+      incidents:
+      - uri: file:///opt/input/source/src/Cart.java
+        message: Replace the stateful EJB
+`)
+	a := &analyzeCommand{log: logr.Discard(), output: output, compressOutput: true}
+	if err := a.compressOutputs(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(output, "output.yaml")); !os.IsNotExist(err) {
+		t.Errorf("compressOutputs() kept output.yaml")
+	}
+	if got := outputFile(filepath.Join(output, "output.yaml")); got != filepath.Join(output, "output.yaml.gz") {
+		t.Errorf("outputFile() = %s, want output.yaml.gz", got)
+	}
+	// readers of the output find the compressed copy
+	rulesets, err := readRuleSetsOutput(filepath.Join(output, "output.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rulesets) != 1 || len(rulesets[0].Violations["session-00010"].Incidents) != 1 {
+		t.Errorf("readRuleSetsOutput() = %v, want the incident of session-00010", rulesets)
+	}
+	v, err := readQueryInput(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list, ok := v.([]interface{}); !ok || len(list) != 1 {
+		t.Errorf("readQueryInput() = %v, want the eap8 ruleset", v)
+	}
+	decompressed, err := a.reportInput(filepath.Join(output, "output.yaml.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(output, reportInputsDir, "output.yaml"); decompressed != want {
+		t.Errorf("reportInput() = %s, want %s", decompressed, want)
+	}
+}
+
+func Test_listBulkApps_compressed(t *testing.T) {
+	output := t.TempDir()
+	writeTestFile(t, filepath.Join(output, bulkAppsDir, "coolstore", "output.yaml"), "[]\n")
+	writeTestFile(t, filepath.Join(output, bulkAppsDir, "coolstore", "dependencies.yaml"), "[]\n")
+	if err := compressFile(filepath.Join(output, bulkAppsDir, "coolstore", "output.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	apps, err := listBulkApps(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apps) != 1 || apps[0].analysis != filepath.Join(output, bulkAppsDir, "coolstore", "output.yaml.gz") ||
+		apps[0].deps != filepath.Join(output, bulkAppsDir, "coolstore", "dependencies.yaml") {
+		t.Errorf("listBulkApps() = %v, want coolstore with its compressed output", apps)
+	}
+}
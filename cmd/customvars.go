@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/analyzer-lsp/engine/labels"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// incidentVariables exposes incident variables to label selectors in the
+// same key=value form the analyzer uses for --incident-selector
+type incidentVariables map[string]interface{}
+
+func (v incidentVariables) GetLabels() []string {
+	if len(v) == 0 {
+		// allows Not selectors to match incidents without variables
+		return []string{""}
+	}
+	s := []string{}
+	for k, val := range v {
+		s = append(s, fmt.Sprintf("%s=%v", k, val))
+	}
+	return s
+}
+
+// matchVariables matches like the analyzer does, a dotted value also
+// matches values it is a prefix of, e.g. a package matches sub packages
+func matchVariables(elem string, items []string) bool {
+	for _, i := range items {
+		if strings.Contains(elem, ".") && strings.Contains(i, fmt.Sprintf("%v.", elem)) {
+			return true
+		}
+		if i == elem {
+			return true
+		}
+	}
+	return false
+}
+
+// engineIncidentSelector returns the incident selector passed to the
+// analyzer, with custom variables the selector is applied by kantra once
+// the variables are added to the incidents
+func (a *analyzeCommand) engineIncidentSelector() string {
+	if len(a.customVars) > 0 {
+		return ""
+	}
+	return a.incidentSelector
+}
+
+func (a *analyzeCommand) validateCustomVars() error {
+	for name := range a.customVars {
+		if name == "" || strings.ContainsAny(name, " =()!&|") {
+			return fmt.Errorf("invalid custom variable name %q", name)
+		}
+	}
+	if len(a.customVars) > 0 && a.incidentSelector != "" {
+		_, err := labels.NewLabelSelector[incidentVariables](a.incidentSelector, matchVariables)
+		if err != nil {
+			return fmt.Errorf("%w invalid incident selector %s", err, a.incidentSelector)
+		}
+	}
+	return nil
+}
+
+// applyCustomVars adds custom variables to all incidents, variables set
+// by rules take precedence, and filters incidents by the incident selector
+func (a *analyzeCommand) applyCustomVars(rulesets []outputv1.RuleSet) ([]outputv1.RuleSet, error) {
+	if len(a.customVars) == 0 {
+		return rulesets, nil
+	}
+	var selector *labels.LabelSelector[incidentVariables]
+	if a.incidentSelector != "" {
+		var err error
+		selector, err = labels.NewLabelSelector[incidentVariables](a.incidentSelector, matchVariables)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i := range rulesets {
+		rs := &rulesets[i]
+		for ruleID, violation := range rs.Violations {
+			incidents := []outputv1.Incident{}
+			for _, incident := range violation.Incidents {
+				if incident.Variables == nil {
+					incident.Variables = map[string]interface{}{}
+				}
+				for name, val := range a.customVars {
+					if _, ok := incident.Variables[name]; !ok {
+						incident.Variables[name] = val
+					}
+				}
+				if selector != nil {
+					matched, err := selector.Matches(incidentVariables(incident.Variables))
+					if err != nil {
+						return nil, err
+					}
+					if !matched {
+						continue
+					}
+				}
+				incidents = append(incidents, incident)
+			}
+			if len(incidents) == 0 {
+				delete(rs.Violations, ruleID)
+				rs.Unmatched = append(rs.Unmatched, ruleID)
+				continue
+			}
+			violation.Incidents = incidents
+			rs.Violations[ruleID] = violation
+		}
+		sort.Strings(rs.Unmatched)
+	}
+	return rulesets, nil
+}
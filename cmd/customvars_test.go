@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func Test_analyzeCommand_applyCustomVars(t *testing.T) {
+	rulesets := func() []outputv1.RuleSet {
+		return []outputv1.RuleSet{
+			{
+				Name: "test",
+				Violations: map[string]outputv1.Violation{
+					"rule-000": {
+						Incidents: []outputv1.Incident{
+							{URI: "file:///a", Variables: map[string]interface{}{"package": "io.konveyor.demo"}},
+							{URI: "file:///b", Variables: map[string]interface{}{"team": "billing"}},
+						},
+					},
+					"rule-001": {
+						Incidents: []outputv1.Incident{
+							{URI: "file:///c", Variables: map[string]interface{}{"team": "billing"}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	a := &analyzeCommand{customVars: map[string]string{"team": "payments"}}
+	got, err := a.applyCustomVars(rulesets())
+	if err != nil {
+		t.Fatal(err)
+	}
+	incidents := got[0].Violations["rule-000"].Incidents
+	if incidents[0].Variables["team"] != "payments" || incidents[1].Variables["team"] != "billing" {
+		t.Errorf("unexpected variables %v, %v", incidents[0].Variables, incidents[1].Variables)
+	}
+
+	a.incidentSelector = "team=payments"
+	got, err = a.applyCustomVars(rulesets())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got[0].Violations) != 1 || len(got[0].Violations["rule-000"].Incidents) != 1 {
+		t.Errorf("unexpected violations after selecting %v", got[0].Violations)
+	}
+	if !reflect.DeepEqual(got[0].Unmatched, []string{"rule-001"}) {
+		t.Errorf("unmatched = %v, want [rule-001]", got[0].Unmatched)
+	}
+	if a.engineIncidentSelector() != "" {
+		t.Errorf("incident selector must not be passed to the analyzer with custom variables")
+	}
+}
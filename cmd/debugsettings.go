@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/konveyor/analyzer-lsp/provider"
+)
+
+// debugSettingsFile is the redacted copy of the provider settings written
+// to the output dir with --debug-settings
+const debugSettingsFile = "settings.json"
+
+// redactedValue replaces credentials in the debug settings
+const redactedValue = "REDACTED"
+
+// settingsPlaceholders returns the host paths replaced in the debug
+// settings, longest first so that nested paths get the closest placeholder
+func (a *analyzeCommand) settingsPlaceholders() [][2]string {
+	paths := map[string]string{
+		"$INPUT":      a.input,
+		"$KANTRA_DIR": a.kantraDir,
+		"$TMPDIR":     os.TempDir(),
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths["$HOME"] = home
+	}
+	placeholders := [][2]string{}
+	for placeholder, path := range paths {
+		if path == "" || path == string(filepath.Separator) {
+			continue
+		}
+		placeholders = append(placeholders, [2]string{filepath.Clean(path), placeholder})
+	}
+	sort.Slice(placeholders, func(i, j int) bool {
+		if len(placeholders[i][0]) != len(placeholders[j][0]) {
+			return len(placeholders[i][0]) > len(placeholders[j][0])
+		}
+		return placeholders[i][1] < placeholders[j][1]
+	})
+	return placeholders
+}
+
+// redactProxyURL hides the password of a proxy URL
+func redactProxyURL(proxy string) string {
+	u, err := url.Parse(proxy)
+	if err != nil || u.User == nil {
+		return proxy
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redactedValue)
+	}
+	return u.String()
+}
+
+// redactAddress hides the port of a provider address, ports are picked per
+// run
+func redactAddress(address string) string {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	return net.JoinHostPort(host, "$PORT")
+}
+
+// redactSettings returns the provider settings without credentials, ports
+// and host paths
+func redactSettings(configs []provider.Config, placeholders [][2]string) ([]byte, error) {
+	redacted := make([]provider.Config, len(configs))
+	for i, config := range configs {
+		if config.JWTToken != "" {
+			config.JWTToken = redactedValue
+		}
+		if config.Address != "" {
+			config.Address = redactAddress(config.Address)
+		}
+		if config.Proxy != nil {
+			proxy := *config.Proxy
+			proxy.HTTPProxy = redactProxyURL(proxy.HTTPProxy)
+			proxy.HTTPSProxy = redactProxyURL(proxy.HTTPSProxy)
+			config.Proxy = &proxy
+		}
+		redacted[i] = config
+	}
+	data, err := json.MarshalIndent(&redacted, "", "	")
+	if err != nil {
+		return nil, err
+	}
+	settings := string(data)
+	for _, placeholder := range placeholders {
+		// paths are escaped in json, e.g. the separators on windows
+		path, err := json.Marshal(placeholder[0])
+		if err != nil {
+			return nil, err
+		}
+		settings = strings.ReplaceAll(settings, strings.Trim(string(path), `"`), placeholder[1])
+	}
+	return []byte(settings), nil
+}
+
+// writeDebugSettings writes the provider settings of the analysis, redacted,
+// to the output dir. Providers get their settings in memory, the copy is
+// for troubleshooting only.
+func (a *analyzeCommand) writeDebugSettings(configs []provider.Config) error {
+	if !a.debugSettings {
+		return nil
+	}
+	data, err := redactSettings(configs, a.settingsPlaceholders())
+	if err != nil {
+		return fmt.Errorf("%w failed to redact provider settings", err)
+	}
+	settingsPath := filepath.Join(a.output, debugSettingsFile)
+	if err := os.WriteFile(settingsPath, data, 0644); err != nil {
+		return fmt.Errorf("%w failed to write provider settings", err)
+	}
+	a.log.Info("wrote redacted provider settings", "file", settingsPath)
+	return nil
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// incident variable with the number of reports merged into the incident
+	reportsVariable = "kantra.io/reports"
+	// incident variable with the distinct URIs of the merged reports
+	provenanceVariable = "kantra.io/provenance"
+)
+
+// incidentKey identifies an incident reported more than once, e.g. by the
+// java and builtin providers or by several modules of a project
+type incidentKey struct {
+	ruleID  string
+	uri     string
+	line    int
+	message string
+}
+
+// normalizeIncidentURI cleans file URIs so that the same file reported
+// through different paths compares equal
+func normalizeIncidentURI(u uri.URI) string {
+	if !strings.HasPrefix(string(u), "file:") {
+		return string(u)
+	}
+	return filepath.ToSlash(filepath.Clean(u.Filename()))
+}
+
+// dedupIncidents merges incidents of a rule with the same location and
+// message, the first one is kept and tagged with the merged reports.
+// It returns the number of incidents removed.
+func dedupIncidents(rulesets []outputv1.RuleSet) int {
+	removed := 0
+	for i := range rulesets {
+		rs := &rulesets[i]
+		for ruleID, violation := range rs.Violations {
+			seen := map[incidentKey]int{}
+			provenance := map[int][]string{}
+			incidents := []outputv1.Incident{}
+			for _, incident := range violation.Incidents {
+				key := incidentKey{ruleID: ruleID, uri: normalizeIncidentURI(incident.URI), message: incident.Message}
+				if incident.LineNumber != nil {
+					key.line = *incident.LineNumber
+				}
+				idx, ok := seen[key]
+				if !ok {
+					seen[key] = len(incidents)
+					provenance[len(incidents)] = []string{string(incident.URI)}
+					incidents = append(incidents, incident)
+					continue
+				}
+				removed++
+				provenance[idx] = append(provenance[idx], string(incident.URI))
+			}
+			if len(incidents) == len(violation.Incidents) {
+				continue
+			}
+			for idx, uris := range provenance {
+				if len(uris) == 1 {
+					continue
+				}
+				variables := map[string]interface{}{}
+				for k, v := range incidents[idx].Variables {
+					variables[k] = v
+				}
+				variables[reportsVariable] = len(uris)
+				distinct := uniqueStrings(uris)
+				if len(distinct) > 1 {
+					variables[provenanceVariable] = distinct
+				}
+				incidents[idx].Variables = variables
+			}
+			violation.Incidents = incidents
+			rs.Violations[ruleID] = violation
+		}
+	}
+	return removed
+}
+
+func uniqueStrings(s []string) []string {
+	set := map[string]bool{}
+	unique := []string{}
+	for _, v := range s {
+		if !set[v] {
+			set[v] = true
+			unique = append(unique, v)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+// postProcessRuleSets applies the changes kantra makes to the analyzer
+// results before they are written out
+func (a *analyzeCommand) postProcessRuleSets(rulesets []outputv1.RuleSet) ([]outputv1.RuleSet, error) {
+	a.unsnapshotURIs(rulesets)
+	a.relinkURIs(rulesets)
+	if removed := dedupIncidents(rulesets); removed > 0 {
+		a.log.V(1).Info("removed duplicate incidents", "count", removed)
+	}
+	addProviderLabels(rulesets)
+	a.addSourceLinks(rulesets)
+	a.addBlame(rulesets)
+	if a.relativePaths {
+		a.relativizeURIs(rulesets)
+	}
+	if a.engineSettings.noCodeSnips {
+		removeCodeSnips(rulesets)
+	}
+	return a.applyCustomVars(rulesets)
+}
+
+// postProcessOutput applies postProcessRuleSets to an output file written
+// by the analyzer container, the file is only rewritten when it changed
+func (a *analyzeCommand) postProcessOutput() error {
+	outputPath := filepath.Join(a.output, "output.yaml")
+	rulesets, err := readRuleSetsOutput(outputPath)
+	if err != nil {
+		return fmt.Errorf("%w failed to read analysis output %s", err, outputPath)
+	}
+	// rulesets are changed in place
+	before, err := yaml.Marshal(rulesets)
+	if err != nil {
+		return err
+	}
+	rulesets, err = a.postProcessRuleSets(rulesets)
+	if err != nil {
+		return err
+	}
+	b, err := yaml.Marshal(rulesets)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(before, b) {
+		a.log.V(1).Info("analysis output unchanged by post-processing", "output", outputPath)
+		return nil
+	}
+	return os.WriteFile(outputPath, b, 0644)
+}
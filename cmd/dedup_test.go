@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"gopkg.in/yaml.v2"
+)
+
+func Test_dedupIncidents(t *testing.T) {
+	line := func(n int) *int { return &n }
+	rulesets := []outputv1.RuleSet{
+		{
+			Name: "test",
+			Violations: map[string]outputv1.Violation{
+				"rule-00010": {
+					Incidents: []outputv1.Incident{
+						{URI: "file:///app/src/Foo.java", Message: "use jakarta", LineNumber: line(3), Variables: map[string]interface{}{"package": "javax"}},
+						{URI: "file:///app/./src/Foo.java", Message: "use jakarta", LineNumber: line(3)},
+						{URI: "file:///app/src/Foo.java", Message: "use jakarta", LineNumber: line(3)},
+						{URI: "file:///app/src/Foo.java", Message: "use jakarta", LineNumber: line(7)},
+						{URI: "file:///app/src/Bar.java", Message: "use jakarta", LineNumber: line(3)},
+					},
+				},
+				"rule-00020": {
+					Incidents: []outputv1.Incident{
+						{URI: "file:///app/pom.xml", Message: "update"},
+						{URI: "file:///app/pom.xml", Message: "update dependency"},
+					},
+				},
+			},
+		},
+	}
+	removed := dedupIncidents(rulesets)
+	if removed != 2 {
+		t.Errorf("dedupIncidents() removed %d incidents, want 2", removed)
+	}
+	incidents := rulesets[0].Violations["rule-00010"].Incidents
+	if len(incidents) != 3 {
+		t.Fatalf("got %d incidents, want 3", len(incidents))
+	}
+	want := map[string]interface{}{
+		"package":          "javax",
+		reportsVariable:    3,
+		provenanceVariable: []string{"file:///app/./src/Foo.java", "file:///app/src/Foo.java"},
+	}
+	if !reflect.DeepEqual(incidents[0].Variables, want) {
+		t.Errorf("variables of merged incident = %v, want %v", incidents[0].Variables, want)
+	}
+	if incidents[1].Variables != nil {
+		t.Errorf("incident without duplicates must not be tagged, got %v", incidents[1].Variables)
+	}
+	if got := len(rulesets[0].Violations["rule-00020"].Incidents); got != 2 {
+		t.Errorf("incidents with different messages were merged, got %d incidents", got)
+	}
+}
+
+func Test_analyzeCommand_postProcessOutput(t *testing.T) {
+	output := t.TempDir()
+	outputPath := filepath.Join(output, "output.yaml")
+	write := func(incidents ...outputv1.Incident) {
+		b, err := yaml.Marshal([]outputv1.RuleSet{{Name: "test", Violations: map[string]outputv1.Violation{
+			"rule-00010": {Labels: []string{"konveyor.io/provider=java"}, Incidents: incidents},
+		}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeTestFile(t, outputPath, string(b))
+		old := time.Now().Add(-time.Hour)
+		if err := os.Chtimes(outputPath, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	modified := func() bool {
+		info, err := os.Stat(outputPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return time.Since(info.ModTime()) < time.Hour
+	}
+	a := &analyzeCommand{output: output, log: logr.Discard()}
+
+	write(outputv1.Incident{URI: "file:///app/pom.xml", Message: "update"})
+	if err := a.postProcessOutput(); err != nil {
+		t.Fatal(err)
+	}
+	if modified() {
+		t.Errorf("output unchanged by post-processing must not be rewritten")
+	}
+
+	write(outputv1.Incident{URI: "file:///app/pom.xml", Message: "update"}, outputv1.Incident{URI: "file:///app/pom.xml", Message: "update"})
+	if err := a.postProcessOutput(); err != nil {
+		t.Fatal(err)
+	}
+	if !modified() {
+		t.Errorf("output with duplicate incidents must be rewritten")
+	}
+}
@@ -0,0 +1,390 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bombsimon/logrusr/v3"
+	"github.com/devfile/alizer/pkg/apis/recognizer"
+	"github.com/go-logr/logr"
+	"github.com/konveyor-ecosystem/kantra/pkg/container"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/analyzer-lsp/provider"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// providers able to list dependencies
+var dependencyProviders = []string{javaProvider, goProvider}
+
+func NewDependenciesCommand(log logr.Logger) *cobra.Command {
+	depsCmd := &analyzeCommand{
+		log:            log,
+		cleanup:        true,
+		mode:           string(provider.FullAnalysisMode),
+		providerStatus: newProviderStatus(os.Stdout),
+	}
+	var sbom bool
+
+	dependenciesCommand := &cobra.Command{
+		Use:   "dependencies",
+		Short: "List the dependencies of an application without analyzing it",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if depsCmd.runLocal {
+				if err := depsCmd.setKantraDir(); err != nil {
+					log.Error(err, "unable to get analyze reqs")
+					return err
+				}
+			}
+			if err := depsCmd.validateDependencies(); err != nil {
+				log.Error(err, "failed to validate flags")
+				return err
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if val, err := cmd.Flags().GetUint32(logLevelFlag); err == nil {
+				depsCmd.logLevel = &val
+			}
+			if val, err := cmd.Flags().GetBool(noCleanupFlag); err == nil {
+				depsCmd.cleanup = !val
+			}
+			if depsCmd.mavenSettingsDir != "" {
+				defer os.RemoveAll(depsCmd.mavenSettingsDir)
+			}
+			if depsCmd.registryAuthDir != "" {
+				defer os.RemoveAll(depsCmd.registryAuthDir)
+			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			var err error
+			if depsCmd.runLocal {
+				err = depsCmd.RunDependenciesContainerless(ctx)
+			} else {
+				err = depsCmd.RunDependencies(ctx)
+			}
+			if err != nil {
+				log.Error(err, "failed to get dependencies")
+				return err
+			}
+			if sbom {
+				if err := depsCmd.writeSBOM(); err != nil {
+					log.Error(err, "failed to write SBOM")
+					return err
+				}
+			}
+			fmt.Fprintf(os.Stdout, "dependencies written to %s\n", filepath.Join(depsCmd.output, "dependencies.yaml"))
+			return nil
+		},
+	}
+	dependenciesCommand.Flags().StringVarP(&depsCmd.input, "input", "i", "", "path to application source code or a binary")
+	dependenciesCommand.Flags().StringVarP(&depsCmd.output, "output", "o", "", "path to the directory for dependencies output")
+	dependenciesCommand.Flags().BoolVar(&depsCmd.overwrite, "overwrite", false, "overwrite output directory")
+	dependenciesCommand.Flags().StringVar(&depsCmd.mavenSettingsFile, "maven-settings", "", "path to a custom maven settings file to use")
+	dependenciesCommand.Flags().StringVar(&depsCmd.mavenMirror, "maven-mirror", "", "URL of a maven repository mirror to resolve dependencies from, generates maven settings instead of --maven-settings. Can also be set with KANTRA_MAVEN_MIRROR")
+	dependenciesCommand.Flags().StringVar(&depsCmd.mavenMirrorOf, "maven-mirror-of", "*", "repositories the maven mirror replaces")
+	dependenciesCommand.Flags().StringVar(&depsCmd.mavenCredentialsID, "maven-credentials-id", "", "id of the maven mirror credentials, read from KANTRA_MAVEN_USERNAME and KANTRA_MAVEN_PASSWORD")
+	dependenciesCommand.Flags().StringArrayVar(&depsCmd.registryAuthFiles, "registry-auth-file", []string{}, "path to private registry credentials of the providers, one of settings.xml (maven), .npmrc or pip.conf. Can be repeated")
+	dependenciesCommand.Flags().StringVar(&depsCmd.jvmMaxMem, "jvm-max-mem", "", "max heap of the jvm of the java provider, e.g. 8g. Can also be set with JVM_MAX_MEM, sized by the lines of java code of the input in containerless mode by default")
+	dependenciesCommand.Flags().StringVar(&depsCmd.jvmGC, "jvm-gc", "", "garbage collector of the jvm of the java provider, one of g1, parallel, serial, z or shenandoah")
+	dependenciesCommand.Flags().StringVar(&depsCmd.jdtlsWorkspace, "jdtls-workspace", "", "path to a directory to keep the jdtls workspace cache in between containerless runs")
+	dependenciesCommand.Flags().BoolVar(&depsCmd.reuseJdtlsWorkspace, "reuse-jdtls-workspace", true, "reuse the jdtls workspace of the input from previous containerless runs, cached in the kantra dir")
+	dependenciesCommand.Flags().BoolVar(&depsCmd.runLocal, "run-local", true, "get Java dependencies in containerless mode")
+	dependenciesCommand.Flags().BoolVar(&sbom, "sbom", false, "also write the dependencies as a CycloneDX SBOM to sbom.cdx.json")
+	dependenciesCommand.MarkFlagRequired("input")
+	dependenciesCommand.MarkFlagRequired("output")
+	return dependenciesCommand
+}
+
+// validateDependencies validates the flags of the dependencies command
+func (a *analyzeCommand) validateDependencies() error {
+	stat, err := os.Stat(a.input)
+	if err != nil {
+		return fmt.Errorf("%w failed to stat input path %s", err, a.input)
+	}
+	a.input, err = filepath.Abs(a.input)
+	if err != nil {
+		return fmt.Errorf("%w failed to get absolute path for input %s", err, a.input)
+	}
+	if !stat.IsDir() {
+		switch filepath.Ext(a.input) {
+		case JavaArchive, WebArchive, EnterpriseArchive:
+		default:
+			return fmt.Errorf("invalid file type %v", filepath.Ext(a.input))
+		}
+		a.isFileInput = true
+	}
+	if err := a.CheckOverwriteOutput(); err != nil {
+		return err
+	}
+	a.output, err = filepath.Abs(a.output)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(a.output, os.ModePerm); err != nil {
+		return fmt.Errorf("%w failed to create output dir %s", err, a.output)
+	}
+	if a.mavenSettingsFile != "" {
+		if _, err := os.Stat(a.mavenSettingsFile); err != nil {
+			return fmt.Errorf("%w failed to stat maven settings file at path %s", err, a.mavenSettingsFile)
+		}
+		if a.mavenSettingsFile, err = filepath.Abs(a.mavenSettingsFile); err != nil {
+			return err
+		}
+	}
+	if err := a.writeMavenSettings(); err != nil {
+		return err
+	}
+	if err := a.writeRegistryAuth(); err != nil {
+		return err
+	}
+	return a.validateJvm()
+}
+
+// RunDependenciesContainerless starts the java provider on the host and
+// writes its dependencies without running rules
+func (a *analyzeCommand) RunDependenciesContainerless(ctx context.Context) error {
+	if err := a.ValidateContainerless(ctx); err != nil {
+		return err
+	}
+	if a.reqMap == nil {
+		a.reqMap = make(map[string]string)
+	}
+	defer func() {
+		if err := a.cleanlsDirs(); err != nil {
+			a.log.Error(err, "failed to clean language server directories")
+		}
+	}()
+	logFile, err := os.Create(filepath.Join(a.output, "dependencies.log"))
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	// log output of the providers to file
+	logrusLog := logrus.New()
+	logrusLog.SetOutput(logFile)
+	logrusLog.SetFormatter(&logrus.TextFormatter{})
+	logrusLog.SetLevel(logrus.Level(logLevel))
+	providerLog := logrusr.New(logrusLog)
+
+	if err := a.setBinMapContainerless(); err != nil {
+		return err
+	}
+	configs, err := a.createProviderConfigsContainerless()
+	if err != nil {
+		return err
+	}
+	providers, _ := a.setInternalProviders(configs, providerLog)
+	javaClient, ok := providers[javaProvider]
+	if !ok {
+		return fmt.Errorf("java provider is not configured")
+	}
+	needProviders := map[string]provider.InternalProviderClient{javaProvider: javaClient}
+	if err := a.startProvidersContainerless(ctx, needProviders); err != nil {
+		return err
+	}
+	defer javaClient.Stop()
+
+	a.log.Info("running dependency analysis")
+	stopProgress := a.startProgress("resolving dependencies")
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	a.DependencyOutputContainerless(ctx, needProviders, "dependencies.yaml", wg)
+	stopProgress()
+	return nil
+}
+
+// RunDependencies starts the provider containers and lists their
+// dependencies with the dependency command of the analyzer
+func (a *analyzeCommand) RunDependencies(ctx context.Context) error {
+	if a.providersMap == nil {
+		a.providersMap = make(map[string]ProviderInit)
+	}
+	foundProviders := []string{}
+	if a.isFileInput {
+		foundProviders = append(foundProviders, javaProvider)
+	} else {
+		languages, err := recognizer.Analyze(a.input)
+		if err != nil {
+			return fmt.Errorf("%w failed to determine languages for input", err)
+		}
+		found, err := a.setProviders(languages, foundProviders)
+		if err != nil {
+			return err
+		}
+		for _, p := range found {
+			if slices.Contains(dependencyProviders, p) {
+				foundProviders = append(foundProviders, p)
+			}
+		}
+		if len(foundProviders) == 0 {
+			foundJava, err := a.detectJavaProviderFallback()
+			if err != nil {
+				return err
+			}
+			if foundJava {
+				foundProviders = append(foundProviders, javaProvider)
+			}
+		}
+	}
+	if len(foundProviders) == 0 {
+		return fmt.Errorf("no provider able to list dependencies found for input, supported providers are %s",
+			strings.Join(dependencyProviders, ", "))
+	}
+	if err := a.setProviderInitInfo(foundProviders); err != nil {
+		return err
+	}
+	defer func() {
+		if err := a.CleanAnalysisResources(context.TODO()); err != nil {
+			a.log.Error(err, "failed to clean temporary directories")
+		}
+	}()
+	networkName, err := a.createContainerNetwork()
+	if err != nil {
+		return err
+	}
+	volName, err := a.createContainerVolume()
+	if err != nil {
+		return err
+	}
+	if err := a.RunProviders(ctx, networkName, volName, 5); err != nil {
+		return err
+	}
+	if err := a.checkProviderContainers(ctx); err != nil {
+		return err
+	}
+	volumes := map[string]string{
+		volName:  a.sourceMountPath(),
+		a.output: OutputPath,
+	}
+	configVols, err := a.getConfigVolumes()
+	if err != nil {
+		return err
+	}
+	maps.Copy(volumes, configVols)
+	maps.Copy(volumes, a.symlinkVolumes)
+
+	logFile, err := os.Create(filepath.Join(a.output, "dependencies.log"))
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	args := []string{
+		fmt.Sprintf("--provider-settings=%s", ProviderSettingsMountPath),
+		fmt.Sprintf("--output-file=%s", DepsOutputMountPath),
+	}
+	a.log.Info("running dependency analysis", "input", a.input, "output", a.output)
+	stopProgress := a.startProgress("resolving dependencies")
+	err = container.NewContainer().Run(
+		ctx,
+		container.WithImage(Settings.RunnerImage),
+		container.WithPlatform(a.imagePlatform(ctx, Settings.RunnerImage)),
+		container.WithLog(a.log.V(1)),
+		container.WithVolumes(volumes),
+		container.WithStdout(logFile),
+		container.WithStderr(logFile),
+		container.WithEntrypointArgs(args...),
+		container.WithName(a.containerName("dependencies")),
+		container.WithEntrypointBin("/usr/local/bin/konveyor-analyzer-dep"),
+		container.WithNetwork(fmt.Sprintf("container:%v", a.providerContainerNames[0])),
+		container.WithContainerToolBin(Settings.ContainerBinary),
+		container.WithCleanup(a.cleanup),
+	)
+	stopProgress()
+	if err != nil {
+		return err
+	}
+	return a.getProviderLogs(ctx)
+}
+
+type cycloneDXComponent struct {
+	Type    string `json:"type"`
+	BOMRef  string `json:"bom-ref"`
+	Group   string `json:"group,omitempty"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Scope   string `json:"scope,omitempty"`
+	PURL    string `json:"purl,omitempty"`
+}
+
+type cycloneDXBOM struct {
+	BOMFormat   string `json:"bomFormat"`
+	SpecVersion string `json:"specVersion"`
+	Version     int    `json:"version"`
+	Metadata    struct {
+		Timestamp string `json:"timestamp"`
+		Tools     struct {
+			Components []cycloneDXComponent `json:"components"`
+		} `json:"tools"`
+		Component cycloneDXComponent `json:"component"`
+	} `json:"metadata"`
+	Components []cycloneDXComponent `json:"components"`
+}
+
+// sbomComponent converts a dependency to a CycloneDX component
+func sbomComponent(providerName string, dep *outputv1.Dep) cycloneDXComponent {
+	c := cycloneDXComponent{Type: "library", Name: dep.Name, Version: dep.Version, Scope: "required"}
+	if dep.Indirect {
+		c.Scope = "optional"
+	}
+	groupID, _ := dep.Extras["groupId"].(string)
+	artifactID, _ := dep.Extras["artifactId"].(string)
+	switch {
+	case providerName == javaProvider && groupID != "" && artifactID != "":
+		c.Group, c.Name = groupID, artifactID
+		c.PURL = fmt.Sprintf("pkg:maven/%s/%s@%s", groupID, artifactID, dep.Version)
+	case providerName == goProvider:
+		c.PURL = fmt.Sprintf("pkg:golang/%s@%s", dep.Name, dep.Version)
+	}
+	c.BOMRef = c.PURL
+	if c.BOMRef == "" {
+		c.BOMRef = fmt.Sprintf("%s:%s@%s", providerName, dep.Name, dep.Version)
+	}
+	return c
+}
+
+// writeSBOM converts dependencies.yaml to a CycloneDX SBOM
+func (a *analyzeCommand) writeSBOM() error {
+	data, err := os.ReadFile(filepath.Join(a.output, "dependencies.yaml"))
+	if err != nil {
+		return err
+	}
+	items := []outputv1.DepsFlatItem{}
+	if err := yaml.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	bom := cycloneDXBOM{BOMFormat: "CycloneDX", SpecVersion: "1.5", Version: 1, Components: []cycloneDXComponent{}}
+	bom.Metadata.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	bom.Metadata.Tools.Components = []cycloneDXComponent{{Type: "application", BOMRef: "kantra", Name: "kantra", Version: Version}}
+	bom.Metadata.Component = cycloneDXComponent{Type: "application", BOMRef: a.inputShortName(), Name: a.inputShortName()}
+	seen := map[string]bool{}
+	for _, item := range items {
+		for _, dep := range item.Dependencies {
+			if dep == nil {
+				continue
+			}
+			c := sbomComponent(item.Provider, dep)
+			if seen[c.BOMRef] {
+				continue
+			}
+			seen[c.BOMRef] = true
+			bom.Components = append(bom.Components, c)
+		}
+	}
+	out, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(a.output, "sbom.cdx.json"), out, 0644)
+}
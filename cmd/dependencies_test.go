@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_analyzeCommand_writeSBOM(t *testing.T) {
+	output := t.TempDir()
+	writeTestFile(t, filepath.Join(output, "dependencies.yaml"), `- fileURI: file:///app/pom.xml
+  provider: java
+  dependencies:
+  - name: org.example.lib
+    version: "1.0"
+    extras:
+      groupId: org.example
+      artifactId: lib
+  - name: io.netty.netty-common
+    version: 4.1.0
+    indirect: true
+- fileURI: file:///app/module/pom.xml
+  provider: java
+  dependencies:
+  - name: org.example.lib
+    version: "1.0"
+    extras:
+      groupId: org.example
+      artifactId: lib
+`)
+	a := &analyzeCommand{input: "/home/user/app", output: output}
+	if err := a.writeSBOM(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(output, "sbom.cdx.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bom := cycloneDXBOM{}
+	if err := json.Unmarshal(data, &bom); err != nil {
+		t.Fatal(err)
+	}
+	if bom.BOMFormat != "CycloneDX" || bom.Metadata.Component.Name != "app" {
+		t.Errorf("unexpected SBOM metadata: %+v", bom.Metadata)
+	}
+	if len(bom.Components) != 2 {
+		t.Fatalf("got %d components, want 2", len(bom.Components))
+	}
+	lib := bom.Components[0]
+	if lib.Group != "org.example" || lib.Name != "lib" || lib.PURL != "pkg:maven/org.example/lib@1.0" || lib.Scope != "required" {
+		t.Errorf("unexpected component %+v", lib)
+	}
+	if bom.Components[1].Scope != "optional" || bom.Components[1].PURL != "" {
+		t.Errorf("unexpected indirect component %+v", bom.Components[1])
+	}
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/konveyor/analyzer-lsp/provider"
+)
+
+// files of dependencies, e.g. of a maven repository or dirs of binary,
+// python or node packages
+var depFileExts = []string{".jar", ".war", ".ear", ".pom", ".whl", ".egg", ".tgz", ".nupkg", ".dll"}
+var depFileNames = []string{"package.json", "METADATA", "PKG-INFO"}
+
+// errDepFound ends the walk of a dependency folder at its first dependency
+var errDepFound = errors.New("dependency found")
+
+func isDepFile(name string) bool {
+	for _, ext := range depFileExts {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	for _, depName := range depFileNames {
+		if name == depName {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDeps reports whether dir has files of dependencies
+func hasDeps(dir string) (bool, error) {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && isDepFile(d.Name()) {
+			return errDepFound
+		}
+		return nil
+	})
+	if errors.Is(err, errDepFound) {
+		return true, nil
+	}
+	return false, err
+}
+
+// validateDepFolders checks the dependency folders are dirs of dependencies,
+// as a folder given by mistake, e.g. the input, is otherwise only reported
+// by the provider once started
+func (a *analyzeCommand) validateDepFolders() error {
+	for _, folder := range a.depFolders {
+		stat, err := os.Stat(folder)
+		if err != nil {
+			return fmt.Errorf("%w failed to stat dependency folder %v", err, folder)
+		}
+		if !stat.IsDir() {
+			return fmt.Errorf("dependency folder %v is not a directory", folder)
+		}
+		found, err := hasDeps(folder)
+		if err != nil {
+			return fmt.Errorf("%w failed to read dependency folder %v", err, folder)
+		}
+		if !found {
+			return fmt.Errorf("dependency folder %v has no dependencies, it must be a maven repository or a dir of jar, wheel, node or nuget packages", folder)
+		}
+	}
+	return nil
+}
+
+// checkDepFolderSettings checks the dependency folders of the provider
+// settings are mounted in the containers of the analysis and of the
+// dependency analysis, e.g. when set in the provider options of
+// ~/.kantra, which fails late when loading the provider settings otherwise
+func checkDepFolderSettings(settingsFile string, volumes map[string]string) error {
+	data, err := os.ReadFile(settingsFile)
+	if err != nil {
+		return err
+	}
+	configs := []provider.Config{}
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return fmt.Errorf("%w failed to parse provider settings %s", err, settingsFile)
+	}
+	for _, config := range configs {
+		for _, init := range config.InitConfig {
+			folders, ok := init.ProviderSpecificConfig["dependencyFolders"]
+			if !ok {
+				continue
+			}
+			list, ok := folders.([]interface{})
+			if !ok {
+				return fmt.Errorf("dependencyFolders of the %s provider settings must be a list of paths", config.Name)
+			}
+			for _, folder := range list {
+				folderPath, ok := folder.(string)
+				if !ok {
+					return fmt.Errorf("dependencyFolders of the %s provider settings must be a list of paths", config.Name)
+				}
+				if !isMounted(folderPath, volumes) {
+					return fmt.Errorf("dependency folder %s of the %s provider settings is not mounted in the containers, give dependency folders with --dependency-folders",
+						folderPath, config.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isMounted reports whether containerPath is in one of the volumes
+func isMounted(containerPath string, volumes map[string]string) bool {
+	containerPath = path.Clean(containerPath)
+	for _, target := range volumes {
+		target = path.Clean(target)
+		if containerPath == target || strings.HasPrefix(containerPath, target+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_validateDepFolders(t *testing.T) {
+	dir := t.TempDir()
+	maven := filepath.Join(dir, "m2")
+	writeTestFile(t, filepath.Join(maven, "org", "acme", "lib", "1.0", "lib-1.0.jar"), "")
+	node := filepath.Join(dir, "node_modules")
+	writeTestFile(t, filepath.Join(node, "express", "package.json"), "{}")
+	source := filepath.Join(dir, "src")
+	writeTestFile(t, filepath.Join(source, "main.py"), "")
+	file := filepath.Join(dir, "lib.jar")
+	writeTestFile(t, file, "")
+
+	tests := []struct {
+		name    string
+		folders []string
+		wantErr string
+	}{
+		{name: "maven repository and node packages", folders: []string{maven, node}},
+		{name: "no dependencies", folders: []string{maven, source}, wantErr: "has no dependencies"},
+		{name: "file", folders: []string{file}, wantErr: "is not a directory"},
+		{name: "missing", folders: []string{filepath.Join(dir, "missing")}, wantErr: "failed to stat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &analyzeCommand{log: logr.Discard(), depFolders: tt.folders}
+			err := a.validateDepFolders()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("validateDepFolders() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("validateDepFolders() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkDepFolderSettings(t *testing.T) {
+	dir := t.TempDir()
+	settings := filepath.Join(dir, "settings.json")
+	volumes := map[string]string{"/home/user/deps": "/opt/input/deps0", dir: ConfigMountPath}
+
+	writeTestFile(t, settings, `[{"name": "python", "initConfig": [{"providerSpecificConfig": {"dependencyFolders": ["/opt/input/deps0/lib"]}}]}]`)
+	if err := checkDepFolderSettings(settings, volumes); err != nil {
+		t.Errorf("checkDepFolderSettings() error = %v", err)
+	}
+	writeTestFile(t, settings, `[{"name": "python", "initConfig": [{"providerSpecificConfig": {"dependencyFolders": ["/home/user/deps"]}}]}]`)
+	err := checkDepFolderSettings(settings, volumes)
+	if err == nil || !strings.Contains(err.Error(), "dependency folder /home/user/deps of the python provider settings is not mounted") {
+		t.Errorf("checkDepFolderSettings() error = %v, want not mounted", err)
+	}
+}
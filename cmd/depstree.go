@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/analyzer-lsp/provider"
+	"gopkg.in/yaml.v2"
+)
+
+// hierarchical dependencies of each module, next to the flat dependencies.yaml
+const depsTreeFile = "dependencies-tree.yaml"
+
+// page of the static report with the dependency trees
+const depsTreePage = "dependencies-tree.html"
+
+// validateDepsTree checks that the analysis retrieves the dependency tree,
+// only the providers running on the host return it to kantra
+func (a *analyzeCommand) validateDepsTree() error {
+	if !a.depsTree {
+		return nil
+	}
+	if !a.runLocal {
+		return fmt.Errorf("deps-tree requires containerless mode, remove --run-local=false")
+	}
+	if a.mode != string(provider.FullAnalysisMode) {
+		return fmt.Errorf("deps-tree requires the dependencies of the full analysis mode")
+	}
+	return nil
+}
+
+// writeDepsTree writes the dependency tree of each module of the providers,
+// where the dependencies added by a dependency are nested under it
+func (a *analyzeCommand) writeDepsTree(ctx context.Context, providers map[string]provider.InternalProviderClient) error {
+	depsTree := []konveyor.DepsTreeItem{}
+	for name, prov := range providers {
+		deps, err := prov.GetDependenciesDAG(ctx)
+		if err != nil {
+			a.log.Error(err, "failed to get dependency tree for provider", "provider", name)
+			continue
+		}
+		for u, ds := range deps {
+			depsTree = append(depsTree, konveyor.DepsTreeItem{
+				Provider:     name,
+				FileURI:      string(u),
+				Dependencies: ds,
+			})
+		}
+	}
+	sort.SliceStable(depsTree, func(i, j int) bool {
+		if depsTree[i].Provider == depsTree[j].Provider {
+			return depsTree[i].FileURI < depsTree[j].FileURI
+		}
+		return depsTree[i].Provider < depsTree[j].Provider
+	})
+	data, err := yaml.Marshal(depsTree)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(a.output, depsTreeFile), data, 0644)
+}
+
+func readDepsTree(path string) ([]konveyor.DepsTreeItem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	depsTree := []konveyor.DepsTreeItem{}
+	if err := yaml.Unmarshal(data, &depsTree); err != nil {
+		return nil, fmt.Errorf("%w failed to parse %s", err, path)
+	}
+	return depsTree, nil
+}
+
+// depsTreeApp is the dependency tree of an application of the report
+type depsTreeApp struct {
+	Name    string
+	Modules []konveyor.DepsTreeItem
+}
+
+var depsTreeTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Dependency trees</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+details { margin-left: 1.5em; }
+summary { cursor: pointer; }
+.leaf { margin-left: 2.6em; }
+.version { color: #6a6e73; }
+.indirect { color: #6a6e73; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>Dependency trees</h1>
+{{- range .}}
+<h2>{{.Name}}</h2>
+{{- range .Modules}}
+<details open>
+<summary><code>{{.FileURI}}</code> ({{.Provider}})</summary>
+{{- range .Dependencies}}{{template "dep" .}}{{end}}
+</details>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+func init() {
+	template.Must(depsTreeTemplate.New("dep").Parse(`{{define "label"}}{{.Dep.Name}} <span class="version">{{.Dep.Version}}</span>{{if .Dep.Indirect}} <span class="indirect">indirect</span>{{end}}{{end}}
+{{- if .AddedDeps}}
+<details>
+<summary>{{template "label" .}}</summary>
+{{- range .AddedDeps}}{{template "dep" .}}{{end}}
+</details>
+{{- else}}
+<div class="leaf">{{template "label" .}}</div>
+{{- end}}`))
+}
+
+// writeDepsTreeReport adds a page with the expandable dependency trees of
+// the applications to the static report, so that transitive dependencies
+// can be traced to the direct dependency adding them
+func (a *analyzeCommand) writeDepsTreeReport() error {
+	if !a.depsTree || a.skipStaticReport {
+		return nil
+	}
+	reportDir := filepath.Join(a.output, "static-report")
+	if _, err := os.Stat(reportDir); err != nil {
+		return nil
+	}
+	trees := map[string]string{filepath.Base(a.input): filepath.Join(a.output, depsTreeFile)}
+	if a.bulk {
+		bulkApps, err := listBulkApps(a.output)
+		if err != nil {
+			return err
+		}
+		trees = map[string]string{}
+		for _, app := range bulkApps {
+			trees[app.name] = filepath.Join(bulkAppDir(a.output, app.name), depsTreeFile)
+		}
+	}
+	apps := []depsTreeApp{}
+	for _, name := range sortedMapKeys(trees) {
+		modules, err := readDepsTree(trees[name])
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		apps = append(apps, depsTreeApp{Name: name, Modules: modules})
+	}
+	if len(apps) == 0 {
+		return nil
+	}
+	page := &bytes.Buffer{}
+	if err := depsTreeTemplate.Execute(page, apps); err != nil {
+		return err
+	}
+	a.log.V(1).Info("wrote static report dependency trees", "page", depsTreePage)
+	return os.WriteFile(filepath.Join(reportDir, depsTreePage), page.Bytes(), 0644)
+}
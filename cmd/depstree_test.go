@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_analyzeCommand_writeDepsTreeReport(t *testing.T) {
+	output := t.TempDir()
+	writeTestFile(t, filepath.Join(output, "static-report", "index.html"), "<html></html>")
+	writeTestFile(t, filepath.Join(output, depsTreeFile), `- fileURI: file:///opt/input/source/pom.xml
+  provider: java
+  dependencies:
+  - dep:
+      name: org.hibernate.hibernate-core
+      version: 5.4.32.Final
+    addedDep:
+    - dep:
+        name: org.jboss.logging.jboss-logging
+        version: 3.4.1.Final
+        indirect: true
+  - dep:
+      name: <script>
+      version: 1.0
+`)
+
+	a := &analyzeCommand{input: "/apps/cart", output: output, depsTree: true, log: logr.Discard()}
+	if err := a.writeDepsTreeReport(); err != nil {
+		t.Fatal(err)
+	}
+	page, err := os.ReadFile(filepath.Join(output, "static-report", depsTreePage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<h2>cart</h2>",
+		"<summary>org.hibernate.hibernate-core <span class=\"version\">5.4.32.Final</span></summary>",
+		"<div class=\"leaf\">org.jboss.logging.jboss-logging <span class=\"version\">3.4.1.Final</span> <span class=\"indirect\">indirect</span></div>",
+		"&lt;script&gt;",
+	} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("writeDepsTreeReport() page does not contain %s:\n%s", want, page)
+		}
+	}
+}
+
+func Test_analyzeCommand_validateDepsTree(t *testing.T) {
+	a := &analyzeCommand{depsTree: true, runLocal: false, mode: "full"}
+	if err := a.validateDepsTree(); err == nil {
+		t.Error("validateDepsTree() accepted container mode")
+	}
+	a.runLocal = true
+	if err := a.validateDepsTree(); err != nil {
+		t.Errorf("validateDepsTree() = %v", err)
+	}
+	a.mode = "source-only"
+	if err := a.validateDepsTree(); err == nil {
+		t.Error("validateDepsTree() accepted source only mode")
+	}
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// nested archives deeper than this are not inspected
+const maxDescriptorArchiveDepth = 3
+
+// descriptorExtensions are the files kept from archives when analyzing
+// descriptors only, compared in lower case
+var descriptorExtensions = map[string]bool{
+	".xml":        true,
+	".properties": true,
+	".yaml":       true,
+	".yml":        true,
+	".json":       true,
+	".mf":         true,
+	".conf":       true,
+}
+
+func isDescriptor(name string) bool {
+	return descriptorExtensions[strings.ToLower(path.Ext(name))]
+}
+
+func isNestedArchive(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case JavaArchive, WebArchive, EnterpriseArchive, ".rar", ".sar":
+		return true
+	}
+	return false
+}
+
+// extractDescriptors replaces a binary input with a dir holding only its
+// descriptors, manifests and config files, including the ones of nested
+// archives, so that rules run on them without decompiling the classes
+func (a *analyzeCommand) extractDescriptors() error {
+	tempDir, err := os.MkdirTemp("", "kantra-descriptors-")
+	if err != nil {
+		return err
+	}
+	// keep the name of the input, it is used in output file and report names
+	dest := filepath.Join(tempDir, filepath.Base(a.input))
+	reader, err := zip.OpenReader(a.input)
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return fmt.Errorf("%w failed to open archive %s", err, a.input)
+	}
+	defer reader.Close()
+	count, err := extractArchiveDescriptors(&reader.Reader, dest, 0)
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return fmt.Errorf("%w failed to extract descriptors of %s", err, a.input)
+	}
+	if count == 0 {
+		os.RemoveAll(tempDir)
+		return fmt.Errorf("no descriptors found in %s", a.input)
+	}
+	a.log.Info("analyzing descriptors of binary without decompilation", "input", a.input, "files", count)
+	a.descriptorsDir = tempDir
+	a.input = dest
+	a.isFileInput = false
+	a.descriptorsOnly = true
+	return nil
+}
+
+// extractArchiveDescriptors writes the descriptors of an archive to dest,
+// nested archives are extracted to a dir named after their entry
+func extractArchiveDescriptors(reader *zip.Reader, dest string, depth int) (int, error) {
+	count := 0
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		target, err := extractPath(dest, f.Name)
+		if err != nil {
+			return count, err
+		}
+		switch {
+		case isNestedArchive(f.Name) && depth < maxDescriptorArchiveDepth:
+			rc, err := f.Open()
+			if err != nil {
+				return count, err
+			}
+			content, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return count, err
+			}
+			nested, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+			if err != nil {
+				// not every file named like an archive is one
+				continue
+			}
+			n, err := extractArchiveDescriptors(nested, target, depth+1)
+			count += n
+			if err != nil {
+				return count, err
+			}
+		case isDescriptor(f.Name):
+			rc, err := f.Open()
+			if err != nil {
+				return count, err
+			}
+			err = writeExtractedFile(target, rc, f.Mode())
+			rc.Close()
+			if err != nil {
+				return count, err
+			}
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func zipBytes(t *testing.T, files map[string][]byte) []byte {
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_extractDescriptors(t *testing.T) {
+	lib := zipBytes(t, map[string][]byte{
+		"META-INF/MANIFEST.MF":     []byte("Manifest-Version: 1.0\n"),
+		"com/example/Lib.class":    {0xCA, 0xFE, 0xBA, 0xBE},
+		"META-INF/persistence.xml": []byte("<persistence/>"),
+	})
+	war := zipBytes(t, map[string][]byte{
+		"WEB-INF/web.xml":                        []byte("<web-app/>"),
+		"WEB-INF/classes/application.properties": []byte("server.port=8080"),
+		"WEB-INF/classes/com/example/App.class":  {0xCA, 0xFE, 0xBA, 0xBE},
+		"WEB-INF/lib/lib.jar":                    lib,
+		"index.jsp":                              []byte("<html/>"),
+	})
+	input := filepath.Join(t.TempDir(), "app.war")
+	if err := os.WriteFile(input, war, 0644); err != nil {
+		t.Fatal(err)
+	}
+	a := &analyzeCommand{log: logr.Discard(), input: input, isFileInput: true}
+	if got, want := a.sourceMountPath(), path.Join(SourceMountPath, "app.war"); got != want {
+		t.Errorf("sourceMountPath() of binary = %s, want %s", got, want)
+	}
+	if err := a.extractDescriptors(); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(a.descriptorsDir)
+	if a.isFileInput || !a.descriptorsOnly || filepath.Base(a.input) != "app.war" {
+		t.Errorf("input was not replaced by descriptors dir: %s", a.input)
+	}
+	if got := a.sourceMountPath(); got != SourceMountPath {
+		t.Errorf("sourceMountPath() of descriptors = %s, want %s", got, SourceMountPath)
+	}
+	for _, name := range []string{"WEB-INF/web.xml", "WEB-INF/classes/application.properties",
+		"WEB-INF/lib/lib.jar/META-INF/MANIFEST.MF", "WEB-INF/lib/lib.jar/META-INF/persistence.xml"} {
+		if _, err := os.Stat(filepath.Join(a.input, name)); err != nil {
+			t.Errorf("descriptor %s was not extracted: %v", name, err)
+		}
+	}
+	for _, name := range []string{"WEB-INF/classes/com/example/App.class", "index.jsp", "WEB-INF/lib/lib.jar/com/example/Lib.class"} {
+		if _, err := os.Stat(filepath.Join(a.input, name)); err == nil {
+			t.Errorf("%s must not be extracted", name)
+		}
+	}
+}
@@ -1,8 +1,9 @@
 package cmd
 
 import (
-	"fmt"
 	"github.com/konveyor/analyzer-lsp/provider"
+
+	kantraprovider "github.com/konveyor-ecosystem/kantra/pkg/provider"
 )
 
 type DotNetProvider struct {
@@ -10,18 +11,10 @@ type DotNetProvider struct {
 }
 
 func (p *DotNetProvider) GetConfigVolume(a *analyzeCommand, tmpDir string) (provider.Config, error) {
-	p.config = provider.Config{
-		Name:    dotnetProvider,
-		Address: fmt.Sprintf("0.0.0.0:%v", a.providersMap[dotnetProvider].port),
-		InitConfig: []provider.InitConfig{
-			{
-				Location:     SourceMountPath,
-				AnalysisMode: provider.SourceOnlyAnalysisMode,
-				ProviderSpecificConfig: map[string]interface{}{
-					provider.LspServerPathConfigKey: "/opt/app-root/.dotnet/tools/csharp-ls",
-				},
-			},
-		},
-	}
+	p.config = kantraprovider.DotNetConfig(kantraprovider.Options{
+		Mode:     kantraprovider.ContainerMode,
+		Location: a.sourceMountPath(),
+		Port:     a.providersMap[dotnetProvider].port,
+	})
 	return p.config, nil
 }
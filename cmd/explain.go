@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/spf13/cobra"
+)
+
+// default number of incidents printed by explain
+const defaultExplainIncidents = 10
+
+type explainCommand struct {
+	output       string
+	ruleset      string
+	maxIncidents int
+	format       string
+	log          logr.Logger
+}
+
+// ruleExplanation is a violated rule with its ruleset and incidents
+type ruleExplanation struct {
+	RuleID             string `json:"ruleID" yaml:"ruleID"`
+	RuleSet            string `json:"ruleset" yaml:"ruleset"`
+	RuleSetDescription string `json:"rulesetDescription,omitempty" yaml:"rulesetDescription,omitempty"`
+	// insights are informational, they have no effort
+	Insight   bool               `json:"insight,omitempty" yaml:"insight,omitempty"`
+	Violation outputv1.Violation `json:"violation" yaml:"violation"`
+}
+
+func NewExplainCommand(log logr.Logger) *cobra.Command {
+	explainCmd := &explainCommand{log: log}
+	explainCommand := &cobra.Command{
+		Use:   "explain <rule ID>",
+		Short: "Explain a rule and the incidents it matched in an analysis output",
+		Args:  cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			switch explainCmd.format {
+			case listFormatText, listFormatJSON, listFormatYAML:
+			default:
+				return fmt.Errorf("format must be one of 'text', 'json' or 'yaml'")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := explainCmd.Run(args[0], os.Stdout); err != nil {
+				log.Error(err, "failed to explain rule", "rule", args[0])
+				return err
+			}
+			return nil
+		},
+	}
+	explainCommand.Flags().StringVarP(&explainCmd.output, "output", "o", "", "path to the output dir of an analysis or its output.yaml")
+	explainCommand.Flags().StringVar(&explainCmd.ruleset, "ruleset", "", "name of the ruleset of the rule, when several rulesets have a rule with the ID")
+	explainCommand.Flags().IntVar(&explainCmd.maxIncidents, "max-incidents", defaultExplainIncidents, "number of incidents to print, 0 prints all")
+	explainCommand.Flags().StringVar(&explainCmd.format, "format", listFormatText, "output format. Must be one of 'text', 'json' or 'yaml'")
+	explainCommand.MarkFlagRequired("output")
+	return explainCommand
+}
+
+// findRule returns the violations and insights of ruleID, limited to the
+// ruleset when given. When the rule did not match, the error tells why.
+func findRule(rulesets []outputv1.RuleSet, ruleID, ruleset string) ([]ruleExplanation, error) {
+	found := []ruleExplanation{}
+	for _, rs := range rulesets {
+		if ruleset != "" && rs.Name != ruleset {
+			continue
+		}
+		if v, ok := rs.Violations[ruleID]; ok {
+			found = append(found, ruleExplanation{RuleID: ruleID, RuleSet: rs.Name, RuleSetDescription: rs.Description, Violation: v})
+		}
+		if v, ok := rs.Insights[ruleID]; ok {
+			found = append(found, ruleExplanation{RuleID: ruleID, RuleSet: rs.Name, RuleSetDescription: rs.Description, Insight: true, Violation: v})
+		}
+	}
+	if len(found) > 0 {
+		return found, nil
+	}
+	for _, rs := range rulesets {
+		if ruleset != "" && rs.Name != ruleset {
+			continue
+		}
+		if msg, ok := rs.Errors[ruleID]; ok {
+			return nil, fmt.Errorf("rule %s of ruleset %s failed: %s", ruleID, rs.Name, msg)
+		}
+		if slices.Contains(rs.Unmatched, ruleID) {
+			return nil, fmt.Errorf("rule %s of ruleset %s did not match the input", ruleID, rs.Name)
+		}
+		if slices.Contains(rs.Skipped, ruleID) {
+			return nil, fmt.Errorf("rule %s of ruleset %s was skipped", ruleID, rs.Name)
+		}
+	}
+	return nil, fmt.Errorf("rule %s not found in the output", ruleID)
+}
+
+func incidentLocation(incident outputv1.Incident) string {
+	location := normalizeIncidentURI(incident.URI)
+	if incident.LineNumber != nil {
+		location = fmt.Sprintf("%s:%d", location, *incident.LineNumber)
+	}
+	return location
+}
+
+func indentText(text, indent string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(indent+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeRuleExplanation prints a rule and up to maxIncidents of its
+// incidents, grouped by their message which holds the remediation guidance
+func writeRuleExplanation(out io.Writer, e ruleExplanation, maxIncidents int) {
+	v := e.Violation
+	kind := "rule"
+	if e.Insight {
+		kind = "insight"
+	}
+	fmt.Fprintf(out, "%s %s of ruleset %s\n", kind, e.RuleID, e.RuleSet)
+	if e.RuleSetDescription != "" {
+		fmt.Fprintln(out, indentText(e.RuleSetDescription, "  "))
+	}
+	details := []string{}
+	if v.Category != nil {
+		details = append(details, fmt.Sprintf("category: %s", *v.Category))
+	}
+	if v.Effort != nil {
+		details = append(details, fmt.Sprintf("effort: %d", *v.Effort))
+	}
+	details = append(details, fmt.Sprintf("incidents: %d", len(v.Incidents)))
+	fmt.Fprintln(out, strings.Join(details, ", "))
+	if len(v.Labels) > 0 {
+		fmt.Fprintf(out, "labels: %s\n", strings.Join(v.Labels, ", "))
+	}
+	if v.Description != "" {
+		fmt.Fprintf(out, "\n%s\n", indentText(v.Description, ""))
+	}
+	if len(v.Links) > 0 {
+		fmt.Fprintln(out, "\nlinks:")
+		for _, link := range v.Links {
+			if link.Title != "" {
+				fmt.Fprintf(out, "  %s: %s\n", link.Title, link.URL)
+			} else {
+				fmt.Fprintf(out, "  %s\n", link.URL)
+			}
+		}
+	}
+	messages := []string{}
+	byMessage := map[string][]outputv1.Incident{}
+	for _, incident := range v.Incidents {
+		if _, ok := byMessage[incident.Message]; !ok {
+			messages = append(messages, incident.Message)
+		}
+		byMessage[incident.Message] = append(byMessage[incident.Message], incident)
+	}
+	printed := 0
+	for _, message := range messages {
+		if maxIncidents > 0 && printed >= maxIncidents {
+			break
+		}
+		fmt.Fprintln(out)
+		if message != "" {
+			fmt.Fprintln(out, indentText(message, ""))
+		}
+		for _, incident := range byMessage[message] {
+			if maxIncidents > 0 && printed >= maxIncidents {
+				break
+			}
+			printed++
+			fmt.Fprintf(out, "  %s [%s]\n", incidentLocation(incident), incidentID(e.RuleSet, e.RuleID, incident))
+			if incident.CodeSnip != "" {
+				fmt.Fprintln(out, indentText(incident.CodeSnip, "    "))
+			}
+		}
+	}
+	if printed < len(v.Incidents) {
+		fmt.Fprintf(out, "\n%d more incidents, use --max-incidents 0 to print all\n", len(v.Incidents)-printed)
+	}
+}
+
+// Run prints the explanation of ruleID from the analysis output
+func (e *explainCommand) Run(ruleID string, out io.Writer) error {
+	outputPath := e.output
+	if stat, err := os.Stat(outputPath); err == nil && stat.IsDir() {
+		outputPath = filepath.Join(outputPath, "output.yaml")
+	}
+	rulesets, err := readRuleSetsOutput(outputPath)
+	if err != nil {
+		return err
+	}
+	found, err := findRule(rulesets, ruleID, e.ruleset)
+	if err != nil {
+		return err
+	}
+	if e.format != listFormatText {
+		return writeListOutput(out, e.format, found)
+	}
+	for i, explanation := range found {
+		if i > 0 {
+			fmt.Fprintln(out, "\n---")
+		}
+		writeRuleExplanation(out, explanation, e.maxIncidents)
+	}
+	return nil
+}
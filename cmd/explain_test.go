@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+const explainTestOutput = `- name: eap8
+  description: Rules for EAP 8
+  violations:
+    rule-1:
+      description: Replace javax with jakarta
+      category: mandatory
+      effort: 3
+      links:
+      - url: https://example.com/jakarta
+        title: Jakarta migration
+      incidents:
+      - uri: file:///src/A.java
+        message: Replace the javax.ejb import
+        lineNumber: 3
+        codeSnip: " 3  import javax.ejb.Stateless;"
+      - uri: file:///src/B.java
+        message: Replace the javax.ejb import
+        lineNumber: 5
+      - uri: file:///src/C.java
+        message: Replace the javax.jms import
+        lineNumber: 7
+  unmatched:
+  - rule-2
+`
+
+func Test_explainCommand_Run(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "output.yaml"), explainTestOutput)
+	e := &explainCommand{output: dir, maxIncidents: 2, format: listFormatText, log: logr.Discard()}
+	out := &bytes.Buffer{}
+	if err := e.Run("rule-1", out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"rule rule-1 of ruleset eap8\n",
+		"category: mandatory, effort: 3, incidents: 3\n",
+		"  Jakarta migration: https://example.com/jakarta\n",
+		"Replace the javax.ejb import\n  /src/A.java:3 [",
+		"]\n     3  import javax.ejb.Stateless;\n  /src/B.java:5 [",
+		"1 more incidents",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Run() = %s, want it to contain %q", out, want)
+		}
+	}
+	if strings.Contains(out.String(), "javax.jms") {
+		t.Errorf("Run() = %s, want at most 2 incidents", out)
+	}
+	if err := e.Run("rule-2", out); err == nil || !strings.Contains(err.Error(), "did not match") {
+		t.Errorf("Run() for an unmatched rule = %v", err)
+	}
+	if err := e.Run("rule-3", out); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Run() for an unknown rule = %v", err)
+	}
+}
@@ -0,0 +1,365 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/spf13/cobra"
+)
+
+// formats of the review export
+const (
+	// diagnostics of reviewdog, which comments them on pull requests
+	reviewFormatRDJSON = "rdjson"
+	// workflow commands annotating the files of the run of a github action
+	reviewFormatGitHub = "github"
+	// code quality report of gitlab, shown in merge requests
+	reviewFormatGitLab = "gitlab"
+)
+
+var reviewFormats = []string{reviewFormatRDJSON, reviewFormatGitHub, reviewFormatGitLab}
+
+// severities of the incidents by the category of their rule
+var reviewSeverities = map[outputv1.Category][3]string{
+	outputv1.Mandatory: {"ERROR", "error", "major"},
+	outputv1.Optional:  {"WARNING", "warning", "minor"},
+	outputv1.Potential: {"INFO", "notice", "info"},
+}
+
+type exportReviewCommand struct {
+	output       string
+	input        string
+	changedSince string
+	format       string
+	log          logr.Logger
+}
+
+// reviewComment is an incident on a file of the repository
+type reviewComment struct {
+	ruleID   string
+	id       string
+	path     string
+	line     int
+	category outputv1.Category
+	message  string
+	link     string
+}
+
+func NewExportCommand(log logr.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export analysis output for other tools",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+	cmd.AddCommand(newExportReviewCommand(log))
+	cmd.AddCommand(newExportOutputCommand(log))
+	return cmd
+}
+
+func newExportReviewCommand(log logr.Logger) *cobra.Command {
+	reviewCmd := &exportReviewCommand{log: log}
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Export incidents as code review comments",
+		Long: "Export the incidents of an analysis output as code review comments, e.g. on the changes of a pull request with\n" +
+			"  kantra export review -o out -i . --changed-since origin/main | reviewdog -f rdjson -reporter github-pr-review",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			for _, format := range reviewFormats {
+				if reviewCmd.format == format {
+					return nil
+				}
+			}
+			return fmt.Errorf("format must be one of %s", strings.Join(reviewFormats, ", "))
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := reviewCmd.Run(os.Stdout); err != nil {
+				log.Error(err, "failed to export review comments")
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&reviewCmd.output, "output", "o", "", "path to the output dir of an analysis or its output.yaml")
+	cmd.Flags().StringVarP(&reviewCmd.input, "input", "i", "", "path of the analyzed application in its git repository")
+	cmd.Flags().StringVar(&reviewCmd.changedSince, "changed-since", "", "export only the incidents on lines changed since a git ref, e.g. the target branch of a pull request")
+	cmd.Flags().StringVar(&reviewCmd.format, "format", reviewFormatRDJSON, fmt.Sprintf("output format. Must be one of %s", strings.Join(reviewFormats, ", ")))
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagRequired("input")
+	return cmd
+}
+
+// repoPaths returns the roots the input is known by in incident URIs and
+// the path of the input in its repository
+func repoPaths(input string) ([]string, string, error) {
+	input, err := filepath.Abs(input)
+	if err != nil {
+		return nil, "", err
+	}
+	prefix, err := gitOutput(input, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, "", fmt.Errorf("%w input %s must be in a git repository", err, input)
+	}
+	roots := []string{filepath.ToSlash(input)}
+	if real, err := filepath.EvalSymlinks(input); err == nil && real != input {
+		roots = append(roots, filepath.ToSlash(real))
+	}
+	return append(roots, SourceMountPath), strings.TrimSuffix(prefix, "/"), nil
+}
+
+// reviewComments returns the incidents of the violations on files of the
+// input, with their path in the repository
+func reviewComments(rulesets []outputv1.RuleSet, roots []string, prefix string) []reviewComment {
+	comments := []reviewComment{}
+	for _, rs := range rulesets {
+		for ruleID, v := range rs.Violations {
+			link := ""
+			if len(v.Links) > 0 {
+				link = v.Links[0].URL
+			}
+			category := outputv1.Potential
+			if v.Category != nil {
+				category = *v.Category
+			}
+			for _, incident := range v.Incidents {
+				rel := string(relativeURI(incident.URI, roots))
+				// incidents of dependencies are not in the repository
+				if strings.HasPrefix(rel, "file:") || strings.Contains(rel, "://") || rel == "." {
+					continue
+				}
+				line := 0
+				if incident.LineNumber != nil {
+					line = *incident.LineNumber
+				}
+				message := strings.TrimSpace(incident.Message)
+				if message == "" {
+					message = v.Description
+				}
+				comments = append(comments, reviewComment{
+					ruleID:   ruleID,
+					id:       incidentID(rs.Name, ruleID, incident),
+					path:     path.Join(prefix, rel),
+					line:     line,
+					category: category,
+					message:  message,
+					link:     link,
+				})
+			}
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		if comments[i].path != comments[j].path {
+			return comments[i].path < comments[j].path
+		}
+		if comments[i].line != comments[j].line {
+			return comments[i].line < comments[j].line
+		}
+		return comments[i].id < comments[j].id
+	})
+	return comments
+}
+
+// parseChangedLines returns the lines added or changed of each file of a
+// diff without context, by their path in the repository
+func parseChangedLines(diff []byte) (map[string][]int, error) {
+	changed := map[string][]int{}
+	file := ""
+	scanner := bufio.NewScanner(bytes.NewReader(diff))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			if file == "/dev/null" {
+				file = ""
+			} else if _, ok := changed[file]; !ok {
+				changed[file] = []int{}
+			}
+		case strings.HasPrefix(line, "@@ ") && file != "":
+			// @@ -<start>[,<count>] +<start>[,<count>] @@
+			fields := strings.Fields(line)
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("unexpected diff hunk %s", line)
+			}
+			start, count, hasCount := strings.Cut(strings.TrimPrefix(fields[2], "+"), ",")
+			first, err := strconv.Atoi(start)
+			if err != nil {
+				return nil, fmt.Errorf("%w unexpected diff hunk %s", err, line)
+			}
+			n := 1
+			if hasCount {
+				if n, err = strconv.Atoi(count); err != nil {
+					return nil, fmt.Errorf("%w unexpected diff hunk %s", err, line)
+				}
+			}
+			for l := first; l < first+n; l++ {
+				changed[file] = append(changed[file], l)
+			}
+		}
+	}
+	return changed, scanner.Err()
+}
+
+// changedLines returns the lines changed in the repository of dir since ref,
+// including the changes of the working tree
+func changedLines(dir string, ref string) (map[string][]int, error) {
+	cmd := exec.Command("git", "-C", dir, "diff", "--unified=0", "--no-color", "--no-ext-diff", ref, "--")
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w failed to diff since %s: %s", err, ref, strings.TrimSpace(stderr.String()))
+	}
+	return parseChangedLines(out)
+}
+
+// onChangedLines keeps the comments on changed lines, and the ones without
+// line on changed files
+func onChangedLines(comments []reviewComment, changed map[string][]int) []reviewComment {
+	kept := []reviewComment{}
+	for _, c := range comments {
+		lines, ok := changed[c.path]
+		if !ok {
+			continue
+		}
+		if c.line == 0 || slices.Contains(lines, c.line) {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+func writeRDJSON(out io.Writer, comments []reviewComment) error {
+	type position struct {
+		Line int `json:"line,omitempty"`
+	}
+	type diagnostic struct {
+		Message  string `json:"message"`
+		Location struct {
+			Path  string `json:"path"`
+			Range *struct {
+				Start position `json:"start"`
+			} `json:"range,omitempty"`
+		} `json:"location"`
+		Severity string `json:"severity"`
+		Code     struct {
+			Value string `json:"value"`
+			URL   string `json:"url,omitempty"`
+		} `json:"code"`
+	}
+	result := struct {
+		Source struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"source"`
+		Diagnostics []diagnostic `json:"diagnostics"`
+	}{Diagnostics: []diagnostic{}}
+	result.Source.Name, result.Source.URL = "kantra", "https://github.com/konveyor/kantra"
+	for _, c := range comments {
+		d := diagnostic{Message: c.message, Severity: reviewSeverities[c.category][0]}
+		d.Location.Path = c.path
+		if c.line > 0 {
+			d.Location.Range = &struct {
+				Start position `json:"start"`
+			}{Start: position{Line: c.line}}
+		}
+		d.Code.Value, d.Code.URL = c.ruleID, c.link
+		result.Diagnostics = append(result.Diagnostics, d)
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
+
+// escapes data of github workflow commands
+var githubCommandEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+var githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+func writeGitHubAnnotations(out io.Writer, comments []reviewComment) error {
+	for _, c := range comments {
+		properties := []string{"file=" + githubPropertyEscaper.Replace(c.path)}
+		if c.line > 0 {
+			properties = append(properties, fmt.Sprintf("line=%d", c.line))
+		}
+		properties = append(properties, "title="+githubPropertyEscaper.Replace(c.ruleID))
+		if _, err := fmt.Fprintf(out, "::%s %s::%s\n", reviewSeverities[c.category][1],
+			strings.Join(properties, ","), githubCommandEscaper.Replace(c.message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeGitLabCodeQuality(out io.Writer, comments []reviewComment) error {
+	type issue struct {
+		Description string `json:"description"`
+		CheckName   string `json:"check_name"`
+		Fingerprint string `json:"fingerprint"`
+		Severity    string `json:"severity"`
+		Location    struct {
+			Path  string `json:"path"`
+			Lines struct {
+				Begin int `json:"begin"`
+			} `json:"lines"`
+		} `json:"location"`
+	}
+	issues := []issue{}
+	for _, c := range comments {
+		i := issue{Description: c.message, CheckName: c.ruleID, Fingerprint: c.id, Severity: reviewSeverities[c.category][2]}
+		i.Location.Path = c.path
+		// the report requires a line
+		i.Location.Lines.Begin = max(c.line, 1)
+		issues = append(issues, i)
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
+
+// Run prints the incidents of the analysis output as review comments
+func (e *exportReviewCommand) Run(out io.Writer) error {
+	outputPath := e.output
+	if stat, err := os.Stat(outputPath); err == nil && stat.IsDir() {
+		outputPath = filepath.Join(outputPath, "output.yaml")
+	}
+	rulesets, err := readRuleSetsOutput(outputPath)
+	if err != nil {
+		return err
+	}
+	roots, prefix, err := repoPaths(e.input)
+	if err != nil {
+		return err
+	}
+	comments := reviewComments(rulesets, roots, prefix)
+	if e.changedSince != "" {
+		changed, err := changedLines(e.input, e.changedSince)
+		if err != nil {
+			return err
+		}
+		all := len(comments)
+		comments = onChangedLines(comments, changed)
+		e.log.V(1).Info("kept incidents on changed lines", "since", e.changedSince, "incidents", len(comments), "all", all)
+	}
+	switch e.format {
+	case reviewFormatGitHub:
+		return writeGitHubAnnotations(out, comments)
+	case reviewFormatGitLab:
+		return writeGitLabCodeQuality(out, comments)
+	}
+	return writeRDJSON(out, comments)
+}
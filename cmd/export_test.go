@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_parseChangedLines(t *testing.T) {
+	diff := `diff --git a/app/src/Cart.java b/app/src/Cart.java
+index 1111111..2222222 100644
+--- a/app/src/Cart.java
++++ b/app/src/Cart.java
+@@ -3 +3,2 @@ class Cart {
+-    @Stateful
++    @Stateless
++    @Remote
+@@ -10,2 +11,0 @@ class Cart {
+-    int a;
+-    int b;
+@@ -20,0 +21 @@ class Cart {
++    int c;
+diff --git a/app/Removed.java b/app/Removed.java
+deleted file mode 100644
+--- a/app/Removed.java
++++ /dev/null
+@@ -1 +0,0 @@
+-class Removed {}
+`
+	changed, err := parseChangedLines([]byte(diff))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]int{"app/src/Cart.java": {3, 4, 21}}
+	if !reflect.DeepEqual(changed, want) {
+		t.Errorf("parseChangedLines() = %v, want %v", changed, want)
+	}
+}
+
+func Test_exportReviewCommand_Run(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := t.TempDir()
+	input := filepath.Join(repo, "app")
+	writeTestFile(t, filepath.Join(input, "src", "Cart.java"), "class Cart {\n    @Stateful\n}\n")
+	writeTestFile(t, filepath.Join(input, "src", "Order.java"), "class Order {\n    @Stateful\n}\n")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=Jane Doe", "-c", "user.email=jane@example.com", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	// changes of the pull request
+	writeTestFile(t, filepath.Join(input, "src", "Cart.java"), "class Cart {\n    @Stateful\n    @Remote\n}\n")
+
+	output := filepath.Join(t.TempDir(), "output.yaml")
+	writeTestFile(t, output, `- name: eap8
+  violations:
+    session-00010:
+      description: Stateful session EJB
+      category: mandatory
+      links:
+      - url: https://example.com/ejb
+        title: EJB
+      incidents:
+      - uri: file:///opt/input/source/src/Cart.java
+        message: Replace the stateful EJB
+        lineNumber: 2
+      - uri: file:///opt/input/source/src/Order.java
+        message: Replace the stateful EJB
+        lineNumber: 2
+    remote-00001:
+      description: Remote EJB
+      category: optional
+      incidents:
+      - uri: file:///opt/input/source/src/Cart.java
+        message: "Remove the remote interface,\nuse REST"
+        lineNumber: 3
+      - uri: file:///root/.m2/repository/org/acme/lib/Lib.java
+        message: Remove the remote interface
+        lineNumber: 3
+`)
+
+	out := &bytes.Buffer{}
+	e := &exportReviewCommand{output: output, input: input, format: reviewFormatGitHub, log: logr.Discard()}
+	if err := e.Run(out); err != nil {
+		t.Fatal(err)
+	}
+	want := `::error file=app/src/Cart.java,line=2,title=session-00010::Replace the stateful EJB
+::warning file=app/src/Cart.java,line=3,title=remote-00001::Remove the remote interface,%0Ause REST
+::error file=app/src/Order.java,line=2,title=session-00010::Replace the stateful EJB
+`
+	if out.String() != want {
+		t.Errorf("Run() = %s, want %s", out, want)
+	}
+
+	out.Reset()
+	e.changedSince, e.format = "HEAD", reviewFormatRDJSON
+	if err := e.Run(out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"path": "app/src/Cart.java"`, `"line": 3`, `"severity": "WARNING"`, `"value": "remote-00001"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Run() = %s, want %s", out, want)
+		}
+	}
+	if strings.Contains(out.String(), "session-00010") {
+		t.Errorf("Run() = %s, want incidents on changed lines only", out)
+	}
+}
+
+func Test_writeGitLabCodeQuality(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := writeGitLabCodeQuality(out, []reviewComment{{ruleID: "session-00010", id: "0123456789ab", path: "src/Cart.java", category: "mandatory", message: "Replace the stateful EJB"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"check_name": "session-00010"`, `"fingerprint": "0123456789ab"`, `"severity": "major"`, `"begin": 1`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("writeGitLabCodeQuality() = %s, want %s", out, want)
+		}
+	}
+}
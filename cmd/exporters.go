@@ -0,0 +1,465 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// built-in formats of the analysis output, output.yaml is always written.
+// Other formats are exported by the executables of the exporters dir.
+const (
+	outputFormatYAML  = "yaml"
+	outputFormatJSON  = "json"
+	outputFormatJUnit = "junit"
+	outputFormatSARIF = "sarif"
+	outputFormatCSV   = "csv"
+)
+
+var outputFormats = []string{outputFormatYAML, outputFormatJSON, outputFormatJUnit, outputFormatSARIF, outputFormatCSV}
+
+// files in the output dir of the sarif and csv outputs
+const (
+	sarifOutputFile = "output.sarif"
+	csvOutputFile   = "output.csv"
+)
+
+// exportersDir in the kantra dir holds exporter executables, each exports
+// the format of its name without extension
+const exportersDir = "exporters"
+
+// exportData is the analysis output given to exporters, as json on the
+// stdin of external ones
+type exportData struct {
+	Name         string                  `json:"name"`
+	RuleSets     []outputv1.RuleSet      `json:"rulesets"`
+	Dependencies []outputv1.DepsFlatItem `json:"dependencies,omitempty"`
+	RunInfo      *runInfo                `json:"runInfo,omitempty"`
+}
+
+// outputExporter writes the analysis output in a format
+type outputExporter interface {
+	// File is the file in the output dir the export is written to
+	File() string
+	Export(w io.Writer, data *exportData) error
+}
+
+var builtinExporters = map[string]outputExporter{
+	outputFormatYAML:  yamlExporter{},
+	outputFormatJSON:  jsonExporter{},
+	outputFormatJUnit: junitExporter{},
+	outputFormatSARIF: sarifExporter{},
+	outputFormatCSV:   csvExporter{},
+}
+
+type yamlExporter struct{}
+
+func (yamlExporter) File() string {
+	return "output.yaml"
+}
+
+func (yamlExporter) Export(w io.Writer, data *exportData) error {
+	out, err := yaml.Marshal(data.RuleSets)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+type jsonExporter struct{}
+
+func (jsonExporter) File() string {
+	return "output.json"
+}
+
+func (jsonExporter) Export(w io.Writer, data *exportData) error {
+	for _, rs := range data.RuleSets {
+		for _, v := range rs.Violations {
+			// variables decoded from yaml may have maps json can not encode
+			for _, incident := range v.Incidents {
+				normalizeQueryValue(incident.Variables)
+			}
+		}
+	}
+	out, err := json.MarshalIndent(data.RuleSets, "", "	")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name    string      `json:"name"`
+			Version string      `json:"version,omitempty"`
+			Rules   []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	HelpURI          string       `json:"helpUri,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region *sarifRegion `json:"region,omitempty"`
+	} `json:"physicalLocation"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifLevels of the incidents by the category of their rule
+var sarifLevels = map[outputv1.Category]string{
+	outputv1.Mandatory: "error",
+	outputv1.Optional:  "warning",
+	outputv1.Potential: "note",
+}
+
+// buildSARIF maps the violated rules to rules of a sarif run and their
+// incidents to its results, for code scanning tools
+func buildSARIF(rulesets []outputv1.RuleSet) sarifLog {
+	run := sarifRun{Results: []sarifResult{}}
+	run.Tool.Driver.Name = "kantra"
+	run.Tool.Driver.Version = Version
+	run.Tool.Driver.Rules = []sarifRule{}
+	for _, rs := range rulesets {
+		for _, ruleID := range sortedMapKeys(rs.Violations) {
+			v := rs.Violations[ruleID]
+			id := rs.Name + "/" + ruleID
+			rule := sarifRule{ID: id, ShortDescription: sarifMessage{Text: v.Description}}
+			if len(v.Links) > 0 {
+				rule.HelpURI = v.Links[0].URL
+			}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+			level := "warning"
+			if v.Category != nil && sarifLevels[*v.Category] != "" {
+				level = sarifLevels[*v.Category]
+			}
+			for _, incident := range v.Incidents {
+				location := sarifLocation{}
+				location.PhysicalLocation.ArtifactLocation.URI = string(incident.URI)
+				if incident.LineNumber != nil && *incident.LineNumber > 0 {
+					location.PhysicalLocation.Region = &sarifRegion{StartLine: *incident.LineNumber}
+				}
+				run.Results = append(run.Results, sarifResult{
+					RuleID:    id,
+					Level:     level,
+					Message:   sarifMessage{Text: strings.TrimSpace(incident.Message)},
+					Locations: []sarifLocation{location},
+				})
+			}
+		}
+	}
+	return sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	}
+}
+
+type sarifExporter struct{}
+
+func (sarifExporter) File() string {
+	return sarifOutputFile
+}
+
+func (sarifExporter) Export(w io.Writer, data *exportData) error {
+	out, err := json.MarshalIndent(buildSARIF(data.RuleSets), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// csvExporter writes a row per incident
+type csvExporter struct{}
+
+func (csvExporter) File() string {
+	return csvOutputFile
+}
+
+func (csvExporter) Export(w io.Writer, data *exportData) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"ruleset", "rule", "category", "effort", "file", "line", "message"}); err != nil {
+		return err
+	}
+	for _, rs := range data.RuleSets {
+		for _, ruleID := range sortedMapKeys(rs.Violations) {
+			v := rs.Violations[ruleID]
+			category, effort := "", ""
+			if v.Category != nil {
+				category = string(*v.Category)
+			}
+			if v.Effort != nil {
+				effort = strconv.Itoa(*v.Effort)
+			}
+			for _, incident := range v.Incidents {
+				line := ""
+				if incident.LineNumber != nil {
+					line = strconv.Itoa(*incident.LineNumber)
+				}
+				message, _, _ := strings.Cut(strings.TrimSpace(incident.Message), "\n")
+				row := []string{rs.Name, ruleID, category, effort, normalizeIncidentURI(incident.URI), line, message}
+				if err := out.Write(row); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// externalExporter runs an executable of the exporters dir with the export
+// data as json on its stdin, what it prints is the export
+type externalExporter struct {
+	name string
+	path string
+}
+
+func (e externalExporter) File() string {
+	return "output." + e.name
+}
+
+func (e externalExporter) Export(w io.Writer, data *exportData) error {
+	input, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(e.path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w exporter %s failed", err, e.path)
+	}
+	return nil
+}
+
+// discoverExporters returns the executables of dir by the format they
+// export, the name of the file without extension
+func discoverExporters(dir string) (map[string]outputExporter, error) {
+	exporters := map[string]outputExporter{}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return exporters, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if runtime.GOOS == "windows" {
+			if !strings.EqualFold(filepath.Ext(entry.Name()), ".exe") {
+				continue
+			}
+		} else if info.Mode()&0111 == 0 {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		exporters[name] = externalExporter{name: name, path: filepath.Join(dir, entry.Name())}
+	}
+	return exporters, nil
+}
+
+// loadExporters returns the built-in exporters and the ones of the exporters
+// dir of the kantra dir, built-in formats can not be replaced
+func loadExporters() (map[string]outputExporter, error) {
+	exporters := map[string]outputExporter{}
+	kantraDir, err := kantraHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	external, err := discoverExporters(filepath.Join(kantraDir, exportersDir))
+	if err != nil {
+		return nil, fmt.Errorf("%w failed to list exporters", err)
+	}
+	for name, exporter := range external {
+		exporters[name] = exporter
+	}
+	for name, exporter := range builtinExporters {
+		exporters[name] = exporter
+	}
+	return exporters, nil
+}
+
+// validateOutputFormats checks the output formats, json is the same as
+// --json-output
+func (a *analyzeCommand) validateOutputFormats() error {
+	if len(a.outputFormats) == 0 {
+		return nil
+	}
+	exporters, err := loadExporters()
+	if err != nil {
+		return err
+	}
+	for _, format := range a.outputFormats {
+		if _, ok := exporters[format]; !ok {
+			return fmt.Errorf("output format %s must be one of %s", format, strings.Join(sortedMapKeys(exporters), ", "))
+		}
+		if format == outputFormatJSON {
+			a.jsonOutput = true
+		}
+	}
+	a.exporters = exporters
+	return nil
+}
+
+// readExportData reads the analysis output of the output dir
+func readExportData(output string, name string) (*exportData, error) {
+	rulesets, err := readRuleSetsOutput(filepath.Join(output, "output.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	data := &exportData{Name: name, RuleSets: rulesets}
+	deps, err := readOutputFile(filepath.Join(output, "dependencies.yaml"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(deps, &data.Dependencies); err != nil {
+		return nil, err
+	}
+	info, err := os.ReadFile(filepath.Join(output, runInfoFile))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if len(info) > 0 {
+		data.RunInfo = &runInfo{}
+		if err := json.Unmarshal(info, data.RunInfo); err != nil {
+			return nil, fmt.Errorf("%w failed to parse %s", err, runInfoFile)
+		}
+	}
+	return data, nil
+}
+
+// writeExports writes the analysis output in the formats of --output-format,
+// yaml and json are written by the analysis
+func (a *analyzeCommand) writeExports() error {
+	var data *exportData
+	for _, format := range a.outputFormats {
+		if format == outputFormatYAML || format == outputFormatJSON {
+			continue
+		}
+		if data == nil {
+			var err error
+			if data, err = readExportData(a.output, a.inputShortName()); err != nil {
+				return err
+			}
+		}
+		exporter := a.exporters[format]
+		file := exporter.File()
+		// outputs of external exporters are not known to --output-prefix
+		if _, ok := exporter.(externalExporter); ok {
+			file = a.outputPrefix + file
+		}
+		a.log.Info("exporting analysis output", "format", format, "file", file)
+		if err := writeExport(filepath.Join(a.output, file), exporter, data); err != nil {
+			return fmt.Errorf("%w failed to export analysis output as %s", err, format)
+		}
+	}
+	return nil
+}
+
+func writeExport(path string, exporter outputExporter, data *exportData) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := exporter.Export(file, data); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+type exportOutputCommand struct {
+	output string
+	format string
+	log    logr.Logger
+}
+
+func newExportOutputCommand(log logr.Logger) *cobra.Command {
+	outputCmd := &exportOutputCommand{log: log}
+	cmd := &cobra.Command{
+		Use:   "output",
+		Short: "Export analysis output in another format",
+		Long: "Export the output of an analysis in a built-in format or the format of an exporter of the exporters dir of\n" +
+			"the kantra dir, e.g. ~/.kantra/exporters/servicenow which reads the output as json on stdin, with\n" +
+			"  kantra export output -o out --format servicenow",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputCmd.Run(os.Stdout); err != nil {
+				log.Error(err, "failed to export analysis output")
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&outputCmd.output, "output", "o", "", "path to the output dir of an analysis")
+	cmd.Flags().StringVar(&outputCmd.format, "format", "", fmt.Sprintf("format to export, one of %s or of an exporter", strings.Join(outputFormats, ", ")))
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagRequired("format")
+	return cmd
+}
+
+func (e *exportOutputCommand) Run(out io.Writer) error {
+	exporters, err := loadExporters()
+	if err != nil {
+		return err
+	}
+	exporter, ok := exporters[e.format]
+	if !ok {
+		return fmt.Errorf("format must be one of %s", strings.Join(sortedMapKeys(exporters), ", "))
+	}
+	data, err := readExportData(e.output, filepath.Base(filepath.Clean(e.output)))
+	if err != nil {
+		return err
+	}
+	return exporter.Export(out, data)
+}
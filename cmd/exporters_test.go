@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func testExportData() *exportData {
+	line := 12
+	effort := 3
+	mandatory := outputv1.Mandatory
+	return &exportData{
+		Name: "coolstore",
+		RuleSets: []outputv1.RuleSet{{
+			Name: "eap8",
+			Violations: map[string]outputv1.Violation{
+				"session-00010": {
+					Description: "Stateful session EJB",
+					Category:    &mandatory,
+					Effort:      &effort,
+					Links:       []outputv1.Link{{URL: "https://example.com/ejb"}},
+					Incidents: []outputv1.Incident{
+						{URI: "file:///opt/input/source/src/Cart.java", LineNumber: &line, Message: "Replace the stateful EJB\nwith a CDI bean"},
+					},
+				},
+			},
+		}},
+	}
+}
+
+func Test_buildSARIF(t *testing.T) {
+	log := buildSARIF(testExportData().RuleSets)
+	if len(log.Runs) != 1 {
+		t.Fatalf("buildSARIF() runs = %d, want 1", len(log.Runs))
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 1 || run.Tool.Driver.Rules[0].ID != "eap8/session-00010" || run.Tool.Driver.Rules[0].HelpURI != "https://example.com/ejb" {
+		t.Errorf("buildSARIF() rules = %+v, want eap8/session-00010 with its link", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 1 {
+		t.Fatalf("buildSARIF() results = %d, want 1", len(run.Results))
+	}
+	result := run.Results[0]
+	if result.Level != "error" || result.Locations[0].PhysicalLocation.Region.StartLine != 12 ||
+		result.Locations[0].PhysicalLocation.ArtifactLocation.URI != "file:///opt/input/source/src/Cart.java" {
+		t.Errorf("buildSARIF() result = %+v, want an error at Cart.java:12", result)
+	}
+}
+
+func Test_csvExporter_Export(t *testing.T) {
+	out := &bytes.Buffer{}
+	if err := (csvExporter{}).Export(out, testExportData()); err != nil {
+		t.Fatal(err)
+	}
+	want := "ruleset,rule,category,effort,file,line,message\n" +
+		"eap8,session-00010,mandatory,3,/opt/input/source/src/Cart.java,12,Replace the stateful EJB\n"
+	if out.String() != want {
+		t.Errorf("csvExporter.Export() = %q, want %q", out.String(), want)
+	}
+}
+
+func Test_analyzeCommand_writeExports_external(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("exporter is a shell script")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+	dir := filepath.Join(home, ".kantra", exportersDir)
+	// counts the rulesets of the export data on stdin
+	writeTestFile(t, filepath.Join(dir, "servicenow.sh"), "#!/bin/sh\ngrep -o '\"rulesets\"' | wc -l | tr -d ' '\n")
+	if err := os.Chmod(filepath.Join(dir, "servicenow.sh"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(dir, "README.md"), "not an exporter\n")
+
+	output := t.TempDir()
+	writeTestFile(t, filepath.Join(output, "output.yaml"), "- name: eap8\n")
+	a := &analyzeCommand{log: logr.Discard(), input: "coolstore", outputFormats: []string{"servicenow", outputFormatJUnit}}
+	a.output = output
+	a.outputPrefix = "v1-"
+	if err := a.validateOutputFormats(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := a.exporters["README"]; ok {
+		t.Errorf("validateOutputFormats() loaded a file that is not executable as exporter")
+	}
+	if err := a.writeExports(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(mustReadFile(t, filepath.Join(output, "v1-output.servicenow"))); got != "1" {
+		t.Errorf("external export = %q, want 1", got)
+	}
+	if got := mustReadFile(t, filepath.Join(output, junitOutputFile)); !strings.Contains(got, `<testsuites name="coolstore"`) {
+		t.Errorf("junit export = %q, want the test suites of coolstore", got)
+	}
+
+	a.outputFormats = []string{"unknown"}
+	if err := a.validateOutputFormats(); err == nil || !strings.Contains(err.Error(), "servicenow") {
+		t.Errorf("validateOutputFormats() error = %v, want one listing the exporters", err)
+	}
+}
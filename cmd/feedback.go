@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-logr/logr"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/spf13/cobra"
+)
+
+// verdicts of incident feedback
+const (
+	verdictFalsePositive = "false-positive"
+	verdictTruePositive  = "true-positive"
+)
+
+var feedbackVerdicts = []string{verdictFalsePositive, verdictTruePositive}
+
+// incidentFeedback is the verdict of a user on an incident, sent to the
+// maintainers of its ruleset
+type incidentFeedback struct {
+	Incident   string    `json:"incident" yaml:"incident"`
+	RuleSet    string    `json:"ruleset" yaml:"ruleset"`
+	RuleID     string    `json:"ruleID" yaml:"ruleID"`
+	URI        string    `json:"uri" yaml:"uri"`
+	LineNumber *int      `json:"lineNumber,omitempty" yaml:"lineNumber,omitempty"`
+	Message    string    `json:"message" yaml:"message"`
+	Verdict    string    `json:"verdict" yaml:"verdict"`
+	Reason     string    `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Version    string    `json:"kantraVersion" yaml:"kantraVersion"`
+	Time       time.Time `json:"time" yaml:"time"`
+	Submitted  bool      `json:"submitted,omitempty" yaml:"submitted,omitempty"`
+}
+
+type feedbackCommand struct {
+	output   string
+	incident string
+	verdict  string
+	reason   string
+	submit   bool
+	format   string
+	log      logr.Logger
+}
+
+// incidentID returns a short stable ID of an incident, shown by explain
+func incidentID(ruleset, ruleID string, incident outputv1.Incident) string {
+	line := 0
+	if incident.LineNumber != nil {
+		line = *incident.LineNumber
+	}
+	digest := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%d\x00%s",
+		ruleset, ruleID, normalizeIncidentURI(incident.URI), line, incident.Message)))
+	return hex.EncodeToString(digest[:])[:12]
+}
+
+// findIncident returns the feedback of the incident with the ID
+func findIncident(rulesets []outputv1.RuleSet, id string) (*incidentFeedback, error) {
+	for _, rs := range rulesets {
+		for _, violations := range []map[string]outputv1.Violation{rs.Violations, rs.Insights} {
+			for ruleID, v := range violations {
+				for _, incident := range v.Incidents {
+					if incidentID(rs.Name, ruleID, incident) != id {
+						continue
+					}
+					return &incidentFeedback{
+						Incident:   id,
+						RuleSet:    rs.Name,
+						RuleID:     ruleID,
+						URI:        normalizeIncidentURI(incident.URI),
+						LineNumber: incident.LineNumber,
+						Message:    incident.Message,
+					}, nil
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("incident %s not found in the output, incident IDs are printed by kantra explain", id)
+}
+
+func feedbackFile() (string, error) {
+	dir, err := kantraHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "feedback.jsonl"), nil
+}
+
+// appendFeedback stores feedback in the local feedback file, one json
+// record per line
+func appendFeedback(path string, feedback *incidentFeedback) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(feedback)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = fmt.Fprintln(file, string(data))
+	return err
+}
+
+func readFeedback(path string) ([]incidentFeedback, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []incidentFeedback{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	records := []incidentFeedback{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		record := incidentFeedback{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("%w failed to parse feedback in %s", err, path)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// submitFeedback posts feedback to the feedback endpoint, e.g. of the Hub
+func submitFeedback(endpoint, token string, feedback *incidentFeedback) error {
+	data, err := json.Marshal(feedback)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s submitting feedback to %s", resp.Status, endpoint)
+	}
+	return nil
+}
+
+// feedbackIssueURL returns a link opening an issue prefilled with the
+// feedback in the issue tracker of the rulesets
+func feedbackIssueURL(issues string, feedback *incidentFeedback) string {
+	location := feedback.URI
+	if feedback.LineNumber != nil {
+		location = fmt.Sprintf("%s:%d", location, *feedback.LineNumber)
+	}
+	body := fmt.Sprintf("Rule `%s` of ruleset `%s` reported a %s.\n\n"+
+		"Incident: `%s`\nMessage: %s\n\nReason: %s\n\nkantra version: %s\n",
+		feedback.RuleID, feedback.RuleSet, feedback.Verdict, location, feedback.Message, feedback.Reason, feedback.Version)
+	query := url.Values{}
+	query.Set("title", fmt.Sprintf("%s: %s", feedback.Verdict, feedback.RuleID))
+	query.Set("body", body)
+	return issues + "?" + query.Encode()
+}
+
+func NewFeedbackCommand(log logr.Logger) *cobra.Command {
+	feedbackCmd := &feedbackCommand{log: log}
+	feedbackCommand := &cobra.Command{
+		Use:   "feedback",
+		Short: "Record feedback on an incident, e.g. to report a false positive",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.Contains(feedbackVerdicts, feedbackCmd.verdict) {
+				return fmt.Errorf("verdict must be one of %s", strings.Join(feedbackVerdicts, ", "))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := feedbackCmd.Run(os.Stdout); err != nil {
+				log.Error(err, "failed to record feedback")
+				return err
+			}
+			return nil
+		},
+	}
+	feedbackCommand.Flags().StringVarP(&feedbackCmd.output, "output", "o", "", "path to the output dir of an analysis or its output.yaml")
+	feedbackCommand.Flags().StringVar(&feedbackCmd.incident, "incident", "", "ID of the incident, printed by kantra explain")
+	feedbackCommand.Flags().StringVar(&feedbackCmd.verdict, "verdict", verdictFalsePositive, fmt.Sprintf("verdict on the incident, one of %s", strings.Join(feedbackVerdicts, ", ")))
+	feedbackCommand.Flags().StringVar(&feedbackCmd.reason, "reason", "", "why the incident is wrong or right")
+	feedbackCommand.Flags().BoolVar(&feedbackCmd.submit, "submit", false, "submit the feedback to KANTRA_FEEDBACK_URL, or print a link opening an issue for the rulesets maintainers when not set")
+	feedbackCommand.MarkFlagRequired("output")
+	feedbackCommand.MarkFlagRequired("incident")
+
+	listCommand := &cobra.Command{
+		Use:   "list",
+		Short: "List the recorded feedback",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return feedbackCmd.List(os.Stdout)
+		},
+	}
+	listCommand.Flags().StringVar(&feedbackCmd.format, "format", listFormatText, "output format. Must be one of 'text', 'json' or 'yaml'")
+	feedbackCommand.AddCommand(listCommand)
+	return feedbackCommand
+}
+
+// Run records the feedback on the incident and submits it when asked
+func (f *feedbackCommand) Run(out io.Writer) error {
+	outputPath := f.output
+	if stat, err := os.Stat(outputPath); err == nil && stat.IsDir() {
+		outputPath = filepath.Join(outputPath, "output.yaml")
+	}
+	rulesets, err := readRuleSetsOutput(outputPath)
+	if err != nil {
+		return err
+	}
+	feedback, err := findIncident(rulesets, f.incident)
+	if err != nil {
+		return err
+	}
+	feedback.Verdict, feedback.Reason = f.verdict, f.reason
+	feedback.Version, feedback.Time = Version, time.Now().UTC()
+	if f.submit && Settings.FeedbackURL != "" {
+		token, err := settingSecrets(Settings.FeedbackToken)
+		if err != nil {
+			return err
+		}
+		if err := submitFeedback(Settings.FeedbackURL, token[0], feedback); err != nil {
+			return err
+		}
+		feedback.Submitted = true
+		fmt.Fprintf(out, "feedback submitted to %s\n", Settings.FeedbackURL)
+	}
+	path, err := feedbackFile()
+	if err != nil {
+		return err
+	}
+	if err := appendFeedback(path, feedback); err != nil {
+		return err
+	}
+	f.log.V(1).Info("recorded feedback", "incident", feedback.Incident, "path", path)
+	fmt.Fprintf(out, "feedback on incident %s of rule %s recorded in %s\n", feedback.Incident, feedback.RuleID, path)
+	if f.submit && !feedback.Submitted {
+		fmt.Fprintf(out, "open an issue for the rulesets maintainers: %s\n", feedbackIssueURL(Settings.FeedbackIssuesURL, feedback))
+	}
+	return nil
+}
+
+// List prints the recorded feedback
+func (f *feedbackCommand) List(out io.Writer) error {
+	path, err := feedbackFile()
+	if err != nil {
+		return err
+	}
+	records, err := readFeedback(path)
+	if err != nil {
+		return err
+	}
+	if f.format != listFormatText {
+		return writeListOutput(out, f.format, records)
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "INCIDENT\tRULE\tVERDICT\tSUBMITTED\tREASON")
+	for _, r := range records {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", r.Incident, r.RuleID, r.Verdict, r.Submitted, r.Reason)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_feedbackCommand_Run(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("HOME", home)
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "output.yaml"), explainTestOutput)
+	rulesets, err := readRuleSetsOutput(filepath.Join(dir, "output.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := incidentID("eap8", "rule-1", rulesets[0].Violations["rule-1"].Incidents[2])
+
+	submitted := []incidentFeedback{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		feedback := incidentFeedback{}
+		json.NewDecoder(r.Body).Decode(&feedback)
+		submitted = append(submitted, feedback)
+	}))
+	defer server.Close()
+	defer func(endpoint, token string) {
+		Settings.FeedbackURL, Settings.FeedbackToken = endpoint, token
+	}(Settings.FeedbackURL, Settings.FeedbackToken)
+	Settings.FeedbackURL, Settings.FeedbackToken = server.URL, "token"
+
+	f := &feedbackCommand{output: dir, incident: id, verdict: verdictFalsePositive, reason: "jms is not used", submit: true, log: logr.Discard()}
+	if err := f.Run(&bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(submitted) != 1 || submitted[0].URI != "/src/C.java" || submitted[0].Reason != "jms is not used" {
+		t.Errorf("submitted feedback = %v, want the feedback on /src/C.java", submitted)
+	}
+
+	Settings.FeedbackURL = ""
+	f = &feedbackCommand{output: dir, incident: id, verdict: verdictTruePositive, submit: true, log: logr.Discard()}
+	out := &bytes.Buffer{}
+	if err := f.Run(out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "?body=") {
+		t.Errorf("Run() without a feedback endpoint = %s, want an issue link", out)
+	}
+
+	path, err := feedbackFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := readFeedback(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || !records[0].Submitted || records[1].Submitted || records[1].Verdict != verdictTruePositive {
+		t.Errorf("readFeedback() = %v", records)
+	}
+
+	f = &feedbackCommand{output: dir, incident: "unknown", verdict: verdictFalsePositive, log: logr.Discard()}
+	if err := f.Run(out); err == nil {
+		t.Errorf("Run() must fail for an unknown incident")
+	}
+}
+
+func Test_feedbackIssueURL(t *testing.T) {
+	line := 7
+	link := feedbackIssueURL("https://github.com/konveyor/rulesets/issues/new", &incidentFeedback{
+		RuleSet: "eap8", RuleID: "rule-1", URI: "/src/C.java", LineNumber: &line, Verdict: verdictFalsePositive,
+	})
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Query().Get("title") != "false-positive: rule-1" || !strings.Contains(u.Query().Get("body"), "`/src/C.java:7`") {
+		t.Errorf("feedbackIssueURL() = %s", link)
+	}
+}
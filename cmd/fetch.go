@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// attempts of image pulls and downloads failing with network errors
+const fetchAttempts = 4
+
+// delay before the first retry, doubled for each retry
+var fetchBackoff = 2 * time.Second
+
+// kinds of errors of image pulls and downloads, only network errors are
+// retried
+const (
+	fetchErrorAuth     = "authentication"
+	fetchErrorNetwork  = "network"
+	fetchErrorDisk     = "disk"
+	fetchErrorNotFound = "not found"
+	fetchErrorUnknown  = "unknown"
+)
+
+// messages of the container tools and of the OS by kind of error, checked
+// in order
+var fetchErrorMessages = []struct {
+	kind     string
+	messages []string
+}{
+	{fetchErrorDisk, []string{"no space left on device", "disk quota exceeded", "read-only file system"}},
+	{fetchErrorAuth, []string{"unauthorized", "authentication required", "access denied", "denied: ", "invalid username/password"}},
+	{fetchErrorNotFound, []string{"manifest unknown", "not found", "no matching manifest"}},
+	{fetchErrorNetwork, []string{"timeout", "timed out", "connection reset", "connection refused", "no such host",
+		"network is unreachable", "tls handshake", "temporary failure", "unexpected eof", "broken pipe", "toomanyrequests"}},
+}
+
+// fetchError is a failed image pull or download, its kind tells whether
+// to check credentials, the network or the disk
+type fetchError struct {
+	kind string
+	// what was fetched, e.g. image quay.io/konveyor/kantra
+	what string
+	err  error
+}
+
+func (e *fetchError) Error() string {
+	hint := ""
+	switch e.kind {
+	case fetchErrorAuth:
+		hint = ", check the credentials of the registry or server"
+	case fetchErrorNetwork:
+		hint = ", check the network connection and proxy settings"
+	case fetchErrorDisk:
+		hint = ", free up disk space"
+	}
+	return fmt.Sprintf("%s error fetching %s%s: %v", e.kind, e.what, hint, e.err)
+}
+
+func (e *fetchError) Unwrap() error {
+	return e.err
+}
+
+// classifyFetchError returns the kind of error of a failed fetch from the
+// error and the output of the command fetching
+func classifyFetchError(err error, output string) string {
+	if errors.Is(err, syscall.ENOSPC) {
+		return fetchErrorDisk
+	}
+	text := strings.ToLower(output + " " + err.Error())
+	for _, kind := range fetchErrorMessages {
+		for _, message := range kind.messages {
+			if strings.Contains(text, message) {
+				return kind.kind
+			}
+		}
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return fetchErrorNetwork
+	}
+	return fetchErrorUnknown
+}
+
+// retryFetch runs fetch until it succeeds, retrying network errors with
+// backoff
+func retryFetch(ctx context.Context, log logr.Logger, what string, fetch func() error) error {
+	delay := fetchBackoff
+	for attempt := 1; ; attempt++ {
+		err := fetch()
+		if err == nil {
+			return nil
+		}
+		fetchErr := &fetchError{}
+		if !errors.As(err, &fetchErr) {
+			fetchErr = &fetchError{kind: classifyFetchError(err, ""), what: what, err: err}
+		}
+		if fetchErr.kind != fetchErrorNetwork || attempt == fetchAttempts {
+			return fetchErr
+		}
+		log.Info("retrying after network error", "what", what, "attempt", attempt+1, "of", fetchAttempts, "in", delay.String(), "error", err.Error())
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// pullImage pulls an image, printing the progress of the container tool to
+// progress and retrying network errors
+func pullImage(ctx context.Context, log logr.Logger, progress io.Writer, image string, platform string) error {
+	args := []string{"pull"}
+	if platform != "" {
+		args = append(args, "--platform", platform)
+	}
+	what := fmt.Sprintf("image %s", image)
+	return retryFetch(ctx, log, what, func() error {
+		out := &bytes.Buffer{}
+		cmd := exec.CommandContext(ctx, Settings.ContainerBinary, append(args, image)...)
+		cmd.Stdout = io.MultiWriter(out, progress)
+		cmd.Stderr = io.MultiWriter(out, progress)
+		if err := cmd.Run(); err != nil {
+			output := strings.TrimSpace(out.String())
+			return &fetchError{kind: classifyFetchError(err, output), what: what, err: fmt.Errorf("%w %s", err, lastLine(output))}
+		}
+		return nil
+	})
+}
+
+// lastLine returns the last line of the output of a command, where the
+// container tools print their errors after the progress
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// downloadFile downloads url to dest, retrying network errors. Retries
+// resume the download when the server supports ranges.
+func downloadFile(log logr.Logger, url string, dest string) error {
+	if err := os.Remove(dest); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return retryFetch(context.Background(), log, url, func() error {
+		return resumeDownload(url, dest)
+	})
+}
+
+// resumeDownload downloads the rest of url to dest, of which the size of
+// dest was downloaded
+func resumeDownload(url string, dest string) error {
+	offset := int64(0)
+	if stat, err := os.Stat(dest); err == nil {
+		offset = stat.Size()
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusPartialContent:
+		flags = os.O_WRONLY | os.O_APPEND
+	default:
+		return &fetchError{kind: statusErrorKind(resp.StatusCode), what: url, err: fmt.Errorf("unexpected status %s downloading %s", resp.Status, url)}
+	}
+	file, err := os.OpenFile(dest, flags, 0644)
+	if err != nil {
+		return &fetchError{kind: classifyFetchError(err, ""), what: url, err: err}
+	}
+	defer file.Close()
+	if _, err := io.Copy(diskWriter{w: file, what: url}, resp.Body); err != nil {
+		return err
+	}
+	return nil
+}
+
+func statusErrorKind(status int) string {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return fetchErrorAuth
+	case status == http.StatusNotFound:
+		return fetchErrorNotFound
+	case status == http.StatusTooManyRequests || status == http.StatusRequestTimeout || status >= 500:
+		return fetchErrorNetwork
+	}
+	return fetchErrorUnknown
+}
+
+// diskWriter tells errors writing to the disk from errors reading from the
+// network
+type diskWriter struct {
+	w    io.Writer
+	what string
+}
+
+func (d diskWriter) Write(p []byte) (int, error) {
+	n, err := d.w.Write(p)
+	if err != nil {
+		return n, &fetchError{kind: fetchErrorDisk, what: d.what, err: err}
+	}
+	return n, nil
+}
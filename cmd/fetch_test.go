@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_classifyFetchError(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"Error: initializing source docker://quay.io/konveyor/kantra:latest: reading manifest latest in quay.io/konveyor/kantra: unauthorized: access to the requested resource is not authorized", fetchErrorAuth},
+		{"Error: copying system image from manifest list: writing blob: storing blob to file \"/var/tmp/storage\": write /var/tmp/storage: no space left on device", fetchErrorDisk},
+		{"Error: initializing source docker://quay.io/konveyor/kantra:v9: reading manifest v9 in quay.io/konveyor/kantra: manifest unknown", fetchErrorNotFound},
+		{"Error: pinging container registry quay.io: Get \"https://quay.io/v2/\": dial tcp: lookup quay.io: no such host", fetchErrorNetwork},
+		{"Error: reading blob sha256:4404: Get \"https://cdn.quay.io/blob\": net/http: TLS handshake timeout", fetchErrorNetwork},
+		{"Error: short-name resolution enforced", fetchErrorUnknown},
+	}
+	for _, tt := range tests {
+		if got := classifyFetchError(errors.New("exit status 125"), tt.output); got != tt.want {
+			t.Errorf("classifyFetchError(%q) = %s, want %s", tt.output, got, tt.want)
+		}
+	}
+}
+
+func Test_downloadFile(t *testing.T) {
+	defer func(backoff time.Duration) { fetchBackoff = backoff }(fetchBackoff)
+	fetchBackoff = 0
+	content := bytes.Repeat([]byte("kantra"), 4096)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/flaky":
+			// the first download breaks off half way
+			if requests == 1 {
+				w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+				w.Write(content[:len(content)/2])
+				panic(http.ErrAbortHandler)
+			}
+			if want := fmt.Sprintf("bytes=%d-", len(content)/2); r.Header.Get("Range") != want {
+				t.Errorf("downloadFile() range = %q, want %q", r.Header.Get("Range"), want)
+			}
+			http.ServeContent(w, r, "rulesets.tar.gz", time.Time{}, bytes.NewReader(content))
+		}
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "rulesets.tar.gz")
+	if err := downloadFile(logr.Discard(), server.URL+"/flaky", dest); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) || requests != 2 {
+		t.Errorf("downloadFile() = %d bytes in %d requests, want %d bytes in 2 requests", len(got), requests, len(content))
+	}
+
+	requests = 0
+	err = downloadFile(logr.Discard(), server.URL+"/missing", dest)
+	fetchErr := &fetchError{}
+	if !errors.As(err, &fetchErr) || fetchErr.kind != fetchErrorNotFound || requests != 1 {
+		t.Errorf("downloadFile() = %v after %d requests, want a not found error without retries", err, requests)
+	}
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// number of bytes inspected when looking for binary content, same as git
+const binarySniffLen = 8000
+
+var byteSizeUnits = []struct {
+	suffix string
+	factor int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"G", 1 << 30},
+	{"M", 1 << 20},
+	{"K", 1 << 10},
+	{"B", 1},
+}
+
+// parseByteSize parses sizes like 512K, 2MB or 1GB, units are powers of 1024
+func parseByteSize(s string) (int64, error) {
+	size := strings.ToUpper(strings.TrimSpace(s))
+	factor := int64(1)
+	for _, unit := range byteSizeUnits {
+		if strings.HasSuffix(size, unit.suffix) {
+			size = strings.TrimSpace(strings.TrimSuffix(size, unit.suffix))
+			factor = unit.factor
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(size, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(value * float64(factor)), nil
+}
+
+// formatByteSize formats a size with the largest unit of parseByteSize
+func formatByteSize(size int64) string {
+	for _, unit := range byteSizeUnits[:3] {
+		if size >= unit.factor {
+			return fmt.Sprintf("%.1f%s", float64(size)/float64(unit.factor), unit.suffix)
+		}
+	}
+	return fmt.Sprintf("%dB", size)
+}
+
+// isBinaryFile returns true when the beginning of the file contains a NUL byte
+func isBinaryFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	buf := make([]byte, binarySniffLen)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+	return bytes.IndexByte(buf[:n], 0) != -1, nil
+}
+
+// findSkippedFiles returns the files under root that are not ignored and
+// are either larger than maxSize or binary when skipBinary is set
+func findSkippedFiles(root string, ignore *kantraIgnore, maxSize int64, skipBinary bool) ([]string, error) {
+	skipped := []string{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if ignore.Ignored(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if maxSize > 0 {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if info.Size() > maxSize {
+				skipped = append(skipped, rel)
+				return nil
+			}
+		}
+		if skipBinary {
+			binary, err := isBinaryFile(p)
+			if err != nil {
+				return err
+			}
+			if binary {
+				skipped = append(skipped, rel)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return skipped, nil
+}
+
+// applyFilePolicy computes the paths the builtin provider searches when
+// large or binary files are to be skipped, other providers are not affected
+func (a *analyzeCommand) applyFilePolicy(ignore *kantraIgnore) error {
+	if a.maxFileSize == "" && !a.skipBinaryFiles {
+		return nil
+	}
+	maxSize := int64(0)
+	if a.maxFileSize != "" {
+		var err error
+		maxSize, err = parseByteSize(a.maxFileSize)
+		if err != nil {
+			return fmt.Errorf("%w for --max-file-size", err)
+		}
+	}
+	skipped, err := findSkippedFiles(a.input, ignore, maxSize, a.skipBinaryFiles)
+	if err != nil {
+		return fmt.Errorf("%w failed to check input file sizes", err)
+	}
+	if len(skipped) == 0 {
+		return nil
+	}
+	builtinIgnore := ignore.clone()
+	for _, rel := range skipped {
+		a.log.V(1).Info("skipping file in builtin provider", "path", rel)
+		builtinIgnore.exclude(rel)
+		file := skippedFile{Path: rel, Provider: builtinProvider, Reason: skipReasonBinary}
+		if info, err := os.Stat(filepath.Join(a.input, filepath.FromSlash(rel))); err == nil && maxSize > 0 && info.Size() > maxSize {
+			file.Reason, file.Detail = skipReasonSize, fmt.Sprintf("%d bytes", info.Size())
+		}
+		a.addSkipped(file)
+	}
+	a.builtinPaths, err = builtinIgnore.IncludedPaths(a.input)
+	if err != nil {
+		return err
+	}
+	if len(a.builtinPaths) == 0 {
+		return fmt.Errorf("all files of the input are skipped by --max-file-size or --skip-binary-files")
+	}
+	a.skippedFiles = len(skipped)
+	a.log.Info("skipping large or binary files in builtin provider", "files", a.skippedFiles)
+	return nil
+}
+
+// builtinIncludedPaths returns the paths the builtin provider searches
+func (a *analyzeCommand) builtinIncludedPaths() []string {
+	if a.builtinPaths != nil {
+		return a.builtinPaths
+	}
+	return a.includedPaths
+}
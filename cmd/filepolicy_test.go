@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_parseByteSize(t *testing.T) {
+	tests := []struct {
+		size    string
+		want    int64
+		wantErr bool
+	}{
+		{size: "100", want: 100},
+		{size: "512K", want: 512 << 10},
+		{size: "2MB", want: 2 << 20},
+		{size: "1.5 gb", want: 3 << 29},
+		{size: "big", wantErr: true},
+		{size: "-1MB", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			got, err := parseByteSize(tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseByteSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseByteSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_findSkippedFiles(t *testing.T) {
+	root := t.TempDir()
+	files := map[string][]byte{
+		"src/App.java":        []byte("class App {}"),
+		"vendor/blob.txt":     make([]byte, 2048),
+		"lib/archive.jar":     {'P', 'K', 3, 4, 0, 0},
+		"target/ignored.bin":  {0, 1, 2},
+		"docs/large-text.txt": bytes.Repeat([]byte("a"), 4096),
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ignore := &kantraIgnore{patterns: []ignorePattern{{pattern: "target", dirOnly: true}}}
+
+	got, err := findSkippedFiles(root, ignore, 1024, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"docs/large-text.txt", "vendor/blob.txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("findSkippedFiles() by size = %v, want %v", got, want)
+	}
+
+	got, err = findSkippedFiles(root, ignore, 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"lib/archive.jar", "vendor/blob.txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("findSkippedFiles() binary = %v, want %v", got, want)
+	}
+}
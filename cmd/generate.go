@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+)
+
+// java versions with runtime images, the version of the input is rounded
+// up to the next one
+var javaRuntimeVersions = []int{8, 11, 17, 21}
+
+// default java version when the input does not set one
+const defaultJavaVersion = 17
+
+// port exposed when none of the components of the input has one
+const defaultContainerPort = 8080
+
+// tags of the discovery rules of the analysis selecting the stack of java
+// applications, applications using Java EE run on an application server
+var stackTags = []struct {
+	stack string
+	tags  []string
+}{
+	{javaStackQuarkus, []string{"Quarkus"}},
+	{javaStackSpringBoot, []string{"Spring Boot"}},
+	{javaStackAppServer, []string{"Java EE", "Jakarta EE", "EJB", "JSF", "JBoss EAP", "WildFly", "Servlet", "JSP", "JAX-RS", "JAX-WS"}},
+}
+
+// stacks of the generated containerfiles
+const (
+	javaStackQuarkus    = "quarkus"
+	javaStackSpringBoot = "spring-boot"
+	javaStackAppServer  = "app-server"
+	javaStackJar        = "java"
+	stackNodeJS         = "nodejs"
+	stackPython         = "python"
+	stackGo             = "go"
+	stackDotnet         = "dotnet"
+)
+
+type generateDockerfileCommand struct {
+	inputs        []string
+	output        string
+	containerfile bool
+	overwrite     bool
+	log           logr.Logger
+}
+
+// containerSpec is what the containerfile of an application is generated
+// from
+type containerSpec struct {
+	Stack       string
+	JavaVersion int
+	BuildTool   string
+	Ports       []int
+}
+
+// BuildDir returns the dir the build tool writes the application to
+func (s containerSpec) BuildDir() string {
+	if s.BuildTool == "gradle" {
+		return "build/libs"
+	}
+	return "target"
+}
+
+func NewGenerateCommand(log logr.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate assets from the analysis of applications",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+	cmd.AddCommand(newGenerateDockerfileCommand(log))
+	return cmd
+}
+
+func newGenerateDockerfileCommand(log logr.Logger) *cobra.Command {
+	dockerfileCmd := &generateDockerfileCommand{log: log}
+	cmd := &cobra.Command{
+		Use:   "dockerfile",
+		Short: "Generate a Dockerfile and .dockerignore for applications",
+		Long: "Generate a Dockerfile and .dockerignore in the dir of each application from its detected language, framework\n" +
+			"and Java version, and the tags of the discovery rules of its analysis when --output is set",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := dockerfileCmd.Run(); err != nil {
+				log.Error(err, "failed to generate dockerfile")
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringArrayVarP(&dockerfileCmd.inputs, "input", "i", []string{}, "path to application source code directory, can be set multiple times")
+	cmd.Flags().StringVarP(&dockerfileCmd.output, "output", "o", "", "path to the output dir of an analysis of the applications, analyzed alone or with --bulk")
+	cmd.Flags().BoolVar(&dockerfileCmd.containerfile, "containerfile", false, "write a Containerfile and .containerignore instead")
+	cmd.Flags().BoolVar(&dockerfileCmd.overwrite, "overwrite", false, "overwrite existing files")
+	cmd.MarkFlagRequired("input")
+	return cmd
+}
+
+// analysisTags returns the tags of the analysis of the application in the
+// output dir, nil when it was not analyzed
+func analysisTags(output string, app string) ([]string, error) {
+	if output == "" {
+		return nil, nil
+	}
+	path := filepath.Join(bulkAppDir(output, filepath.Base(app)), "output.yaml")
+	if existingFile(outputFile(path)) == "" {
+		path = filepath.Join(output, "output.yaml")
+	}
+	rulesets, err := readRuleSetsOutput(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	tags := []string{}
+	for _, rs := range rulesets {
+		for _, tag := range rs.Tags {
+			// tags may be set as category=value1,value2
+			if category, values, ok := strings.Cut(tag, "="); ok {
+				tags = append(tags, category)
+				tag = values
+			}
+			for _, value := range strings.Split(tag, ",") {
+				tags = append(tags, strings.TrimSpace(value))
+			}
+		}
+	}
+	return uniqueStrings(tags), nil
+}
+
+// javaStack returns the stack of a java application from its frameworks
+// and tags
+func javaStack(frameworks []string, tags []string) string {
+	for _, s := range stackTags {
+		for _, tag := range s.tags {
+			if slices.Contains(frameworks, tag) || slices.Contains(tags, tag) {
+				return s.stack
+			}
+		}
+	}
+	return javaStackJar
+}
+
+// runtimeJavaVersion returns the java version with a runtime image the
+// application runs on
+func runtimeJavaVersion(version int) int {
+	if version == 0 {
+		return defaultJavaVersion
+	}
+	for _, v := range javaRuntimeVersions {
+		if v >= version {
+			return v
+		}
+	}
+	return javaRuntimeVersions[len(javaRuntimeVersions)-1]
+}
+
+// newContainerSpec picks the stack of the language with the highest
+// confidence the generator supports
+func newContainerSpec(report *languageReport, javaVersion int, tags []string) (containerSpec, error) {
+	for _, l := range report.Languages {
+		spec := containerSpec{Ports: l.Ports}
+		if len(spec.Ports) == 0 {
+			spec.Ports = []int{defaultContainerPort}
+		}
+		switch l.Name {
+		case "Java":
+			spec.Stack = javaStack(l.Frameworks, tags)
+			spec.JavaVersion = runtimeJavaVersion(javaVersion)
+			spec.BuildTool = "maven"
+			if slices.Contains(l.Tools, "Gradle") {
+				spec.BuildTool = "gradle"
+			}
+		case "JavaScript", "TypeScript":
+			spec.Stack = stackNodeJS
+		case "Python":
+			spec.Stack = stackPython
+		case "Go":
+			spec.Stack = stackGo
+		case "C#":
+			spec.Stack = stackDotnet
+		default:
+			continue
+		}
+		return spec, nil
+	}
+	return containerSpec{}, fmt.Errorf("no language supported by the generator detected")
+}
+
+const javaBuildStage = `FROM registry.access.redhat.com/ubi9/openjdk-{{ .JavaVersion }}:latest AS build
+USER root
+WORKDIR /build
+COPY . .
+{{ if eq .BuildTool "gradle" -}}
+RUN ./gradlew build -x test --no-daemon
+{{- else -}}
+RUN mvn -B package -DskipTests
+{{- end }}
+`
+
+var dockerfileTemplates = map[string]string{
+	javaStackQuarkus: javaBuildStage + `
+FROM registry.access.redhat.com/ubi9/openjdk-{{ .JavaVersion }}-runtime:latest
+COPY --from=build /build/{{ if eq .BuildTool "gradle" }}build{{ else }}target{{ end }}/quarkus-app/lib/ /deployments/lib/
+COPY --from=build /build/{{ if eq .BuildTool "gradle" }}build{{ else }}target{{ end }}/quarkus-app/*.jar /deployments/
+COPY --from=build /build/{{ if eq .BuildTool "gradle" }}build{{ else }}target{{ end }}/quarkus-app/app/ /deployments/app/
+COPY --from=build /build/{{ if eq .BuildTool "gradle" }}build{{ else }}target{{ end }}/quarkus-app/quarkus/ /deployments/quarkus/
+ENV JAVA_APP_JAR="/deployments/quarkus-run.jar"
+`,
+	javaStackSpringBoot: javaBuildStage + `
+FROM registry.access.redhat.com/ubi9/openjdk-{{ .JavaVersion }}-runtime:latest
+COPY --from=build /build/{{ .BuildDir }}/*.jar /deployments/app.jar
+`,
+	javaStackJar: javaBuildStage + `
+FROM registry.access.redhat.com/ubi9/openjdk-{{ .JavaVersion }}-runtime:latest
+COPY --from=build /build/{{ .BuildDir }}/*.jar /deployments/
+`,
+	javaStackAppServer: javaBuildStage + `
+FROM quay.io/wildfly/wildfly:latest-jdk{{ .JavaVersion }}
+COPY --from=build /build/{{ .BuildDir }}/*.war /opt/jboss/wildfly/standalone/deployments/
+`,
+	stackNodeJS: `FROM registry.access.redhat.com/ubi9/nodejs-20:latest
+WORKDIR /opt/app-root/src
+COPY --chown=1001:0 package*.json ./
+RUN npm ci --omit=dev
+COPY --chown=1001:0 . .
+CMD ["npm", "start"]
+`,
+	stackPython: `FROM registry.access.redhat.com/ubi9/python-311:latest
+WORKDIR /opt/app-root/src
+COPY --chown=1001:0 requirements.txt ./
+RUN pip install --no-cache-dir -r requirements.txt
+COPY --chown=1001:0 . .
+CMD ["python", "app.py"]
+`,
+	stackGo: `FROM registry.access.redhat.com/ubi9/go-toolset:latest AS build
+COPY --chown=1001:0 . .
+RUN CGO_ENABLED=0 go build -o /tmp/app .
+
+FROM registry.access.redhat.com/ubi9/ubi-minimal:latest
+COPY --from=build /tmp/app /usr/local/bin/app
+USER 1001
+CMD ["/usr/local/bin/app"]
+`,
+	stackDotnet: `FROM registry.access.redhat.com/ubi9/dotnet-80:latest AS build
+COPY --chown=1001:0 . .
+RUN dotnet publish -c Release -o /tmp/app
+
+FROM registry.access.redhat.com/ubi9/dotnet-80-runtime:latest
+COPY --from=build /tmp/app .
+CMD ["dotnet", "app.dll"]
+`,
+}
+
+// ignored files of the build context by stack, the build runs in the
+// container
+var dockerignoreEntries = map[string][]string{
+	javaStackQuarkus:    {"target/", "build/", ".gradle/"},
+	javaStackSpringBoot: {"target/", "build/", ".gradle/"},
+	javaStackJar:        {"target/", "build/", ".gradle/"},
+	javaStackAppServer:  {"target/", "build/", ".gradle/"},
+	stackNodeJS:         {"node_modules/", "npm-debug.log"},
+	stackPython:         {"__pycache__/", "*.pyc", ".venv/", "venv/"},
+	stackGo:             {"vendor/"},
+	stackDotnet:         {"bin/", "obj/"},
+}
+
+// generateDockerfile returns the containerfile and ignore file of the spec
+func generateDockerfile(spec containerSpec) ([]byte, []byte, error) {
+	tmpl, err := template.New(spec.Stack).Parse(dockerfileTemplates[spec.Stack])
+	if err != nil {
+		return nil, nil, err
+	}
+	dockerfile := &bytes.Buffer{}
+	fmt.Fprintf(dockerfile, "# generated by kantra for a %s application, review before use\n", spec.Stack)
+	if err := tmpl.Execute(dockerfile, spec); err != nil {
+		return nil, nil, err
+	}
+	ports := []string{}
+	for _, p := range spec.Ports {
+		ports = append(ports, fmt.Sprint(p))
+	}
+	fmt.Fprintf(dockerfile, "EXPOSE %s\n", strings.Join(ports, " "))
+	ignore := append([]string{".git/", "Dockerfile", "Containerfile", ".dockerignore", ".containerignore"}, dockerignoreEntries[spec.Stack]...)
+	return dockerfile.Bytes(), []byte(strings.Join(ignore, "\n") + "\n"), nil
+}
+
+// writeNewFile writes a file unless it exists and overwrite is false
+func writeNewFile(path string, data []byte, overwrite bool) error {
+	if _, err := os.Stat(path); err == nil && !overwrite {
+		return fmt.Errorf("%s already exists, use --overwrite to replace it", path)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (g *generateDockerfileCommand) Run() error {
+	names := []string{"Dockerfile", ".dockerignore"}
+	if g.containerfile {
+		names = []string{"Containerfile", ".containerignore"}
+	}
+	for _, input := range g.inputs {
+		stat, err := os.Stat(input)
+		if err != nil {
+			return fmt.Errorf("%w failed to stat input %s", err, input)
+		}
+		if !stat.IsDir() {
+			return fmt.Errorf("input %s must be a directory", input)
+		}
+		report, err := detectLanguages(input)
+		if err != nil {
+			return fmt.Errorf("%w failed to determine languages of %s", err, input)
+		}
+		javaVersion, err := (&analyzeCommand{input: input}).projectJavaVersion()
+		if err != nil {
+			g.log.V(1).Info("failed to detect java version", "input", input, "error", err)
+		}
+		tags, err := analysisTags(g.output, input)
+		if err != nil {
+			return err
+		}
+		spec, err := newContainerSpec(report, javaVersion, tags)
+		if err != nil {
+			return fmt.Errorf("%w in %s", err, input)
+		}
+		dockerfile, ignore, err := generateDockerfile(spec)
+		if err != nil {
+			return err
+		}
+		if err := writeNewFile(filepath.Join(input, names[0]), dockerfile, g.overwrite); err != nil {
+			return err
+		}
+		if err := writeNewFile(filepath.Join(input, names[1]), ignore, g.overwrite); err != nil {
+			return err
+		}
+		g.log.Info("generated containerfile", "input", input, "stack", spec.Stack, "file", filepath.Join(input, names[0]))
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_analysisTags(t *testing.T) {
+	output := t.TempDir()
+	writeTestFile(t, filepath.Join(bulkAppDir(output, "cart"), "output.yaml"), `- name: discovery
+  tags:
+  - Java EE=EJB,JPA
+  - Spring Boot
+`)
+	tags, err := analysisTags(output, "/src/cart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"EJB", "JPA", "Java EE", "Spring Boot"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("analysisTags() = %v, want %v", tags, want)
+	}
+	tags, err = analysisTags(output, "/src/orders")
+	if err != nil || tags != nil {
+		t.Errorf("analysisTags() of an application not analyzed = %v, %v", tags, err)
+	}
+}
+
+func Test_newContainerSpec(t *testing.T) {
+	tests := []struct {
+		name        string
+		languages   []detectedLanguage
+		javaVersion int
+		tags        []string
+		want        containerSpec
+		wantErr     bool
+	}{
+		{
+			name:        "spring boot with gradle",
+			languages:   []detectedLanguage{{Name: "Java", Frameworks: []string{"Spring Boot"}, Tools: []string{"Gradle"}, Ports: []int{8081}}},
+			javaVersion: 11,
+			want:        containerSpec{Stack: javaStackSpringBoot, JavaVersion: 11, BuildTool: "gradle", Ports: []int{8081}},
+		},
+		{
+			name:        "java ee from analysis tags",
+			languages:   []detectedLanguage{{Name: "Java", Tools: []string{"Maven"}}},
+			javaVersion: 7,
+			tags:        []string{"EJB"},
+			want:        containerSpec{Stack: javaStackAppServer, JavaVersion: 8, BuildTool: "maven", Ports: []int{defaultContainerPort}},
+		},
+		{
+			name:      "java version unknown",
+			languages: []detectedLanguage{{Name: "Java"}},
+			want:      containerSpec{Stack: javaStackJar, JavaVersion: defaultJavaVersion, BuildTool: "maven", Ports: []int{defaultContainerPort}},
+		},
+		{
+			name:      "first supported language",
+			languages: []detectedLanguage{{Name: "HTML"}, {Name: "Python", Ports: []int{5000}}},
+			want:      containerSpec{Stack: stackPython, Ports: []int{5000}},
+		},
+		{
+			name:      "no supported language",
+			languages: []detectedLanguage{{Name: "Shell"}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newContainerSpec(&languageReport{Languages: tt.languages}, tt.javaVersion, tt.tags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newContainerSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newContainerSpec() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_generateDockerfile(t *testing.T) {
+	dockerfile, ignore, err := generateDockerfile(containerSpec{Stack: javaStackAppServer, JavaVersion: 17, BuildTool: "maven", Ports: []int{8080, 8443}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"FROM registry.access.redhat.com/ubi9/openjdk-17:latest AS build\n",
+		"RUN mvn -B package -DskipTests\n",
+		"FROM quay.io/wildfly/wildfly:latest-jdk17\n",
+		"COPY --from=build /build/target/*.war /opt/jboss/wildfly/standalone/deployments/\n",
+		"EXPOSE 8080 8443\n",
+	} {
+		if !strings.Contains(string(dockerfile), line) {
+			t.Errorf("generateDockerfile() dockerfile misses %q:\n%s", line, dockerfile)
+		}
+	}
+	if !strings.Contains(string(ignore), "target/\n") {
+		t.Errorf("generateDockerfile() ignore file misses target/:\n%s", ignore)
+	}
+}
+
+func Test_generateDockerfileCommand_Run(t *testing.T) {
+	input := t.TempDir()
+	writeTestFile(t, filepath.Join(input, "pom.xml"), `<project>
+  <properties><maven.compiler.release>21</maven.compiler.release></properties>
+</project>`)
+	writeTestFile(t, filepath.Join(input, "src/main/java/App.java"), "class App {}\n")
+	g := &generateDockerfileCommand{inputs: []string{input}, containerfile: true, log: logr.Discard()}
+	if err := g.Run(); err != nil {
+		t.Fatal(err)
+	}
+	containerfile, err := os.ReadFile(filepath.Join(input, "Containerfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(containerfile), "openjdk-21-runtime") {
+		t.Errorf("Containerfile does not run on java 21:\n%s", containerfile)
+	}
+	if _, err := os.Stat(filepath.Join(input, ".containerignore")); err != nil {
+		t.Error(err)
+	}
+	if err := g.Run(); err == nil {
+		t.Error("Run() overwrote the existing Containerfile")
+	}
+	g.overwrite = true
+	if err := g.Run(); err != nil {
+		t.Errorf("Run() with overwrite: %v", err)
+	}
+}
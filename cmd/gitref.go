@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// runGit runs git in dir and returns its trimmed output
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w git %s: %s", err, args[0], strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// gitWorktree is a detached worktree of the repository of the input at a
+// ref, the checkout of the user is left untouched
+type gitWorktree struct {
+	repo   string
+	dir    string
+	commit string
+	// input in the worktree, the input may be a dir of the repository
+	input string
+}
+
+// addGitWorktree checks out the commit of ref of the repository of input
+// into a worktree in a new dir of parent, named as the repository so that
+// the application keeps its name in the report
+func addGitWorktree(input string, ref string, parent string) (*gitWorktree, error) {
+	repo, err := runGit(input, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%w input %s is not in a git repository", err, input)
+	}
+	prefix, err := runGit(input, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, err
+	}
+	commit, err := runGit(repo, "rev-parse", "--verify", "--end-of-options", ref+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("%w unknown git ref %s", err, ref)
+	}
+	dir := filepath.Join(parent, filepath.Base(repo))
+	if _, err := runGit(repo, "worktree", "add", "--detach", dir, commit); err != nil {
+		return nil, fmt.Errorf("%w failed to check out git ref %s", err, ref)
+	}
+	return &gitWorktree{
+		repo:   repo,
+		dir:    dir,
+		commit: commit,
+		input:  filepath.Join(dir, filepath.FromSlash(prefix)),
+	}, nil
+}
+
+// remove removes the worktree and its administrative files of the repository
+func (w *gitWorktree) remove() error {
+	_, err := runGit(w.repo, "worktree", "remove", "--force", w.dir)
+	return err
+}
+
+// validateGitRef checks out --git-ref of the repository of the input into a
+// temp worktree, which is analyzed instead of the input
+func (a *analyzeCommand) validateGitRef() error {
+	if a.gitRef == "" {
+		return nil
+	}
+	if a.input == stdinInput {
+		return fmt.Errorf("git-ref is not supported with input from stdin")
+	}
+	stat, err := os.Stat(a.input)
+	if err != nil {
+		return fmt.Errorf("%w failed to stat input path %s", err, a.input)
+	}
+	if !stat.IsDir() {
+		return fmt.Errorf("git-ref requires a source code input in a git repository")
+	}
+	dir, err := os.MkdirTemp("", "kantra-git-ref-")
+	if err != nil {
+		return err
+	}
+	worktree, err := addGitWorktree(a.input, a.gitRef, dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+	a.gitWorktreeDir = dir
+	a.gitWorktree = worktree
+	a.log.Info("analyzing git ref of input", "input", a.input, "ref", a.gitRef, "commit", worktree.commit)
+	a.input = worktree.input
+	return nil
+}
+
+// removeGitWorktree removes the worktree of --git-ref and its temp dir
+func (a *analyzeCommand) removeGitWorktree() error {
+	defer os.RemoveAll(a.gitWorktreeDir)
+	if err := a.gitWorktree.remove(); err != nil {
+		return fmt.Errorf("%w failed to remove git worktree %s", err, a.gitWorktree.dir)
+	}
+	return nil
+}
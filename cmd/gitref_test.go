@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_analyzeCommand_validateGitRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo := filepath.Join(t.TempDir(), "payments")
+	input := filepath.Join(repo, "app")
+	writeTestFile(t, filepath.Join(input, "Cart.java"), "class Cart {}\n")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=Jane Doe", "-c", "user.email=jane@example.com", "commit", "-q", "-m", "init"},
+		{"tag", "v1.2.3"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	// uncommitted change of the checkout of the user
+	writeTestFile(t, filepath.Join(input, "Cart.java"), "class Cart { int items; }\n")
+
+	a := &analyzeCommand{log: logr.Discard(), input: input, gitRef: "v1.2.3"}
+	if err := a.validateGitRef(); err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(a.gitWorktreeDir, "payments", "app"); a.input != want {
+		t.Errorf("validateGitRef() input = %s, want %s", a.input, want)
+	}
+	if got := mustReadFile(t, filepath.Join(a.input, "Cart.java")); got != "class Cart {}\n" {
+		t.Errorf("Cart.java of the ref = %q, want the committed content", got)
+	}
+	if got := mustReadFile(t, filepath.Join(input, "Cart.java")); got != "class Cart { int items; }\n" {
+		t.Errorf("Cart.java of the checkout = %q, want it untouched", got)
+	}
+	if len(a.gitWorktree.commit) != 40 {
+		t.Errorf("validateGitRef() commit = %q, want a commit hash", a.gitWorktree.commit)
+	}
+	if err := a.removeGitWorktree(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(a.gitWorktreeDir); !os.IsNotExist(err) {
+		t.Errorf("worktree dir %s was not removed", a.gitWorktreeDir)
+	}
+
+	a = &analyzeCommand{log: logr.Discard(), input: input, gitRef: "v9.9.9"}
+	if err := a.validateGitRef(); err == nil {
+		t.Errorf("validateGitRef() of an unknown ref succeeded, want error")
+	}
+}
@@ -1,8 +1,9 @@
 package cmd
 
 import (
-	"fmt"
 	"github.com/konveyor/analyzer-lsp/provider"
+
+	kantraprovider "github.com/konveyor-ecosystem/kantra/pkg/provider"
 )
 
 type GoProvider struct {
@@ -10,20 +11,10 @@ type GoProvider struct {
 }
 
 func (p *GoProvider) GetConfigVolume(a *analyzeCommand, tmpDir string) (provider.Config, error) {
-	p.config = provider.Config{
-		Name:    goProvider,
-		Address: fmt.Sprintf("0.0.0.0:%v", a.providersMap[goProvider].port),
-		InitConfig: []provider.InitConfig{
-			{
-				AnalysisMode: provider.FullAnalysisMode,
-				ProviderSpecificConfig: map[string]interface{}{
-					"lspServerName":                 "generic",
-					"workspaceFolders":              []string{fmt.Sprintf("file://%s", SourceMountPath)},
-					"dependencyProviderPath":        "/usr/local/bin/golang-dependency-provider",
-					provider.LspServerPathConfigKey: "/root/go/bin/gopls",
-				},
-			},
-		},
-	}
+	p.config = kantraprovider.GoConfig(kantraprovider.Options{
+		Mode:     kantraprovider.ContainerMode,
+		Location: a.sourceMountPath(),
+		Port:     a.providersMap[goProvider].port,
+	})
 	return p.config, nil
 }
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// groupings of the incidents of output.json, by rule as in output.yaml by
+// default
+const (
+	groupByRule    = "rule"
+	groupByFile    = "file"
+	groupByPackage = "package"
+)
+
+var groupByValues = []string{groupByRule, groupByFile, groupByPackage}
+
+// groupedViolation is the violation of a rule with its incidents of a group
+type groupedViolation struct {
+	RuleSet     string              `json:"ruleset"`
+	Rule        string              `json:"rule"`
+	Description string              `json:"description,omitempty"`
+	Category    *outputv1.Category  `json:"category,omitempty"`
+	Effort      *int                `json:"effort,omitempty"`
+	Labels      []string            `json:"labels,omitempty"`
+	Incidents   []outputv1.Incident `json:"incidents"`
+}
+
+// incidentGroup holds the incidents of a file or package by rule
+type incidentGroup struct {
+	File       string             `json:"file,omitempty"`
+	Package    string             `json:"package,omitempty"`
+	Incidents  int                `json:"incidents"`
+	Violations []groupedViolation `json:"violations"`
+}
+
+// validateGroupBy checks --group-by, the table of the grouping is printed
+// first in the console summary unless --summary-columns is set
+func (a *analyzeCommand) validateGroupBy() error {
+	if a.groupBy == "" {
+		return nil
+	}
+	if !slices.Contains(groupByValues, a.groupBy) {
+		return fmt.Errorf("group-by must be one of %s", strings.Join(groupByValues, ", "))
+	}
+	if slices.Equal(a.summaryColumns, defaultSummaryColumns) {
+		column := map[string]string{
+			groupByRule:    summaryRules,
+			groupByFile:    summaryFiles,
+			groupByPackage: summaryPackages,
+		}[a.groupBy]
+		a.summaryColumns = []string{column, summaryCategories}
+	}
+	return nil
+}
+
+// incidentPackage returns the package of an incident set by its provider,
+// e.g. the java package, or the dir of its file
+func incidentPackage(incident outputv1.Incident) string {
+	if pkg, ok := incident.Variables["package"].(string); ok && pkg != "" {
+		return pkg
+	}
+	return path.Dir(normalizeIncidentURI(incident.URI))
+}
+
+// groupIncidents returns the incidents of the rulesets grouped by file or
+// package, sorted by name, with the violations of each group sorted by
+// ruleset and rule
+func groupIncidents(rulesets []outputv1.RuleSet, groupBy string) []incidentGroup {
+	groups := map[string]*incidentGroup{}
+	for _, rs := range rulesets {
+		for _, ruleID := range sortedMapKeys(rs.Violations) {
+			v := rs.Violations[ruleID]
+			// index of the violation of the rule in each group
+			violations := map[string]int{}
+			for _, incident := range v.Incidents {
+				name := normalizeIncidentURI(incident.URI)
+				if groupBy == groupByPackage {
+					name = incidentPackage(incident)
+				}
+				group, ok := groups[name]
+				if !ok {
+					group = &incidentGroup{Violations: []groupedViolation{}}
+					if groupBy == groupByPackage {
+						group.Package = name
+					} else {
+						group.File = name
+					}
+					groups[name] = group
+				}
+				i, ok := violations[name]
+				if !ok {
+					group.Violations = append(group.Violations, groupedViolation{
+						RuleSet:     rs.Name,
+						Rule:        ruleID,
+						Description: v.Description,
+						Category:    v.Category,
+						Effort:      v.Effort,
+						Labels:      v.Labels,
+						Incidents:   []outputv1.Incident{},
+					})
+					i = len(group.Violations) - 1
+					violations[name] = i
+				}
+				group.Violations[i].Incidents = append(group.Violations[i].Incidents, incident)
+				group.Incidents++
+			}
+		}
+	}
+	grouped := []incidentGroup{}
+	for _, name := range sortedMapKeys(groups) {
+		grouped = append(grouped, *groups[name])
+	}
+	return grouped
+}
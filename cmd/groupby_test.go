@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func Test_groupIncidents(t *testing.T) {
+	mandatory := outputv1.Mandatory
+	rulesets := []outputv1.RuleSet{{
+		Name: "eap8",
+		Violations: map[string]outputv1.Violation{
+			"jakarta-00001": {
+				Category: &mandatory,
+				Incidents: []outputv1.Incident{
+					{URI: "file:///app/src/com/acme/Foo.java", Variables: map[string]interface{}{"package": "com.acme"}},
+					{URI: "file:///app/src/com/acme/Bar.java", Variables: map[string]interface{}{"package": "com.acme"}},
+					{URI: "file:///app/src/com/acme/Foo.java", Variables: map[string]interface{}{"package": "com.acme"}},
+				},
+			},
+			"config-00001": {
+				Incidents: []outputv1.Incident{{URI: "file:///app/src/main/resources/app.properties"}},
+			},
+		},
+	}}
+
+	byFile := groupIncidents(rulesets, groupByFile)
+	files := []string{}
+	for _, group := range byFile {
+		files = append(files, group.File)
+	}
+	wantFiles := []string{"/app/src/com/acme/Bar.java", "/app/src/com/acme/Foo.java", "/app/src/main/resources/app.properties"}
+	if !reflect.DeepEqual(files, wantFiles) {
+		t.Fatalf("groupIncidents() files = %v, want %v", files, wantFiles)
+	}
+	foo := byFile[1]
+	if foo.Incidents != 2 || len(foo.Violations) != 1 || foo.Violations[0].Rule != "jakarta-00001" || len(foo.Violations[0].Incidents) != 2 {
+		t.Errorf("groupIncidents() group of Foo.java = %+v, want 2 incidents of jakarta-00001", foo)
+	}
+	if foo.Violations[0].Category == nil || *foo.Violations[0].Category != mandatory {
+		t.Errorf("groupIncidents() violation = %+v, want the category of the rule", foo.Violations[0])
+	}
+
+	byPackage := groupIncidents(rulesets, groupByPackage)
+	packages := map[string]int{}
+	for _, group := range byPackage {
+		packages[group.Package] = group.Incidents
+	}
+	wantPackages := map[string]int{"com.acme": 3, "/app/src/main/resources": 1}
+	if !reflect.DeepEqual(packages, wantPackages) {
+		t.Errorf("groupIncidents() packages = %v, want %v", packages, wantPackages)
+	}
+}
+
+func Test_analyzeCommand_validateGroupBy(t *testing.T) {
+	tests := []struct {
+		name    string
+		groupBy string
+		columns []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "default", columns: defaultSummaryColumns, want: defaultSummaryColumns},
+		{name: "rule", groupBy: groupByRule, columns: defaultSummaryColumns, want: []string{summaryRules, summaryCategories}},
+		{name: "package", groupBy: groupByPackage, columns: defaultSummaryColumns, want: []string{summaryPackages, summaryCategories}},
+		{name: "summary columns set", groupBy: groupByFile, columns: []string{summaryRules}, want: []string{summaryRules}},
+		{name: "invalid", groupBy: "line", columns: defaultSummaryColumns, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &analyzeCommand{groupBy: tt.groupBy, summaryColumns: tt.columns}
+			err := a.validateGroupBy()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateGroupBy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(a.summaryColumns, tt.want) {
+				t.Errorf("summary columns = %v, want %v", a.summaryColumns, tt.want)
+			}
+		})
+	}
+}
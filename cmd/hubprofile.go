@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/analyzer-lsp/provider"
+	"github.com/spf13/cobra"
+)
+
+// path of the analysis profiles of the Hub API
+const hubProfilesPath = "/analysis/profiles"
+
+// hubInExList are the included and excluded values of a Hub profile
+type hubInExList struct {
+	Included []string `json:"included,omitempty" yaml:"included,omitempty"`
+	Excluded []string `json:"excluded,omitempty" yaml:"excluded,omitempty"`
+}
+
+// hubRef is a reference to a resource of the Hub
+type hubRef struct {
+	ID   uint   `json:"id" yaml:"id"`
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+}
+
+// hubAnalysisProfile is an AnalysisProfile of the Hub API
+type hubAnalysisProfile struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Mode        struct {
+		WithDeps bool `json:"withDeps" yaml:"withDeps"`
+	} `json:"mode" yaml:"mode"`
+	Scope struct {
+		WithKnownLibs bool        `json:"withKnownLibs" yaml:"withKnownLibs"`
+		Packages      hubInExList `json:"packages,omitempty" yaml:"packages,omitempty"`
+	} `json:"scope" yaml:"scope"`
+	Rules struct {
+		// targets are resources of the Hub, rules are selected by label instead
+		Targets []hubRef    `json:"targets" yaml:"targets"`
+		Labels  hubInExList `json:"labels,omitempty" yaml:"labels,omitempty"`
+	} `json:"rules" yaml:"rules"`
+}
+
+type profileExportHubCommand struct {
+	profile               string
+	profilesDir           string
+	name                  string
+	description           string
+	targets               []string
+	sources               []string
+	labelSelector         string
+	mode                  string
+	analyzeKnownLibraries bool
+	format                string
+	post                  bool
+	log                   logr.Logger
+}
+
+func NewProfileCommand(log logr.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage analysis profiles",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+	cmd.AddCommand(newProfileExportHubCommand(log))
+	return cmd
+}
+
+func newProfileExportHubCommand(log logr.Logger) *cobra.Command {
+	exportCmd := &profileExportHubCommand{log: log}
+	cmd := &cobra.Command{
+		Use:   "export-hub",
+		Short: "Export an analysis configuration as an analysis profile of the Hub",
+		Long: "Convert a profile of the profiles dir, or the analyze flags given, into an AnalysisProfile of the Hub API.\n" +
+			"The profile is printed, or created on the Hub of KANTRA_HUB_URL with --post, authenticated with KANTRA_HUB_TOKEN.",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if exportCmd.format != listFormatJSON && exportCmd.format != listFormatYAML {
+				return fmt.Errorf("format must be one of 'json' or 'yaml'")
+			}
+			if exportCmd.mode != string(provider.FullAnalysisMode) && exportCmd.mode != string(provider.SourceOnlyAnalysisMode) {
+				return fmt.Errorf("mode must be one of 'full' or 'source-only'")
+			}
+			if exportCmd.post && Settings.HubURL == "" {
+				return fmt.Errorf("post requires the URL of the Hub in KANTRA_HUB_URL")
+			}
+			if exportCmd.profile != "" && (len(exportCmd.targets) > 0 || len(exportCmd.sources) > 0 ||
+				exportCmd.labelSelector != "" || cmd.Flags().Lookup("mode").Changed) {
+				return fmt.Errorf("must not specify profile and target, source, label-selector or mode")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := exportCmd.Run(os.Stdout); err != nil {
+				log.Error(err, "failed to export profile")
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&exportCmd.profile, "profile", "", "name of the profile of the profiles dir to export, the other flags are exported when not set")
+	cmd.Flags().StringVar(&exportCmd.profilesDir, "profiles-dir", defaultProfilesDir, "dir of the analysis profiles")
+	cmd.Flags().StringVar(&exportCmd.name, "name", "", "name of the Hub profile, defaults to the name of the profile")
+	cmd.Flags().StringVar(&exportCmd.description, "description", "", "description of the Hub profile")
+	cmd.Flags().StringArrayVarP(&exportCmd.targets, "target", "t", []string{}, "target technology of the rules")
+	cmd.Flags().StringArrayVarP(&exportCmd.sources, "source", "s", []string{}, "source technology of the rules")
+	cmd.Flags().StringVarP(&exportCmd.labelSelector, "label-selector", "l", "", "labels of the rules joined by ||, negated labels are excluded")
+	cmd.Flags().StringVarP(&exportCmd.mode, "mode", "m", string(provider.FullAnalysisMode), "analysis mode. Must be one of 'full' or 'source-only'")
+	cmd.Flags().BoolVar(&exportCmd.analyzeKnownLibraries, "analyze-known-libraries", false, "analyze known open-source libraries")
+	cmd.Flags().StringVar(&exportCmd.format, "format", listFormatJSON, "output format. Must be one of 'json' or 'yaml'")
+	cmd.Flags().BoolVar(&exportCmd.post, "post", false, "create the profile on the Hub of KANTRA_HUB_URL instead of printing it")
+	return cmd
+}
+
+// hubLabels converts a label selector into included and excluded labels,
+// the Hub selects rules having any included label and no excluded one
+func hubLabels(selector string) (hubInExList, error) {
+	labels := hubInExList{}
+	if strings.TrimSpace(selector) == "" {
+		return labels, nil
+	}
+	for _, term := range strings.Split(selector, "||") {
+		term = strings.TrimSpace(term)
+		label, excluded := strings.CutPrefix(term, "!")
+		label = strings.TrimSpace(label)
+		if label == "" || strings.ContainsAny(label, "&()!|") {
+			return labels, fmt.Errorf("label selector %s must be labels joined by ||, the Hub does not support other expressions", selector)
+		}
+		if excluded {
+			labels.Excluded = append(labels.Excluded, label)
+		} else {
+			labels.Included = append(labels.Included, label)
+		}
+	}
+	return labels, nil
+}
+
+// hubProfile converts an analysis profile into a profile of the Hub
+func hubProfile(profile analysisProfile, analyzeKnownLibraries bool) (hubAnalysisProfile, error) {
+	hub := hubAnalysisProfile{Name: profile.Name}
+	hub.Mode.WithDeps = profile.Mode != string(provider.SourceOnlyAnalysisMode)
+	hub.Scope.WithKnownLibs = analyzeKnownLibraries
+	hub.Rules.Targets = []hubRef{}
+	labels, err := hubLabels(profile.LabelSelector)
+	if err != nil {
+		return hub, err
+	}
+	for _, target := range profile.Targets {
+		labels.Included = append(labels.Included, fmt.Sprintf("%s=%s", outputv1.TargetTechnologyLabel, target))
+	}
+	for _, source := range profile.Sources {
+		labels.Included = append(labels.Included, fmt.Sprintf("%s=%s", outputv1.SourceTechnologyLabel, source))
+	}
+	hub.Rules.Labels = labels
+	return hub, nil
+}
+
+// postHubProfile creates the profile on the Hub
+func postHubProfile(hubURL, token string, profile hubAnalysisProfile) error {
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+	endpoint := strings.TrimSuffix(hubURL, "/") + hubProfilesPath
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status %s creating profile on %s: %s", resp.Status, endpoint, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func (p *profileExportHubCommand) Run(out io.Writer) error {
+	profile := analysisProfile{Targets: p.targets, Sources: p.sources, LabelSelector: p.labelSelector, Mode: p.mode}
+	if p.profile != "" {
+		profiles, err := loadProfiles(p.profilesDir, []string{p.profile})
+		if err != nil {
+			return err
+		}
+		profile = profiles[0]
+	}
+	if profile.LabelSelector != "" && (len(profile.Targets) > 0 || len(profile.Sources) > 0) {
+		return fmt.Errorf("must not specify label-selector and sources or targets")
+	}
+	if len(profile.Rules) > 0 {
+		p.log.Info("warning: rules of the profile are not exported, upload them to the Hub", "rules", profile.Rules)
+	}
+	hub, err := hubProfile(profile, p.analyzeKnownLibraries)
+	if err != nil {
+		return err
+	}
+	if p.name != "" {
+		hub.Name = p.name
+	}
+	if hub.Name == "" {
+		return fmt.Errorf("name of the profile is required, set --name")
+	}
+	hub.Description = p.description
+	if !p.post {
+		return writeListOutput(out, p.format, hub)
+	}
+	token, err := settingSecrets(Settings.HubToken)
+	if err != nil {
+		return err
+	}
+	if err := postHubProfile(Settings.HubURL, token[0], hub); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "profile %s created on %s\n", hub.Name, Settings.HubURL)
+	return nil
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_hubLabels(t *testing.T) {
+	got, err := hubLabels("konveyor.io/target=eap8 || !konveyor.io/source=eap6 || custom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := hubInExList{Included: []string{"konveyor.io/target=eap8", "custom"}, Excluded: []string{"konveyor.io/source=eap6"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("hubLabels() = %+v, want %+v", got, want)
+	}
+	if _, err := hubLabels("konveyor.io/target=eap8 && konveyor.io/source=eap7"); err == nil {
+		t.Errorf("hubLabels() must fail for && expressions")
+	}
+}
+
+func Test_profileExportHubCommand_Run(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "eap.yaml"), "name: eap8\ntargets: [eap8]\nsources: [eap7]\nmode: source-only\n")
+	var posted map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != hubProfilesPath || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	defer func(url, token string) { Settings.HubURL, Settings.HubToken = url, token }(Settings.HubURL, Settings.HubToken)
+	// the token is read from its secret store when the profile is posted
+	tokenFile := filepath.Join(dir, "token")
+	writeTestFile(t, tokenFile, "token\n")
+	Settings.HubURL, Settings.HubToken = server.URL, "file:"+tokenFile
+
+	p := &profileExportHubCommand{profile: "eap8", profilesDir: dir, format: listFormatJSON, post: true, log: logr.Discard()}
+	if err := p.Run(&bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"name":  "eap8",
+		"mode":  map[string]any{"withDeps": false},
+		"scope": map[string]any{"withKnownLibs": false, "packages": map[string]any{}},
+		"rules": map[string]any{
+			"targets": []any{},
+			"labels":  map[string]any{"included": []any{"konveyor.io/target=eap8", "konveyor.io/source=eap7"}},
+		},
+	}
+	if !reflect.DeepEqual(posted, want) {
+		t.Errorf("posted profile = %v, want %v", posted, want)
+	}
+
+	Settings.HubToken = ""
+	if err := p.Run(&bytes.Buffer{}); err == nil {
+		t.Errorf("Run() must fail when the Hub rejects the profile")
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// incidentStoreFile holds the incidents of the analysis one per line, so
+// that very large outputs can be processed one incident at a time
+const incidentStoreFile = "incidents.jsonl"
+
+// storedIncident is an incident of the store with the rule it violates
+type storedIncident struct {
+	RuleSet  string   `json:"ruleset"`
+	Rule     string   `json:"rule"`
+	Category string   `json:"category,omitempty"`
+	Effort   *int     `json:"effort,omitempty"`
+	Labels   []string `json:"labels,omitempty"`
+	outputv1.Incident
+}
+
+// writeIncidentStore writes the incidents of the rulesets to path, one json
+// document per line, ordered by ruleset and rule
+func writeIncidentStore(path string, rulesets []outputv1.RuleSet) (int, error) {
+	file, err := os.CreateTemp(filepath.Dir(path), ".incidents-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	w := bufio.NewWriter(file)
+	enc := json.NewEncoder(w)
+	count := 0
+	for _, rs := range rulesets {
+		for _, ruleID := range sortedMapKeys(rs.Violations) {
+			violation := rs.Violations[ruleID]
+			stored := storedIncident{RuleSet: rs.Name, Rule: ruleID, Effort: violation.Effort, Labels: violation.Labels}
+			if violation.Category != nil {
+				stored.Category = string(*violation.Category)
+			}
+			for _, incident := range violation.Incidents {
+				// variables decoded from yaml may have maps json can not encode
+				normalizeQueryValue(incident.Variables)
+				stored.Incident = incident
+				if err := enc.Encode(stored); err != nil {
+					return count, err
+				}
+				count++
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return count, err
+	}
+	if err := file.Close(); err != nil {
+		return count, err
+	}
+	return count, os.Rename(file.Name(), path)
+}
+
+// readIncidentStore calls fn with each incident of the store at path, or of
+// its compressed copy, without reading the whole store into memory
+func readIncidentStore(path string, fn func(line []byte) error) error {
+	path = outputFile(path)
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if strings.HasSuffix(path, gzipExt) {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return fmt.Errorf("%w failed to decompress %s", err, path)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	reader := bufio.NewReader(r)
+	for {
+		// incidents with large code snippets exceed the token size of a scanner
+		line, err := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) > 0 {
+			if err := fn(line); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// writeIncidentStoreReport writes the incident store of the analysis output
+// with --incident-store
+func (a *analyzeCommand) writeIncidentStoreReport() error {
+	if !a.incidentStore {
+		return nil
+	}
+	outputPath := filepath.Join(a.output, "output.yaml")
+	storePath := filepath.Join(a.output, incidentStoreFile)
+	// bulk analysis moves results to the application dir
+	if _, err := os.Stat(outputPath); errors.Is(err, os.ErrNotExist) && a.bulk {
+		outputPath = a.bulkResultPath("output.yaml")
+		storePath = a.bulkResultPath(incidentStoreFile)
+	}
+	rulesets, err := readRuleSetsOutput(outputPath)
+	if err != nil {
+		return err
+	}
+	count, err := writeIncidentStore(storePath, rulesets)
+	if err != nil {
+		return fmt.Errorf("%w failed to write incident store", err)
+	}
+	a.log.Info("wrote incident store", "file", storePath, "incidents", count)
+	return nil
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_writeIncidentStore(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "output.yaml"), queryTestOutput)
+	rulesets, err := readRuleSetsOutput(filepath.Join(dir, "output.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	storePath := filepath.Join(dir, incidentStoreFile)
+	count, err := writeIncidentStore(storePath, rulesets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("writeIncidentStore() = %d, want 3", count)
+	}
+	lines := 0
+	if err := readIncidentStore(storePath, func(line []byte) error {
+		lines++
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if lines != count {
+		t.Errorf("readIncidentStore() read %d incidents, want %d", lines, count)
+	}
+
+	q := &queryCommand{input: dir, stream: true, format: listFormatJSON, log: logr.Discard()}
+	out := &bytes.Buffer{}
+	if err := q.Run(context.Background(), `select(.category == "mandatory") | .uri`, out); err != nil {
+		t.Fatal(err)
+	}
+	want := "\"file:///src/A.java\"\n\"file:///src/B.java\"\n"
+	if strings.ReplaceAll(out.String(), " ", "") != want {
+		t.Errorf("Run() = %q, want %q", out.String(), want)
+	}
+}
@@ -6,6 +6,8 @@ package hiddenfile
 import (
 	"path/filepath"
 	"syscall"
+
+	"github.com/konveyor-ecosystem/kantra/cmd/internal/hostpath"
 )
 
 const dotCharacter = 46
@@ -22,11 +24,10 @@ func IsHidden(name string) (bool, error) {
 		return false, err
 	}
 
-	// Appending `\\?\` to the absolute path helps with
-	// preventing 'Path Not Specified Error' when accessing
-	// long paths and filenames
+	// the long path prefix prevents 'Path Not Specified Error' when
+	// accessing long paths and filenames
 	// https://docs.microsoft.com/en-us/windows/win32/fileio/maximum-file-path-limitation?tabs=cmd
-	pointer, err := syscall.UTF16PtrFromString(`\\?\` + absPath)
+	pointer, err := syscall.UTF16PtrFromString(hostpath.Long(absPath))
 	if err != nil {
 		return false, err
 	}
@@ -0,0 +1,19 @@
+// Package hostpath converts paths of the host for the APIs and tools
+// consuming them, the conversions only change paths on Windows.
+package hostpath
+
+import "strings"
+
+// maxPath is the length from which Windows APIs need the long path prefix,
+// directories are limited to MAX_PATH minus the 12 characters of a 8.3 file name
+const maxPath = 248
+
+const (
+	longPrefix    = `\\?\`
+	longUNCPrefix = `\\?\UNC\`
+)
+
+// IsUNC reports whether p is a Windows UNC path, e.g. \\server\share\app
+func IsUNC(p string) bool {
+	return (strings.HasPrefix(p, `\\`) || strings.HasPrefix(p, `//`)) && !strings.HasPrefix(p, longPrefix)
+}
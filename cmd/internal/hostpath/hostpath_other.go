@@ -0,0 +1,21 @@
+//go:build !windows
+// +build !windows
+
+package hostpath
+
+import "path/filepath"
+
+// Long returns p, only Windows limits the length of paths
+func Long(p string) string {
+	return p
+}
+
+// Mount returns the absolute path of p to bind mount it into containers
+func Mount(p string) (string, error) {
+	return filepath.Abs(p)
+}
+
+// MachinePath returns p, volumes are mounted from the host as is
+func MachinePath(p string) (string, error) {
+	return p, nil
+}
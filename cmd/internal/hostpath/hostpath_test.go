@@ -0,0 +1,19 @@
+package hostpath
+
+import "testing"
+
+func TestIsUNC(t *testing.T) {
+	tests := map[string]bool{
+		`\\server\share\app`:   true,
+		`//server/share/app`:   true,
+		`\\?\C:\app`:           false,
+		`C:\app`:               false,
+		`/home/user/app`:       false,
+		`relative\path\to\app`: false,
+	}
+	for p, want := range tests {
+		if got := IsUNC(p); got != want {
+			t.Errorf("IsUNC(%q) = %v, want %v", p, got, want)
+		}
+	}
+}
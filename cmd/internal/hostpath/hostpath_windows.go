@@ -0,0 +1,52 @@
+//go:build windows
+// +build windows
+
+package hostpath
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Long returns p with the long path prefix when it is too long for Windows
+// APIs without it, UNC paths use the \\?\UNC\ form of the prefix
+func Long(p string) string {
+	if strings.HasPrefix(p, longPrefix) {
+		return p
+	}
+	abs, err := filepath.Abs(p)
+	if err != nil || len(abs) < maxPath {
+		return p
+	}
+	if IsUNC(abs) {
+		return longUNCPrefix + strings.TrimLeft(abs, `\/`)
+	}
+	return longPrefix + abs
+}
+
+// Mount returns the absolute path of p to bind mount it into containers,
+// without the long path prefix the container tools do not accept. Network
+// paths cannot be bind mounted.
+func Mount(p string) (string, error) {
+	if strings.HasPrefix(p, longUNCPrefix) {
+		p = `\\` + strings.TrimPrefix(p, longUNCPrefix)
+	}
+	p = strings.TrimPrefix(p, longPrefix)
+	if IsUNC(p) {
+		return "", fmt.Errorf("network path %s cannot be mounted into containers, map it to a drive letter instead", p)
+	}
+	return filepath.Abs(p)
+}
+
+// MachinePath returns the path of a host path in the podman machine, where
+// drives are mounted under /mnt, e.g. C:\app is /mnt/c/app
+func MachinePath(p string) (string, error) {
+	p = strings.TrimPrefix(p, longPrefix)
+	if IsUNC(p) || strings.HasPrefix(p, `UNC\`) {
+		return "", fmt.Errorf("network path %s cannot be mounted in the podman machine, map it to a drive letter instead", p)
+	}
+	volumeName := filepath.VolumeName(p)
+	driveLetter := strings.ToLower(strings.TrimSuffix(volumeName, ":"))
+	return fmt.Sprintf("/mnt/%s%s", driveLetter, filepath.ToSlash(p[len(volumeName):])), nil
+}
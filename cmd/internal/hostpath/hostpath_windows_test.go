@@ -0,0 +1,57 @@
+//go:build windows
+// +build windows
+
+package hostpath
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLong(t *testing.T) {
+	short := `C:\app\src`
+	if got := Long(short); got != short {
+		t.Errorf("Long(%q) = %q, want it unchanged", short, got)
+	}
+	long := `C:\app\` + strings.Repeat(`a\`, maxPath/2) + "b"
+	if got := Long(long); got != longPrefix+long {
+		t.Errorf("Long(%q) = %q, want the long path prefix", long, got)
+	}
+	if got := Long(longPrefix + long); got != longPrefix+long {
+		t.Errorf("Long() added the long path prefix twice: %q", got)
+	}
+	unc := `\\server\share\` + strings.Repeat(`a\`, maxPath/2) + "b"
+	if got := Long(unc); got != longUNCPrefix+strings.TrimPrefix(unc, `\\`) {
+		t.Errorf("Long(%q) = %q, want the UNC long path prefix", unc, got)
+	}
+}
+
+func TestMachinePath(t *testing.T) {
+	got, err := MachinePath(`C:\Users\dev\app`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "/mnt/c/Users/dev/app" {
+		t.Errorf("MachinePath() = %q, want /mnt/c/Users/dev/app", got)
+	}
+	if _, err := MachinePath(`\\server\share\app`); err == nil {
+		t.Errorf("MachinePath() of a UNC path must fail")
+	}
+}
+
+func TestMount(t *testing.T) {
+	for _, p := range []string{`C:\app\src`, longPrefix + `C:\app\src`} {
+		got, err := Mount(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != `C:\app\src` {
+			t.Errorf("Mount(%q) = %q, want C:\\app\\src", p, got)
+		}
+	}
+	for _, p := range []string{`\\server\share\app`, longUNCPrefix + `server\share\app`} {
+		if _, err := Mount(p); err == nil {
+			t.Errorf("Mount(%q) of a network path must fail", p)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package i18n
+
+// catalogs maps a language to translations of English messages.
+// Format verbs must appear in the same order as in the English message.
+var catalogs = map[string]map[string]string{
+	"es": {
+		"must not specify both quiet and summary-only":                                 "no se puede especificar quiet y summary-only a la vez",
+		"No providers found with default rules. Use --rules option":                    "No se encontraron proveedores con reglas predeterminadas. Use la opción --rules",
+		"must not specify label-selector and sources or targets":                       "no se puede especificar label-selector junto con sources o targets",
+		"progress-style must be one of 'auto' or 'plain'":                              "progress-style debe ser 'auto' o 'plain'",
+		"unknown source: \"%s\"":                                                       "fuente desconocida: \"%s\"",
+		"unknown target: \"%s\"":                                                       "destino desconocido: \"%s\"",
+		"must specify only one input source":                                           "solo se puede especificar una fuente de entrada",
+		"%w failed to stat input path %s":                                              "%w no se pudo acceder a la ruta de entrada %s",
+		"invalid file type %v":                                                         "tipo de archivo no válido %v",
+		"output path %s is not a directory":                                            "la ruta de salida %s no es un directorio",
+		"mode must be one of 'full' or 'source-only'":                                  "el modo debe ser 'full' o 'source-only'",
+		"must specify rules if default rulesets are not enabled":                       "debe especificar reglas si los rulesets predeterminados no están habilitados",
+		"output dir %v already exists and --overwrite not set":                         "el directorio de salida %v ya existe y --overwrite no está establecido",
+		"provider %v not supported. Use --providerOverride or --provider option":       "el proveedor %v no está soportado. Use la opción --providerOverride o --provider",
+		"analysis complete: %s, results written to %s":                                 "análisis completado: %s, resultados escritos en %s",
+		"%d rules matched with %d incidents in %d rulesets":                            "%d reglas coincidentes con %d incidentes en %d rulesets",
+		"%s, %d files skipped":                                                         "%s, %d archivos omitidos",
+		"%s, readiness score %d":                                                       "%s, puntuación de preparación %d",
+		"target %s requires Java %d or later, project is built for Java %d":            "el destino %s requiere Java %d o posterior, el proyecto está compilado para Java %d",
+		"target %s supports up to Java %d, project is built for Java %d":               "el destino %s admite hasta Java %d, el proyecto está compilado para Java %d",
+		"target %s supports up to Java %d, dependency %s is built for Java %d":         "el destino %s admite hasta Java %d, la dependencia %s está compilada para Java %d",
+		"target %s supports up to Jakarta EE %d, descriptor %s requires Jakarta EE %d": "el destino %s admite hasta Jakarta EE %d, el descriptor %s requiere Jakarta EE %d",
+		"incompatible with target runtime: %s":                                         "incompatible con el entorno de destino: %s",
+		"%s platform check failed: %s (%d incidents)":                                  "comprobación de plataforma %s fallida: %s (%d incidencias)",
+		"provider %s: %s": "proveedor %s: %s",
+		"provider(s) %s failed to start, see provider output above": "los proveedores %s no se iniciaron, consulte la salida del proveedor arriba",
+		"evaluating rules for violations":                           "evaluando reglas en busca de violaciones",
+		"evaluating %d rules for violations":                        "evaluando %d reglas en busca de violaciones",
+		"%s (%s elapsed)":                                           "%s (%s transcurrido)",
+		"Processed %d/%d rules (%d%%)":                              "Procesadas %d/%d reglas (%d%%)",
+		"initializing":                                              "inicializando",
+		"ready":                                                     "listo",
+		"analyzing":                                                 "analizando",
+		"failed":                                                    "fallido",
+		"%d errors and %d warnings during the analysis:":            "%d errores y %d advertencias durante el análisis:",
+		"RULE":      "REGLA",
+		"CATEGORY":  "CATEGORÍA",
+		"FILE":      "ARCHIVO",
+		"PACKAGE":   "PAQUETE",
+		"INCIDENTS": "INCIDENTES",
+		"%d more":   "%d más",
+	},
+	"pt": {
+		"must not specify both quiet and summary-only":                                 "não é possível especificar quiet e summary-only ao mesmo tempo",
+		"No providers found with default rules. Use --rules option":                    "Nenhum provedor encontrado com regras padrão. Use a opção --rules",
+		"must not specify label-selector and sources or targets":                       "não é possível especificar label-selector junto com sources ou targets",
+		"progress-style must be one of 'auto' or 'plain'":                              "progress-style deve ser 'auto' ou 'plain'",
+		"unknown source: \"%s\"":                                                       "origem desconhecida: \"%s\"",
+		"unknown target: \"%s\"":                                                       "destino desconhecido: \"%s\"",
+		"must specify only one input source":                                           "especifique apenas uma fonte de entrada",
+		"%w failed to stat input path %s":                                              "%w falha ao acessar o caminho de entrada %s",
+		"invalid file type %v":                                                         "tipo de arquivo inválido %v",
+		"output path %s is not a directory":                                            "o caminho de saída %s não é um diretório",
+		"mode must be one of 'full' or 'source-only'":                                  "o modo deve ser 'full' ou 'source-only'",
+		"must specify rules if default rulesets are not enabled":                       "é necessário especificar regras se os rulesets padrão não estiverem habilitados",
+		"output dir %v already exists and --overwrite not set":                         "o diretório de saída %v já existe e --overwrite não foi definido",
+		"provider %v not supported. Use --providerOverride or --provider option":       "o provedor %v não é suportado. Use a opção --providerOverride ou --provider",
+		"analysis complete: %s, results written to %s":                                 "análise concluída: %s, resultados gravados em %s",
+		"%d rules matched with %d incidents in %d rulesets":                            "%d regras correspondidas com %d incidentes em %d rulesets",
+		"%s, %d files skipped":                                                         "%s, %d arquivos ignorados",
+		"%s, readiness score %d":                                                       "%s, pontuação de prontidão %d",
+		"target %s requires Java %d or later, project is built for Java %d":            "o destino %s requer Java %d ou posterior, o projeto é compilado para Java %d",
+		"target %s supports up to Java %d, project is built for Java %d":               "o destino %s suporta até Java %d, o projeto é compilado para Java %d",
+		"target %s supports up to Java %d, dependency %s is built for Java %d":         "o destino %s suporta até Java %d, a dependência %s é compilada para Java %d",
+		"target %s supports up to Jakarta EE %d, descriptor %s requires Jakarta EE %d": "o destino %s suporta até Jakarta EE %d, o descritor %s requer Jakarta EE %d",
+		"incompatible with target runtime: %s":                                         "incompatível com o ambiente de destino: %s",
+		"%s platform check failed: %s (%d incidents)":                                  "verificação de plataforma %s falhou: %s (%d incidentes)",
+		"provider %s: %s": "provedor %s: %s",
+		"provider(s) %s failed to start, see provider output above": "o(s) provedor(es) %s não iniciaram, veja a saída do provedor acima",
+		"evaluating rules for violations":                           "avaliando regras em busca de violações",
+		"evaluating %d rules for violations":                        "avaliando %d regras em busca de violações",
+		"%s (%s elapsed)":                                           "%s (%s decorridos)",
+		"Processed %d/%d rules (%d%%)":                              "Processadas %d/%d regras (%d%%)",
+		"initializing":                                              "inicializando",
+		"ready":                                                     "pronto",
+		"analyzing":                                                 "analisando",
+		"failed":                                                    "falhou",
+		"%d errors and %d warnings during the analysis:":            "%d erros e %d avisos durante a análise:",
+		"RULE":      "REGRA",
+		"CATEGORY":  "CATEGORIA",
+		"FILE":      "ARQUIVO",
+		"PACKAGE":   "PACOTE",
+		"INCIDENTS": "INCIDENTES",
+		"%d more":   "mais %d",
+	},
+	"ja": {
+		"must not specify both quiet and summary-only":                                 "quiet と summary-only は同時に指定できません",
+		"No providers found with default rules. Use --rules option":                    "デフォルトルールを持つプロバイダーが見つかりません。--rules オプションを使用してください",
+		"must not specify label-selector and sources or targets":                       "label-selector と sources または targets は同時に指定できません",
+		"progress-style must be one of 'auto' or 'plain'":                              "progress-style は 'auto' または 'plain' のいずれかである必要があります",
+		"unknown source: \"%s\"":                                                       "不明なソース: \"%s\"",
+		"unknown target: \"%s\"":                                                       "不明なターゲット: \"%s\"",
+		"must specify only one input source":                                           "入力ソースは 1 つだけ指定してください",
+		"%w failed to stat input path %s":                                              "%w 入力パス %s にアクセスできません",
+		"invalid file type %v":                                                         "無効なファイル形式 %v",
+		"output path %s is not a directory":                                            "出力パス %s はディレクトリではありません",
+		"mode must be one of 'full' or 'source-only'":                                  "モードは 'full' または 'source-only' のいずれかである必要があります",
+		"must specify rules if default rulesets are not enabled":                       "デフォルトのルールセットが無効な場合はルールを指定する必要があります",
+		"output dir %v already exists and --overwrite not set":                         "出力ディレクトリ %v は既に存在し、--overwrite が指定されていません",
+		"provider %v not supported. Use --providerOverride or --provider option":       "プロバイダー %v はサポートされていません。--providerOverride または --provider オプションを使用してください",
+		"analysis complete: %s, results written to %s":                                 "分析完了: %s、結果の出力先 %s",
+		"%d rules matched with %d incidents in %d rulesets":                            "%d 件のルールが一致、%d 件のインシデント (%d ルールセット)",
+		"%s, %d files skipped":                                                         "%s、%d 件のファイルをスキップ",
+		"%s, readiness score %d":                                                       "%s、移行準備スコア %d",
+		"target %s requires Java %d or later, project is built for Java %d":            "ターゲット %s には Java %d 以降が必要ですが、プロジェクトは Java %d 向けにビルドされています",
+		"target %s supports up to Java %d, project is built for Java %d":               "ターゲット %s は Java %d までサポートしますが、プロジェクトは Java %d 向けにビルドされています",
+		"target %s supports up to Java %d, dependency %s is built for Java %d":         "ターゲット %s は Java %d までサポートしますが、依存関係 %s は Java %d 向けにビルドされています",
+		"target %s supports up to Jakarta EE %d, descriptor %s requires Jakarta EE %d": "ターゲット %s は Jakarta EE %d までサポートしますが、記述子 %s には Jakarta EE %d が必要です",
+		"incompatible with target runtime: %s":                                         "ターゲットランタイムと互換性がありません: %s",
+		"%s platform check failed: %s (%d incidents)":                                  "%s プラットフォームチェックに失敗しました: %s (インシデント %d 件)",
+		"provider %s: %s": "プロバイダー %s: %s",
+		"provider(s) %s failed to start, see provider output above": "プロバイダー %s の起動に失敗しました。上記のプロバイダー出力を確認してください",
+		"evaluating rules for violations":                           "ルール違反を評価しています",
+		"evaluating %d rules for violations":                        "%d 件のルールで違反を評価しています",
+		"%s (%s elapsed)":                                           "%s (経過時間 %s)",
+		"Processed %d/%d rules (%d%%)":                              "%d/%d 件のルールを処理しました (%d%%)",
+		"initializing":                                              "初期化中",
+		"ready":                                                     "準備完了",
+		"analyzing":                                                 "分析中",
+		"failed":                                                    "失敗",
+		"%d errors and %d warnings during the analysis:":            "分析中のエラー %d 件、警告 %d 件:",
+		"RULE":      "ルール",
+		"CATEGORY":  "カテゴリ",
+		"FILE":      "ファイル",
+		"PACKAGE":   "パッケージ",
+		"INCIDENTS": "インシデント",
+		"%d more":   "他 %d 件",
+	},
+}
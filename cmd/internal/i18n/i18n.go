@@ -0,0 +1,66 @@
+// Package i18n provides translations of user facing kantra messages.
+//
+// Messages are looked up by their English text, which is also used when
+// no translation exists for the selected language.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const DefaultLanguage = "en"
+
+var (
+	mu       sync.RWMutex
+	language = DefaultLanguage
+)
+
+// SetLanguage selects the language used for messages. It accepts locale
+// strings like "es", "pt-BR" or "ja_JP.UTF-8" and falls back to English
+// for unsupported languages.
+func SetLanguage(lang string) {
+	lang = normalize(lang)
+	if _, ok := catalogs[lang]; !ok {
+		lang = DefaultLanguage
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	language = lang
+}
+
+// Language returns the selected language
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return language
+}
+
+// T returns the translation of msg in the selected language
+func T(msg string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if translated, ok := catalogs[language][msg]; ok {
+		return translated
+	}
+	return msg
+}
+
+// Sprintf formats the translation of format
+func Sprintf(format string, args ...interface{}) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Errorf creates an error from the translation of format, %w is supported
+func Errorf(format string, args ...interface{}) error {
+	return fmt.Errorf(T(format), args...)
+}
+
+func normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "_-."); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
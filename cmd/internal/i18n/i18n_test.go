@@ -0,0 +1,67 @@
+package i18n
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestSetLanguage(t *testing.T) {
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{lang: "", want: "en"},
+		{lang: "es", want: "es"},
+		{lang: "pt-BR", want: "pt"},
+		{lang: "ja_JP.UTF-8", want: "ja"},
+		{lang: "ES", want: "es"},
+		{lang: "xx", want: "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			SetLanguage(tt.lang)
+			defer SetLanguage(DefaultLanguage)
+			if got := Language(); got != tt.want {
+				t.Errorf("Language() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	defer SetLanguage(DefaultLanguage)
+	SetLanguage("es")
+	if got := Sprintf("unknown target: \"%s\"", "eap9"); got != "destino desconocido: \"eap9\"" {
+		t.Errorf("Sprintf() = %v", got)
+	}
+	if got := Sprintf("not in the catalog %d", 1); got != "not in the catalog 1" {
+		t.Errorf("Sprintf() = %v", got)
+	}
+}
+
+// translations must keep the format verbs of the original message in order
+func TestCatalogVerbs(t *testing.T) {
+	verbs := regexp.MustCompile(`%[a-z]`)
+	for lang, catalog := range catalogs {
+		for msg, translated := range catalog {
+			want := verbs.FindAllString(msg, -1)
+			got := verbs.FindAllString(translated, -1)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s translation of %q has verbs %v, want %v", lang, msg, got, want)
+			}
+		}
+	}
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for other, otherCatalog := range catalogs {
+			for msg := range otherCatalog {
+				if _, ok := catalog[msg]; !ok {
+					t.Errorf("%s catalog is missing %q present in %s catalog", lang, msg, other)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// mount options of the input with --read-only-input
+const (
+	// writes of the container go to a scratch overlay discarded with it
+	overlayMount  = "O"
+	readOnlyMount = "ro"
+)
+
+func isPodman() bool {
+	return strings.Contains(filepath.Base(Settings.ContainerBinary), "podman")
+}
+
+// sourceVolume returns the volume of the input mounted into containers and
+// its mount options. With --read-only-input podman mounts the input as an
+// overlay, providers still write e.g. build output into the project but
+// the writes go to a scratch layer. Other container tools mount it read-only.
+func (a *analyzeCommand) sourceVolume(volName string) (string, map[string]string) {
+	// streamed volumes are a copy of the input already
+	if !a.readOnlyInput || a.streamInput {
+		return volName, nil
+	}
+	if isPodman() {
+		if input, err := a.inputMountDir(); err == nil {
+			return input, map[string]string{a.sourceMountPath(): overlayMount}
+		}
+	}
+	return volName, map[string]string{a.sourceMountPath(): readOnlyMount}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/analyzer-lsp/provider"
+	"go.lsp.dev/uri"
+)
+
+func Test_analyzeCommand_sourceVolume(t *testing.T) {
+	binary := Settings.ContainerBinary
+	defer func() { Settings.ContainerBinary = binary }()
+	input := t.TempDir()
+	real, err := resolveSymlinkPath(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		a           *analyzeCommand
+		binary      string
+		wantVolume  string
+		wantOptions map[string]string
+	}{
+		{"default", &analyzeCommand{input: input}, "/usr/bin/podman", "vol", nil},
+		{"podman", &analyzeCommand{input: input, readOnlyInput: true}, "/usr/bin/podman", real, map[string]string{SourceMountPath: overlayMount}},
+		{"docker", &analyzeCommand{input: input, readOnlyInput: true}, "/usr/bin/docker", "vol", map[string]string{SourceMountPath: readOnlyMount}},
+		{"streamed", &analyzeCommand{input: input, readOnlyInput: true, streamInput: true}, "/usr/bin/podman", "vol", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Settings.ContainerBinary = tt.binary
+			volume, options := tt.a.sourceVolume("vol")
+			if volume != tt.wantVolume || !reflect.DeepEqual(options, tt.wantOptions) {
+				t.Errorf("sourceVolume() = %s, %v, want %s, %v", volume, options, tt.wantVolume, tt.wantOptions)
+			}
+		})
+	}
+}
+
+func Test_analyzeCommand_readOnlyInputContainerless(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "app")
+	if err := os.MkdirAll(filepath.Join(input, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(input, "src", "App.java"), []byte("class App {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// defaults of the flags checked by Validate
+	a := &analyzeCommand{
+		log:            logr.Discard(),
+		cleanup:        true,
+		input:          input,
+		output:         filepath.Join(t.TempDir(), "output"),
+		rules:          []string{t.TempDir()},
+		runLocal:       true,
+		readOnlyInput:  true,
+		mode:           string(provider.FullAnalysisMode),
+		reportName:     defaultReportName,
+		reportIndex:    reportIndexFull,
+		progressStyle:  progressStyleAuto,
+		selinuxLabel:   selinuxLabelAuto,
+		providerUserNS: usernsAuto,
+		engineName:     defaultEngine,
+	}
+	if err := a.Validate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer a.removeValidateResources()
+	if a.snapshotDir == "" || !strings.HasPrefix(a.input, a.snapshotDir) {
+		t.Fatalf("read-only input %s is analyzed in place in containerless mode", a.input)
+	}
+
+	// incidents as reported by providers on the host analyzing the snapshot
+	rulesets, err := a.postProcessRuleSets([]outputv1.RuleSet{{
+		Name: "test",
+		Violations: map[string]outputv1.Violation{
+			"rule-00010": {Incidents: []outputv1.Incident{{URI: uri.File(filepath.Join(a.input, "src", "App.java"))}}},
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := uri.File(filepath.Join(input, "src", "App.java"))
+	if got := rulesets[0].Violations["rule-00010"].Incidents[0].URI; got != want {
+		t.Errorf("incident URI = %s, want %s", got, want)
+	}
+}
@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"fmt"
-	"github.com/konveyor/analyzer-lsp/provider"
 	"path"
 	"path/filepath"
+
+	"github.com/konveyor/analyzer-lsp/provider"
+
+	kantraprovider "github.com/konveyor-ecosystem/kantra/pkg/provider"
 )
 
 type JavaProvider struct {
@@ -13,42 +16,45 @@ type JavaProvider struct {
 
 func (p *JavaProvider) GetConfigVolume(a *analyzeCommand, tmpDir string) (provider.Config, error) {
 
-	var mountPath = SourceMountPath
+	var mountPath = a.sourceMountPath()
 	// when input is a file, it means it's probably a binary
 	// only java provider can work with binaries, all others
 	// continue pointing to the directory instead of file
 	if a.isFileInput {
-		mountPath = path.Join(SourceMountPath, filepath.Base(a.input))
+		mountPath = path.Join(mountPath, filepath.Base(a.input))
 	}
 
-	p.config = provider.Config{
-		Name:    javaProvider,
-		Address: fmt.Sprintf("0.0.0.0:%v", a.providersMap[javaProvider].port),
-		InitConfig: []provider.InitConfig{
-			{
-				Location:     mountPath,
-				AnalysisMode: provider.AnalysisMode(a.mode),
-				ProviderSpecificConfig: map[string]interface{}{
-					"lspServerName":                 javaProvider,
-					"bundles":                       JavaBundlesLocation,
-					"depOpenSourceLabelsFile":       "/usr/local/etc/maven.default.index",
-					provider.LspServerPathConfigKey: "/jdtls/bin/jdtls",
-				},
-			},
-		},
+	opts := kantraprovider.Options{
+		Mode:           kantraprovider.ContainerMode,
+		Location:       mountPath,
+		AnalysisMode:   provider.AnalysisMode(a.mode),
+		Port:           a.providersMap[javaProvider].port,
+		IncludedPaths:  a.includedPaths,
+		JVMMaxMemory:   a.jvmMaxMemory(),
+		JDTLSPath:      kantraprovider.ContainerJDTLSPath,
+		JavaBundlePath: JavaBundlesLocation,
 	}
 
-	if a.mavenSettingsFile != "" {
+	if a.isRegistryAuthMavenSettings() {
+		// credentials stay in the private registry auth dir
+		opts.MavenSettingsFile = path.Join(RegistryAuthMountPath, mavenSettingsName)
+	} else if a.mavenSettingsFile != "" {
 		err := copyFileContents(a.mavenSettingsFile, filepath.Join(tmpDir, "settings.xml"))
 		if err != nil {
 			a.log.V(1).Error(err, "failed copying maven settings file", "path", a.mavenSettingsFile)
 			return provider.Config{}, err
 		}
-		p.config.InitConfig[0].ProviderSpecificConfig["mavenSettingsFile"] = fmt.Sprintf("%s/%s", ConfigMountPath, "settings.xml")
+		opts.MavenSettingsFile = fmt.Sprintf("%s/%s", ConfigMountPath, "settings.xml")
 	}
-	if Settings.JvmMaxMem != "" {
-		p.config.InitConfig[0].ProviderSpecificConfig["jvmMaxMem"] = Settings.JvmMaxMem
+	// without network maven only resolves dependencies from the local repository
+	if a.providerNetwork == networkNone {
+		if err := writeOfflineMavenSettings(filepath.Join(tmpDir, "settings.xml")); err != nil {
+			a.log.V(1).Error(err, "failed writing offline maven settings file")
+			return provider.Config{}, err
+		}
+		opts.MavenSettingsFile = fmt.Sprintf("%s/%s", ConfigMountPath, "settings.xml")
 	}
 
+	p.config = kantraprovider.JavaConfig(opts)
 	return p.config, nil
 }
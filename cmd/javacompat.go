@@ -0,0 +1,425 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor-ecosystem/kantra/cmd/internal/i18n"
+)
+
+// javaRange is the range of Java versions a target runtime supports,
+// zero means unbounded
+type javaRange struct {
+	min int
+	max int
+}
+
+// javaTargetVersions maps migration targets to the Java versions they run on
+var javaTargetVersions = map[string]javaRange{
+	"eap7":        {min: 8, max: 11},
+	"eap8":        {min: 11, max: 21},
+	"jakarta-ee9": {min: 11},
+	"quarkus":     {min: 17},
+	"openjdk11":   {min: 11},
+	"openjdk17":   {min: 17},
+	"openjdk21":   {min: 21},
+}
+
+// eeTargetVersions maps application server targets to the latest Jakarta EE
+// version they run, Java EE 8 is Jakarta EE 8
+var eeTargetVersions = map[string]int{
+	"eap7": 8,
+	"eap8": 10,
+}
+
+// eeDescriptorVersions maps the root element and version of deployment
+// descriptors to their Jakarta EE version
+var eeDescriptorVersions = map[string]map[string]int{
+	"web-app":     {"2.5": 5, "3.0": 6, "3.1": 7, "4.0": 8, "5.0": 9, "6.0": 10, "6.1": 11},
+	"application": {"5": 5, "6": 6, "7": 7, "8": 8, "9": 9, "10": 10, "11": 11},
+	"ejb-jar":     {"3.0": 5, "3.1": 6, "3.2": 7, "4.0": 9},
+	"persistence": {"1.0": 5, "2.0": 6, "2.1": 7, "2.2": 8, "3.0": 9, "3.1": 10, "3.2": 11},
+}
+
+// file names of the deployment descriptors
+var eeDescriptorNames = []string{"web.xml", "application.xml", "ejb-jar.xml", "persistence.xml"}
+
+// number of class files read from an archive to find its bytecode level
+const maxInspectedClasses = 50
+
+type pomPlugin struct {
+	ArtifactID    string `xml:"artifactId"`
+	Configuration struct {
+		Source  string `xml:"source"`
+		Target  string `xml:"target"`
+		Release string `xml:"release"`
+	} `xml:"configuration"`
+}
+
+type pomProperty struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type pomProject struct {
+	Properties struct {
+		Entries []pomProperty `xml:",any"`
+	} `xml:"properties"`
+	Build struct {
+		Plugins []pomPlugin `xml:"plugins>plugin"`
+	} `xml:"build"`
+}
+
+var (
+	pomPropertyRef        = regexp.MustCompile(`^\$\{(.+)\}$`)
+	gradleCompatibility   = regexp.MustCompile(`(?:source|target)Compatibility\s*=\s*['"]?(?:JavaVersion\.VERSION_)?([0-9][0-9_.]*)`)
+	gradleLanguageVersion = regexp.MustCompile(`JavaLanguageVersion\.of\(\s*(\d+)\s*\)`)
+)
+
+// parseJavaVersion converts versions like 1.8, 8, 11 or 1_8 to the feature release
+func parseJavaVersion(v string) int {
+	v = strings.ReplaceAll(strings.TrimSpace(v), "_", ".")
+	v = strings.TrimPrefix(v, "1.")
+	major, _, _ := strings.Cut(v, ".")
+	version, err := strconv.Atoi(major)
+	if err != nil {
+		return 0
+	}
+	return version
+}
+
+// pomJavaVersion returns the Java version a maven project compiles for
+func pomJavaVersion(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	project := pomProject{}
+	err = xml.Unmarshal(data, &project)
+	if err != nil {
+		return 0, err
+	}
+	properties := map[string]string{}
+	for _, p := range project.Properties.Entries {
+		properties[p.XMLName.Local] = strings.TrimSpace(p.Value)
+	}
+	resolve := func(v string) string {
+		if m := pomPropertyRef.FindStringSubmatch(strings.TrimSpace(v)); m != nil {
+			return properties[m[1]]
+		}
+		return v
+	}
+	candidates := []string{}
+	for _, plugin := range project.Build.Plugins {
+		if plugin.ArtifactID == "maven-compiler-plugin" {
+			candidates = append(candidates, plugin.Configuration.Release, plugin.Configuration.Target, plugin.Configuration.Source)
+		}
+	}
+	candidates = append(candidates,
+		properties["maven.compiler.release"],
+		properties["maven.compiler.target"],
+		properties["maven.compiler.source"],
+		properties["java.version"])
+	for _, c := range candidates {
+		if version := parseJavaVersion(resolve(c)); version > 0 {
+			return version, nil
+		}
+	}
+	return 0, nil
+}
+
+// gradleJavaVersion returns the Java version a gradle build compiles for
+func gradleJavaVersion(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	for _, re := range []*regexp.Regexp{gradleLanguageVersion, gradleCompatibility} {
+		if m := re.FindSubmatch(data); m != nil {
+			return parseJavaVersion(string(m[1])), nil
+		}
+	}
+	return 0, nil
+}
+
+// archiveJavaVersion returns the highest Java version of the class
+// files in a jar, war or ear
+func archiveJavaVersion(path string) (int, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	return classesJavaVersion(reader.File)
+}
+
+// classesJavaVersion returns the highest Java version of the class files
+// of an archive
+func classesJavaVersion(files []*zip.File) (int, error) {
+	version := 0
+	inspected := 0
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name, ".class") || strings.HasSuffix(f.Name, "module-info.class") {
+			continue
+		}
+		header := struct {
+			Magic uint32
+			Minor uint16
+			Major uint16
+		}{}
+		rc, err := f.Open()
+		if err != nil {
+			return 0, err
+		}
+		err = binary.Read(rc, binary.BigEndian, &header)
+		rc.Close()
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, err
+		}
+		// class file major version 52 is Java 8
+		if header.Magic == 0xCAFEBABE && int(header.Major)-44 > version {
+			version = int(header.Major) - 44
+		}
+		inspected++
+		if inspected >= maxInspectedClasses {
+			break
+		}
+	}
+	return version, nil
+}
+
+// descriptorEEVersion returns the Jakarta EE version of a deployment
+// descriptor, 0 when unknown
+func descriptorEEVersion(r io.Reader) (int, error) {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return 0, err
+		}
+		root, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, attr := range root.Attr {
+			if attr.Name.Local == "version" {
+				return eeDescriptorVersions[root.Name.Local][strings.TrimSpace(attr.Value)], nil
+			}
+		}
+		return 0, nil
+	}
+}
+
+// javaInputVersions are the versions of the dependencies and deployment
+// descriptors of the input, by their slash separated path in the input
+type javaInputVersions struct {
+	dependencies map[string]int
+	descriptors  map[string]int
+}
+
+// inputJavaVersions returns the Java versions of the dependency jars and the
+// Jakarta EE versions of the deployment descriptors of the input, jars and
+// descriptors that fail to read are skipped
+func (a *analyzeCommand) inputJavaVersions() (javaInputVersions, error) {
+	versions := javaInputVersions{dependencies: map[string]int{}, descriptors: map[string]int{}}
+	if a.isFileInput {
+		switch strings.ToLower(filepath.Ext(a.input)) {
+		case JavaArchive, WebArchive, EnterpriseArchive:
+		default:
+			return versions, nil
+		}
+		reader, err := zip.OpenReader(a.input)
+		if err != nil {
+			return versions, err
+		}
+		defer reader.Close()
+		for _, f := range reader.File {
+			if err := a.addArchiveJavaVersion(versions, f); err != nil {
+				a.log.V(1).Error(err, "failed to read java version", "file", f.Name)
+			}
+		}
+		return versions, nil
+	}
+	err := filepath.WalkDir(a.input, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(a.input, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if a.inputIgnore.Ignored(rel, true) || slices.Contains(codeSkippedDirs, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if a.inputIgnore.Ignored(rel, false) {
+			return nil
+		}
+		if strings.EqualFold(filepath.Ext(p), JavaArchive) {
+			version, err := archiveJavaVersion(p)
+			if err != nil {
+				a.log.V(1).Error(err, "failed to read java version", "file", p)
+				return nil
+			}
+			versions.dependencies[rel] = version
+			return nil
+		}
+		if slices.Contains(eeDescriptorNames, d.Name()) {
+			file, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			version, err := descriptorEEVersion(file)
+			if err != nil {
+				a.log.V(1).Error(err, "failed to read jakarta ee version", "file", p)
+				return nil
+			}
+			versions.descriptors[rel] = version
+		}
+		return nil
+	})
+	return versions, err
+}
+
+// addArchiveJavaVersion adds the version of a dependency jar or deployment
+// descriptor in an archive input
+func (a *analyzeCommand) addArchiveJavaVersion(versions javaInputVersions, f *zip.File) error {
+	isJar := strings.EqualFold(path.Ext(f.Name), JavaArchive)
+	if !isJar && !slices.Contains(eeDescriptorNames, path.Base(f.Name)) {
+		return nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if !isJar {
+		version, err := descriptorEEVersion(rc)
+		if err != nil {
+			return err
+		}
+		versions.descriptors[f.Name] = version
+		return nil
+	}
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+	jar, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	version, err := classesJavaVersion(jar.File)
+	if err != nil {
+		return err
+	}
+	versions.dependencies[f.Name] = version
+	return nil
+}
+
+// latestVersion returns the path with the highest version, the first by
+// name of those with the same version
+func latestVersion(versions map[string]int) (string, int) {
+	latest, latestVersion := "", 0
+	for p, version := range versions {
+		if version > latestVersion || (version == latestVersion && version > 0 && p < latest) {
+			latest, latestVersion = p, version
+		}
+	}
+	return latest, latestVersion
+}
+
+// projectJavaVersion detects the Java version of the input, 0 when unknown
+func (a *analyzeCommand) projectJavaVersion() (int, error) {
+	if a.isFileInput {
+		switch strings.ToLower(filepath.Ext(a.input)) {
+		case JavaArchive, WebArchive, EnterpriseArchive:
+			return archiveJavaVersion(a.input)
+		}
+		return 0, nil
+	}
+	buildFiles := []struct {
+		name   string
+		detect func(string) (int, error)
+	}{
+		{"pom.xml", pomJavaVersion},
+		{"build.gradle", gradleJavaVersion},
+		{"build.gradle.kts", gradleJavaVersion},
+	}
+	for _, buildFile := range buildFiles {
+		path := filepath.Join(a.input, buildFile.name)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		return buildFile.detect(path)
+	}
+	return 0, nil
+}
+
+// checkJavaCompatibility compares the Java version of the input with the
+// versions supported by the selected targets
+func (a *analyzeCommand) checkJavaCompatibility() []string {
+	issues := []string{}
+	ranges := map[string]javaRange{}
+	for _, target := range a.targets {
+		if r, ok := javaTargetVersions[target]; ok {
+			ranges[target] = r
+		}
+	}
+	if len(ranges) == 0 {
+		return issues
+	}
+	version, err := a.projectJavaVersion()
+	if err != nil {
+		a.log.V(1).Error(err, "failed to detect java version of input")
+	}
+	inputVersions, err := a.inputJavaVersions()
+	if err != nil {
+		a.log.V(1).Error(err, "failed to detect java versions of dependencies and descriptors of input")
+	}
+	dependency, dependencyVersion := latestVersion(inputVersions.dependencies)
+	descriptor, descriptorVersion := latestVersion(inputVersions.descriptors)
+	for _, target := range a.targets {
+		r, ok := ranges[target]
+		if !ok {
+			continue
+		}
+		// dependencies built for older versions run on the later ones
+		if r.max > 0 && dependencyVersion > r.max {
+			issues = append(issues, i18n.Sprintf("target %s supports up to Java %d, dependency %s is built for Java %d",
+				target, r.max, dependency, dependencyVersion))
+		}
+		if ee := eeTargetVersions[target]; ee > 0 && descriptorVersion > ee {
+			issues = append(issues, i18n.Sprintf("target %s supports up to Jakarta EE %d, descriptor %s requires Jakarta EE %d",
+				target, ee, descriptor, descriptorVersion))
+		}
+		if version == 0 {
+			continue
+		}
+		if r.min > 0 && version < r.min {
+			issues = append(issues, i18n.Sprintf("target %s requires Java %d or later, project is built for Java %d",
+				target, r.min, version))
+		}
+		if r.max > 0 && version > r.max {
+			issues = append(issues, i18n.Sprintf("target %s supports up to Java %d, project is built for Java %d",
+				target, r.max, version))
+		}
+	}
+	return issues
+}
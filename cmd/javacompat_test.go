@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_parseJavaVersion(t *testing.T) {
+	tests := map[string]int{
+		"1.8":  8,
+		"8":    8,
+		"1_8":  8,
+		"11":   11,
+		"17.0": 17,
+		"":     0,
+		"abc":  0,
+	}
+	for v, want := range tests {
+		if got := parseJavaVersion(v); got != want {
+			t.Errorf("parseJavaVersion(%q) = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func Test_analyzeCommand_checkJavaCompatibility(t *testing.T) {
+	input := t.TempDir()
+	pom := `<project>
+  <properties>
+    <java.release>1.8</java.release>
+  </properties>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+        <configuration>
+          <release>${java.release}</release>
+        </configuration>
+      </plugin>
+    </plugins>
+  </build>
+</project>`
+	if err := os.WriteFile(filepath.Join(input, "pom.xml"), []byte(pom), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a := &analyzeCommand{input: input, log: logr.Discard(), targets: []string{"eap8", "cloud-readiness", "eap7"}}
+	want := []string{"target eap8 requires Java 11 or later, project is built for Java 8"}
+	if got := a.checkJavaCompatibility(); !reflect.DeepEqual(got, want) {
+		t.Errorf("checkJavaCompatibility() = %v, want %v", got, want)
+	}
+}
+
+// writeJar writes a jar with a class file of a Java version
+func writeJar(t *testing.T, w io.Writer, version int) {
+	t.Helper()
+	jar := zip.NewWriter(w)
+	class, err := jar.Create("org/example/App.class")
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := []byte{0xCA, 0xFE, 0xBA, 0xBE, 0, 0, 0, byte(version + 44)}
+	if _, err := class.Write(header); err != nil {
+		t.Fatal(err)
+	}
+	if err := jar.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_analyzeCommand_checkJavaCompatibilityDependencies(t *testing.T) {
+	webXML := `<web-app xmlns="https://jakarta.ee/xml/ns/jakartaee" version="6.0"></web-app>`
+	want := []string{
+		"target eap7 supports up to Java 11, dependency WEB-INF/lib/dep.jar is built for Java 17",
+		"target eap7 supports up to Jakarta EE 8, descriptor WEB-INF/web.xml requires Jakarta EE 10",
+	}
+
+	input := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(input, "WEB-INF", "lib"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	jar, err := os.Create(filepath.Join(input, "WEB-INF", "lib", "dep.jar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeJar(t, jar, 17)
+	jar.Close()
+	if err := os.WriteFile(filepath.Join(input, "WEB-INF", "web.xml"), []byte(webXML), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a := &analyzeCommand{input: input, log: logr.Discard(), targets: []string{"eap8", "eap7"}}
+	if got := a.checkJavaCompatibility(); !reflect.DeepEqual(got, want) {
+		t.Errorf("checkJavaCompatibility() = %v, want %v", got, want)
+	}
+
+	war := filepath.Join(t.TempDir(), "app.war")
+	file, err := os.Create(war)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive := zip.NewWriter(file)
+	dep, err := archive.Create("WEB-INF/lib/dep.jar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeJar(t, dep, 17)
+	descriptor, err := archive.Create("WEB-INF/web.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := descriptor.Write([]byte(webXML)); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	file.Close()
+	a = &analyzeCommand{input: war, isFileInput: true, log: logr.Discard(), targets: []string{"eap8", "eap7"}}
+	if got := a.checkJavaCompatibility(); !reflect.DeepEqual(got, want) {
+		t.Errorf("checkJavaCompatibility() of archive = %v, want %v", got, want)
+	}
+}
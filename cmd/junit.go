@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// file in the output dir of the junit output
+const junitOutputFile = "junit.xml"
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// junitViolation returns the failure of a rule with the incidents of its
+// violation, one per line
+func junitViolation(v outputv1.Violation) *junitMessage {
+	category := ""
+	if v.Category != nil {
+		category = string(*v.Category)
+	}
+	lines := []string{}
+	for _, incident := range v.Incidents {
+		location := normalizeIncidentURI(incident.URI)
+		if incident.LineNumber != nil {
+			location = fmt.Sprintf("%s:%d", location, *incident.LineNumber)
+		}
+		message, _, _ := strings.Cut(strings.TrimSpace(incident.Message), "\n")
+		lines = append(lines, fmt.Sprintf("%s: %s", location, message))
+	}
+	return &junitMessage{
+		Message: fmt.Sprintf("%s (%d incidents)", v.Description, len(v.Incidents)),
+		Type:    category,
+		Text:    strings.Join(lines, "\n"),
+	}
+}
+
+// buildJUnit maps the rules of each ruleset to test cases of a test suite,
+// failing with the incidents of the rules violated, so that CI servers show
+// them in their test reports
+func buildJUnit(name string, rulesets []outputv1.RuleSet) junitTestSuites {
+	suites := junitTestSuites{Name: name}
+	for _, rs := range rulesets {
+		suite := junitTestSuite{Name: rs.Name}
+		for ruleID, v := range rs.Violations {
+			suite.TestCases = append(suite.TestCases, junitTestCase{Name: ruleID, ClassName: rs.Name, Failure: junitViolation(v)})
+			suite.Failures++
+		}
+		for ruleID, err := range rs.Errors {
+			suite.TestCases = append(suite.TestCases, junitTestCase{Name: ruleID, ClassName: rs.Name, Error: &junitMessage{Message: err}})
+			suite.Errors++
+		}
+		for _, ruleID := range rs.Skipped {
+			suite.TestCases = append(suite.TestCases, junitTestCase{Name: ruleID, ClassName: rs.Name, Skipped: &junitMessage{}})
+			suite.Skipped++
+		}
+		for _, ruleID := range rs.Unmatched {
+			suite.TestCases = append(suite.TestCases, junitTestCase{Name: ruleID, ClassName: rs.Name})
+		}
+		if len(suite.TestCases) == 0 {
+			continue
+		}
+		sort.Slice(suite.TestCases, func(i, j int) bool { return suite.TestCases[i].Name < suite.TestCases[j].Name })
+		suite.Tests = len(suite.TestCases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+	return suites
+}
+
+// junitExporter writes the analysis output as a junit report
+type junitExporter struct{}
+
+func (junitExporter) File() string {
+	return junitOutputFile
+}
+
+func (junitExporter) Export(w io.Writer, data *exportData) error {
+	out, err := xml.MarshalIndent(buildJUnit(data.Name, data.RuleSets), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append([]byte(xml.Header), out...))
+	return err
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func Test_buildJUnit(t *testing.T) {
+	line := 12
+	mandatory := outputv1.Mandatory
+	rulesets := []outputv1.RuleSet{{
+		Name: "eap8",
+		Violations: map[string]outputv1.Violation{
+			"session-00010": {Description: "Stateful session EJB", Category: &mandatory, Incidents: []outputv1.Incident{
+				{URI: "file:///opt/input/source/src/Cart.java", LineNumber: &line, Message: "Replace the stateful EJB\nwith a CDI bean"},
+				{URI: "file:///opt/input/source/src/Order.java", Message: "Replace the stateful EJB"},
+			}},
+		},
+		Errors:    map[string]string{"jms-00001": "failed to evaluate condition"},
+		Skipped:   []string{"jms-00002"},
+		Unmatched: []string{"jaxrs-00001"},
+	}, {
+		Name: "empty",
+	}}
+
+	suites := buildJUnit("coolstore", rulesets)
+	if suites.Tests != 4 || suites.Failures != 1 || suites.Errors != 1 || suites.Skipped != 1 {
+		t.Errorf("buildJUnit() tests = %d, failures = %d, errors = %d, skipped = %d, want 4, 1, 1, 1",
+			suites.Tests, suites.Failures, suites.Errors, suites.Skipped)
+	}
+	if len(suites.Suites) != 1 {
+		t.Fatalf("buildJUnit() suites = %v, want the eap8 suite only", suites.Suites)
+	}
+	data, err := xml.Marshal(suites)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<testsuites name="coolstore" tests="4" failures="1" errors="1" skipped="1">`,
+		`<testcase name="jaxrs-00001" classname="eap8"></testcase>`,
+		`<error message="failed to evaluate condition"></error>`,
+		`<testcase name="jms-00002" classname="eap8"><skipped></skipped></testcase>`,
+		`<failure message="Stateful session EJB (2 incidents)" type="mandatory">/opt/input/source/src/Cart.java:12: Replace the stateful EJB&#xA;/opt/input/source/src/Order.java: Replace the stateful EJB</failure>`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("buildJUnit() = %s, want %s", data, want)
+		}
+	}
+}
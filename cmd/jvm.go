@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// environment variable read by the java launcher, options of the jvm
+// running jdtls are passed through it
+const javaOptionsEnv = "JDK_JAVA_OPTIONS"
+
+// garbage collectors of --jvm-gc
+var jvmGarbageCollectors = map[string]string{
+	"g1":         "-XX:+UseG1GC",
+	"parallel":   "-XX:+UseParallelGC",
+	"serial":     "-XX:+UseSerialGC",
+	"z":          "-XX:+UseZGC",
+	"shenandoah": "-XX:+UseShenandoahGC",
+}
+
+// heap of jdtls by lines of java code of the input, inputs below the
+// smallest size run with the defaults of the jvm
+var jvmHeapSizes = []struct {
+	lines int
+	heap  int
+}{
+	{lines: 2000000, heap: 16},
+	{lines: 1000000, heap: 12},
+	{lines: 500000, heap: 8},
+	{lines: 100000, heap: 4},
+}
+
+// average size of a line of java code in a binary, used to size the heap
+// for binaries that are decompiled during the analysis
+const binaryBytesPerLine = 10
+
+// validateJvm checks the jvm options of the java provider
+func (a *analyzeCommand) validateJvm() error {
+	if a.jvmGC != "" {
+		if _, ok := jvmGarbageCollectors[a.jvmGC]; !ok {
+			names := []string{}
+			for name := range jvmGarbageCollectors {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("jvm-gc must be one of %s", strings.Join(names, ", "))
+		}
+	}
+	if a.jdtlsWorkspace == "" {
+		return nil
+	}
+	if !a.runLocal {
+		return fmt.Errorf("jdtls-workspace requires containerless mode, set --run-local=true")
+	}
+	workspace, err := filepath.Abs(a.jdtlsWorkspace)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(workspace, 0755); err != nil {
+		return fmt.Errorf("%w failed to create jdtls workspace %s", err, workspace)
+	}
+	a.jdtlsWorkspace = workspace
+	return nil
+}
+
+// countJavaLines returns the lines of java code in input, binaries are
+// estimated from their size
+func countJavaLines(input string, ignore *kantraIgnore) (int, error) {
+	stat, err := os.Stat(input)
+	if err != nil {
+		return 0, err
+	}
+	if !stat.IsDir() {
+		return int(stat.Size() / binaryBytesPerLine), nil
+	}
+	lines := 0
+	err = filepath.WalkDir(input, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(input, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		if ignore.Ignored(filepath.ToSlash(rel), d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || filepath.Ext(p) != ".java" {
+			return nil
+		}
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		lines += bytes.Count(content, []byte("\n"))
+		return nil
+	})
+	return lines, err
+}
+
+// hostMemoryGB returns the memory of the host, 0 when unknown
+func hostMemoryGB() int {
+	if runtime.GOOS != "linux" {
+		return 0
+	}
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemTotal:" {
+			continue
+		}
+		kb, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return 0
+		}
+		return kb / 1024 / 1024
+	}
+	return 0
+}
+
+// jvmHeapForLines returns the max heap for lines of java code, at most
+// three quarters of the memory of the host when known
+func jvmHeapForLines(lines, memoryGB int) string {
+	for _, size := range jvmHeapSizes {
+		if lines < size.lines {
+			continue
+		}
+		heap := size.heap
+		if limit := memoryGB * 3 / 4; memoryGB > 0 && heap > limit {
+			heap = limit
+		}
+		if heap <= 1 {
+			// the initial heap of jdtls is 1g already
+			return ""
+		}
+		return fmt.Sprintf("%dg", heap)
+	}
+	return ""
+}
+
+// jvmMaxMemory returns the max heap of the java provider, from --jvm-max-mem,
+// JVM_MAX_MEM or sized by the lines of java code of the input in
+// containerless mode
+func (a *analyzeCommand) jvmMaxMemory() string {
+	if a.jvmMaxMem != "" {
+		return a.jvmMaxMem
+	}
+	if Settings.JvmMaxMem != "" {
+		return Settings.JvmMaxMem
+	}
+	// in containers jdtls sizes its heap by the memory of the container
+	if !a.runLocal || a.jvmHeapSized {
+		return a.jvmMaxMem
+	}
+	a.jvmHeapSized = true
+	lines, err := countJavaLines(a.input, a.inputIgnore)
+	if err != nil {
+		a.log.V(1).Error(err, "failed to count lines of java code, using the default jvm heap")
+		return ""
+	}
+	a.jvmMaxMem = jvmHeapForLines(lines, hostMemoryGB())
+	if a.jvmMaxMem != "" {
+		a.log.Info("sized jvm heap for input", "lines", lines, "jvmMaxMem", a.jvmMaxMem)
+	}
+	return a.jvmMaxMem
+}
+
+// jvmOptions returns the options of the jvm running jdtls
+func (a *analyzeCommand) jvmOptions() []string {
+	options := []string{}
+	if maxMem := a.jvmMaxMemory(); maxMem != "" {
+		options = append(options, "-Xmx"+maxMem)
+	}
+	if gc, ok := jvmGarbageCollectors[a.jvmGC]; ok {
+		options = append(options, gc)
+	}
+	return options
+}
+
+// javaProviderEnv returns the environment of the java provider container
+func (a *analyzeCommand) javaProviderEnv(prov string) map[string]string {
+	if prov != javaProvider {
+		return nil
+	}
+	if options := a.jvmOptions(); len(options) > 0 {
+		return map[string]string{javaOptionsEnv: strings.Join(options, " ")}
+	}
+	return nil
+}
+
+// setJavaOptionsContainerless passes the jvm options to jdtls started on
+// the host, keeping the options already set in the environment
+func (a *analyzeCommand) setJavaOptionsContainerless() error {
+	options := a.jvmOptions()
+	if len(options) == 0 {
+		return nil
+	}
+	if current := os.Getenv(javaOptionsEnv); current != "" {
+		options = append([]string{current}, options...)
+	}
+	return os.Setenv(javaOptionsEnv, strings.Join(options, " "))
+}
+
+// jdtlsWorkspaceDir returns the workspace of jdtls in containerless mode,
+// from --jdtls-workspace or cached per input unless disabled
+func (a *analyzeCommand) jdtlsWorkspaceDir() string {
+	if a.jdtlsWorkspace != "" || !a.reuseJdtlsWorkspace {
+		return a.jdtlsWorkspace
+	}
+	dir, err := cachedJdtlsWorkspace(a.input)
+	if err != nil {
+		a.log.V(1).Error(err, "failed to create cached jdtls workspace")
+		return ""
+	}
+	a.log.V(1).Info("using cached jdtls workspace", "input", a.input, "path", dir)
+	a.jdtlsWorkspace = dir
+	return dir
+}
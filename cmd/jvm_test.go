@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_jvmHeapForLines(t *testing.T) {
+	tests := []struct {
+		lines    int
+		memoryGB int
+		want     string
+	}{
+		{lines: 5000, memoryGB: 32, want: ""},
+		{lines: 150000, memoryGB: 32, want: "4g"},
+		{lines: 750000, memoryGB: 0, want: "8g"},
+		{lines: 3000000, memoryGB: 32, want: "16g"},
+		{lines: 3000000, memoryGB: 8, want: "6g"},
+		{lines: 3000000, memoryGB: 2, want: ""},
+	}
+	for _, tt := range tests {
+		if got := jvmHeapForLines(tt.lines, tt.memoryGB); got != tt.want {
+			t.Errorf("jvmHeapForLines(%d, %d) = %q, want %q", tt.lines, tt.memoryGB, got, tt.want)
+		}
+	}
+}
+
+func Test_countJavaLines(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "src", "App.java"), "class App {\n}\n")
+	writeTestFile(t, filepath.Join(dir, "src", "README.md"), "docs\n")
+	writeTestFile(t, filepath.Join(dir, "target", "Gen.java"), "class Gen {\n}\n")
+	writeTestFile(t, filepath.Join(dir, ".kantraignore"), "target/\n")
+	ignore, err := loadKantraIgnore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines, err := countJavaLines(dir, ignore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines != 2 {
+		t.Errorf("countJavaLines() = %d, want 2", lines)
+	}
+}
+
+func Test_analyzeCommand_jvmOptions(t *testing.T) {
+	a := &analyzeCommand{log: logr.Discard(), jvmMaxMem: "6g", jvmGC: "z"}
+	if err := a.validateJvm(); err != nil {
+		t.Fatal(err)
+	}
+	env := a.javaProviderEnv(javaProvider)
+	if got := env[javaOptionsEnv]; got != "-Xmx6g -XX:+UseZGC" {
+		t.Errorf("javaProviderEnv() = %q, want -Xmx6g -XX:+UseZGC", got)
+	}
+	if env := a.javaProviderEnv(goProvider); env != nil {
+		t.Errorf("javaProviderEnv(%s) = %v, want none", goProvider, env)
+	}
+	a = &analyzeCommand{log: logr.Discard(), jvmGC: "cms"}
+	if err := a.validateJvm(); err == nil || !strings.Contains(err.Error(), "g1") {
+		t.Errorf("validateJvm() = %v, want an error listing the garbage collectors", err)
+	}
+	a = &analyzeCommand{log: logr.Discard(), jdtlsWorkspace: t.TempDir()}
+	if err := a.validateJvm(); err == nil {
+		t.Errorf("validateJvm() must fail for jdtls-workspace in container mode")
+	}
+}
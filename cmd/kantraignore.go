@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// kantraIgnoreFile lists paths to exclude from analysis, it is read
+// from the root of the input and follows the .gitignore syntax
+const kantraIgnoreFile = ".kantraignore"
+
+type ignorePattern struct {
+	pattern string
+	negate  bool
+	dirOnly bool
+	// anchored patterns are matched against the path relative to the
+	// input root, others against the name of the file or directory
+	anchored bool
+}
+
+type kantraIgnore struct {
+	patterns []ignorePattern
+	// paths excluded explicitly, e.g. broken symlinks
+	excluded map[string]bool
+}
+
+// loadKantraIgnore reads the ignore file from the input root, it returns
+// nil when the file does not exist
+func loadKantraIgnore(root string) (*kantraIgnore, error) {
+	file, err := os.Open(filepath.Join(root, kantraIgnoreFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ignore := &kantraIgnore{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if p, ok := parseIgnorePattern(line); ok {
+			ignore.patterns = append(ignore.patterns, p)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ignore, nil
+}
+
+func parseIgnorePattern(line string) (ignorePattern, bool) {
+	p := ignorePattern{}
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		p.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return p, false
+	}
+	p.pattern = line
+	return p, true
+}
+
+// excludeDirs adds the dirs of --exclude-dir to the patterns of the ignore
+// file, as patterns of dirs after those of the file
+func excludeDirs(k *kantraIgnore, dirs []string) *kantraIgnore {
+	if len(dirs) == 0 {
+		return k
+	}
+	k = k.clone()
+	for _, dir := range dirs {
+		if p, ok := parseIgnorePattern(strings.TrimSuffix(filepath.ToSlash(dir), "/") + "/"); ok {
+			k.patterns = append(k.patterns, p)
+		}
+	}
+	return k
+}
+
+// Ignored returns true when the slash separated path relative to the
+// input root is excluded, the last matching pattern wins
+func (k *kantraIgnore) Ignored(relPath string, isDir bool) bool {
+	if k == nil {
+		return false
+	}
+	if k.excluded[relPath] {
+		return true
+	}
+	ignored := false
+	for _, p := range k.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.match(relPath) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// exclude excludes the exact slash separated path relative to the input root
+func (k *kantraIgnore) exclude(relPath string) {
+	if k.excluded == nil {
+		k.excluded = map[string]bool{}
+	}
+	k.excluded[relPath] = true
+}
+
+func (p ignorePattern) match(relPath string) bool {
+	if !p.anchored {
+		matched, _ := path.Match(p.pattern, path.Base(relPath))
+		return matched
+	}
+	pattern := p.pattern
+	switch {
+	case strings.HasPrefix(pattern, "**/"):
+		// match in any directory
+		pattern = strings.TrimPrefix(pattern, "**/")
+		segments := strings.Split(relPath, "/")
+		for i := range segments {
+			if matched, _ := path.Match(pattern, strings.Join(segments[i:], "/")); matched {
+				return true
+			}
+		}
+		return false
+	case strings.HasSuffix(pattern, "/**"):
+		// match everything inside of a directory
+		pattern = strings.TrimSuffix(pattern, "/**")
+		segments := strings.Split(relPath, "/")
+		for i := 1; i < len(segments); i++ {
+			if matched, _ := path.Match(pattern, strings.Join(segments[:i], "/")); matched {
+				return true
+			}
+		}
+		return false
+	}
+	matched, _ := path.Match(pattern, relPath)
+	return matched
+}
+
+// IncludedPaths converts the ignore patterns into the list of paths
+// relative to root that providers should analyze. Directories that are
+// not affected by any pattern are included as a whole. It returns nil
+// when nothing under root is ignored.
+func (k *kantraIgnore) IncludedPaths(root string) ([]string, error) {
+	if k == nil {
+		return nil, nil
+	}
+	included, complete, err := k.includedPaths(root, "")
+	if err != nil || complete {
+		return nil, err
+	}
+	sort.Strings(included)
+	return included, nil
+}
+
+func (k *kantraIgnore) includedPaths(root string, relDir string) ([]string, bool, error) {
+	entries, err := os.ReadDir(filepath.Join(root, filepath.FromSlash(relDir)))
+	if err != nil {
+		return nil, false, err
+	}
+	included := []string{}
+	complete := true
+	for _, entry := range entries {
+		relPath := path.Join(relDir, entry.Name())
+		if relDir == "" && entry.Name() == kantraIgnoreFile {
+			complete = false
+			continue
+		}
+		if k.Ignored(relPath, entry.IsDir()) {
+			complete = false
+			continue
+		}
+		if !entry.IsDir() {
+			included = append(included, relPath)
+			continue
+		}
+		subIncluded, subComplete, err := k.includedPaths(root, relPath)
+		if err != nil {
+			return nil, false, err
+		}
+		if subComplete {
+			included = append(included, relPath)
+		} else {
+			complete = false
+			included = append(included, subIncluded...)
+		}
+	}
+	return included, complete, nil
+}
+
+// clone returns a copy that can be extended without affecting k
+func (k *kantraIgnore) clone() *kantraIgnore {
+	c := &kantraIgnore{}
+	if k == nil {
+		return c
+	}
+	c.patterns = append(c.patterns, k.patterns...)
+	for rel := range k.excluded {
+		c.exclude(rel)
+	}
+	return c
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_kantraIgnore_IncludedPaths(t *testing.T) {
+	tests := []struct {
+		name        string
+		ignore      string
+		excludeDirs []string
+		files       []string
+		want        []string
+	}{
+		{
+			name:  "no ignore file means no filtering",
+			files: []string{"pom.xml", "src/main/App.java"},
+		},
+		{
+			name:   "ignored directory at any level",
+			ignore: "# build output\ntarget/\n",
+			files:  []string{"pom.xml", "src/main/App.java", "target/App.class", "module/target/Lib.class", "module/pom.xml"},
+			want:   []string{"module/pom.xml", "pom.xml", "src"},
+		},
+		{
+			name:   "anchored pattern with negation",
+			ignore: "/docs/*.md\n!docs/README.md\n",
+			files:  []string{"pom.xml", "docs/README.md", "docs/guide.md", "docs/img/logo.png"},
+			want:   []string{"docs/README.md", "docs/img", "pom.xml"},
+		},
+		{
+			name:   "double star patterns",
+			ignore: "**/generated/*.java\nvendor/**\n",
+			files:  []string{"src/generated/Gen.java", "src/App.java", "vendor/lib/a.js", "vendor.txt"},
+			want:   []string{"src/App.java", "vendor.txt"},
+		},
+		{
+			name:        "excluded dirs after the ignore file",
+			ignore:      "*.log\n",
+			excludeDirs: []string{"node_modules", "src/test/"},
+			files:       []string{"app.log", "node_modules/a.js", "web/node_modules/b.js", "web/index.html", "src/main/App.java", "src/test/AppTest.java", "test/a.txt"},
+			want:        []string{"src/main", "test", "web/index.html"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, f := range tt.files {
+				path := filepath.Join(root, filepath.FromSlash(f))
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte{}, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.ignore != "" {
+				if err := os.WriteFile(filepath.Join(root, kantraIgnoreFile), []byte(tt.ignore), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			ignore, err := loadKantraIgnore(root)
+			if err != nil {
+				t.Fatalf("loadKantraIgnore() error = %v", err)
+			}
+			got, err := excludeDirs(ignore, tt.excludeDirs).IncludedPaths(root)
+			if err != nil {
+				t.Fatalf("IncludedPaths() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IncludedPaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
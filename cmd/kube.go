@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+// paths in the pod of the kube job
+const (
+	kubeInputPath  = "/opt/input"
+	kubeOutputPath = "/opt/output"
+	// written by the job when the analysis exits, with its exit code
+	kubeExitCodeFile = "/opt/output/.exit-code"
+	// written by kantra once the output is copied, ending the job
+	kubeFetchedFile = "/opt/output/.fetched"
+)
+
+// interval of polling the kube job
+const kubePollInterval = 10 * time.Second
+
+// flags of the kube job, not passed to the analysis in the job
+var kubeFlags = []string{"kube", "kube-namespace", "kube-context", "kube-input-pvc", "kube-timeout", "input", "output", "run-local", "overwrite", "static-report-dir", "report-name", "metrics-file"}
+
+// runs the analysis, records its exit code and waits for kantra to copy the output
+const kubeJobScript = `kantra "$@"; echo $? > ` + kubeExitCodeFile + `.tmp; mv ` + kubeExitCodeFile + `.tmp ` + kubeExitCodeFile + `; while [ ! -f ` + kubeFetchedFile + ` ]; do sleep 5; done`
+
+var kubeNameInvalid = regexp.MustCompile(`[^a-z0-9-]+`)
+
+func isGitURL(input string) bool {
+	if scpRemote.MatchString(input) {
+		return true
+	}
+	for _, scheme := range []string{"https://", "http://", "ssh://", "git://"} {
+		if strings.HasPrefix(input, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// kubeJobName returns a job name valid in kubernetes, unique per run
+func kubeJobName(app string, now time.Time) string {
+	name := strings.Trim(kubeNameInvalid.ReplaceAllString(strings.ToLower(app), "-"), "-")
+	if len(name) > 40 {
+		name = strings.TrimRight(name[:40], "-")
+	}
+	if name == "" {
+		name = "input"
+	}
+	return fmt.Sprintf("kantra-%s-%s", name, strconv.FormatInt(now.Unix(), 36))
+}
+
+// kubeInputShortName returns the application name of the input of the job
+func kubeInputShortName(input string) string {
+	if isGitURL(input) {
+		input = strings.TrimSuffix(strings.TrimSuffix(input, "/"), ".git")
+	}
+	return path.Base(strings.ReplaceAll(input, ":", "/"))
+}
+
+// validateKube checks the flags of the kube job, the analysis in the job
+// validates the other flags
+func (a *analyzeCommand) validateKube() error {
+	if a.bulk || len(a.bulkInputs) > 0 {
+		return fmt.Errorf("must not specify both kube and bulk")
+	}
+	if a.allProfiles || len(a.profileNames) > 0 {
+		return fmt.Errorf("must not specify both kube and profiles")
+	}
+	if a.changedSince != "" {
+		// the job clones the last commit only
+		return fmt.Errorf("must not specify both kube and changed-since")
+	}
+	if !isGitURL(a.input) && a.kubeInputPVC == "" {
+		return fmt.Errorf("kube input must be a git URL or a path in the volume of --kube-input-pvc")
+	}
+	if isGitURL(a.input) && a.kubeInputPVC != "" {
+		return fmt.Errorf("must not specify both a git URL input and kube-input-pvc")
+	}
+	if path.IsAbs(a.input) && a.kubeInputPVC != "" {
+		return fmt.Errorf("input must be relative to the root of the volume of --kube-input-pvc")
+	}
+	for _, rules := range a.rules {
+		// rules in the volume of the input are read in the job
+		if a.kubeInputPVC == "" || !strings.HasPrefix(path.Clean(rules), kubeInputPath+"/") {
+			return fmt.Errorf("kube rules must be paths under %s in the volume of --kube-input-pvc, %s does not exist in the job", kubeInputPath, rules)
+		}
+	}
+	// the job is passed the other flags, host paths do not exist in it
+	hostPathFlags := []struct {
+		name string
+		set  bool
+	}{
+		{"maven-settings", a.mavenSettingsFile != ""},
+		{"registry-auth-file", len(a.registryAuthFiles) > 0},
+		{"profiles-dir", a.profilesDir != defaultProfilesDir},
+		{"jdtls-workspace", a.jdtlsWorkspace != ""},
+		{"override-provider-settings", a.overrideProviderSettings != ""},
+		{"app-metadata", a.appMetadataFile != ""},
+		{"scoring-model", a.scoringModelFile != ""},
+		{"quality-gates", a.qualityGatesFile != ""},
+	}
+	for _, flag := range hostPathFlags {
+		if flag.set {
+			return fmt.Errorf("must not specify both kube and %s, its host path does not exist in the job", flag.name)
+		}
+	}
+	if err := a.validateReportLocation(); err != nil {
+		return err
+	}
+	if _, err := exec.LookPath(Settings.Kubectl); err != nil {
+		return fmt.Errorf("%w kubectl is required to run the analysis in kubernetes", err)
+	}
+	// the output dir is created when copied from the job
+	return a.CheckOverwriteOutput()
+}
+
+// kubeJobArgs returns the arguments of the analysis in the job, the flags
+// set by the user with the input and output in the pod
+func (a *analyzeCommand) kubeJobArgs(flags *pflag.FlagSet) []string {
+	args := []string{"analyze", "--run-local", "--output", kubeOutputPath}
+	if a.kubeInputPVC != "" {
+		// the volume is mounted read-only
+		args = append(args, "--input", path.Join(kubeInputPath, a.input), "--read-only-input")
+	} else {
+		args = append(args, "--input", path.Join(kubeInputPath, "source"))
+	}
+	return append(args, flagArgs(flags, kubeFlags)...)
+}
+
+// kubeJobManifest returns the job analyzing the input in the cluster
+func (a *analyzeCommand) kubeJobManifest(name string, args []string) map[string]interface{} {
+	inputVolume := map[string]interface{}{"name": "input", "emptyDir": map[string]interface{}{}}
+	if a.kubeInputPVC != "" {
+		inputVolume = map[string]interface{}{
+			"name":                  "input",
+			"persistentVolumeClaim": map[string]interface{}{"claimName": a.kubeInputPVC, "readOnly": true},
+		}
+	}
+	mounts := []map[string]interface{}{
+		{"name": "input", "mountPath": kubeInputPath},
+		{"name": "output", "mountPath": kubeOutputPath},
+	}
+	spec := map[string]interface{}{
+		"restartPolicy": "Never",
+		"volumes": []map[string]interface{}{
+			inputVolume,
+			{"name": "output", "emptyDir": map[string]interface{}{}},
+		},
+		"containers": []map[string]interface{}{{
+			"name":         "kantra",
+			"image":        Settings.KubeImage,
+			"command":      []string{"/bin/sh", "-c", kubeJobScript, "kantra"},
+			"args":         args,
+			"volumeMounts": mounts,
+		}},
+	}
+	if isGitURL(a.input) {
+		spec["initContainers"] = []map[string]interface{}{{
+			"name":         "clone",
+			"image":        Settings.KubeGitImage,
+			"args":         []string{"clone", "--depth", "1", a.input, path.Join(kubeInputPath, "source")},
+			"volumeMounts": mounts[:1],
+		}}
+	}
+	return map[string]interface{}{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata": map[string]interface{}{
+			"name":   name,
+			"labels": map[string]string{"app.kubernetes.io/name": "kantra"},
+		},
+		"spec": map[string]interface{}{
+			"backoffLimit":          0,
+			"activeDeadlineSeconds": int64(a.kubeTimeout.Seconds()),
+			"template":              map[string]interface{}{"spec": spec},
+		},
+	}
+}
+
+func (a *analyzeCommand) kubectl(ctx context.Context, stdin io.Reader, args ...string) (string, error) {
+	if a.kubeContext != "" {
+		args = append([]string{"--context", a.kubeContext}, args...)
+	}
+	if a.kubeNamespace != "" {
+		args = append([]string{"--namespace", a.kubeNamespace}, args...)
+	}
+	cmd := exec.CommandContext(ctx, Settings.Kubectl, args...)
+	cmd.Stdin = stdin
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%w running kubectl %s: %s", err, strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// waitKubeJob waits for the analysis in the pod of the job to exit and
+// returns the pod and the exit code of the analysis
+func (a *analyzeCommand) waitKubeJob(ctx context.Context, name string) (string, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.kubeTimeout)
+	defer cancel()
+	pod := ""
+	for {
+		if pod == "" {
+			out, err := a.kubectl(ctx, nil, "get", "pods", "--selector", "job-name="+name,
+				"--output", "jsonpath={.items[0].metadata.name}")
+			if err == nil {
+				pod = out
+			}
+		}
+		if pod != "" {
+			phase, err := a.kubectl(ctx, nil, "get", "pod", pod, "--output", "jsonpath={.status.phase}")
+			if err == nil && phase == "Failed" {
+				return pod, 0, fmt.Errorf("pod %s of job %s failed, see kubectl logs %s --all-containers", pod, name, pod)
+			}
+			if phase == "Running" {
+				out, err := a.kubectl(ctx, nil, "exec", pod, "--container", "kantra", "--", "cat", kubeExitCodeFile)
+				if err == nil {
+					code, err := strconv.Atoi(out)
+					if err != nil {
+						return pod, 0, fmt.Errorf("%w unexpected exit code of the analysis in pod %s", err, pod)
+					}
+					return pod, code, nil
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return pod, 0, fmt.Errorf("%w waiting for job %s", ctx.Err(), name)
+		case <-time.After(kubePollInterval):
+		}
+	}
+}
+
+// RunKube analyzes the input in a kubernetes job with kubectl and copies
+// the output of the job to the output dir
+func (a *analyzeCommand) RunKube(ctx context.Context, flags *pflag.FlagSet) error {
+	name := kubeJobName(kubeInputShortName(a.input), time.Now())
+	manifest, err := yaml.Marshal(a.kubeJobManifest(name, a.kubeJobArgs(flags)))
+	if err != nil {
+		return err
+	}
+	a.log.V(5).Info("kube job", "manifest", string(manifest))
+	if _, err := a.kubectl(ctx, bytes.NewReader(manifest), "apply", "--filename", "-"); err != nil {
+		return err
+	}
+	a.log.Info("created kube job, waiting for the analysis", "job", name, "timeout", a.kubeTimeout)
+	if a.cleanup {
+		defer func() {
+			// the context of the run may be canceled
+			if _, err := a.kubectl(context.Background(), nil, "delete", "job", name, "--wait=false", "--cascade=background"); err != nil {
+				a.log.Error(err, "failed to delete kube job", "job", name)
+			}
+		}()
+	}
+	pod, code, err := a.waitKubeJob(ctx, name)
+	if err != nil {
+		return err
+	}
+	a.log.Info("copying analysis output from kube job", "pod", pod, "output", a.output)
+	if _, err := a.kubectl(ctx, nil, "cp", "--container", "kantra", pod+":"+kubeOutputPath, a.output); err != nil {
+		return err
+	}
+	if _, err := a.kubectl(ctx, nil, "exec", pod, "--container", "kantra", "--", "touch", kubeFetchedFile); err != nil {
+		a.log.Error(err, "failed to end kube job", "job", name)
+	}
+	for _, file := range []string{kubeExitCodeFile, kubeFetchedFile} {
+		os.Remove(filepath.Join(a.output, path.Base(file)))
+	}
+	if code != 0 {
+		return fmt.Errorf("analysis in kube job %s exited with code %d, see %s", name, code, filepath.Join(a.output, "analysis.log"))
+	}
+	a.log.Info("analysis in kube job done", "job", name, "output", a.output)
+	return a.publishStaticReport()
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func Test_kubeInputShortName(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/konveyor/example-applications.git": "example-applications",
+		"git@github.com:konveyor/coolstore.git":                "coolstore",
+		"apps/petclinic":                                       "petclinic",
+	}
+	for input, want := range tests {
+		if got := kubeInputShortName(input); got != want {
+			t.Errorf("kubeInputShortName(%s) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func Test_kubeJobName(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	if got, want := kubeJobName("Cool_Store.App", now), "kantra-cool-store-app-s44we8"; got != want {
+		t.Errorf("kubeJobName() = %s, want %s", got, want)
+	}
+}
+
+func Test_analyzeCommand_kubeJob(t *testing.T) {
+	flags := pflag.NewFlagSet("analyze", pflag.ContinueOnError)
+	flags.StringArray("target", []string{}, "")
+	flags.String("input", "", "")
+	flags.Bool("kube", false, "")
+	if err := flags.Parse([]string{"--kube", "--input", "https://github.com/org/app.git", "--target", "quarkus"}); err != nil {
+		t.Fatal(err)
+	}
+	a := &analyzeCommand{input: "https://github.com/org/app.git", kubeTimeout: time.Hour}
+	args := a.kubeJobArgs(flags)
+	want := []string{"analyze", "--run-local", "--output", "/opt/output", "--input", "/opt/input/source", "--target=quarkus"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("kubeJobArgs() = %v, want %v", args, want)
+	}
+	manifest := a.kubeJobManifest("kantra-app-1", args)
+	spec := manifest["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	if _, ok := spec["initContainers"]; !ok {
+		t.Error("kubeJobManifest() has no init container cloning the git input")
+	}
+
+	a = &analyzeCommand{input: "apps/petclinic", kubeInputPVC: "sources", kubeTimeout: time.Hour}
+	manifest = a.kubeJobManifest("kantra-petclinic-1", a.kubeJobArgs(flags))
+	spec = manifest["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	if _, ok := spec["initContainers"]; ok {
+		t.Error("kubeJobManifest() clones the input of a volume")
+	}
+	volume := spec["volumes"].([]map[string]interface{})[0]
+	if claim := volume["persistentVolumeClaim"].(map[string]interface{})["claimName"]; claim != "sources" {
+		t.Errorf("kubeJobManifest() input volume claim = %v, want sources", claim)
+	}
+}
+
+func Test_analyzeCommand_validateKubeHostPaths(t *testing.T) {
+	tests := map[string]*analyzeCommand{
+		"host rules":         {rules: []string{"/home/user/rules"}},
+		"rules without pvc":  {input: "https://github.com/org/app.git", rules: []string{"/opt/input/rules"}},
+		"maven settings":     {mavenSettingsFile: "settings.xml"},
+		"registry auth file": {registryAuthFiles: []string{".npmrc"}},
+	}
+	for name, a := range tests {
+		t.Run(name, func(t *testing.T) {
+			if a.input == "" {
+				a.input, a.kubeInputPVC = "apps/petclinic", "sources"
+			}
+			a.profilesDir = defaultProfilesDir
+			if err := a.validateKube(); err == nil || !strings.Contains(err.Error(), "does not exist in the job") {
+				t.Errorf("validateKube() error = %v, want host path error", err)
+			}
+		})
+	}
+
+	a := &analyzeCommand{input: "apps/petclinic", kubeInputPVC: "sources", profilesDir: defaultProfilesDir, rules: []string{"/opt/input/rules"}}
+	if err := a.validateKube(); err != nil && strings.Contains(err.Error(), "does not exist in the job") {
+		t.Errorf("validateKube() error = %v, want rules of the volume accepted", err)
+	}
+}
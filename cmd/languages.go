@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/devfile/alizer/pkg/apis/model"
+	"github.com/devfile/alizer/pkg/apis/recognizer"
+)
+
+// targets suggested for frameworks detected by alizer
+var frameworkTargets = map[string][]string{
+	"Spring":      {"quarkus", "eap8", "openjdk17"},
+	"Spring Boot": {"quarkus", "eap8", "openjdk17"},
+	"JBoss EAP":   {"eap8", "quarkus", "jakarta-ee"},
+	"WildFly":     {"eap8", "quarkus", "jakarta-ee"},
+	"OpenLiberty": {"openliberty", "quarkus"},
+	"Micronaut":   {"quarkus", "openjdk17"},
+	"Vertx":       {"quarkus", "openjdk17"},
+	"Quarkus":     {"openjdk17"},
+}
+
+// targets suggested for languages without a known framework
+var languageTargets = map[string][]string{
+	"Java": {"cloud-readiness", "openjdk17"},
+}
+
+// detectedLanguage is a language alizer found in the input
+type detectedLanguage struct {
+	Name string `json:"name" yaml:"name"`
+	// share of the input in percent, used as the confidence of the detection
+	Confidence float64  `json:"confidence" yaml:"confidence"`
+	Frameworks []string `json:"frameworks,omitempty" yaml:"frameworks,omitempty"`
+	Tools      []string `json:"tools,omitempty" yaml:"tools,omitempty"`
+	// ports the components of the language listen on
+	Ports []int `json:"ports,omitempty" yaml:"ports,omitempty"`
+}
+
+type languageReport struct {
+	Languages        []detectedLanguage `json:"languages" yaml:"languages"`
+	SuggestedTargets []string           `json:"suggestedTargets" yaml:"suggestedTargets"`
+}
+
+// suggestTargets returns the analysis targets matching the detected
+// frameworks, or the languages of the input when none is known
+func suggestTargets(languages []detectedLanguage) []string {
+	targets := []string{}
+	for _, l := range languages {
+		found := false
+		for _, f := range l.Frameworks {
+			if t, ok := frameworkTargets[f]; ok {
+				targets = append(targets, t...)
+				found = true
+			}
+		}
+		if !found {
+			targets = append(targets, languageTargets[l.Name]...)
+		}
+	}
+	return uniqueStrings(targets)
+}
+
+// detectLanguages runs the language detection and adds the ports of the
+// detected components to their languages
+func detectLanguages(input string) (*languageReport, error) {
+	languages, err := recognizer.Analyze(input)
+	if err != nil {
+		return nil, err
+	}
+	components, err := recognizer.DetectComponents(input)
+	if err != nil {
+		return nil, err
+	}
+	return newLanguageReport(languages, components), nil
+}
+
+func newLanguageReport(languages []model.Language, components []model.Component) *languageReport {
+	ports := map[string][]int{}
+	for _, c := range components {
+		for _, l := range c.Languages {
+			ports[l.Name] = append(ports[l.Name], c.Ports...)
+		}
+	}
+	report := &languageReport{Languages: []detectedLanguage{}}
+	for _, l := range languages {
+		lang := detectedLanguage{
+			Name:       l.Name,
+			Confidence: float64(int(l.Weight*100)) / 100,
+			Frameworks: l.Frameworks,
+			Tools:      l.Tools,
+		}
+		if p := ports[l.Name]; len(p) > 0 {
+			sort.Ints(p)
+			lang.Ports = p
+		}
+		report.Languages = append(report.Languages, lang)
+	}
+	sort.SliceStable(report.Languages, func(i, j int) bool {
+		return report.Languages[i].Confidence > report.Languages[j].Confidence
+	})
+	report.SuggestedTargets = suggestTargets(report.Languages)
+	return report
+}
+
+func formatPorts(ports []int) string {
+	s := []string{}
+	for _, p := range ports {
+		s = append(s, fmt.Sprint(p))
+	}
+	return strings.Join(s, ",")
+}
+
+// ListLanguages prints the languages, frameworks and tools of the input
+// and the targets suggested for them
+func (a *analyzeCommand) ListLanguages(out io.Writer) error {
+	report, err := detectLanguages(a.input)
+	if err != nil {
+		return fmt.Errorf("%w failed to determine languages for input", err)
+	}
+	if a.listFormat != "" && a.listFormat != listFormatText {
+		return writeListOutput(out, a.listFormat, report)
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LANGUAGE\tCONFIDENCE\tFRAMEWORKS\tTOOLS\tPORTS")
+	for _, l := range report.Languages {
+		fmt.Fprintf(w, "%s\t%.2f%%\t%s\t%s\t%s\n", l.Name, l.Confidence,
+			strings.Join(l.Frameworks, ","), strings.Join(l.Tools, ","), formatPorts(l.Ports))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, l := range report.Languages {
+		// frameworks with the same targets, e.g. Spring and Spring Boot, share a line
+		keys := []string{}
+		byTargets := map[string][]string{}
+		for _, f := range l.Frameworks {
+			t, ok := frameworkTargets[f]
+			if !ok {
+				continue
+			}
+			key := strings.Join(t, ", ")
+			if _, ok := byTargets[key]; !ok {
+				keys = append(keys, key)
+			}
+			byTargets[key] = append(byTargets[key], f)
+		}
+		for _, key := range keys {
+			fmt.Fprintf(out, "detected %s: suggested targets %s\n", strings.Join(byTargets[key], ", "), key)
+		}
+	}
+	if len(report.SuggestedTargets) > 0 {
+		fmt.Fprintf(out, "suggested targets: %s\n", strings.Join(report.SuggestedTargets, ", "))
+	}
+	return nil
+}
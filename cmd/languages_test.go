@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/devfile/alizer/pkg/apis/model"
+)
+
+func Test_newLanguageReport(t *testing.T) {
+	languages := []model.Language{
+		{Name: "JavaScript", Weight: 20.123},
+		{Name: "Java", Weight: 79.877, Frameworks: []string{"Spring", "Spring Boot"}, Tools: []string{"Maven"}},
+	}
+	components := []model.Component{
+		{Name: "app", Languages: []model.Language{{Name: "Java"}}, Ports: []int{8443, 8080}},
+	}
+	report := newLanguageReport(languages, components)
+	want := []detectedLanguage{
+		{Name: "Java", Confidence: 79.87, Frameworks: []string{"Spring", "Spring Boot"}, Tools: []string{"Maven"}, Ports: []int{8080, 8443}},
+		{Name: "JavaScript", Confidence: 20.12},
+	}
+	if !reflect.DeepEqual(report.Languages, want) {
+		t.Errorf("newLanguageReport() languages = %+v, want %+v", report.Languages, want)
+	}
+	if want := []string{"eap8", "openjdk17", "quarkus"}; !reflect.DeepEqual(report.SuggestedTargets, want) {
+		t.Errorf("newLanguageReport() suggested targets = %v, want %v", report.SuggestedTargets, want)
+	}
+}
+
+func Test_suggestTargets(t *testing.T) {
+	got := suggestTargets([]detectedLanguage{{Name: "Java"}, {Name: "Go"}})
+	if want := []string{"cloud-readiness", "openjdk17"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("suggestTargets() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,377 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"gopkg.in/yaml.v2"
+)
+
+// status of a dependency in the license report
+const (
+	licenseAllowed    = "allowed"
+	licenseDenied     = "denied"
+	licenseNotAllowed = "not-allowed"
+	licenseUnknown    = "unknown"
+)
+
+// parent poms followed to find the licenses of a maven dependency
+const maxPomParents = 5
+
+type dependencyLicense struct {
+	Name     string   `yaml:"name" json:"name"`
+	Version  string   `yaml:"version,omitempty" json:"version,omitempty"`
+	Provider string   `yaml:"provider" json:"provider"`
+	Licenses []string `yaml:"licenses" json:"licenses"`
+	Status   string   `yaml:"status" json:"status"`
+}
+
+type licenseReport struct {
+	Dependencies []dependencyLicense `yaml:"dependencies" json:"dependencies"`
+	// dependencies with a denied or not allowed license
+	Violations int `yaml:"violations" json:"violations"`
+	// dependencies without license metadata
+	Unknown int `yaml:"unknown" json:"unknown"`
+}
+
+type pomLicenses struct {
+	Parent struct {
+		GroupID    string `xml:"groupId"`
+		ArtifactID string `xml:"artifactId"`
+		Version    string `xml:"version"`
+	} `xml:"parent"`
+	Licenses []struct {
+		Name string `xml:"name"`
+	} `xml:"licenses>license"`
+}
+
+// mavenRepositories returns local maven repositories holding the poms of
+// the dependencies, the one shared with provider containers first
+func (a *analyzeCommand) mavenRepositories() []string {
+	repos := []string{}
+	if a.m2Dir != "" {
+		repos = append(repos, filepath.Join(a.m2Dir, "repository"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		repos = append(repos, filepath.Join(home, ".m2", "repository"))
+	}
+	return repos
+}
+
+// mavenLicenses reads the licenses of an artifact from its pom, or the
+// pom of its closest parent declaring them
+func mavenLicenses(repos []string, groupID, artifactID, version string) []string {
+	for i := 0; i <= maxPomParents && groupID != "" && artifactID != "" && version != ""; i++ {
+		var data []byte
+		for _, repo := range repos {
+			pomPath := filepath.Join(repo, filepath.FromSlash(strings.ReplaceAll(groupID, ".", "/")),
+				artifactID, version, fmt.Sprintf("%s-%s.pom", artifactID, version))
+			if content, err := os.ReadFile(pomPath); err == nil {
+				data = content
+				break
+			}
+		}
+		if data == nil {
+			return nil
+		}
+		pom := pomLicenses{}
+		if err := xml.Unmarshal(data, &pom); err != nil {
+			return nil
+		}
+		if len(pom.Licenses) > 0 {
+			licenses := []string{}
+			for _, l := range pom.Licenses {
+				if name := strings.TrimSpace(l.Name); name != "" {
+					licenses = append(licenses, name)
+				}
+			}
+			return licenses
+		}
+		groupID, artifactID, version = pom.Parent.GroupID, pom.Parent.ArtifactID, pom.Parent.Version
+	}
+	return nil
+}
+
+type packageJSON struct {
+	Version      string            `json:"version"`
+	Dependencies map[string]string `json:"dependencies"`
+	// a SPDX expression, or an object in old packages
+	License  interface{} `json:"license"`
+	Licenses []struct {
+		Type string `json:"type"`
+	} `json:"licenses"`
+}
+
+func (p packageJSON) licenses() []string {
+	licenses := []string{}
+	switch l := p.License.(type) {
+	case string:
+		licenses = append(licenses, l)
+	case map[string]interface{}:
+		if t, ok := l["type"].(string); ok {
+			licenses = append(licenses, t)
+		}
+	}
+	for _, l := range p.Licenses {
+		licenses = append(licenses, l.Type)
+	}
+	return licenses
+}
+
+func readPackageJSON(path string) (packageJSON, error) {
+	pkg := packageJSON{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return pkg, err
+	}
+	err = json.Unmarshal(data, &pkg)
+	return pkg, err
+}
+
+// npmDependencyLicenses returns the licenses of the dependencies declared
+// in the package.json of dir, read from their installed package
+func npmDependencyLicenses(dir string) []dependencyLicense {
+	pkg, err := readPackageJSON(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+	deps := []dependencyLicense{}
+	for name, spec := range pkg.Dependencies {
+		dep := dependencyLicense{Name: name, Version: spec, Provider: nodeJSProvider, Licenses: []string{}}
+		installed, err := readPackageJSON(filepath.Join(dir, "node_modules", filepath.FromSlash(name), "package.json"))
+		if err == nil {
+			dep.Version = installed.Version
+			dep.Licenses = installed.licenses()
+		}
+		deps = append(deps, dep)
+	}
+	return deps
+}
+
+func matchesLicense(patterns []string, license string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(license)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// licenseStatus checks the licenses of a dependency against the deny and
+// allow lists, a dependency is allowed when one of its licenses is
+func licenseStatus(licenses, allow, deny []string) string {
+	if len(licenses) == 0 {
+		return licenseUnknown
+	}
+	allowed := false
+	for _, l := range licenses {
+		if matchesLicense(deny, l) {
+			return licenseDenied
+		}
+		if len(allow) == 0 || matchesLicense(allow, l) {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return licenseNotAllowed
+	}
+	return licenseAllowed
+}
+
+// buildLicenseReport collects the licenses of the dependencies found by
+// dependency analysis and of the npm packages of the input
+func (a *analyzeCommand) buildLicenseReport(depsPath string) (*licenseReport, error) {
+	report := &licenseReport{Dependencies: []dependencyLicense{}}
+	data, err := readOutputFile(depsPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	items := []outputv1.DepsFlatItem{}
+	if err := yaml.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	repos := a.mavenRepositories()
+	seen := map[string]bool{}
+	for _, item := range items {
+		for _, dep := range item.Dependencies {
+			if dep == nil {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s@%s", item.Provider, dep.Name, dep.Version)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			d := dependencyLicense{Name: dep.Name, Version: dep.Version, Provider: item.Provider, Licenses: []string{}}
+			if item.Provider == javaProvider {
+				groupID, _ := dep.Extras["groupId"].(string)
+				artifactID, _ := dep.Extras["artifactId"].(string)
+				if licenses := mavenLicenses(repos, groupID, artifactID, dep.Version); licenses != nil {
+					d.Licenses = licenses
+				}
+			}
+			report.Dependencies = append(report.Dependencies, d)
+		}
+	}
+	if !a.isFileInput {
+		for _, d := range npmDependencyLicenses(a.input) {
+			key := fmt.Sprintf("%s/%s@%s", d.Provider, d.Name, d.Version)
+			if !seen[key] {
+				seen[key] = true
+				report.Dependencies = append(report.Dependencies, d)
+			}
+		}
+	}
+	for i := range report.Dependencies {
+		d := &report.Dependencies[i]
+		d.Status = licenseStatus(d.Licenses, a.licenseAllow, a.licenseDeny)
+		switch d.Status {
+		case licenseDenied, licenseNotAllowed:
+			report.Violations++
+		case licenseUnknown:
+			report.Unknown++
+		}
+	}
+	sort.SliceStable(report.Dependencies, func(i, j int) bool {
+		if report.Dependencies[i].Provider != report.Dependencies[j].Provider {
+			return report.Dependencies[i].Provider < report.Dependencies[j].Provider
+		}
+		return report.Dependencies[i].Name < report.Dependencies[j].Name
+	})
+	return report, nil
+}
+
+// writeLicenseReport writes licenses.json to the output dir
+func (a *analyzeCommand) writeLicenseReport() error {
+	if !a.licenseReport {
+		return nil
+	}
+	depsPath := filepath.Join(a.output, "dependencies.yaml")
+	reportPath := filepath.Join(a.output, "licenses.json")
+	// bulk analysis moves results to the application dir
+	if _, err := os.Stat(depsPath); errors.Is(err, os.ErrNotExist) && a.bulk {
+		depsPath = a.bulkResultPath("dependencies.yaml")
+		reportPath = a.bulkResultPath("licenses.json")
+	}
+	report, err := a.buildLicenseReport(depsPath)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(reportPath, data, 0644); err != nil {
+		return err
+	}
+	a.log.Info("wrote license report", "file", reportPath, "dependencies", len(report.Dependencies),
+		"violations", report.Violations, "unknown", report.Unknown)
+	a.licenseViolations = report.Violations
+	return nil
+}
+
+// page of the static report with the licenses of the dependencies
+const licensesPage = "licenses.html"
+
+// licensesApp is the license report of an application of the static report
+type licensesApp struct {
+	Name string
+	*licenseReport
+}
+
+var licensesTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Licenses</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border-bottom: 1px solid #d2d2d2; padding: 0.3em 1em 0.3em 0; text-align: left; }
+.version { color: #6a6e73; }
+.denied, .not-allowed { color: #c9190b; font-weight: bold; }
+.unknown { color: #6a6e73; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>Licenses</h1>
+{{- range .}}
+<h2>{{.Name}}</h2>
+<p>{{len .Dependencies}} dependencies, {{.Violations}} denied or not allowed, {{.Unknown}} without license metadata</p>
+<table>
+<tr><th>Dependency</th><th>Provider</th><th>Licenses</th><th>Status</th></tr>
+{{- range .Dependencies}}
+<tr><td>{{.Name}} <span class="version">{{.Version}}</span></td><td>{{.Provider}}</td><td>{{range $i, $l := .Licenses}}{{if $i}}, {{end}}{{$l}}{{end}}</td><td class="{{.Status}}">{{.Status}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
+
+// writeLicensesPage adds a page with the licenses of the dependencies of
+// the applications to the static report, so that they can be reviewed next
+// to the incidents instead of in licenses.json
+func (a *analyzeCommand) writeLicensesPage() error {
+	if !a.licenseReport || a.skipStaticReport {
+		return nil
+	}
+	reportDir := filepath.Join(a.output, "static-report")
+	if _, err := os.Stat(reportDir); err != nil {
+		return nil
+	}
+	reports := map[string]string{filepath.Base(a.input): filepath.Join(a.output, "licenses.json")}
+	if a.bulk {
+		bulkApps, err := listBulkApps(a.output)
+		if err != nil {
+			return err
+		}
+		reports = map[string]string{}
+		for _, app := range bulkApps {
+			reports[app.name] = filepath.Join(bulkAppDir(a.output, app.name), "licenses.json")
+		}
+	}
+	apps := []licensesApp{}
+	for _, name := range sortedMapKeys(reports) {
+		data, err := os.ReadFile(reports[name])
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		report := &licenseReport{}
+		if err := json.Unmarshal(data, report); err != nil {
+			return fmt.Errorf("%w failed to parse %s", err, reports[name])
+		}
+		apps = append(apps, licensesApp{Name: name, licenseReport: report})
+	}
+	if len(apps) == 0 {
+		return nil
+	}
+	page := &bytes.Buffer{}
+	if err := licensesTemplate.Execute(page, apps); err != nil {
+		return err
+	}
+	a.log.V(1).Info("wrote static report licenses", "page", licensesPage)
+	return os.WriteFile(filepath.Join(reportDir, licensesPage), page.Bytes(), 0644)
+}
+
+// checkLicenses fails when --fail-on-license is set and a dependency has
+// a denied or not allowed license
+func (a *analyzeCommand) checkLicenses() error {
+	if !a.failOnLicense || a.licenseViolations == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d dependencies have denied or not allowed licenses, see licenses.json", a.licenseViolations)
+}
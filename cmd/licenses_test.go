@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func writeTestFile(t *testing.T, path string, content string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_analyzeCommand_buildLicenseReport(t *testing.T) {
+	m2Dir := t.TempDir()
+	repo := filepath.Join(m2Dir, "repository")
+	writeTestFile(t, filepath.Join(repo, "org", "example", "lib", "1.0", "lib-1.0.pom"), `<project>
+  <parent><groupId>org.example</groupId><artifactId>parent</artifactId><version>2</version></parent>
+</project>`)
+	writeTestFile(t, filepath.Join(repo, "org", "example", "parent", "2", "parent-2.pom"), `<project>
+  <licenses><license><name>Apache License, Version 2.0</name></license></licenses>
+</project>`)
+	writeTestFile(t, filepath.Join(repo, "org", "gnu", "tool", "3.1", "tool-3.1.pom"), `<project>
+  <licenses><license><name>GPL-3.0</name></license></licenses>
+</project>`)
+
+	input := t.TempDir()
+	writeTestFile(t, filepath.Join(input, "package.json"), `{"dependencies": {"left-pad": "^1.0.0", "missing": "^2.0.0"}}`)
+	writeTestFile(t, filepath.Join(input, "node_modules", "left-pad", "package.json"), `{"version": "1.3.0", "license": "WTFPL"}`)
+
+	depsPath := filepath.Join(t.TempDir(), "dependencies.yaml")
+	writeTestFile(t, depsPath, `- fileURI: file:///app/pom.xml
+  provider: java
+  dependencies:
+  - name: org.example.lib
+    version: "1.0"
+    extras:
+      groupId: org.example
+      artifactId: lib
+  - name: org.gnu.tool
+    version: "3.1"
+    extras:
+      groupId: org.gnu
+      artifactId: tool
+`)
+
+	a := &analyzeCommand{
+		log:          logr.Discard(),
+		input:        input,
+		m2Dir:        m2Dir,
+		licenseAllow: []string{"Apache*", "MIT"},
+		licenseDeny:  []string{"GPL*"},
+	}
+	report, err := a.buildLicenseReport(depsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"org.example.lib": licenseAllowed,
+		"org.gnu.tool":    licenseDenied,
+		"left-pad":        licenseNotAllowed,
+		"missing":         licenseUnknown,
+	}
+	if len(report.Dependencies) != len(want) {
+		t.Fatalf("got %d dependencies, want %d", len(report.Dependencies), len(want))
+	}
+	for _, d := range report.Dependencies {
+		if d.Status != want[d.Name] {
+			t.Errorf("status of %s with licenses %v = %s, want %s", d.Name, d.Licenses, d.Status, want[d.Name])
+		}
+	}
+	if report.Violations != 2 || report.Unknown != 1 {
+		t.Errorf("got %d violations and %d unknown, want 2 and 1", report.Violations, report.Unknown)
+	}
+}
+
+func Test_analyzeCommand_writeLicensesPage(t *testing.T) {
+	output := t.TempDir()
+	writeTestFile(t, filepath.Join(output, "static-report", "index.html"), "<html></html>")
+	writeTestFile(t, filepath.Join(output, "licenses.json"), `{
+  "dependencies": [
+    {"name": "junit.junit", "version": "4.12", "provider": "java", "licenses": ["EPL-1.0"], "status": "denied"},
+    {"name": "<script>", "provider": "nodejs", "licenses": [], "status": "unknown"}
+  ],
+  "violations": 1,
+  "unknown": 1
+}`)
+
+	a := &analyzeCommand{input: "/apps/cart", output: output, licenseReport: true, log: logr.Discard()}
+	if err := a.writeLicensesPage(); err != nil {
+		t.Fatal(err)
+	}
+	page, err := os.ReadFile(filepath.Join(output, "static-report", licensesPage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<h2>cart</h2>",
+		"2 dependencies, 1 denied or not allowed, 1 without license metadata",
+		"<td>junit.junit <span class=\"version\">4.12</span></td><td>java</td><td>EPL-1.0</td><td class=\"denied\">denied</td>",
+		"&lt;script&gt;",
+	} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("writeLicensesPage() page does not contain %s:\n%s", want, page)
+		}
+	}
+}
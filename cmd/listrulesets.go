@@ -0,0 +1,373 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/konveyor-ecosystem/kantra/cmd/internal/i18n"
+	"github.com/konveyor-ecosystem/kantra/pkg/container"
+	"github.com/konveyor/analyzer-lsp/engine"
+	"github.com/konveyor/analyzer-lsp/engine/labels"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"gopkg.in/yaml.v2"
+)
+
+// origins of the listed rulesets
+const (
+	rulesetOriginDefault      = "default"
+	rulesetOriginRules        = "rules"
+	rulesetOriginPlatform     = "platform"
+	rulesetOriginServerConfig = "server-config"
+	rulesetOriginProfile      = "profile"
+)
+
+// rulesetListing is a ruleset the analysis loads
+type rulesetListing struct {
+	Name   string `json:"name" yaml:"name"`
+	Origin string `json:"origin" yaml:"origin"`
+	// profile of the rules of profile origin
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Path    string `json:"path" yaml:"path"`
+	Rules   int    `json:"rules" yaml:"rules"`
+	// rules selected by the sources, targets or label selector
+	Selected int `json:"selected" yaml:"selected"`
+	// rules by source and target label
+	Labels map[string]int `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// readRuleSetListings reads the rulesets of the rule files in root of fsys,
+// the rule files of a dir belong to the ruleset of its ruleset.yaml or to a
+// ruleset named after the dir. Paths are reported under displayRoot.
+func readRuleSetListings(fsys fs.FS, root string, displayRoot string, origin string, selector engine.RuleSelector) ([]rulesetListing, error) {
+	byDir := map[string]*rulesetListing{}
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := path.Ext(p)
+		if d.IsDir() || (ext != ".yaml" && ext != ".yml") || isRuleTestFile(p) {
+			return nil
+		}
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		dir := path.Dir(p)
+		listing := byDir[dir]
+		if listing == nil {
+			rel := strings.TrimPrefix(strings.TrimPrefix(dir, root), "/")
+			listing = &rulesetListing{Origin: origin, Path: filepath.Join(displayRoot, filepath.FromSlash(rel)), Labels: map[string]int{}}
+			listing.Name = filepath.Base(listing.Path)
+			byDir[dir] = listing
+		}
+		if path.Base(p) == "ruleset.yaml" {
+			return nil
+		}
+		rules := []engine.RuleMeta{}
+		// files which are not rules are skipped by the rule parser too
+		if err := yaml.Unmarshal(content, &rules); err != nil {
+			return nil
+		}
+		for _, rule := range rules {
+			if rule.RuleID == "" {
+				continue
+			}
+			listing.Rules++
+			for _, label := range rule.Labels {
+				if isTechnologyLabel(label) {
+					listing.Labels[label]++
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	listings := []rulesetListing{}
+	for _, dir := range sortedMapKeys(byDir) {
+		listing := byDir[dir]
+		// rules inherit the labels of their ruleset
+		rulesetLabels := []string{}
+		if content, err := fs.ReadFile(fsys, path.Join(dir, "ruleset.yaml")); err == nil {
+			ruleset := struct {
+				Name   string   `yaml:"name"`
+				Labels []string `yaml:"labels"`
+			}{}
+			if err := yaml.Unmarshal(content, &ruleset); err == nil {
+				if ruleset.Name != "" {
+					listing.Name = ruleset.Name
+				}
+				rulesetLabels = ruleset.Labels
+			}
+		}
+		for _, label := range rulesetLabels {
+			if isTechnologyLabel(label) {
+				listing.Labels[label] += listing.Rules
+			}
+		}
+		if listing.Rules == 0 {
+			continue
+		}
+		selected, err := countSelectedRules(fsys, dir, rulesetLabels, selector)
+		if err != nil {
+			return nil, err
+		}
+		listing.Selected = selected
+		listings = append(listings, *listing)
+	}
+	return listings, nil
+}
+
+// countSelectedRules counts the rules of the rule files of dir the selector
+// selects, all of them without selector
+func countSelectedRules(fsys fs.FS, dir string, rulesetLabels []string, selector engine.RuleSelector) (int, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return 0, err
+	}
+	selected := 0
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := path.Ext(name)
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") || isRuleTestFile(name) || name == "ruleset.yaml" {
+			continue
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return 0, err
+		}
+		rules := []engine.RuleMeta{}
+		if err := yaml.Unmarshal(content, &rules); err != nil {
+			continue
+		}
+		for _, rule := range rules {
+			if rule.RuleID == "" {
+				continue
+			}
+			if selector == nil {
+				selected++
+				continue
+			}
+			rule.Labels = append(rule.Labels, rulesetLabels...)
+			if ok, err := selector.Matches(&rule); err == nil && ok {
+				selected++
+			}
+		}
+	}
+	return selected, nil
+}
+
+func isTechnologyLabel(label string) bool {
+	return strings.HasPrefix(label, outputv1.SourceTechnologyLabel+"=") || strings.HasPrefix(label, outputv1.TargetTechnologyLabel+"=")
+}
+
+// ruleSelector returns the selector of the rules of the label selector of
+// the analysis, nil when all rules run
+func (a *analyzeCommand) ruleSelector() (engine.RuleSelector, error) {
+	expr := a.getLabelSelector()
+	if expr == "" {
+		return nil, nil
+	}
+	selector, err := labels.NewLabelSelector[*engine.RuleMeta](expr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w invalid label selector %s", err, expr)
+	}
+	return selector, nil
+}
+
+// readRuleSetPaths reads the rulesets of rule files or dirs on the host
+func readRuleSetPaths(paths []string, origin string, selector engine.RuleSelector) ([]rulesetListing, error) {
+	listings := []rulesetListing{}
+	for _, p := range paths {
+		stat, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("%w failed to stat rules %s", err, p)
+		}
+		var fsys fs.FS = os.DirFS(p)
+		if !stat.IsDir() {
+			// a single rule file is a ruleset of its own
+			fsys = singleFileFS{FS: os.DirFS(filepath.Dir(p)), name: filepath.Base(p)}
+		}
+		found, err := readRuleSetListings(fsys, ".", p, origin, selector)
+		if err != nil {
+			return nil, err
+		}
+		listings = append(listings, found...)
+	}
+	return listings, nil
+}
+
+// singleFileFS is a dir holding a single file of another dir
+type singleFileFS struct {
+	fs.FS
+	name string
+}
+
+func (s singleFileFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(s.FS, name)
+	if err != nil {
+		return nil, err
+	}
+	kept := []fs.DirEntry{}
+	for _, entry := range entries {
+		if entry.Name() == s.name || entry.Name() == "ruleset.yaml" {
+			kept = append(kept, entry)
+		}
+	}
+	return kept, nil
+}
+
+// listDefaultRuleSets lists the default rulesets, of the runner image in
+// container mode unless they are replaced
+func (a *analyzeCommand) listDefaultRuleSets(ctx context.Context, selector engine.RuleSelector) ([]rulesetListing, error) {
+	if !a.enableDefaultRulesets {
+		return nil, nil
+	}
+	if a.runLocal || a.defaultRulesetsDir != "" || os.Getenv("RUN_MODE") == "container" {
+		dir := a.defaultRulesetsDir
+		switch {
+		case dir != "":
+		case a.runLocal:
+			dir = a.defaultRulesetsContainerless()
+		default:
+			dir = RulesetPath
+		}
+		return readRuleSetListings(os.DirFS(dir), ".", dir, rulesetOriginDefault, selector)
+	}
+	args := []string{"analyze", "--run-local=false", "--list-rulesets", "--format=json"}
+	for _, source := range a.sources {
+		args = append(args, fmt.Sprintf("--source=%s", source))
+	}
+	for _, target := range a.targets {
+		args = append(args, fmt.Sprintf("--target=%s", target))
+	}
+	if a.labelSelector != "" {
+		args = append(args, fmt.Sprintf("--label-selector=%s", a.labelSelector))
+	}
+	out := &bytes.Buffer{}
+	err := container.NewContainer().Run(
+		ctx,
+		container.WithImage(Settings.RunnerImage),
+		container.WithPlatform(a.imagePlatform(ctx, Settings.RunnerImage)),
+		container.WithLog(a.log.V(1)),
+		container.WithEnv("RUN_MODE", "container"),
+		container.WithEntrypointBin(fmt.Sprintf("/usr/local/bin/%s", Settings.RootCommandName)),
+		container.WithContainerToolBin(Settings.ContainerBinary),
+		container.WithEntrypointArgs(args...),
+		container.WithStdout(out),
+		container.WithCleanup(a.cleanup),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w failed to list the default rulesets of the runner image", err)
+	}
+	listings := []rulesetListing{}
+	if err := json.Unmarshal(out.Bytes(), &listings); err != nil {
+		return nil, fmt.Errorf("%w failed to parse the default rulesets of the runner image", err)
+	}
+	return listings, nil
+}
+
+// validateListRuleSets checks the selector of the listed rules and loads
+// the profiles whose rules are listed
+func (a *analyzeCommand) validateListRuleSets() error {
+	if a.labelSelector != "" && (len(a.sources) > 0 || len(a.targets) > 0) {
+		return i18n.Errorf("must not specify label-selector and sources or targets")
+	}
+	for _, rule := range a.rules {
+		if _, err := os.Stat(rule); err != nil {
+			return fmt.Errorf("%w failed to stat rules %s", err, rule)
+		}
+	}
+	if a.allProfiles || len(a.profileNames) > 0 {
+		profiles, err := loadProfiles(a.profilesDir, a.profileNames)
+		if err != nil {
+			return err
+		}
+		a.analysisProfiles = profiles
+	}
+	return nil
+}
+
+// ListRuleSets prints the rulesets the analysis would load, with their
+// rules and the rules selected by the sources, targets or label selector
+func (a *analyzeCommand) ListRuleSets(ctx context.Context, out io.Writer) error {
+	selector, err := a.ruleSelector()
+	if err != nil {
+		return err
+	}
+	listings, err := a.listDefaultRuleSets(ctx, selector)
+	if err != nil {
+		return err
+	}
+	rules, err := readRuleSetPaths(a.rules, rulesetOriginRules, selector)
+	if err != nil {
+		return err
+	}
+	listings = append(listings, rules...)
+	bundled := map[string]fs.FS{}
+	if a.platformChecks {
+		bundled[rulesetOriginPlatform] = platformRules
+	}
+	if a.serverConfig {
+		bundled[rulesetOriginServerConfig] = serverConfigRules
+	}
+	for _, origin := range sortedMapKeys(bundled) {
+		found, err := readRuleSetListings(bundled[origin], origin+"-rules", "(bundled)", origin, selector)
+		if err != nil {
+			return err
+		}
+		listings = append(listings, found...)
+	}
+	for _, profile := range a.analysisProfiles {
+		profileCmd := &analyzeCommand{sources: profile.Sources, targets: profile.Targets, labelSelector: profile.LabelSelector}
+		profileSelector, err := profileCmd.ruleSelector()
+		if err != nil {
+			return fmt.Errorf("%w in profile %s", err, profile.Name)
+		}
+		found, err := readRuleSetPaths(profile.Rules, rulesetOriginProfile, profileSelector)
+		if err != nil {
+			return fmt.Errorf("%w in profile %s", err, profile.Name)
+		}
+		for i := range found {
+			found[i].Profile = profile.Name
+		}
+		listings = append(listings, found...)
+	}
+	return writeRuleSetListings(out, a.listFormat, listings)
+}
+
+func writeRuleSetListings(out io.Writer, format string, listings []rulesetListing) error {
+	if format != "" && format != listFormatText {
+		return writeListOutput(out, format, listings)
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "RULESET\tORIGIN\tRULES\tSELECTED\tTARGETS\tPATH")
+	total, selected := 0, 0
+	for _, l := range listings {
+		origin := l.Origin
+		if l.Profile != "" {
+			origin = fmt.Sprintf("%s %s", origin, l.Profile)
+		}
+		targets := []string{}
+		for _, label := range sortedMapKeys(l.Labels) {
+			if target, ok := strings.CutPrefix(label, outputv1.TargetTechnologyLabel+"="); ok {
+				targets = append(targets, fmt.Sprintf("%s(%d)", target, l.Labels[label]))
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\n", l.Name, origin, l.Rules, l.Selected, strings.Join(targets, ","), l.Path)
+		total += l.Rules
+		selected += l.Selected
+	}
+	fmt.Fprintf(w, "%d rulesets\t\t%d\t%d\t\t\n", len(listings), total, selected)
+	return w.Flush()
+}
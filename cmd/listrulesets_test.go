@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_analyzeCommand_ListRuleSets(t *testing.T) {
+	defaults := t.TempDir()
+	writeTestFile(t, filepath.Join(defaults, "eap8", "ruleset.yaml"), "name: eap8/eap7\nlabels:\n- konveyor.io/source=eap7\n")
+	writeTestFile(t, filepath.Join(defaults, "eap8", "rules.yaml"), `- ruleID: eap8-00010
+  labels:
+  - konveyor.io/target=eap8
+  when:
+    builtin.file:
+      pattern: jboss-web.xml
+- ruleID: eap8-00020
+  labels:
+  - konveyor.io/target=eap8
+  - konveyor.io/target=quarkus
+  when:
+    builtin.file:
+      pattern: jboss-app.xml
+`)
+	writeTestFile(t, filepath.Join(defaults, "eap8", "rules.test.yaml"), "rulesPath: rules.yaml\n")
+	writeTestFile(t, filepath.Join(defaults, "discovery", "rules.yaml"), `- ruleID: discover-00010
+  labels:
+  - konveyor.io/include=always
+  - discovery
+  when:
+    builtin.file:
+      pattern: pom.xml
+`)
+	custom := t.TempDir()
+	writeTestFile(t, filepath.Join(custom, "custom.yaml"), `- ruleID: custom-00010
+  when:
+    builtin.file:
+      pattern: web.xml
+`)
+
+	a := &analyzeCommand{
+		enableDefaultRulesets: true,
+		defaultRulesetsDir:    defaults,
+		rules:                 []string{filepath.Join(custom, "custom.yaml")},
+		targets:               []string{"quarkus"},
+		listFormat:            listFormatJSON,
+	}
+	if err := a.validateListRuleSets(); err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := a.ListRuleSets(context.Background(), out); err != nil {
+		t.Fatal(err)
+	}
+	got := []rulesetListing{}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid json %s: %v", out.String(), err)
+	}
+	want := []rulesetListing{
+		{Name: "discovery", Origin: rulesetOriginDefault, Path: filepath.Join(defaults, "discovery"), Rules: 1, Selected: 1},
+		{Name: "eap8/eap7", Origin: rulesetOriginDefault, Path: filepath.Join(defaults, "eap8"), Rules: 2, Selected: 1,
+			Labels: map[string]int{"konveyor.io/source=eap7": 2, "konveyor.io/target=eap8": 2, "konveyor.io/target=quarkus": 1}},
+		{Name: "custom.yaml", Origin: rulesetOriginRules, Path: filepath.Join(custom, "custom.yaml"), Rules: 1, Selected: 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListRuleSets() = %+v, want %+v", got, want)
+	}
+
+	a.listFormat = listFormatText
+	out.Reset()
+	if err := a.ListRuleSets(context.Background(), out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out.Bytes(), []byte("3 rulesets")) || !bytes.Contains(out.Bytes(), []byte("eap8(2),quarkus(1)")) {
+		t.Errorf("ListRuleSets() text = %s", out.String())
+	}
+}
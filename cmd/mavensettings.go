@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// id of the generated mirror when no credentials id is given
+const defaultMavenMirrorID = "kantra-mirror"
+
+type mavenMirror struct {
+	ID       string `xml:"id"`
+	MirrorOf string `xml:"mirrorOf"`
+	URL      string `xml:"url"`
+}
+
+type mavenServer struct {
+	ID       string `xml:"id"`
+	Username string `xml:"username,omitempty"`
+	Password string `xml:"password,omitempty"`
+}
+
+type mavenServers struct {
+	Servers []mavenServer `xml:"server"`
+}
+
+type mavenSettings struct {
+	XMLName xml.Name      `xml:"settings"`
+	Xmlns   string        `xml:"xmlns,attr"`
+	Servers *mavenServers `xml:"servers,omitempty"`
+	Mirrors []mavenMirror `xml:"mirrors>mirror"`
+}
+
+// generateMavenSettings returns maven settings routing all repositories
+// through a mirror, authenticated with the credentials of the environment
+func generateMavenSettings(mirror, mirrorOf, credentialsID, username, password string) ([]byte, error) {
+	settings := mavenSettings{Xmlns: "http://maven.apache.org/SETTINGS/1.0.0"}
+	id := defaultMavenMirrorID
+	if credentialsID != "" {
+		if username == "" || password == "" {
+			return nil, fmt.Errorf("maven-credentials-id requires KANTRA_MAVEN_USERNAME and KANTRA_MAVEN_PASSWORD to be set")
+		}
+		id = credentialsID
+		settings.Servers = &mavenServers{Servers: []mavenServer{{ID: id, Username: username, Password: password}}}
+	}
+	settings.Mirrors = append(settings.Mirrors, mavenMirror{ID: id, MirrorOf: mirrorOf, URL: mirror})
+	data, err := xml.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// writeMavenSettings generates the maven settings file of --maven-mirror
+func (a *analyzeCommand) writeMavenSettings() error {
+	mirror := a.mavenMirror
+	if mirror == "" {
+		mirror = Settings.MavenMirror
+	}
+	if mirror == "" {
+		if a.mavenCredentialsID != "" {
+			return fmt.Errorf("maven-credentials-id requires maven-mirror")
+		}
+		return nil
+	}
+	if a.mavenSettingsFile != "" {
+		return fmt.Errorf("must not specify both maven-settings and maven-mirror")
+	}
+	credentials, err := settingSecrets(Settings.MavenUsername, Settings.MavenPassword)
+	if err != nil {
+		return err
+	}
+	settings, err := generateMavenSettings(mirror, a.mavenMirrorOf, a.mavenCredentialsID,
+		credentials[0], credentials[1])
+	if err != nil {
+		return err
+	}
+	// kept out of the output dir as it may hold credentials
+	dir, err := os.MkdirTemp("", "maven-settings-")
+	if err != nil {
+		return err
+	}
+	a.mavenSettingsDir = dir
+	a.mavenSettingsFile = filepath.Join(dir, "settings.xml")
+	a.log.V(1).Info("generated maven settings", "mirror", mirror, "file", a.mavenSettingsFile)
+	return os.WriteFile(a.mavenSettingsFile, settings, 0600)
+}
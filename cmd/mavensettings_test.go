@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_generateMavenSettings(t *testing.T) {
+	settings, err := generateMavenSettings("https://nexus.example.com/repository/maven/", "*", "nexus", "dev", "s3cr&t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<id>nexus</id>",
+		"<mirrorOf>*</mirrorOf>",
+		"<url>https://nexus.example.com/repository/maven/</url>",
+		"<password>s3cr&amp;t</password>",
+	} {
+		if !strings.Contains(string(settings), want) {
+			t.Errorf("generateMavenSettings() = %s, want it to contain %s", settings, want)
+		}
+	}
+	settings, err = generateMavenSettings("https://nexus.example.com/", "central", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(settings), "<servers>") || !strings.Contains(string(settings), "<id>"+defaultMavenMirrorID+"</id>") {
+		t.Errorf("generateMavenSettings() without credentials = %s", settings)
+	}
+	if _, err := generateMavenSettings("https://nexus.example.com/", "*", "nexus", "", ""); err == nil {
+		t.Errorf("generateMavenSettings() must fail without credentials in the environment")
+	}
+}
+
+func Test_analyzeCommand_writeMavenSettings(t *testing.T) {
+	a := &analyzeCommand{log: logr.Discard(), mavenMirror: "https://nexus.example.com/", mavenMirrorOf: "*"}
+	if err := a.writeMavenSettings(); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(a.mavenSettingsDir)
+	if _, err := os.Stat(a.mavenSettingsFile); err != nil {
+		t.Errorf("writeMavenSettings() did not write %s: %v", a.mavenSettingsFile, err)
+	}
+	a = &analyzeCommand{log: logr.Discard(), mavenMirror: "https://nexus.example.com/", mavenSettingsFile: "settings.xml"}
+	if err := a.writeMavenSettings(); err == nil {
+		t.Errorf("writeMavenSettings() must fail with maven-settings")
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// prefix of the static report data file, followed by the applications as JSON
+const staticReportAppsPrefix = `window["apps"] = `
+
+// loadAppMetadata reads business metadata of the analyzed application,
+// e.g. criticality, owner and lifecycle, from a flat YAML map
+func loadAppMetadata(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	metadata := map[string]string{}
+	err = yaml.Unmarshal(data, &metadata)
+	if err != nil {
+		return nil, fmt.Errorf("%w metadata must be a map of string values", err)
+	}
+	return metadata, nil
+}
+
+// annotateStaticReport adds the application metadata to the data of the
+// static report. The report UI does not read it, it is only kept in the data
+// for tools reading output.js.
+func (a *analyzeCommand) annotateStaticReport() error {
+	if len(a.appMetadata) == 0 || a.skipStaticReport {
+		return nil
+	}
+	outputJSPath := filepath.Join(a.output, "static-report", "output.js")
+	content, err := os.ReadFile(outputJSPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	content = bytes.TrimSpace(content)
+	if !bytes.HasPrefix(content, []byte(staticReportAppsPrefix)) {
+		return fmt.Errorf("unexpected static report data in %s", outputJSPath)
+	}
+	apps := []map[string]interface{}{}
+	err = json.Unmarshal(bytes.TrimPrefix(content, []byte(staticReportAppsPrefix)), &apps)
+	if err != nil {
+		return err
+	}
+	for _, app := range apps {
+		// bulk reports hold all applications analyzed into the output dir
+		if len(apps) > 1 && app["name"] != a.inputShortName() {
+			continue
+		}
+		app["metadata"] = a.appMetadata
+	}
+	output, err := json.Marshal(apps)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outputJSPath, []byte(fmt.Sprintf("\n%s%s\n", staticReportAppsPrefix, output)), 0644)
+}
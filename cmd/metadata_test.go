@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_analyzeCommand_annotateStaticReport(t *testing.T) {
+	output := t.TempDir()
+	metadataPath := filepath.Join(output, "metadata.yaml")
+	err := os.WriteFile(metadataPath, []byte("criticality: high\nowner: team-payments\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata, err := loadAppMetadata(metadataPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(output, "static-report"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	outputJSPath := filepath.Join(output, "static-report", "output.js")
+	err = os.WriteFile(outputJSPath, []byte("\n"+staticReportAppsPrefix+`[{"id":"0000","name":"app","rulesets":[]}]`+"\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := &analyzeCommand{input: "/tmp/app", output: output, appMetadata: metadata}
+	if err := a.annotateStaticReport(); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(outputJSPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	apps := []struct {
+		Name     string            `json:"name"`
+		Metadata map[string]string `json:"metadata"`
+	}{}
+	data := strings.TrimPrefix(strings.TrimSpace(string(content)), staticReportAppsPrefix)
+	if err := json.Unmarshal([]byte(data), &apps); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"criticality": "high", "owner": "team-payments"}
+	if len(apps) != 1 || !reflect.DeepEqual(apps[0].Metadata, want) {
+		t.Errorf("annotateStaticReport() apps = %v, want metadata %v", apps, want)
+	}
+}
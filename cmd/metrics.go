@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// upper bounds in seconds of the buckets of the analysis duration histogram
+var durationBuckets = []float64{60, 300, 600, 1800, 3600}
+
+// metric families of the metrics file, in the order they are written
+var metricFamilies = []struct {
+	name string
+	kind string
+	help string
+}{
+	{"kantra_analysis_runs_total", "counter", "Analyses run, by result."},
+	{"kantra_analysis_duration_seconds", "histogram", "Duration of the analyses."},
+	{"kantra_provider_restarts_total", "counter", "Provider containers started again after failing to start."},
+	{"kantra_analysis_rules", "gauge", "Rules matched by the last analysis, by application."},
+	{"kantra_analysis_incidents", "gauge", "Incidents of the last analysis, by application."},
+	{"kantra_analysis_last_run_timestamp_seconds", "gauge", "Time the last analysis ended, by application."},
+	{"kantra_memory_bytes", "gauge", "Memory obtained from the system by kantra in the last analysis, including the analyzer in containerless mode."},
+	{"kantra_code_lines", "gauge", "Lines of code of the application analyzed last, by application and language."},
+	{"kantra_code_complexity", "gauge", "Decision points of the code of the application analyzed last, by application and language."},
+}
+
+// analysisMetrics are series of metrics in the prometheus text format, by
+// their name and labels
+type analysisMetrics map[string]float64
+
+// readMetrics reads the series of a metrics file, so that counters and
+// histograms add up the analyses writing to the file
+func readMetrics(path string) (analysisMetrics, error) {
+	metrics := analysisMetrics{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return metrics, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.LastIndex(line, " ")
+		if i < 0 {
+			return nil, fmt.Errorf("unexpected metric %s in %s", line, path)
+		}
+		value, err := strconv.ParseFloat(line[i+1:], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w unexpected metric %s in %s", err, line, path)
+		}
+		metrics[line[:i]] = value
+	}
+	return metrics, scanner.Err()
+}
+
+func formatBucket(le float64) string {
+	return fmt.Sprintf(`kantra_analysis_duration_seconds_bucket{le="%s"}`, strconv.FormatFloat(le, 'f', -1, 64))
+}
+
+// observe adds an analysis to the metrics
+func (m analysisMetrics) observe(app string, duration time.Duration, runErr error, restarts int, summary *analysisSummary, memory uint64, ended time.Time) {
+	result := bulkSucceeded
+	if runErr != nil {
+		result = bulkFailed
+	}
+	m[fmt.Sprintf(`kantra_analysis_runs_total{result="%s"}`, result)]++
+	seconds := duration.Seconds()
+	for _, le := range durationBuckets {
+		key := formatBucket(le)
+		m[key] += 0
+		if seconds <= le {
+			m[key]++
+		}
+	}
+	m[`kantra_analysis_duration_seconds_bucket{le="+Inf"}`]++
+	m["kantra_analysis_duration_seconds_sum"] += seconds
+	m["kantra_analysis_duration_seconds_count"]++
+	m["kantra_provider_restarts_total"] += float64(restarts)
+	appLabel := fmt.Sprintf(`{app=%s}`, strconv.Quote(app))
+	if summary != nil {
+		m["kantra_analysis_rules"+appLabel] = float64(summary.Rules)
+		m["kantra_analysis_incidents"+appLabel] = float64(summary.Incidents)
+	}
+	if summary != nil && summary.Code != nil {
+		// languages removed from the application are not reported anymore
+		appPrefix := fmt.Sprintf(`{app=%s,`, strconv.Quote(app))
+		for key := range m {
+			if strings.HasPrefix(key, "kantra_code_lines"+appPrefix) || strings.HasPrefix(key, "kantra_code_complexity"+appPrefix) {
+				delete(m, key)
+			}
+		}
+		for _, l := range summary.Code.Languages {
+			languageLabel := fmt.Sprintf(`%slanguage=%s}`, appPrefix, strconv.Quote(l.Language))
+			m["kantra_code_lines"+languageLabel] = float64(l.Code)
+			m["kantra_code_complexity"+languageLabel] = float64(l.Complexity)
+		}
+	}
+	m["kantra_analysis_last_run_timestamp_seconds"+appLabel] = float64(ended.Unix())
+	m["kantra_memory_bytes"] = float64(memory)
+}
+
+// write writes the metrics in the prometheus text format
+func (m analysisMetrics) write(path string) error {
+	b := &bytes.Buffer{}
+	for _, family := range metricFamilies {
+		keys := []string{}
+		for key := range m {
+			name, _, _ := strings.Cut(key, "{")
+			if name == family.name && family.kind != "histogram" {
+				keys = append(keys, key)
+			}
+		}
+		if family.kind == "histogram" {
+			for _, le := range durationBuckets {
+				keys = append(keys, formatBucket(le))
+			}
+			keys = append(keys, family.name+`_bucket{le="+Inf"}`, family.name+"_sum", family.name+"_count")
+		} else {
+			sort.Strings(keys)
+		}
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", family.name, family.help, family.name, family.kind)
+		for _, key := range keys {
+			fmt.Fprintf(b, "%s %s\n", key, strconv.FormatFloat(m[key], 'f', -1, 64))
+		}
+	}
+	// replaced at once, as collectors may read it at any time
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b.Bytes(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// time to wait for another analysis updating the metrics file
+const metricsLockTimeout = 30 * time.Second
+
+// lockMetrics creates the lock file of a metrics file, so that analyses
+// finishing at the same time do not lose each others updates. It waits for
+// the lock held by another analysis, locks of processes that are not running
+// anymore on this host are removed. It returns the func removing the lock.
+func lockMetrics(path string) (func(), error) {
+	lockPath := path + ".lock"
+	host, _ := os.Hostname()
+	data, err := json.Marshal(outputLock{PID: os.Getpid(), Host: host, Started: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(metricsLockTimeout)
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, err = file.Write(data)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(lockPath)
+				return nil, err
+			}
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("%w failed to lock metrics file %s", err, path)
+		}
+		lock := outputLock{}
+		if data, err := os.ReadFile(lockPath); err == nil && json.Unmarshal(data, &lock) == nil &&
+			lock.Host == host && !processRunning(lock.PID) {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("metrics file %s is locked by %s, remove it when no analysis is running", path, lockPath)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// writeMetrics adds the analysis to the metrics file, e.g. read by the
+// textfile collector of the prometheus node exporter on hosts running
+// analyses for a team
+func (a *analyzeCommand) writeMetrics(started time.Time, runErr error) error {
+	unlock, err := lockMetrics(a.metricsFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	metrics, err := readMetrics(a.metricsFile)
+	if err != nil {
+		return err
+	}
+	var summary *analysisSummary
+	summaryPath := filepath.Join(a.output, "summary.json")
+	if a.bulk {
+		summaryPath = a.bulkResultPath("summary.json")
+	}
+	if data, err := os.ReadFile(summaryPath); err == nil && runErr == nil {
+		summary = &analysisSummary{}
+		if err := json.Unmarshal(data, summary); err != nil {
+			return fmt.Errorf("%w failed to parse %s", err, summaryPath)
+		}
+	}
+	mem := runtime.MemStats{}
+	runtime.ReadMemStats(&mem)
+	metrics.observe(a.inputShortName(), time.Since(started), runErr, a.providerRestarts, summary, mem.Sys, time.Now())
+	return metrics.write(a.metricsFile)
+}
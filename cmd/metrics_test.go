@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_analysisMetrics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kantra.prom")
+	ended := time.Unix(1700000000, 0)
+	for _, run := range []struct {
+		app      string
+		duration time.Duration
+		err      error
+		restarts int
+		summary  *analysisSummary
+	}{
+		{app: "coolstore", duration: 4 * time.Minute, restarts: 1, summary: &analysisSummary{Rules: 7, Incidents: 42}},
+		{app: "coolstore", duration: 20 * time.Minute, err: errors.New("provider failed")},
+		{app: "petclinic", duration: 2 * time.Hour, summary: &analysisSummary{Rules: 3, Incidents: 5}},
+	} {
+		metrics, err := readMetrics(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		metrics.observe(run.app, run.duration, run.err, run.restarts, run.summary, 1024, ended)
+		if err := metrics.write(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP kantra_analysis_runs_total Analyses run, by result.
+# TYPE kantra_analysis_runs_total counter
+kantra_analysis_runs_total{result="failed"} 1
+kantra_analysis_runs_total{result="succeeded"} 2
+# HELP kantra_analysis_duration_seconds Duration of the analyses.
+# TYPE kantra_analysis_duration_seconds histogram
+kantra_analysis_duration_seconds_bucket{le="60"} 0
+kantra_analysis_duration_seconds_bucket{le="300"} 1
+kantra_analysis_duration_seconds_bucket{le="600"} 1
+kantra_analysis_duration_seconds_bucket{le="1800"} 2
+kantra_analysis_duration_seconds_bucket{le="3600"} 2
+kantra_analysis_duration_seconds_bucket{le="+Inf"} 3
+kantra_analysis_duration_seconds_sum 8640
+kantra_analysis_duration_seconds_count 3
+# HELP kantra_provider_restarts_total Provider containers started again after failing to start.
+# TYPE kantra_provider_restarts_total counter
+kantra_provider_restarts_total 1
+# HELP kantra_analysis_rules Rules matched by the last analysis, by application.
+# TYPE kantra_analysis_rules gauge
+kantra_analysis_rules{app="coolstore"} 7
+kantra_analysis_rules{app="petclinic"} 3
+# HELP kantra_analysis_incidents Incidents of the last analysis, by application.
+# TYPE kantra_analysis_incidents gauge
+kantra_analysis_incidents{app="coolstore"} 42
+kantra_analysis_incidents{app="petclinic"} 5
+# HELP kantra_analysis_last_run_timestamp_seconds Time the last analysis ended, by application.
+# TYPE kantra_analysis_last_run_timestamp_seconds gauge
+kantra_analysis_last_run_timestamp_seconds{app="coolstore"} 1700000000
+kantra_analysis_last_run_timestamp_seconds{app="petclinic"} 1700000000
+`
+	if !strings.HasPrefix(string(data), want) {
+		t.Errorf("metrics = %s, want %s", data, want)
+	}
+}
+
+func Test_analysisMetrics_code(t *testing.T) {
+	metrics := analysisMetrics{}
+	code := &codeStats{Languages: []languageStats{{Language: "Java", Code: 1200, Complexity: 150}, {Language: "JavaScript", Code: 300, Complexity: 40}}}
+	metrics.observe("coolstore", time.Minute, nil, 0, &analysisSummary{Code: code}, 1024, time.Unix(1700000000, 0))
+	code = &codeStats{Languages: []languageStats{{Language: "Java", Code: 1000, Complexity: 120}}}
+	metrics.observe("coolstore", time.Minute, nil, 0, &analysisSummary{Code: code}, 1024, time.Unix(1700000000, 0))
+	path := filepath.Join(t.TempDir(), "kantra.prom")
+	if err := metrics.write(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP kantra_code_lines Lines of code of the application analyzed last, by application and language.
+# TYPE kantra_code_lines gauge
+kantra_code_lines{app="coolstore",language="Java"} 1000
+# HELP kantra_code_complexity Decision points of the code of the application analyzed last, by application and language.
+# TYPE kantra_code_complexity gauge
+kantra_code_complexity{app="coolstore",language="Java"} 120
+`
+	if !strings.HasSuffix(string(data), want) {
+		t.Errorf("metrics = %s, want suffix %s", data, want)
+	}
+}
+
+func Test_analyzeCommand_writeMetrics_concurrent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "kantra.prom")
+	errs := make(chan error)
+	for i := 0; i < 10; i++ {
+		a := &analyzeCommand{input: filepath.Join(dir, fmt.Sprintf("app%d", i)), output: dir, metricsFile: path}
+		go func() { errs <- a.writeMetrics(time.Now(), nil) }()
+	}
+	for i := 0; i < 10; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	metrics, err := readMetrics(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, ok := metrics[fmt.Sprintf(`kantra_analysis_last_run_timestamp_seconds{app="app%d"}`, i)]; !ok {
+			t.Errorf("metrics of app%d lost by a concurrent update", i)
+		}
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock of the metrics file not removed: %v", err)
+	}
+}
@@ -1,8 +1,9 @@
 package cmd
 
 import (
-	"fmt"
 	"github.com/konveyor/analyzer-lsp/provider"
+
+	kantraprovider "github.com/konveyor-ecosystem/kantra/pkg/provider"
 )
 
 type NodeJsProvider struct {
@@ -10,23 +11,12 @@ type NodeJsProvider struct {
 }
 
 func (p *NodeJsProvider) GetConfigVolume(a *analyzeCommand, tmpDir string) (provider.Config, error) {
-	p.config = provider.Config{
-		Name:    nodeJSProvider,
-		Address: fmt.Sprintf("0.0.0.0:%v", a.providersMap[nodeJSProvider].port),
-		InitConfig: []provider.InitConfig{
-			{
-				AnalysisMode: provider.SourceOnlyAnalysisMode,
-				ProviderSpecificConfig: map[string]interface{}{
-					"lspServerName":                 "nodejs",
-					"workspaceFolders":              []string{fmt.Sprintf("file://%s", SourceMountPath)},
-					provider.LspServerPathConfigKey: "/usr/local/bin/typescript-language-server",
-				},
-			},
-		},
-	}
 	_, dependencyFolders := a.getDepsFolders()
-	if len(dependencyFolders) != 0 {
-		p.config.InitConfig[0].ProviderSpecificConfig["dependencyFolders"] = dependencyFolders
-	}
+	p.config = kantraprovider.NodeJSConfig(kantraprovider.Options{
+		Mode:              kantraprovider.ContainerMode,
+		Location:          a.sourceMountPath(),
+		Port:              a.providersMap[nodeJSProvider].port,
+		DependencyFolders: dependencyFolders,
+	})
 	return p.config, nil
 }
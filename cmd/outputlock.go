@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// file in the output dir held by the analysis writing to it
+const outputLockFile = ".kantra.lock"
+
+// outputLock identifies the analysis writing to an output dir
+type outputLock struct {
+	RunID   string    `json:"runID"`
+	PID     int       `json:"pid"`
+	Host    string    `json:"host"`
+	Started time.Time `json:"started"`
+}
+
+func (l outputLock) String() string {
+	return fmt.Sprintf("run %s (pid %d on %s since %s)", l.RunID, l.PID, l.Host, l.Started.Format(time.RFC3339))
+}
+
+func newRunID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// processRunning reports whether a process of this host is running
+func processRunning(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// a process of another user fails with a permission error
+	return !errors.Is(p.Signal(syscall.Signal(0)), os.ErrProcessDone)
+}
+
+func readOutputLock(output string) (*outputLock, error) {
+	data, err := os.ReadFile(filepath.Join(output, outputLockFile))
+	if err != nil {
+		return nil, err
+	}
+	lock := &outputLock{}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("%w failed to parse %s", err, filepath.Join(output, outputLockFile))
+	}
+	return lock, nil
+}
+
+// checkOutputLock fails when another analysis writes to the output dir.
+// Locks of analyses that are not running anymore on this host are removed,
+// --force-unlock removes any lock, e.g. of a run on another host sharing
+// the output dir that was killed.
+func (a *analyzeCommand) checkOutputLock() error {
+	// the analyses of the bulk inputs run under the lock of the bulk analysis
+	if a.bulkSequence {
+		return nil
+	}
+	lock, err := readOutputLock(a.output)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil && !a.forceUnlock {
+		return err
+	}
+	host, _ := os.Hostname()
+	switch {
+	case a.forceUnlock:
+		a.log.Info("removing lock of output dir", "output", a.output)
+	case lock.Host == host && !processRunning(lock.PID):
+		a.log.Info("removing lock of an analysis not running anymore", "output", a.output, "run", lock.RunID, "pid", lock.PID)
+	default:
+		return fmt.Errorf("output dir %s is used by the analysis %s, wait for it to finish or use --force-unlock when it is not running anymore", a.output, lock)
+	}
+	return os.Remove(filepath.Join(a.output, outputLockFile))
+}
+
+// lockOutput creates the lock of the output dir of the analysis
+func (a *analyzeCommand) lockOutput() error {
+	if a.bulkSequence {
+		return nil
+	}
+	host, _ := os.Hostname()
+	lock := outputLock{RunID: newRunID(), PID: os.Getpid(), Host: host, Started: time.Now()}
+	data, err := json.Marshal(lock)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(a.output, os.ModePerm); err != nil {
+		return err
+	}
+	path := filepath.Join(a.output, outputLockFile)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if errors.Is(err, os.ErrExist) {
+		if other, err := readOutputLock(a.output); err == nil {
+			return fmt.Errorf("output dir %s is used by the analysis %s", a.output, other)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%w failed to lock output dir %s", err, a.output)
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		return err
+	}
+	a.runID = lock.RunID
+	a.log.Info("starting analysis", "run", a.runID)
+	return nil
+}
+
+// unlockOutput removes the lock of the output dir when the analysis holds it
+func (a *analyzeCommand) unlockOutput() error {
+	if a.runID == "" {
+		return nil
+	}
+	lock, err := readOutputLock(a.output)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if lock.RunID != a.runID {
+		return nil
+	}
+	return os.Remove(filepath.Join(a.output, outputLockFile))
+}
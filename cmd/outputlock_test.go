@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func writeTestLock(t *testing.T, output string, lock outputLock) {
+	data, err := json.Marshal(lock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(output, outputLockFile), string(data))
+}
+
+func Test_outputLock(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "output")
+	a := &analyzeCommand{output: output, log: logr.Discard()}
+	if err := a.checkOutputLock(); err != nil {
+		t.Fatalf("checkOutputLock() of a new output dir: %v", err)
+	}
+	if err := a.lockOutput(); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := readOutputLock(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock.RunID != a.runID || lock.PID != os.Getpid() {
+		t.Errorf("lock = %+v, want run %s of pid %d", lock, a.runID, os.Getpid())
+	}
+	other := &analyzeCommand{output: output, log: logr.Discard()}
+	if err := other.checkOutputLock(); err == nil || !strings.Contains(err.Error(), a.runID) {
+		t.Errorf("checkOutputLock() of a locked output dir = %v", err)
+	}
+	if err := other.lockOutput(); err == nil {
+		t.Error("lockOutput() locked an output dir locked by another analysis")
+	}
+	// bulk inputs are analyzed under the lock of the bulk analysis
+	sequence := &analyzeCommand{output: output, bulkSequence: true, log: logr.Discard()}
+	if err := sequence.checkOutputLock(); err != nil {
+		t.Errorf("checkOutputLock() of a bulk input: %v", err)
+	}
+	if err := other.unlockOutput(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(output, outputLockFile)); err != nil {
+		t.Error("unlockOutput() removed the lock of another analysis")
+	}
+	if err := a.unlockOutput(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(output, outputLockFile)); !os.IsNotExist(err) {
+		t.Errorf("unlockOutput() kept the lock: %v", err)
+	}
+}
+
+func Test_checkOutputLock_stale(t *testing.T) {
+	output := t.TempDir()
+	host, _ := os.Hostname()
+	a := &analyzeCommand{output: output, log: logr.Discard()}
+	// pids are far below the maximum of linux
+	writeTestLock(t, output, outputLock{RunID: "a1b2c3", PID: 1 << 30, Host: host, Started: time.Now()})
+	if err := a.checkOutputLock(); err != nil {
+		t.Errorf("checkOutputLock() of a stale lock: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(output, outputLockFile)); !os.IsNotExist(err) {
+		t.Errorf("checkOutputLock() kept the stale lock: %v", err)
+	}
+	writeTestLock(t, output, outputLock{RunID: "a1b2c3", PID: 1 << 30, Host: "other-host", Started: time.Now()})
+	if err := a.checkOutputLock(); err == nil {
+		t.Error("checkOutputLock() removed the lock of another host")
+	}
+	a.forceUnlock = true
+	if err := a.checkOutputLock(); err != nil {
+		t.Errorf("checkOutputLock() with force unlock: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(output, outputLockFile)); !os.IsNotExist(err) {
+		t.Errorf("checkOutputLock() with force unlock kept the lock: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// prefixedOutputs are the results renamed with --output-prefix once the
+// analysis is done, compressed outputs keep their gzip extension
+var prefixedOutputs = []string{
+	"output.yaml", "output.json", "dependencies.yaml", "dependencies.json", depsTreeFile,
+	junitOutputFile, sarifOutputFile, csvOutputFile, incidentStoreFile, "summary.json", "licenses.json", "sbom.cdx.json",
+	serverConfigFile, skippedReportFile, debugSettingsFile, runInfoFile, outputManifestFile, outputManifestBundle, "static-report", reportInputsDir,
+	"analysis.log", "dependencies.log", "provider.log", "shim.log", "static-report.log",
+}
+
+// validateOutputPrefix checks the prefix of --output-prefix can name files
+// of the output dir
+func (a *analyzeCommand) validateOutputPrefix() error {
+	if a.outputPrefix == "" {
+		return nil
+	}
+	if strings.ContainsAny(a.outputPrefix, `/\`) || strings.TrimLeft(a.outputPrefix, ".") == "" {
+		return fmt.Errorf("output-prefix %s must be a file name prefix, not a path", a.outputPrefix)
+	}
+	if a.bulk || len(a.bulkInputs) > 0 || a.kube || a.allProfiles || len(a.profileNames) > 0 {
+		return fmt.Errorf("output-prefix is not supported with bulk, kube and profile analyses, they have their own output dirs")
+	}
+	return nil
+}
+
+// existingOutputs returns the outputs of the output dir named with the
+// prefix, with or without the gzip extension
+func existingOutputs(output string, prefix string) []string {
+	existing := []string{}
+	for _, name := range prefixedOutputs {
+		for _, file := range []string{name, name + gzipExt} {
+			if _, err := os.Stat(filepath.Join(output, prefix+file)); err == nil {
+				existing = append(existing, prefix+file)
+			}
+		}
+	}
+	return existing
+}
+
+// checkPrefixedOutputs allows analyses with different prefixes to share the
+// output dir. Outputs of the same prefix, or not renamed yet, are replaced
+// with --overwrite only, the other files of the dir are kept.
+func (a *analyzeCommand) checkPrefixedOutputs() error {
+	existing := append(existingOutputs(a.output, a.outputPrefix), existingOutputs(a.output, "")...)
+	if len(existing) == 0 {
+		return nil
+	}
+	if !a.overwrite {
+		return fmt.Errorf("output dir %v already contains %s and --overwrite not set", a.output, strings.Join(existing, ", "))
+	}
+	for _, file := range existing {
+		if err := os.RemoveAll(filepath.Join(a.output, file)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prefixOutputs renames the outputs of the analysis with --output-prefix
+func (a *analyzeCommand) prefixOutputs() error {
+	if a.outputPrefix == "" {
+		return nil
+	}
+	for _, file := range existingOutputs(a.output, "") {
+		err := os.Rename(filepath.Join(a.output, file), filepath.Join(a.output, a.outputPrefix+file))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	a.log.Info("prefixed analysis outputs", "output", a.output, "prefix", a.outputPrefix)
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_analyzeCommand_prefixOutputs(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "payments-output.yaml"), "[]")
+	writeTestFile(t, filepath.Join(dir, "notes.txt"), "kept")
+
+	a := &analyzeCommand{output: dir, outputPrefix: "orders-", log: logr.Discard()}
+	if err := a.validateOutputPrefix(); err != nil {
+		t.Fatal(err)
+	}
+	// outputs of other prefixes do not block the analysis
+	if err := a.CheckOverwriteOutput(); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(dir, "output.yaml.gz"), "")
+	writeTestFile(t, filepath.Join(dir, "analysis.log"), "")
+	writeTestFile(t, filepath.Join(dir, "static-report", "index.html"), "")
+	if err := a.prefixOutputs(); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"orders-output.yaml.gz", "orders-analysis.log", filepath.Join("orders-static-report", "index.html"), "payments-output.yaml", "notes.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("prefixOutputs() did not keep %s: %v", file, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "analysis.log")); err == nil {
+		t.Errorf("prefixOutputs() left analysis.log")
+	}
+
+	// outputs of the same prefix need --overwrite
+	if err := a.CheckOverwriteOutput(); err == nil {
+		t.Errorf("CheckOverwriteOutput() expected error for existing outputs of the prefix")
+	}
+	a.overwrite = true
+	if err := a.CheckOverwriteOutput(); err != nil {
+		t.Fatal(err)
+	}
+	if existing := existingOutputs(dir, "orders-"); len(existing) != 0 {
+		t.Errorf("CheckOverwriteOutput() left %v", existing)
+	}
+	for _, file := range []string{"payments-output.yaml", "notes.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("CheckOverwriteOutput() removed %s", file)
+		}
+	}
+
+	for _, prefix := range []string{"../orders-", ".."} {
+		a := &analyzeCommand{outputPrefix: prefix}
+		if err := a.validateOutputPrefix(); err == nil {
+			t.Errorf("validateOutputPrefix() expected error for %s", prefix)
+		}
+	}
+	if err := (&analyzeCommand{outputPrefix: "orders-", bulk: true}).validateOutputPrefix(); err == nil {
+		t.Errorf("validateOutputPrefix() expected error with bulk")
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"embed"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// platformLabel is set on rules checking deployment artifacts
+const platformLabel = "konveyor.io/platform"
+
+//go:embed platform-rules/*.yaml
+var platformRules embed.FS
+
+// hasUserRules returns true when rules are given with --rules, besides the
+// rules bundled with kantra
+func (a *analyzeCommand) hasUserRules() bool {
+	return len(a.rules) > len(a.bundledRulesDirs)
+}
+
+// addPlatformRules writes the platform rules bundled with kantra into a
+// temp dir and adds it to the rules of the analysis
+func (a *analyzeCommand) addPlatformRules() error {
+	tempDir, err := os.MkdirTemp("", "platform-rules-")
+	if err != nil {
+		return err
+	}
+	a.bundledRulesDirs = append(a.bundledRulesDirs, tempDir)
+	entries, err := platformRules.ReadDir("platform-rules")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		content, err := platformRules.ReadFile(path.Join("platform-rules", entry.Name()))
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(filepath.Join(tempDir, entry.Name()), content, 0644)
+		if err != nil {
+			return err
+		}
+	}
+	a.log.V(1).Info("adding platform rules", "path", tempDir)
+	a.rules = append(a.rules, tempDir)
+	return nil
+}
+
+// platformIssue is a violated platform rule, listed in the platform section
+// of summary.json apart from the migration issues
+type platformIssue struct {
+	// value of the platform label, e.g. openshift
+	Platform    string `yaml:"platform" json:"platform"`
+	RuleID      string `yaml:"ruleID" json:"ruleID"`
+	Description string `yaml:"description" json:"description"`
+	Incidents   int    `yaml:"incidents" json:"incidents"`
+}
+
+// summarizePlatform returns the violations of rules with the platform label
+// ordered by platform and rule
+func summarizePlatform(rulesets []outputv1.RuleSet) []platformIssue {
+	issues := []platformIssue{}
+	for _, rs := range rulesets {
+		for ruleID, violation := range rs.Violations {
+			platform := ruleLabel(violation.Labels, platformLabel)
+			if platform == "" {
+				continue
+			}
+			issues = append(issues, platformIssue{
+				Platform:    platform,
+				RuleID:      ruleID,
+				Description: violation.Description,
+				Incidents:   len(violation.Incidents),
+			})
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Platform != issues[j].Platform {
+			return issues[i].Platform < issues[j].Platform
+		}
+		return issues[i].RuleID < issues[j].RuleID
+	})
+	return issues
+}
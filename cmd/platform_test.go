@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"gopkg.in/yaml.v2"
+)
+
+func Test_analyzeCommand_addPlatformRules(t *testing.T) {
+	a := &analyzeCommand{log: logr.Discard(), platformChecks: true, targets: []string{"openshift"}}
+	if err := a.addPlatformRules(); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(a.rules[0])
+
+	content, err := os.ReadFile(filepath.Join(a.rules[0], "openshift.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := []struct {
+		RuleID string   `yaml:"ruleID"`
+		Labels []string `yaml:"labels"`
+		When   struct {
+			FileContent struct {
+				Pattern     string `yaml:"pattern"`
+				FilePattern string `yaml:"filePattern"`
+			} `yaml:"builtin.filecontent"`
+		} `yaml:"when"`
+	}{}
+	if err := yaml.Unmarshal(content, &rules); err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) == 0 {
+		t.Fatal("no platform rules found")
+	}
+	for _, rule := range rules {
+		if _, err := regexp.Compile(rule.When.FileContent.Pattern); err != nil {
+			t.Errorf("rule %s has invalid pattern: %v", rule.RuleID, err)
+		}
+		if _, err := regexp.Compile(rule.When.FileContent.FilePattern); err != nil {
+			t.Errorf("rule %s has invalid file pattern: %v", rule.RuleID, err)
+		}
+		if !strings.HasPrefix(strings.Join(rule.Labels, ","), platformLabel+"=") {
+			t.Errorf("rule %s is missing the %s label", rule.RuleID, platformLabel)
+		}
+	}
+	if selector := a.getLabelSelector(); !strings.Contains(selector, platformLabel) {
+		t.Errorf("label selector %s does not select platform rules", selector)
+	}
+	// non java inputs still need rules of --rules
+	if a.hasUserRules() {
+		t.Errorf("platform rules are counted as rules of --rules")
+	}
+	// containerless analyses do not clean tempDirs
+	a.cleanup = true
+	a.removeValidateResources()
+	if _, err := os.Stat(a.rules[0]); !os.IsNotExist(err) {
+		t.Errorf("platform rules dir %s was not removed", a.rules[0])
+	}
+}
+
+func Test_summarizePlatform(t *testing.T) {
+	rulesets := []outputv1.RuleSet{
+		{
+			Name: "platform",
+			Violations: map[string]outputv1.Violation{
+				"platform-00020": {
+					Description: "Privileged port",
+					Labels:      []string{platformLabel + "=openshift", "container"},
+					Incidents:   []outputv1.Incident{{}, {}},
+				},
+				"platform-00010": {
+					Description: "Runs as root",
+					Labels:      []string{platformLabel + "=openshift"},
+					Incidents:   []outputv1.Incident{{}},
+				},
+			},
+		},
+		{
+			Name: "eap8",
+			Violations: map[string]outputv1.Violation{
+				"eap8-00010": {Description: "Migration issue", Incidents: []outputv1.Incident{{}}},
+			},
+		},
+	}
+	want := []platformIssue{
+		{Platform: "openshift", RuleID: "platform-00010", Description: "Runs as root", Incidents: 1},
+		{Platform: "openshift", RuleID: "platform-00020", Description: "Privileged port", Incidents: 2},
+	}
+	if got := summarizePlatform(rulesets); !reflect.DeepEqual(got, want) {
+		t.Errorf("summarizePlatform() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/konveyor-ecosystem/kantra/cmd/internal/i18n"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/sirupsen/logrus"
+)
+
+// problem is an error or warning raised during the analysis, the same
+// problem raised again is counted
+type problem struct {
+	level   logrus.Level
+	message string
+	err     string
+	count   int
+}
+
+// problemCollector is a logrus hook collecting the errors and warnings
+// logged during the analysis, printed grouped at its end instead of
+// scattered through the log
+type problemCollector struct {
+	mu       sync.Mutex
+	problems []*problem
+	byKey    map[string]*problem
+}
+
+func newProblemCollector() *problemCollector {
+	return &problemCollector{byKey: map[string]*problem{}}
+}
+
+func (c *problemCollector) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel, logrus.WarnLevel}
+}
+
+func (c *problemCollector) Fire(entry *logrus.Entry) error {
+	err := ""
+	if v, ok := entry.Data[logrus.ErrorKey]; ok {
+		err = fmt.Sprint(v)
+	}
+	c.add(entry.Level, entry.Message, err)
+	return nil
+}
+
+func (c *problemCollector) add(level logrus.Level, message string, err string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := fmt.Sprintf("%d\x00%s\x00%s", level, message, err)
+	if p, ok := c.byKey[key]; ok {
+		p.count++
+		return
+	}
+	p := &problem{level: level, message: message, err: err, count: 1}
+	c.byKey[key] = p
+	c.problems = append(c.problems, p)
+}
+
+// addRuleErrors adds the rules that failed in the analysis output as
+// warnings, grouped by their error
+func (c *problemCollector) addRuleErrors(rulesets []outputv1.RuleSet) {
+	for _, rs := range rulesets {
+		for _, ruleID := range sortedMapKeys(rs.Errors) {
+			c.add(logrus.WarnLevel, "rule failed", rs.Errors[ruleID])
+		}
+	}
+}
+
+// print writes the problems, errors first, in the order they were first
+// raised
+func (c *problemCollector) print(out io.Writer, color bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.problems) == 0 {
+		return
+	}
+	problems := append([]*problem{}, c.problems...)
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].level < problems[j].level })
+	errors, warnings := 0, 0
+	for _, p := range problems {
+		if p.level <= logrus.ErrorLevel {
+			errors += p.count
+		} else {
+			warnings += p.count
+		}
+	}
+	fmt.Fprintln(out, i18n.Sprintf("%d errors and %d warnings during the analysis:", errors, warnings))
+	for _, p := range problems {
+		level := "ERROR"
+		colorCode := "31"
+		if p.level == logrus.WarnLevel {
+			level = "WARNING"
+			colorCode = "33"
+		}
+		level = fmt.Sprintf("%-7s", level)
+		if color {
+			level = fmt.Sprintf("\033[%sm%s\033[0m", colorCode, level)
+		}
+		line := p.message
+		if p.err != "" {
+			line = fmt.Sprintf("%s: %s", line, p.err)
+		}
+		fmt.Fprintf(out, "  %s %4dx  %s\n", level, p.count, line)
+	}
+}
+
+// collectProblems starts collecting the problems of the analysis, the
+// returned func prints them
+func (a *analyzeCommand) collectProblems() func() {
+	if a.quiet {
+		return func() {}
+	}
+	a.problems = newProblemCollector()
+	logrusLog.AddHook(a.problems)
+	return func() {
+		color := isInteractiveTerminal(os.Stdout) && os.Getenv("NO_COLOR") == ""
+		a.problems.print(os.Stdout, color)
+	}
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/bombsimon/logrusr/v3"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/sirupsen/logrus"
+)
+
+func Test_problemCollector(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(&bytes.Buffer{})
+	collector := newProblemCollector()
+	logger.AddHook(collector)
+	log := logrusr.New(logger)
+
+	log.Info("starting provider")
+	log.Error(errors.New("connection refused"), "failed to start provider, retrying")
+	log.Error(errors.New("connection refused"), "failed to start provider, retrying")
+	logger.Warn("maven settings not found")
+	log.Error(errors.New("no such file"), "failed to generate static report")
+	collector.addRuleErrors([]outputv1.RuleSet{{
+		Name:   "eap8",
+		Errors: map[string]string{"eap8-00001": "invalid pattern", "eap8-00002": "invalid pattern"},
+	}})
+
+	out := &bytes.Buffer{}
+	collector.print(out, false)
+	want := `3 errors and 3 warnings during the analysis:
+  ERROR      2x  failed to start provider, retrying: connection refused
+  ERROR      1x  failed to generate static report: no such file
+  WARNING    1x  maven settings not found
+  WARNING    2x  rule failed: invalid pattern
+`
+	if out.String() != want {
+		t.Errorf("print() =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	out.Reset()
+	newProblemCollector().print(out, false)
+	if out.Len() != 0 {
+		t.Errorf("print() without problems = %q", out.String())
+	}
+}
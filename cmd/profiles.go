@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v2"
+)
+
+// default dir of the analysis profiles of --all-profiles
+const defaultProfilesDir = "profiles"
+
+// flags of the profile matrix, not passed to the analysis of each profile
+var profileMatrixFlags = []string{"all-profiles", "profile", "profiles-dir", "output"}
+
+// analysisProfile is an assessment of the input, e.g. defined per target
+// on the Hub, stored as a yaml file in the profiles dir
+type analysisProfile struct {
+	// defaults to the name of the file
+	Name          string   `yaml:"name"`
+	Targets       []string `yaml:"targets,omitempty"`
+	Sources       []string `yaml:"sources,omitempty"`
+	LabelSelector string   `yaml:"labelSelector,omitempty"`
+	// rule files or dirs, relative to the profile file
+	Rules                 []string `yaml:"rules,omitempty"`
+	Mode                  string   `yaml:"mode,omitempty"`
+	EnableDefaultRulesets *bool    `yaml:"enableDefaultRulesets,omitempty"`
+	// volumes of provider containers as <provider>=<host path>:<container
+	// path>[:ro], host paths relative to the profile file
+	ProviderVolumes []string `yaml:"providerVolumes,omitempty"`
+	// settings of the providers, those set override the flags of the command
+	Providers profileProviders `yaml:"providers,omitempty"`
+}
+
+// profileProviders are the provider settings of a profile
+type profileProviders struct {
+	Java struct {
+		// max heap of the jvm, e.g. 8g
+		MaxMemory string `yaml:"maxMemory,omitempty"`
+		// maven settings file, relative to the profile file
+		MavenSettings string `yaml:"mavenSettings,omitempty"`
+	} `yaml:"java,omitempty"`
+	// dirs of the input excluded from the analysis of all providers
+	ExcludedDirs []string `yaml:"excludedDirs,omitempty"`
+}
+
+// profileResult is a row of the comparison of the profiles
+type profileResult struct {
+	Profile   string `json:"profile" yaml:"profile"`
+	Output    string `json:"output" yaml:"output"`
+	Rules     int    `json:"rules" yaml:"rules"`
+	Incidents int    `json:"incidents" yaml:"incidents"`
+	Score     int    `json:"score" yaml:"score"`
+	Error     string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// loadProfiles reads the profiles in dir, limited to names when given
+func loadProfiles(dir string, names []string) ([]analysisProfile, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	more, err := filepath.Glob(filepath.Join(dir, "*.yml"))
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, more...)
+	sort.Strings(files)
+	profiles := []analysisProfile{}
+	found := map[string]bool{}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		profile := analysisProfile{}
+		if err := yaml.Unmarshal(data, &profile); err != nil {
+			return nil, fmt.Errorf("%w failed to parse profile %s", err, file)
+		}
+		if profile.Name == "" {
+			profile.Name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		}
+		if found[profile.Name] {
+			return nil, fmt.Errorf("duplicate profile %s in %s", profile.Name, file)
+		}
+		found[profile.Name] = true
+		if len(names) > 0 && !slices.Contains(names, profile.Name) {
+			continue
+		}
+		if profile.LabelSelector != "" && (len(profile.Sources) > 0 || len(profile.Targets) > 0) {
+			return nil, fmt.Errorf("profile %s must not specify labelSelector and sources or targets", profile.Name)
+		}
+		for i, rule := range profile.Rules {
+			if !filepath.IsAbs(rule) {
+				profile.Rules[i] = filepath.Join(filepath.Dir(file), rule)
+			}
+		}
+		if settings := profile.Providers.Java.MavenSettings; settings != "" && !filepath.IsAbs(settings) {
+			profile.Providers.Java.MavenSettings = filepath.Join(filepath.Dir(file), settings)
+		}
+		for i, volume := range profile.ProviderVolumes {
+			if provider, mount, ok := strings.Cut(volume, "="); ok && !filepath.IsAbs(mount) {
+				profile.ProviderVolumes[i] = provider + "=" + filepath.Join(filepath.Dir(file), mount)
+			}
+		}
+		profiles = append(profiles, profile)
+	}
+	for _, name := range names {
+		if !found[name] {
+			return nil, fmt.Errorf("unknown profile %s in %s", name, dir)
+		}
+	}
+	if len(profiles) == 0 {
+		return nil, fmt.Errorf("no profiles found in %s", dir)
+	}
+	return profiles, nil
+}
+
+// validateProfiles checks the flags of the profile matrix, the analysis of
+// each profile validates the other flags
+func (a *analyzeCommand) validateProfiles() error {
+	if len(a.sources) > 0 || len(a.targets) > 0 || a.labelSelector != "" {
+		return fmt.Errorf("must not specify profiles and sources, targets or label-selector")
+	}
+	if a.bulk {
+		return fmt.Errorf("must not specify both profiles and bulk")
+	}
+	if _, err := os.Stat(a.input); err != nil {
+		return fmt.Errorf("%w failed to stat input path %s", err, a.input)
+	}
+	profiles, err := loadProfiles(a.profilesDir, a.profileNames)
+	if err != nil {
+		return err
+	}
+	a.analysisProfiles = profiles
+	if err := a.CheckOverwriteOutput(); err != nil {
+		return err
+	}
+	return os.MkdirAll(a.output, os.ModePerm)
+}
+
+// flagArgs returns the arguments of the flags set by the user, except the
+// skipped ones
+func flagArgs(flags *pflag.FlagSet, skip []string) []string {
+	args := []string{}
+	flags.Visit(func(f *pflag.Flag) {
+		if slices.Contains(skip, f.Name) {
+			return
+		}
+		if values, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range values.GetSlice() {
+				args = append(args, fmt.Sprintf("--%s=%s", f.Name, v))
+			}
+			return
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+	})
+	return args
+}
+
+// profileArgs returns the analyze arguments of a profile, the flags set
+// by the user and the options of the profile
+func profileArgs(flags *pflag.FlagSet, profile analysisProfile, output string) []string {
+	skip := slices.Clone(profileMatrixFlags)
+	if profile.Mode != "" {
+		skip = append(skip, "mode")
+	}
+	if profile.EnableDefaultRulesets != nil {
+		skip = append(skip, "enable-default-rulesets")
+	}
+	if profile.Providers.Java.MaxMemory != "" {
+		skip = append(skip, "jvm-max-mem")
+	}
+	if profile.Providers.Java.MavenSettings != "" {
+		skip = append(skip, "maven-settings")
+	}
+	args := append([]string{"analyze", "--output", output}, flagArgs(flags, skip)...)
+	for _, t := range profile.Targets {
+		args = append(args, "--target", t)
+	}
+	for _, s := range profile.Sources {
+		args = append(args, "--source", s)
+	}
+	if profile.LabelSelector != "" {
+		args = append(args, "--label-selector", profile.LabelSelector)
+	}
+	for _, r := range profile.Rules {
+		args = append(args, "--rules", r)
+	}
+	if profile.Mode != "" {
+		args = append(args, "--mode", profile.Mode)
+	}
+	if profile.EnableDefaultRulesets != nil {
+		args = append(args, fmt.Sprintf("--enable-default-rulesets=%t", *profile.EnableDefaultRulesets))
+	}
+	for _, v := range profile.ProviderVolumes {
+		args = append(args, "--provider-volume", v)
+	}
+	if profile.Providers.Java.MaxMemory != "" {
+		args = append(args, "--jvm-max-mem", profile.Providers.Java.MaxMemory)
+	}
+	if profile.Providers.Java.MavenSettings != "" {
+		args = append(args, "--maven-settings", profile.Providers.Java.MavenSettings)
+	}
+	for _, dir := range profile.Providers.ExcludedDirs {
+		args = append(args, "--exclude-dir", dir)
+	}
+	return args
+}
+
+// readProfileResult reads the summary of the analysis of a profile
+func readProfileResult(profile, output string) profileResult {
+	result := profileResult{Profile: profile, Output: output}
+	data, err := os.ReadFile(filepath.Join(output, "summary.json"))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	summary := analysisSummary{}
+	if err := json.Unmarshal(data, &summary); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Rules, result.Incidents, result.Score = summary.Rules, summary.Incidents, summary.Score
+	return result
+}
+
+func writeProfileComparison(out io.Writer, results []profileResult) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROFILE\tRULES\tINCIDENTS\tSCORE\tOUTPUT")
+	for _, r := range results {
+		if r.Error != "" {
+			fmt.Fprintf(w, "%s\t-\t-\t-\tfailed: %s\n", r.Profile, r.Error)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\n", r.Profile, r.Rules, r.Incidents, r.Score, r.Output)
+	}
+	return w.Flush()
+}
+
+// RunProfiles analyzes the input once per profile, each in a subdir of the
+// output, and writes a comparison of the profiles to profiles.json
+func (a *analyzeCommand) RunProfiles(ctx context.Context, flags *pflag.FlagSet) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	results := []profileResult{}
+	failed := []string{}
+	for _, profile := range a.analysisProfiles {
+		output := filepath.Join(a.output, profile.Name)
+		a.log.Info("analyzing profile", "profile", profile.Name, "output", output)
+		cmd := exec.CommandContext(ctx, executable, profileArgs(flags, profile, output)...)
+		cmd.Stdout = a.consoleWriter()
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			a.log.Error(err, "failed to analyze profile", "profile", profile.Name)
+			failed = append(failed, profile.Name)
+			results = append(results, profileResult{Profile: profile.Name, Output: output, Error: err.Error()})
+			continue
+		}
+		results = append(results, readProfileResult(profile.Name, output))
+	}
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(a.output, "profiles.json"), data, 0644); err != nil {
+		return err
+	}
+	if !a.quiet {
+		if err := writeProfileComparison(os.Stdout, results); err != nil {
+			return err
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("analysis failed for profiles %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func Test_loadProfiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "eap.yaml"), "targets: [eap8]\nrules: [rules/eap]\nproviderVolumes: ['java=certs/ca.crt:/etc/pki/ca.crt:ro']\n"+
+		"providers:\n  java:\n    maxMemory: 8g\n    mavenSettings: maven/settings.xml\n  excludedDirs: [target]\n")
+	writeTestFile(t, filepath.Join(dir, "cloud.yml"), "name: cloud-readiness\ntargets: [cloud-readiness]\nmode: source-only\n")
+	profiles, err := loadProfiles(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 2 || profiles[0].Name != "cloud-readiness" || profiles[1].Name != "eap" {
+		t.Fatalf("loadProfiles() = %v, want cloud-readiness and eap", profiles)
+	}
+	if want := filepath.Join(dir, "rules", "eap"); profiles[1].Rules[0] != want {
+		t.Errorf("loadProfiles() rules = %v, want %s", profiles[1].Rules, want)
+	}
+	if want := "java=" + filepath.Join(dir, "certs", "ca.crt:") + "/etc/pki/ca.crt:ro"; profiles[1].ProviderVolumes[0] != want {
+		t.Errorf("loadProfiles() provider volumes = %v, want %s", profiles[1].ProviderVolumes, want)
+	}
+	if want := filepath.Join(dir, "maven", "settings.xml"); profiles[1].Providers.Java.MavenSettings != want || profiles[1].Providers.Java.MaxMemory != "8g" {
+		t.Errorf("loadProfiles() providers = %+v, want maxMemory 8g and maven settings %s", profiles[1].Providers, want)
+	}
+	profiles, err = loadProfiles(dir, []string{"cloud-readiness"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 1 || profiles[0].Name != "cloud-readiness" {
+		t.Errorf("loadProfiles() selected = %v, want cloud-readiness", profiles)
+	}
+	if _, err := loadProfiles(dir, []string{"quarkus"}); err == nil {
+		t.Errorf("loadProfiles() must fail for an unknown profile")
+	}
+	if _, err := loadProfiles(t.TempDir(), nil); err == nil {
+		t.Errorf("loadProfiles() must fail without profiles")
+	}
+}
+
+func Test_profileArgs(t *testing.T) {
+	a := &analyzeCommand{}
+	flags := pflag.NewFlagSet("analyze", pflag.ContinueOnError)
+	flags.StringVarP(&a.input, "input", "i", "", "")
+	flags.StringVarP(&a.output, "output", "o", "", "")
+	flags.StringArrayVar(&a.rules, "rules", []string{}, "")
+	flags.StringVarP(&a.mode, "mode", "m", "full", "")
+	flags.BoolVar(&a.allProfiles, "all-profiles", false, "")
+	flags.BoolVar(&a.runLocal, "run-local", true, "")
+	flags.StringVar(&a.jvmMaxMem, "jvm-max-mem", "", "")
+	err := flags.Parse([]string{"-i", "app", "-o", "out", "--rules", "a", "--rules", "b", "-m", "full", "--all-profiles", "--run-local=false", "--jvm-max-mem", "4g"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile := analysisProfile{Name: "eap", Targets: []string{"eap8"}, Mode: "source-only", ProviderVolumes: []string{"java=/certs:/etc/pki/extra"}}
+	profile.Providers.Java.MaxMemory = "8g"
+	profile.Providers.ExcludedDirs = []string{"target"}
+	got := profileArgs(flags, profile, filepath.Join("out", "eap"))
+	want := []string{"analyze", "--output", filepath.Join("out", "eap"), "--input=app", "--rules=a", "--rules=b",
+		"--run-local=false", "--target", "eap8", "--mode", "source-only", "--provider-volume", "java=/certs:/etc/pki/extra",
+		"--jvm-max-mem", "8g", "--exclude-dir", "target"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("profileArgs() = %v, want %v", got, want)
+	}
+}
+
+func Test_writeProfileComparison(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "eap")
+	writeTestFile(t, filepath.Join(output, "summary.json"), `{"rules": 3, "incidents": 12, "score": 80}`)
+	results := []profileResult{
+		readProfileResult("eap", output),
+		readProfileResult("quarkus", filepath.Join(dir, "quarkus")),
+	}
+	if results[0].Incidents != 12 || results[0].Score != 80 || results[1].Error == "" {
+		t.Fatalf("readProfileResult() = %v", results)
+	}
+	out := &bytes.Buffer{}
+	if err := writeProfileComparison(out, results); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "eap ") || !strings.Contains(lines[2], "failed") {
+		t.Errorf("writeProfileComparison() = %s", out)
+	}
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/konveyor-ecosystem/kantra/cmd/internal/i18n"
+)
+
+// message of the engine log with the number of rules evaluated so far,
+// logged at ruleResponseLevel
+const (
+	ruleResponseMessage = "rule response received"
+	ruleResponseLevel   = 5
+)
+
+// progress styles
+const (
+	progressStyleAuto  = "auto"
+	progressStylePlain = "plain"
+)
+
+// environment variables set by common CI systems
+var ciEnvVars = []string{
+	"CI",
+	"BUILD_NUMBER",
+	"GITHUB_ACTIONS",
+	"GITLAB_CI",
+	"JENKINS_URL",
+	"TEKTON_PIPELINE",
+	"TF_BUILD",
+}
+
+// progress reports that a long running step is still making progress.
+// On an interactive terminal a single line is redrawn in place, otherwise
+// (CI logs, redirected output) a plain line is printed every interval.
+type progress struct {
+	out      io.Writer
+	ansi     bool
+	interval time.Duration
+	message  string
+	// rules evaluated by the engine, nil when it does not report them
+	rules *ruleProgress
+	start time.Time
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+func newProgress(out io.Writer, ansi bool, interval time.Duration, message string) *progress {
+	return &progress{
+		out:      out,
+		ansi:     ansi,
+		interval: interval,
+		message:  message,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (p *progress) Start() {
+	p.start = time.Now()
+	interval := p.interval
+	if p.ansi {
+		// redrawing a line is cheap, keep the elapsed time current
+		interval = time.Second
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		p.render()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.render()
+			}
+		}
+	}()
+}
+
+func (p *progress) Stop() {
+	close(p.stop)
+	p.wg.Wait()
+	if p.ansi {
+		// clear the progress line
+		fmt.Fprint(p.out, "\r\033[K")
+	}
+}
+
+func (p *progress) render() {
+	message := p.message
+	if p.rules != nil {
+		if done, total := p.rules.counts(); total > 0 {
+			message = i18n.Sprintf("Processed %d/%d rules (%d%%)", done, total, done*100/total)
+		}
+	}
+	line := i18n.Sprintf("%s (%s elapsed)", message,
+		time.Since(p.start).Round(time.Second))
+	if p.ansi {
+		fmt.Fprintf(p.out, "\r\033[K%s", line)
+		return
+	}
+	fmt.Fprintln(p.out, line)
+}
+
+// isInteractiveTerminal returns true when f is a terminal that handles
+// ANSI escape codes and we are not running in a CI system
+func isInteractiveTerminal(f *os.File) bool {
+	if strings.EqualFold(os.Getenv("TERM"), "dumb") {
+		return false
+	}
+	for _, env := range ciEnvVars {
+		if val, ok := os.LookupEnv(env); ok && val != "" && !strings.EqualFold(val, "false") {
+			return false
+		}
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// startProgress starts reporting progress of a long running step,
+// the returned func stops it
+func (a *analyzeCommand) startProgress(message string) func() {
+	return a.startRuleProgress(message, nil)
+}
+
+// startRuleProgress starts reporting progress of the evaluation of rules,
+// with the number of rules evaluated once the engine reports it
+func (a *analyzeCommand) startRuleProgress(message string, rules *ruleProgress) func() {
+	if a.quiet || a.summaryOnly || a.progressInterval <= 0 {
+		return func() {}
+	}
+	// plain style never uses escape codes
+	ansi := a.progressStyle != progressStylePlain && isInteractiveTerminal(os.Stdout)
+	p := newProgress(os.Stdout, ansi, a.progressInterval, message)
+	p.rules = rules
+	p.Start()
+	return p.Stop
+}
+
+// ruleProgress counts the rules evaluated by the engine. The engine has no
+// progress api, it logs the counts of the rules it evaluated with each
+// response of a rule.
+type ruleProgress struct {
+	done  atomic.Int64
+	total atomic.Int64
+}
+
+func (r *ruleProgress) counts() (int64, int64) {
+	return r.done.Load(), r.total.Load()
+}
+
+// record records the counts of a ruleResponseMessage
+func (r *ruleProgress) record(keysAndValues []interface{}) {
+	var done int64
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		var value int64
+		switch v := keysAndValues[i+1].(type) {
+		case int32:
+			value = int64(v)
+		case int:
+			value = int64(v)
+		default:
+			continue
+		}
+		switch keysAndValues[i] {
+		case "total":
+			r.total.Store(value)
+		case "failed", "matched", "unmatched":
+			done += value
+		}
+	}
+	r.done.Store(done)
+}
+
+// ruleProgressSink passes the log of the engine on to its sink and records
+// the rules evaluated, also when the log level of the sink drops the
+// messages with the counts
+type ruleProgressSink struct {
+	logr.LogSink
+	rules *ruleProgress
+}
+
+// ruleProgressLogger returns a logger for the engine recording its progress
+// in rules and logging to log
+func ruleProgressLogger(log logr.Logger, rules *ruleProgress) logr.Logger {
+	return logr.New(ruleProgressSink{LogSink: log.GetSink(), rules: rules})
+}
+
+// Enabled enables the level of the rule responses to record their counts
+func (s ruleProgressSink) Enabled(level int) bool {
+	return level == ruleResponseLevel || s.LogSink.Enabled(level)
+}
+
+func (s ruleProgressSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	if msg == ruleResponseMessage {
+		s.rules.record(keysAndValues)
+	}
+	if s.LogSink.Enabled(level) {
+		s.LogSink.Info(level, msg, keysAndValues...)
+	}
+}
+
+func (s ruleProgressSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return ruleProgressSink{LogSink: s.LogSink.WithValues(keysAndValues...), rules: s.rules}
+}
+
+func (s ruleProgressSink) WithName(name string) logr.LogSink {
+	return ruleProgressSink{LogSink: s.LogSink.WithName(name), rules: s.rules}
+}
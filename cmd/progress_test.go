@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bombsimon/logrusr/v3"
+	"github.com/sirupsen/logrus"
+)
+
+func Test_progressRender(t *testing.T) {
+	tests := []struct {
+		name  string
+		ansi  bool
+		rules [2]int64
+		want  string
+	}{
+		{
+			name: "plain progress prints a line without escape codes",
+			want: "evaluating rules for violations (0s elapsed)\n",
+		},
+		{
+			name: "ansi progress redraws the line in place",
+			ansi: true,
+			want: "\r\033[Kevaluating rules for violations (0s elapsed)",
+		},
+		{
+			name:  "progress reports the rules processed by the engine",
+			rules: [2]int64{3, 12},
+			want:  "Processed 3/12 rules (25%) (0s elapsed)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			p := newProgress(out, tt.ansi, time.Minute, "evaluating rules for violations")
+			p.start = time.Now()
+			if tt.rules[1] > 0 {
+				p.rules = &ruleProgress{}
+				p.rules.done.Store(tt.rules[0])
+				p.rules.total.Store(tt.rules[1])
+			}
+			p.render()
+			if got := out.String(); got != tt.want {
+				t.Errorf("progress.render() = %q, want %q", got, tt.want)
+			}
+			if !tt.ansi && strings.Contains(out.String(), "\033") {
+				t.Errorf("plain progress contains escape codes: %q", out.String())
+			}
+		})
+	}
+}
+
+func Test_ruleProgressLogger(t *testing.T) {
+	logger := logrus.New()
+	out := &bytes.Buffer{}
+	logger.SetOutput(out)
+	logger.SetLevel(logrus.InfoLevel)
+	rules := &ruleProgress{}
+	log := ruleProgressLogger(logrusr.New(logger), rules).WithName("engine")
+
+	log.V(5).Info(ruleResponseMessage, "total", 10, "failed", int32(1), "matched", int32(2), "unmatched", int32(4))
+	if done, total := rules.counts(); done != 7 || total != 10 {
+		t.Errorf("ruleProgress.counts() = %d/%d, want 7/10", done, total)
+	}
+	if strings.Contains(out.String(), ruleResponseMessage) {
+		t.Errorf("message above the log level was logged: %s", out.String())
+	}
+	if log.V(6).Enabled() {
+		t.Errorf("level without rule responses above the log level is enabled")
+	}
+	log.Info("rule returned")
+	if !strings.Contains(out.String(), "rule returned") {
+		t.Errorf("message at the log level was not logged: %s", out.String())
+	}
+}
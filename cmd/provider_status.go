@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/konveyor-ecosystem/kantra/cmd/internal/i18n"
+)
+
+type providerState string
+
+// provider states reported while analysis is running
+const (
+	providerInitializing providerState = "initializing"
+	providerReady        providerState = "ready"
+	providerAnalyzing    providerState = "analyzing"
+	providerFailed       providerState = "failed"
+)
+
+// number of provider log lines shown when a provider fails
+const providerLogTailLines = 20
+
+// providerStatus keeps track of the state of each provider and prints
+// a status line whenever it changes, so a stuck analysis can be traced
+// back to a provider without reading the logs
+type providerStatus struct {
+	mu     sync.Mutex
+	out    io.Writer
+	states map[string]providerState
+}
+
+func newProviderStatus(out io.Writer) *providerStatus {
+	return &providerStatus{
+		out:    out,
+		states: map[string]providerState{},
+	}
+}
+
+func (p *providerStatus) set(name string, state providerState, detail string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if prev, ok := p.states[name]; ok && prev == state && detail == "" {
+		return
+	}
+	p.states[name] = state
+	if p.out == nil {
+		return
+	}
+	line := i18n.Sprintf("provider %s: %s", name, i18n.T(string(state)))
+	if detail != "" {
+		line = fmt.Sprintf("%s\n%s", line, indentLines(detail, "  | "))
+	}
+	fmt.Fprintln(p.out, line)
+}
+
+// setAll moves every known provider that has not failed to the given state
+func (p *providerStatus) setAll(state providerState) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	names := []string{}
+	for name, current := range p.states {
+		if current != providerFailed {
+			names = append(names, name)
+		}
+	}
+	p.mu.Unlock()
+	sort.Strings(names)
+	for _, name := range names {
+		p.set(name, state, "")
+	}
+}
+
+func (p *providerStatus) failed() []string {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	names := []string{}
+	for name, state := range p.states {
+		if state == providerFailed {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func indentLines(s string, prefix string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i := range lines {
+		lines[i] = prefix + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// checkProviderContainers inspects the started provider containers and
+// reports the ones that are no longer running along with their stderr. With
+// --provider-health-timeout the containers are checked until it passes, so
+// that providers failing while they start are reported before analysis.
+func (a *analyzeCommand) checkProviderContainers(ctx context.Context) error {
+	deadline := time.Now().Add(a.providerHealthTimeout)
+	for {
+		if err := a.checkProviderContainersOnce(ctx); err != nil {
+			return err
+		}
+		if !time.Now().Before(deadline) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(min(providerHealthInterval, time.Until(deadline))):
+		}
+	}
+}
+
+func (a *analyzeCommand) checkProviderContainersOnce(ctx context.Context) error {
+	provs := []string{}
+	for prov := range a.providersMap {
+		provs = append(provs, prov)
+	}
+	sort.Strings(provs)
+	for _, prov := range provs {
+		init := a.providersMap[prov]
+		if init.containerName == "" {
+			continue
+		}
+		running, err := containerRunning(ctx, init.containerName)
+		if err != nil {
+			a.log.V(1).Error(err, "failed to inspect provider container", "container", init.containerName)
+			continue
+		}
+		if running {
+			a.providerStatus.set(prov, providerReady, "")
+			continue
+		}
+		a.providerStatus.set(prov, providerFailed, containerLogTail(ctx, init.containerName, providerLogTailLines))
+	}
+	if failed := a.providerStatus.failed(); len(failed) > 0 {
+		return i18n.Errorf("provider(s) %s failed to start, see provider output above", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func containerRunning(ctx context.Context, name string) (bool, error) {
+	cmd := exec.CommandContext(ctx, Settings.ContainerBinary,
+		"inspect", "--format", "{{.State.Running}}", name)
+	out, err := cmd.Output()
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(out)) == "true", nil
+}
+
+func containerLogTail(ctx context.Context, name string, lines int) string {
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, Settings.ContainerBinary,
+		"logs", "--tail", fmt.Sprintf("%d", lines), name)
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil && out.Len() == 0 {
+		return fmt.Sprintf("unable to get container logs: %v", err)
+	}
+	return out.String()
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func Test_providerStatus(t *testing.T) {
+	out := &bytes.Buffer{}
+	status := newProviderStatus(out)
+	status.set("java", providerInitializing, "")
+	status.set("builtin", providerInitializing, "")
+	status.set("java", providerInitializing, "")
+	status.set("java", providerFailed, "error: port in use\n")
+	status.setAll(providerAnalyzing)
+
+	want := "provider java: initializing\n" +
+		"provider builtin: initializing\n" +
+		"provider java: failed\n" +
+		"  | error: port in use\n" +
+		"provider builtin: analyzing\n"
+	if got := out.String(); got != want {
+		t.Errorf("providerStatus output = %q, want %q", got, want)
+	}
+	if got := status.failed(); !reflect.DeepEqual(got, []string{"java"}) {
+		t.Errorf("providerStatus.failed() = %v, want [java]", got)
+	}
+
+	var nilStatus *providerStatus
+	nilStatus.set("java", providerReady, "")
+	if got := nilStatus.failed(); got != nil {
+		t.Errorf("nil providerStatus.failed() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"path"
+	"slices"
+	"strings"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// providerLabel is set on violations with the providers of their incidents,
+// so that reports of polyglot applications can be filtered by technology
+const providerLabel = "konveyor.io/provider"
+
+// providers of incidents by the extension of their file
+var providerExtensions = map[string]string{
+	".java":   javaProvider,
+	".class":  javaProvider,
+	".jar":    javaProvider,
+	".war":    javaProvider,
+	".ear":    javaProvider,
+	".go":     goProvider,
+	".py":     pythonProvider,
+	".js":     nodeJSProvider,
+	".jsx":    nodeJSProvider,
+	".ts":     nodeJSProvider,
+	".tsx":    nodeJSProvider,
+	".cs":     dotnetProvider,
+	".csproj": dotnetProvider,
+}
+
+// providers of incidents in build files, e.g. of dependency conditions
+var providerBuildFiles = map[string]string{
+	"pom.xml":           javaProvider,
+	"build.gradle":      javaProvider,
+	"build.gradle.kts":  javaProvider,
+	"go.mod":            goProvider,
+	"go.sum":            goProvider,
+	"requirements.txt":  pythonProvider,
+	"pyproject.toml":    pythonProvider,
+	"package.json":      nodeJSProvider,
+	"package-lock.json": nodeJSProvider,
+	"packages.config":   dotnetProvider,
+}
+
+// incidentProvider returns the provider of the language of the file of an
+// incident, the builtin provider for other files
+func incidentProvider(incident outputv1.Incident) string {
+	name := path.Base(string(incident.URI))
+	if provider, ok := providerBuildFiles[name]; ok {
+		return provider
+	}
+	if provider, ok := providerExtensions[strings.ToLower(path.Ext(name))]; ok {
+		return provider
+	}
+	return builtinProvider
+}
+
+// addProviderLabels labels violations with the providers of their incidents
+func addProviderLabels(rulesets []outputv1.RuleSet) {
+	for i := range rulesets {
+		for ruleID, violation := range rulesets[i].Violations {
+			providers := map[string]bool{}
+			for _, incident := range violation.Incidents {
+				providers[incidentProvider(incident)] = true
+			}
+			for _, provider := range sortedMapKeys(providers) {
+				label := providerLabel + "=" + provider
+				if !slices.Contains(violation.Labels, label) {
+					violation.Labels = append(violation.Labels, label)
+				}
+			}
+			rulesets[i].Violations[ruleID] = violation
+		}
+	}
+}
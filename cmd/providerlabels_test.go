@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+)
+
+func Test_addProviderLabels(t *testing.T) {
+	rulesets := []outputv1.RuleSet{
+		{
+			Name: "polyglot",
+			Violations: map[string]outputv1.Violation{
+				"java-00010": {
+					Labels: []string{"konveyor.io/target=quarkus"},
+					Incidents: []outputv1.Incident{
+						{URI: uri.File("/app/backend/src/App.java")},
+						{URI: uri.File("/app/backend/pom.xml")},
+					},
+				},
+				"node-00010": {
+					Incidents: []outputv1.Incident{{URI: uri.File("/app/frontend/src/index.ts")}},
+				},
+				"mixed-00010": {
+					Incidents: []outputv1.Incident{
+						{URI: uri.File("/app/frontend/package.json")},
+						{URI: uri.File("/app/deploy/Dockerfile")},
+					},
+				},
+			},
+		},
+	}
+	addProviderLabels(rulesets)
+	// labeling again does not duplicate labels
+	addProviderLabels(rulesets)
+	want := map[string][]string{
+		"java-00010":  {"konveyor.io/target=quarkus", "konveyor.io/provider=java"},
+		"node-00010":  {"konveyor.io/provider=nodejs"},
+		"mixed-00010": {"konveyor.io/provider=builtin", "konveyor.io/provider=nodejs"},
+	}
+	for ruleID, labels := range want {
+		if got := rulesets[0].Violations[ruleID].Labels; !reflect.DeepEqual(got, labels) {
+			t.Errorf("labels of %s = %v, want %v", ruleID, got, labels)
+		}
+	}
+}
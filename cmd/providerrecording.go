@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/konveyor/analyzer-lsp/engine"
+	"github.com/konveyor/analyzer-lsp/provider"
+	"go.lsp.dev/uri"
+)
+
+// providerRecording holds the responses of the providers of an analysis
+// with --record, to run the analysis again with --replay without providers
+type providerRecording struct {
+	Providers map[string]*recordedProvider `json:"providers"`
+}
+
+// recordedProvider holds the responses of a provider, evaluations are keyed
+// by capability and condition, code snippets by file and location
+type recordedProvider struct {
+	Capabilities      []string                          `json:"capabilities"`
+	Evaluations       map[string]recordedEvaluation     `json:"evaluations,omitempty"`
+	Snippets          map[string]string                 `json:"snippets,omitempty"`
+	Dependencies      map[uri.URI][]*provider.Dep       `json:"dependencies,omitempty"`
+	DependenciesDAG   map[uri.URI][]provider.DepDAGItem `json:"dependenciesDAG,omitempty"`
+	DependenciesError string                            `json:"dependenciesError,omitempty"`
+}
+
+type recordedEvaluation struct {
+	Response provider.ProviderEvaluateResponse `json:"response"`
+	Error    string                            `json:"error,omitempty"`
+}
+
+func evaluationKey(capability string, conditionInfo []byte) string {
+	return capability + "\n" + string(conditionInfo)
+}
+
+func snippetKey(u uri.URI, l engine.Location) string {
+	return fmt.Sprintf("%s:%d:%d-%d:%d", u, l.StartPosition.Line, l.StartPosition.Character, l.EndPosition.Line, l.EndPosition.Character)
+}
+
+func newProviderRecording() *providerRecording {
+	return &providerRecording{Providers: map[string]*recordedProvider{}}
+}
+
+// loadProviderRecording reads the recording of --replay
+func loadProviderRecording(path string) (*providerRecording, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	recording := newProviderRecording()
+	if err := json.Unmarshal(data, recording); err != nil {
+		return nil, fmt.Errorf("%w failed to parse provider recording %s", err, path)
+	}
+	return recording, nil
+}
+
+// write writes the recording of --record, it may be shared to reproduce
+// the analysis, the code snippets of the input it holds are not redacted
+func (r *providerRecording) write(path string) error {
+	for _, recorded := range r.Providers {
+		for _, evaluation := range recorded.Evaluations {
+			// variables decoded from yaml may have maps json can not encode
+			for _, incident := range evaluation.Response.Incidents {
+				normalizeQueryValue(incident.Variables)
+			}
+			normalizeQueryValue(evaluation.Response.TemplateContext)
+		}
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// recordingProviderClient records the responses of a provider of the analysis
+type recordingProviderClient struct {
+	provider.InternalProviderClient
+	mu       sync.Mutex
+	recorded *recordedProvider
+}
+
+var _ engine.CodeSnip = &recordingProviderClient{}
+
+// record wraps a provider to record its responses
+func (r *providerRecording) record(name string, client provider.InternalProviderClient) provider.InternalProviderClient {
+	recorded := &recordedProvider{
+		Evaluations: map[string]recordedEvaluation{},
+		Snippets:    map[string]string{},
+	}
+	for _, capability := range client.Capabilities() {
+		recorded.Capabilities = append(recorded.Capabilities, capability.Name)
+	}
+	r.Providers[name] = recorded
+	return &recordingProviderClient{InternalProviderClient: client, recorded: recorded}
+}
+
+func (p *recordingProviderClient) Evaluate(ctx context.Context, cap string, conditionInfo []byte) (provider.ProviderEvaluateResponse, error) {
+	resp, err := p.InternalProviderClient.Evaluate(ctx, cap, conditionInfo)
+	evaluation := recordedEvaluation{Response: resp}
+	if err != nil {
+		evaluation.Error = err.Error()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.recorded.Evaluations[evaluationKey(cap, conditionInfo)] = evaluation
+	return resp, err
+}
+
+func (p *recordingProviderClient) GetCodeSnip(u uri.URI, l engine.Location) (string, error) {
+	snipper, ok := p.InternalProviderClient.(engine.CodeSnip)
+	if !ok {
+		return "", nil
+	}
+	snip, err := snipper.GetCodeSnip(u, l)
+	if err != nil {
+		return snip, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.recorded.Snippets[snippetKey(u, l)] = snip
+	return snip, nil
+}
+
+func (p *recordingProviderClient) GetDependencies(ctx context.Context) (map[uri.URI][]*provider.Dep, error) {
+	deps, err := p.InternalProviderClient.GetDependencies(ctx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.recorded.Dependencies = deps
+	if err != nil {
+		p.recorded.DependenciesError = err.Error()
+	}
+	return deps, err
+}
+
+func (p *recordingProviderClient) GetDependenciesDAG(ctx context.Context) (map[uri.URI][]provider.DepDAGItem, error) {
+	deps, err := p.InternalProviderClient.GetDependenciesDAG(ctx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.recorded.DependenciesDAG = deps
+	if err != nil {
+		p.recorded.DependenciesError = err.Error()
+	}
+	return deps, err
+}
+
+// replayProviderClient answers the engine with the recorded responses of a
+// provider, it needs neither the provider nor its dependencies
+type replayProviderClient struct {
+	name     string
+	recorded *recordedProvider
+}
+
+var _ provider.InternalProviderClient = &replayProviderClient{}
+var _ engine.CodeSnip = &replayProviderClient{}
+
+// replay returns a provider answering with the recorded responses of the
+// provider of the given name, one without capabilities when it was not
+// recorded
+func (r *providerRecording) replay(name string) provider.InternalProviderClient {
+	recorded, ok := r.Providers[name]
+	if !ok {
+		recorded = &recordedProvider{}
+	}
+	return &replayProviderClient{name: name, recorded: recorded}
+}
+
+func (p *replayProviderClient) Capabilities() []provider.Capability {
+	capabilities := []provider.Capability{}
+	for _, name := range p.recorded.Capabilities {
+		capabilities = append(capabilities, provider.Capability{Name: name})
+	}
+	return capabilities
+}
+
+func (p *replayProviderClient) Init(context.Context, logr.Logger, provider.InitConfig) (provider.ServiceClient, provider.InitConfig, error) {
+	return p, provider.InitConfig{}, nil
+}
+
+func (p *replayProviderClient) ProviderInit(context.Context, []provider.InitConfig) ([]provider.InitConfig, error) {
+	return nil, nil
+}
+
+func (p *replayProviderClient) Evaluate(ctx context.Context, cap string, conditionInfo []byte) (provider.ProviderEvaluateResponse, error) {
+	evaluation, ok := p.recorded.Evaluations[evaluationKey(cap, conditionInfo)]
+	if !ok {
+		return provider.ProviderEvaluateResponse{}, fmt.Errorf("no recorded response of provider %s for %s condition %s", p.name, cap, conditionInfo)
+	}
+	if evaluation.Error != "" {
+		return evaluation.Response, errors.New(evaluation.Error)
+	}
+	return evaluation.Response, nil
+}
+
+func (p *replayProviderClient) GetCodeSnip(u uri.URI, l engine.Location) (string, error) {
+	return p.recorded.Snippets[snippetKey(u, l)], nil
+}
+
+func (p *replayProviderClient) GetDependencies(ctx context.Context) (map[uri.URI][]*provider.Dep, error) {
+	if p.recorded.DependenciesError != "" {
+		return p.recorded.Dependencies, errors.New(p.recorded.DependenciesError)
+	}
+	return p.recorded.Dependencies, nil
+}
+
+func (p *replayProviderClient) GetDependenciesDAG(ctx context.Context) (map[uri.URI][]provider.DepDAGItem, error) {
+	if p.recorded.DependenciesError != "" {
+		return p.recorded.DependenciesDAG, errors.New(p.recorded.DependenciesError)
+	}
+	return p.recorded.DependenciesDAG, nil
+}
+
+func (p *replayProviderClient) Stop() {}
+
+// validateRecording checks --record and --replay, providers are recorded
+// and replayed in containerless mode where they run in kantra
+func (a *analyzeCommand) validateRecording() error {
+	if a.record == "" && a.replay == "" {
+		return nil
+	}
+	if a.record != "" && a.replay != "" {
+		return fmt.Errorf("must not specify both record and replay")
+	}
+	if !a.runLocal {
+		return fmt.Errorf("record and replay require containerless mode, set --run-local")
+	}
+	if a.replay != "" {
+		recording, err := loadProviderRecording(a.replay)
+		if err != nil {
+			return fmt.Errorf("%w failed to load provider recording", err)
+		}
+		a.replayRecording = recording
+	}
+	if a.record != "" {
+		a.recording = newProviderRecording()
+	}
+	return nil
+}
+
+// writeRecording writes the provider responses recorded with --record
+func (a *analyzeCommand) writeRecording() error {
+	if a.recording == nil {
+		return nil
+	}
+	if err := a.recording.write(a.record); err != nil {
+		return fmt.Errorf("%w failed to write provider recording", err)
+	}
+	a.log.Info("wrote provider recording", "file", a.record)
+	return nil
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/konveyor/analyzer-lsp/engine"
+	"github.com/konveyor/analyzer-lsp/provider"
+	"go.lsp.dev/uri"
+)
+
+// fakeRecordedClient answers file conditions and has dependencies
+type fakeRecordedClient struct {
+	fakeProviderClient
+}
+
+func (fakeRecordedClient) Capabilities() []provider.Capability {
+	return []provider.Capability{{Name: "file"}, {Name: "filecontent"}}
+}
+
+func (fakeRecordedClient) GetCodeSnip(u uri.URI, l engine.Location) (string, error) {
+	return "<project>", nil
+}
+
+func (fakeRecordedClient) GetDependencies(ctx context.Context) (map[uri.URI][]*provider.Dep, error) {
+	return map[uri.URI][]*provider.Dep{uri.File("/app/pom.xml"): {{Name: "junit.junit", Version: "4.12"}}}, nil
+}
+
+func Test_providerRecording(t *testing.T) {
+	ctx := context.Background()
+	recording := newProviderRecording()
+	client := recording.record(builtinProvider, fakeRecordedClient{})
+	resp, err := client.Evaluate(ctx, "file", []byte("pattern: pom.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	location := engine.Location{StartPosition: engine.Position{Line: 1}, EndPosition: engine.Position{Line: 2}}
+	if _, err := client.(engine.CodeSnip).GetCodeSnip(uri.File("/app/pom.xml"), location); err != nil {
+		t.Fatal(err)
+	}
+	deps, err := client.GetDependencies(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "providers.rec")
+	if err := recording.write(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadProviderRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replay := loaded.replay(builtinProvider)
+	if got := replay.Capabilities(); len(got) != 2 || got[0].Name != "file" {
+		t.Errorf("Capabilities() = %v, want the recorded capabilities", got)
+	}
+	replayed, err := replay.Evaluate(ctx, "file", []byte("pattern: pom.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(replayed, resp) {
+		t.Errorf("Evaluate() = %+v, want recorded %+v", replayed, resp)
+	}
+	if _, err := replay.Evaluate(ctx, "file", []byte("pattern: build.gradle")); err == nil {
+		t.Error("Evaluate() of a condition not recorded must fail")
+	}
+	snip, err := replay.(engine.CodeSnip).GetCodeSnip(uri.File("/app/pom.xml"), location)
+	if err != nil || snip != "<project>" {
+		t.Errorf("GetCodeSnip() = %q, %v, want the recorded snippet", snip, err)
+	}
+	replayedDeps, err := replay.GetDependencies(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(replayedDeps, deps) {
+		t.Errorf("GetDependencies() = %v, want recorded %v", replayedDeps, deps)
+	}
+
+	if got := loaded.replay(javaProvider).Capabilities(); len(got) != 0 {
+		t.Errorf("Capabilities() of a provider not recorded = %v, want none", got)
+	}
+}
+
+func Test_validateRecording(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "providers.rec")
+	if err := newProviderRecording().write(path); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		a       analyzeCommand
+		wantErr bool
+	}{
+		{name: "none", a: analyzeCommand{}},
+		{name: "record", a: analyzeCommand{record: path, runLocal: true}},
+		{name: "replay", a: analyzeCommand{replay: path, runLocal: true}},
+		{name: "both", a: analyzeCommand{record: path, replay: path, runLocal: true}, wantErr: true},
+		{name: "container mode", a: analyzeCommand{record: path}, wantErr: true},
+		{name: "missing recording", a: analyzeCommand{replay: path + ".missing", runLocal: true}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.a.validateRecording()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateRecording() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/konveyor/analyzer-lsp/provider"
+)
+
+// interval between the checks of the provider containers during
+// --provider-health-timeout
+var providerHealthInterval = 2 * time.Second
+
+// initWithTimeout runs the init of a provider and fails once the timeout
+// passes, 0 waits for the init to finish. The init is not canceled, providers
+// such as java keep their language server running with its context.
+func initWithTimeout(name string, timeout time.Duration, init func() ([]provider.InitConfig, error)) ([]provider.InitConfig, error) {
+	if timeout <= 0 {
+		return init()
+	}
+	type initResult struct {
+		configs []provider.InitConfig
+		err     error
+	}
+	done := make(chan initResult, 1)
+	go func() {
+		configs, err := init()
+		done <- initResult{configs: configs, err: err}
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case result := <-done:
+		return result.configs, result.err
+	case <-timer.C:
+		return nil, fmt.Errorf("provider %s did not initialize within %s, large inputs may need a longer --provider-init-timeout", name, timeout)
+	}
+}
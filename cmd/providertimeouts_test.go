@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/konveyor/analyzer-lsp/provider"
+)
+
+func Test_initWithTimeout(t *testing.T) {
+	initErr := errors.New("init failed")
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		delay   time.Duration
+		err     error
+		want    int
+		wantErr bool
+	}{
+		{name: "no limit", delay: 10 * time.Millisecond, want: 1},
+		{name: "within timeout", timeout: time.Second, want: 1},
+		{name: "init error", timeout: time.Second, err: initErr, wantErr: true},
+		{name: "timed out", timeout: 10 * time.Millisecond, delay: time.Second, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configs, err := initWithTimeout("java", tt.timeout, func() ([]provider.InitConfig, error) {
+				time.Sleep(tt.delay)
+				if tt.err != nil {
+					return nil, tt.err
+				}
+				return []provider.InitConfig{{Location: "/app"}}, nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("initWithTimeout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(configs) != tt.want {
+				t.Errorf("initWithTimeout() = %v, want %d configs", configs, tt.want)
+			}
+		})
+	}
+}
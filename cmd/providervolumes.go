@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+var volumeProviders = []string{javaProvider, goProvider, pythonProvider, nodeJSProvider, dotnetProvider}
+
+// providerVolume is a host path mounted in the container of a provider,
+// e.g. a CA trust store or a local maven repository
+type providerVolume struct {
+	provider      string
+	hostPath      string
+	containerPath string
+	readOnly      bool
+}
+
+// parseProviderVolume parses <provider>=<host path>:<container path>[:ro]
+func parseProviderVolume(v string) (providerVolume, error) {
+	vol := providerVolume{}
+	provider, mount, ok := strings.Cut(v, "=")
+	if !ok {
+		return vol, fmt.Errorf("provider volume %s must be <provider>=<host path>:<container path>[:ro]", v)
+	}
+	vol.provider = provider
+	if !slices.Contains(volumeProviders, provider) {
+		return vol, fmt.Errorf("provider %s of volume %s must be one of %s", provider, v, strings.Join(volumeProviders, ", "))
+	}
+	if strings.HasSuffix(mount, ":ro") {
+		vol.readOnly = true
+		mount = strings.TrimSuffix(mount, ":ro")
+	}
+	// host paths on windows have colons, container paths do not
+	i := strings.LastIndex(mount, ":")
+	if i <= 0 {
+		return vol, fmt.Errorf("provider volume %s must be <provider>=<host path>:<container path>[:ro]", v)
+	}
+	vol.hostPath, vol.containerPath = mount[:i], mount[i+1:]
+	if !path.IsAbs(vol.containerPath) {
+		return vol, fmt.Errorf("container path %s of provider volume %s must be absolute", vol.containerPath, v)
+	}
+	return vol, nil
+}
+
+// validateProviderVolumes parses the provider volumes, their host paths
+// must exist
+func (a *analyzeCommand) validateProviderVolumes() error {
+	if len(a.providerVolumeArgs) == 0 {
+		return nil
+	}
+	if a.runLocal {
+		return fmt.Errorf("provider-volume requires container mode, set --run-local=false")
+	}
+	a.providerVolumes = []providerVolume{}
+	for _, arg := range a.providerVolumeArgs {
+		vol, err := parseProviderVolume(arg)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(vol.hostPath); err != nil {
+			return fmt.Errorf("%w failed to stat host path of provider volume %s", err, arg)
+		}
+		if vol.hostPath, err = filepath.Abs(vol.hostPath); err != nil {
+			return err
+		}
+		a.providerVolumes = append(a.providerVolumes, vol)
+	}
+	return nil
+}
+
+// providerContainerVolumes returns the volumes and volume options of the
+// container of the provider, the volumes of all providers and its own
+func (a *analyzeCommand) providerContainerVolumes(provider string, volumes map[string]string, options map[string]string) (map[string]string, map[string]string) {
+	own := []providerVolume{}
+	for _, vol := range a.providerVolumes {
+		if vol.provider == provider {
+			own = append(own, vol)
+		}
+	}
+	if len(own) == 0 {
+		return volumes, options
+	}
+	volumes, options = maps.Clone(volumes), maps.Clone(options)
+	if options == nil {
+		options = map[string]string{}
+	}
+	for _, vol := range own {
+		volumes[vol.hostPath] = vol.containerPath
+		if vol.readOnly {
+			options[vol.containerPath] = readOnlyMount
+		}
+	}
+	return volumes, options
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_parseProviderVolume(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    providerVolume
+		wantErr bool
+	}{
+		{arg: "java=/etc/pki/ca.crt:/etc/pki/extra/ca.crt:ro", want: providerVolume{provider: "java", hostPath: "/etc/pki/ca.crt", containerPath: "/etc/pki/extra/ca.crt", readOnly: true}},
+		{arg: "dotnet=/home/me/.nuget:/root/.nuget", want: providerVolume{provider: "dotnet", hostPath: "/home/me/.nuget", containerPath: "/root/.nuget"}},
+		{arg: `java=C:\Users\me\.m2:/root/.m2`, want: providerVolume{provider: "java", hostPath: `C:\Users\me\.m2`, containerPath: "/root/.m2"}},
+		{arg: "/etc/pki:/etc/pki", wantErr: true},
+		{arg: "ruby=/gems:/gems", wantErr: true},
+		{arg: "java=/etc/pki", wantErr: true},
+		{arg: "java=/etc/pki:pki", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := parseProviderVolume(tt.arg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseProviderVolume() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parseProviderVolume() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_analyzeCommand_providerContainerVolumes(t *testing.T) {
+	dir := t.TempDir()
+	a := &analyzeCommand{providerVolumeArgs: []string{"java=" + dir + ":/etc/pki/extra:ro", "go=" + dir + ":/go/pkg/mod"}}
+	if err := a.validateProviderVolumes(); err != nil {
+		t.Fatal(err)
+	}
+	volumes := map[string]string{"source": SourceMountPath}
+	options := map[string]string{SourceMountPath: readOnlyMount}
+	gotVolumes, gotOptions := a.providerContainerVolumes(javaProvider, volumes, options)
+	if want := map[string]string{"source": SourceMountPath, dir: "/etc/pki/extra"}; !reflect.DeepEqual(gotVolumes, want) {
+		t.Errorf("providerContainerVolumes() volumes = %v, want %v", gotVolumes, want)
+	}
+	if want := map[string]string{SourceMountPath: readOnlyMount, "/etc/pki/extra": readOnlyMount}; !reflect.DeepEqual(gotOptions, want) {
+		t.Errorf("providerContainerVolumes() options = %v, want %v", gotOptions, want)
+	}
+	// the volumes of the other providers are not changed
+	if len(volumes) != 1 || len(options) != 1 {
+		t.Errorf("providerContainerVolumes() changed the shared volumes %v and options %v", volumes, options)
+	}
+	gotVolumes, _ = a.providerContainerVolumes(pythonProvider, volumes, options)
+	if !reflect.DeepEqual(gotVolumes, volumes) {
+		t.Errorf("providerContainerVolumes() of a provider without volumes = %v", gotVolumes)
+	}
+
+	a = &analyzeCommand{providerVolumeArgs: []string{"java=" + filepath.Join(dir, "missing") + ":/etc/pki/extra"}}
+	if err := a.validateProviderVolumes(); err == nil {
+		t.Error("validateProviderVolumes() of a missing host path succeeded")
+	}
+	a = &analyzeCommand{runLocal: true, providerVolumeArgs: []string{"java=" + dir + ":/etc/pki/extra"}}
+	if err := a.validateProviderVolumes(); err == nil {
+		t.Error("validateProviderVolumes() in containerless mode succeeded")
+	}
+}
@@ -1,8 +1,9 @@
 package cmd
 
 import (
-	"fmt"
 	"github.com/konveyor/analyzer-lsp/provider"
+
+	kantraprovider "github.com/konveyor-ecosystem/kantra/pkg/provider"
 )
 
 type PythonProvider struct {
@@ -10,23 +11,12 @@ type PythonProvider struct {
 }
 
 func (p *PythonProvider) GetConfigVolume(a *analyzeCommand, tmpDir string) (provider.Config, error) {
-	p.config = provider.Config{
-		Name:    pythonProvider,
-		Address: fmt.Sprintf("0.0.0.0:%v", a.providersMap[pythonProvider].port),
-		InitConfig: []provider.InitConfig{
-			{
-				AnalysisMode: provider.SourceOnlyAnalysisMode,
-				ProviderSpecificConfig: map[string]interface{}{
-					"lspServerName":                 "generic",
-					"workspaceFolders":              []string{fmt.Sprintf("file://%s", SourceMountPath)},
-					provider.LspServerPathConfigKey: "/usr/local/bin/pylsp",
-				},
-			},
-		},
-	}
 	_, dependencyFolders := a.getDepsFolders()
-	if len(dependencyFolders) != 0 {
-		p.config.InitConfig[0].ProviderSpecificConfig["dependencyFolders"] = dependencyFolders
-	}
+	p.config = kantraprovider.PythonConfig(kantraprovider.Options{
+		Mode:              kantraprovider.ContainerMode,
+		Location:          a.sourceMountPath(),
+		Port:              a.providersMap[pythonProvider].port,
+		DependencyFolders: dependencyFolders,
+	})
 	return p.config, nil
 }
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"gopkg.in/yaml.v2"
+)
+
+// defaultQualityGatesFile is read from the working dir when --gate is set
+// without --quality-gates
+const defaultQualityGatesFile = "quality-gates.yaml"
+
+// qualityGates are the named policies of a quality gates file
+type qualityGates struct {
+	Gates map[string]qualityGate `yaml:"gates"`
+}
+
+// qualityGate fails the analysis when its results exceed the thresholds of
+// the gate, thresholds not set are not checked
+type qualityGate struct {
+	Description string `yaml:"description,omitempty"`
+	// MinScore is the lowest readiness score
+	MinScore *int `yaml:"minScore,omitempty"`
+	// MaxIncidents is the most incidents of all rules
+	MaxIncidents *int `yaml:"maxIncidents,omitempty"`
+	// MaxEffort is the most effort of all incidents, the effort of a rule
+	// times its incidents
+	MaxEffort *int `yaml:"maxEffort,omitempty"`
+	// Categories are the most incidents by category, e.g. mandatory: 0
+	Categories map[string]int `yaml:"categories,omitempty"`
+	// Rules are the most incidents by rule, keys are rule IDs of any ruleset
+	// or <ruleset>/<rule ID>
+	Rules map[string]int `yaml:"rules,omitempty"`
+	// FailOnLicense fails on dependencies with denied or not allowed licenses
+	FailOnLicense bool `yaml:"failOnLicense,omitempty"`
+}
+
+// loadQualityGate reads the gate of the given name from a quality gates file
+func loadQualityGate(path string, name string) (qualityGate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return qualityGate{}, err
+	}
+	gates := qualityGates{}
+	if err := yaml.UnmarshalStrict(data, &gates); err != nil {
+		return qualityGate{}, err
+	}
+	gate, ok := gates.Gates[name]
+	if !ok {
+		return qualityGate{}, fmt.Errorf("quality gate %s not found in %s, must be one of %s",
+			name, path, strings.Join(sortedMapKeys(gates.Gates), ", "))
+	}
+	if gate.MinScore != nil && (*gate.MinScore < 0 || *gate.MinScore > 100) {
+		return gate, fmt.Errorf("minScore of quality gate %s must be between 0 and 100", name)
+	}
+	if (gate.MaxIncidents != nil && *gate.MaxIncidents < 0) || (gate.MaxEffort != nil && *gate.MaxEffort < 0) {
+		return gate, fmt.Errorf("maxIncidents and maxEffort of quality gate %s must not be negative", name)
+	}
+	for _, limits := range []map[string]int{gate.Categories, gate.Rules} {
+		for key, limit := range limits {
+			if limit < 0 {
+				return gate, fmt.Errorf("limit of %s in quality gate %s must not be negative", key, name)
+			}
+		}
+	}
+	return gate, nil
+}
+
+// validateQualityGate loads the gate selected with --gate
+func (a *analyzeCommand) validateQualityGate() error {
+	if a.gate == "" {
+		if a.qualityGatesFile != "" {
+			return fmt.Errorf("quality-gates requires a gate, set --gate")
+		}
+		return nil
+	}
+	if a.failOnScore > 0 || a.failOnLicense {
+		return fmt.Errorf("must not specify gate and fail-on-score or fail-on-license, set minScore and failOnLicense in the gate")
+	}
+	path := a.qualityGatesFile
+	if path == "" {
+		path = defaultQualityGatesFile
+	}
+	gate, err := loadQualityGate(path, a.gate)
+	if err != nil {
+		return fmt.Errorf("%w failed to load quality gate %s", err, a.gate)
+	}
+	a.qualityGate = &gate
+	if gate.FailOnLicense {
+		a.licenseReport = true
+	}
+	return nil
+}
+
+// check returns the thresholds of the gate the analysis exceeds
+func (g qualityGate) check(summary *analysisSummary) []string {
+	failures := []string{}
+	if g.MinScore != nil && summary.Score < *g.MinScore {
+		failures = append(failures, fmt.Sprintf("readiness score %d is below %d", summary.Score, *g.MinScore))
+	}
+	if g.MaxIncidents != nil && summary.Incidents > *g.MaxIncidents {
+		failures = append(failures, fmt.Sprintf("%d incidents exceed %d", summary.Incidents, *g.MaxIncidents))
+	}
+	if g.FailOnLicense && summary.LicenseViolations > 0 {
+		failures = append(failures, fmt.Sprintf("%d dependencies have denied or not allowed licenses", summary.LicenseViolations))
+	}
+	effort := 0
+	categories := map[string]int{}
+	rules := map[string]int{}
+	for _, rs := range summary.rulesets {
+		for ruleID, violation := range rs.Violations {
+			incidents := len(violation.Incidents)
+			if violation.Effort != nil {
+				effort += *violation.Effort * incidents
+			}
+			category := string(outputv1.Optional)
+			if violation.Category != nil {
+				category = string(*violation.Category)
+			}
+			categories[category] += incidents
+			rules[ruleID] += incidents
+			rules[rs.Name+"/"+ruleID] += incidents
+		}
+	}
+	if g.MaxEffort != nil && effort > *g.MaxEffort {
+		failures = append(failures, fmt.Sprintf("effort %d exceeds %d", effort, *g.MaxEffort))
+	}
+	for _, category := range sortedMapKeys(g.Categories) {
+		if categories[category] > g.Categories[category] {
+			failures = append(failures, fmt.Sprintf("%d %s incidents exceed %d", categories[category], category, g.Categories[category]))
+		}
+	}
+	for _, rule := range sortedMapKeys(g.Rules) {
+		if rules[rule] > g.Rules[rule] {
+			failures = append(failures, fmt.Sprintf("%d incidents of rule %s exceed %d", rules[rule], rule, g.Rules[rule]))
+		}
+	}
+	return failures
+}
+
+// checkQualityGate fails when the analysis does not pass the gate of --gate
+func (a *analyzeCommand) checkQualityGate(summary *analysisSummary) error {
+	if a.qualityGate == nil {
+		return nil
+	}
+	if summary == nil {
+		return fmt.Errorf("unable to check quality gate %s without the analysis output", a.gate)
+	}
+	failures := a.qualityGate.check(summary)
+	if len(failures) > 0 {
+		return fmt.Errorf("quality gate %s failed: %s", a.gate, strings.Join(failures, "; "))
+	}
+	a.log.Info("passed quality gate", "gate", a.gate)
+	return nil
+}
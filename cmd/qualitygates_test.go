@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func Test_qualityGate_check(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quality-gates.yaml")
+	writeTestFile(t, path, `gates:
+  strict:
+    minScore: 80
+    maxIncidents: 0
+  migration-phase-1:
+    description: no mandatory changes left
+    maxEffort: 10
+    categories:
+      mandatory: 0
+    rules:
+      quarkus/rule-001: 1
+`)
+	if _, err := loadQualityGate(path, "relaxed"); err == nil {
+		t.Errorf("loadQualityGate() expected error for missing gate")
+	}
+	gate, err := loadQualityGate(path, "migration-phase-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mandatory := outputv1.Mandatory
+	effort := 3
+	summary := &analysisSummary{
+		Incidents: 3,
+		Score:     70,
+		rulesets: []outputv1.RuleSet{
+			{
+				Name: "quarkus",
+				Violations: map[string]outputv1.Violation{
+					"rule-000": {Category: &mandatory, Effort: &effort, Incidents: make([]outputv1.Incident, 1)},
+					"rule-001": {Effort: &effort, Incidents: make([]outputv1.Incident, 2)},
+				},
+			},
+		},
+	}
+	// effort of 9 is within the gate
+	want := []string{
+		"1 mandatory incidents exceed 0",
+		"2 incidents of rule quarkus/rule-001 exceed 1",
+	}
+	if got := gate.check(summary); !reflect.DeepEqual(got, want) {
+		t.Errorf("check() = %v, want %v", got, want)
+	}
+
+	a := &analyzeCommand{gate: "strict", qualityGatesFile: path}
+	if err := a.validateQualityGate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.checkQualityGate(summary); err == nil {
+		t.Errorf("checkQualityGate() expected error for score 70 and 3 incidents")
+	}
+	if err := a.checkQualityGate(&analysisSummary{Score: 90}); err != nil {
+		t.Errorf("checkQualityGate() unexpected error %v", err)
+	}
+	a = &analyzeCommand{gate: "strict", qualityGatesFile: path, failOnScore: 50}
+	if err := a.validateQualityGate(); err == nil {
+		t.Errorf("validateQualityGate() expected error for gate and fail-on-score")
+	}
+}
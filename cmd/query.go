@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/itchyny/gojq"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// queries of the common questions about an analysis output
+var queryShortcuts = map[string]string{
+	"incidents-by-rule": `[.[] | .name as $ruleset | (.violations // {}) | to_entries[] |
+		{ruleset: $ruleset, rule: .key, category: .value.category, incidents: (.value.incidents // [] | length)}] |
+		sort_by(-.incidents)`,
+	"files-by-incidents": `[.[] | (.violations // {})[] | (.incidents // [])[] | .uri] |
+		group_by(.) | map({file: .[0], incidents: length}) | sort_by(-.incidents)`,
+	"incidents-by-category": `[.[] | (.violations // {})[] | {category: (.category // "none"), incidents: (.incidents // [] | length)}] |
+		group_by(.category) | map({category: .[0].category, incidents: (map(.incidents) | add)}) | sort_by(-.incidents)`,
+	"tags": `[.[] | (.tags // [])[]] | unique`,
+	"effort-by-rule": `[.[] | .name as $ruleset | (.violations // {}) | to_entries[] |
+		{ruleset: $ruleset, rule: .key, effort: ((.value.effort // 0) * (.value.incidents // [] | length))}] |
+		sort_by(-.effort)`,
+}
+
+type queryCommand struct {
+	input    string
+	shortcut string
+	format   string
+	limit    int
+	stream   bool
+	log      logr.Logger
+}
+
+func NewQueryCommand(log logr.Logger) *cobra.Command {
+	queryCmd := &queryCommand{log: log}
+	queryCommand := &cobra.Command{
+		Use:   "query [expression]",
+		Short: "Query analysis or dependency output with jq expressions",
+		Long: "Query analysis or dependency output with jq expressions, e.g.\n" +
+			"  kantra query '.[] | select(.violations) | .name' -i out/output.yaml\n" +
+			"or with a shortcut for common questions, e.g.\n" +
+			"  kantra query --shortcut files-by-incidents --limit 10 -i out",
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && queryCmd.shortcut == "" {
+				return fmt.Errorf("an expression or --shortcut is required")
+			}
+			if len(args) > 0 && queryCmd.shortcut != "" {
+				return fmt.Errorf("must not specify both an expression and --shortcut")
+			}
+			if queryCmd.stream && queryCmd.shortcut != "" {
+				return fmt.Errorf("shortcuts query the whole output, they do not support --stream")
+			}
+			switch queryCmd.format {
+			case listFormatJSON, listFormatYAML:
+			default:
+				return fmt.Errorf("format must be one of 'json' or 'yaml'")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			expression := ""
+			if len(args) > 0 {
+				expression = args[0]
+			}
+			if err := queryCmd.Run(cmd.Context(), expression, os.Stdout); err != nil {
+				log.Error(err, "failed to query output")
+				return err
+			}
+			return nil
+		},
+	}
+	queryCommand.Flags().StringVarP(&queryCmd.input, "input", "i", "", "path to an output file or the output dir of an analysis, its output.yaml is queried")
+	queryCommand.Flags().StringVar(&queryCmd.shortcut, "shortcut", "", fmt.Sprintf("query of a common question instead of an expression, one of %s", strings.Join(queryShortcutNames(), ", ")))
+	queryCommand.Flags().StringVar(&queryCmd.format, "format", listFormatJSON, "output format. Must be one of 'json' or 'yaml'")
+	queryCommand.Flags().IntVar(&queryCmd.limit, "limit", 0, "limit arrays in the results to their first entries, e.g. the top 10 files of a shortcut")
+	queryCommand.Flags().BoolVar(&queryCmd.stream, "stream", false, "run the expression on each incident of the incidents.jsonl written by analyze --incident-store, one at a time, so that very large outputs are not loaded into memory")
+	queryCommand.MarkFlagRequired("input")
+	return queryCommand
+}
+
+func queryShortcutNames() []string {
+	names := []string{}
+	for name := range queryShortcuts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// readQueryInput reads a yaml or json output file into values of the types
+// supported by gojq
+func readQueryInput(path string) (interface{}, error) {
+	if stat, err := os.Stat(path); err == nil && stat.IsDir() {
+		path = filepath.Join(path, "output.yaml")
+	}
+	data, err := readOutputFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	// json output is valid yaml
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("%w failed to parse %s", err, path)
+	}
+	return normalizeQueryValue(v), nil
+}
+
+// normalizeQueryValue converts decoded yaml to the types of decoded json
+func normalizeQueryValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			v[k] = normalizeQueryValue(value)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, value := range v {
+			m[fmt.Sprint(k)] = normalizeQueryValue(value)
+		}
+		return m
+	case []interface{}:
+		for i, value := range v {
+			v[i] = normalizeQueryValue(value)
+		}
+		return v
+	case nil, string, bool, int, float64:
+		return v
+	case uint64:
+		return float64(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func compileQuery(expression string) (*gojq.Code, error) {
+	query, err := gojq.Parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("%w failed to parse query", err)
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, fmt.Errorf("%w failed to compile query", err)
+	}
+	return code, nil
+}
+
+// runQuery returns the results of the jq expression on input
+func runQuery(ctx context.Context, expression string, input interface{}) ([]interface{}, error) {
+	code, err := compileQuery(expression)
+	if err != nil {
+		return nil, err
+	}
+	return runCompiledQuery(ctx, code, input)
+}
+
+func runCompiledQuery(ctx context.Context, code *gojq.Code, input interface{}) ([]interface{}, error) {
+	results := []interface{}{}
+	iter := code.RunWithContext(ctx, input)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return results, nil
+		}
+		if err, ok := v.(error); ok {
+			return nil, err
+		}
+		results = append(results, v)
+	}
+}
+
+func limitQueryResult(v interface{}, limit int) interface{} {
+	if values, ok := v.([]interface{}); ok && limit > 0 && len(values) > limit {
+		return values[:limit]
+	}
+	return v
+}
+
+func writeQueryResult(out io.Writer, format string, v interface{}) error {
+	if format == listFormatYAML {
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "---\n%s", data)
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
+
+// Run queries the output with the expression or the shortcut and writes
+// each result
+func (q *queryCommand) Run(ctx context.Context, expression string, out io.Writer) error {
+	if q.shortcut != "" {
+		var ok bool
+		if expression, ok = queryShortcuts[q.shortcut]; !ok {
+			return fmt.Errorf("unknown shortcut %s, must be one of %s", q.shortcut, strings.Join(queryShortcutNames(), ", "))
+		}
+	}
+	if q.stream {
+		return q.runStream(ctx, expression, out)
+	}
+	input, err := readQueryInput(q.input)
+	if err != nil {
+		return err
+	}
+	q.log.V(5).Info("running query", "expression", expression, "input", q.input)
+	results, err := runQuery(ctx, expression, input)
+	if err != nil {
+		return err
+	}
+	for _, v := range results {
+		if err := writeQueryResult(out, q.format, limitQueryResult(v, q.limit)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runStream runs the expression on each incident of an incident store and
+// writes the results as they come
+func (q *queryCommand) runStream(ctx context.Context, expression string, out io.Writer) error {
+	code, err := compileQuery(expression)
+	if err != nil {
+		return err
+	}
+	path := q.input
+	if stat, err := os.Stat(path); err == nil && stat.IsDir() {
+		path = filepath.Join(path, incidentStoreFile)
+	}
+	q.log.V(5).Info("running query on incidents", "expression", expression, "input", path)
+	return readIncidentStore(path, func(line []byte) error {
+		var incident interface{}
+		if err := json.Unmarshal(line, &incident); err != nil {
+			return fmt.Errorf("%w failed to parse incident of %s", err, path)
+		}
+		results, err := runCompiledQuery(ctx, code, incident)
+		if err != nil {
+			return err
+		}
+		for _, v := range results {
+			if err := writeQueryResult(out, q.format, limitQueryResult(v, q.limit)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
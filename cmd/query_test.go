@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+const queryTestOutput = `- name: eap8
+  violations:
+    rule-1:
+      category: mandatory
+      effort: 3
+      incidents:
+      - uri: file:///src/A.java
+      - uri: file:///src/B.java
+    rule-2:
+      category: optional
+      effort: 1
+      incidents:
+      - uri: file:///src/A.java
+- name: empty
+`
+
+func Test_queryCommand_Run(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "output.yaml"), queryTestOutput)
+	tests := []struct {
+		name       string
+		expression string
+		shortcut   string
+		limit      int
+		want       string
+	}{
+		{
+			name:       "expression",
+			expression: `[.[] | select(.violations) | .name]`,
+			want:       `["eap8"]`,
+		},
+		{
+			name:     "incidents by rule",
+			shortcut: "incidents-by-rule",
+			want: `[{"category":"mandatory","incidents":2,"rule":"rule-1","ruleset":"eap8"},` +
+				`{"category":"optional","incidents":1,"rule":"rule-2","ruleset":"eap8"}]`,
+		},
+		{
+			name:     "files by incidents",
+			shortcut: "files-by-incidents",
+			limit:    1,
+			want:     `[{"file":"file:///src/A.java","incidents":2}]`,
+		},
+		{
+			name:     "effort by rule",
+			shortcut: "effort-by-rule",
+			limit:    1,
+			want:     `[{"effort":6,"rule":"rule-1","ruleset":"eap8"}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &queryCommand{input: dir, shortcut: tt.shortcut, format: listFormatJSON, limit: tt.limit, log: logr.Discard()}
+			out := &bytes.Buffer{}
+			if err := q.Run(context.Background(), tt.expression, out); err != nil {
+				t.Fatal(err)
+			}
+			compact := &bytes.Buffer{}
+			if err := json.Compact(compact, out.Bytes()); err != nil {
+				t.Fatal(err)
+			}
+			if compact.String() != tt.want {
+				t.Errorf("Run() = %s, want %s", compact, tt.want)
+			}
+		})
+	}
+
+	q := &queryCommand{input: filepath.Join(dir, "output.yaml"), format: listFormatYAML, log: logr.Discard()}
+	out := &bytes.Buffer{}
+	if err := q.Run(context.Background(), ".[].name", out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "---\neap8\n---\nempty\n" {
+		t.Errorf("Run() yaml = %q", out)
+	}
+	q = &queryCommand{input: dir, shortcut: "unknown", log: logr.Discard()}
+	if err := q.Run(context.Background(), "", out); err == nil || !strings.Contains(err.Error(), "incidents-by-rule") {
+		t.Errorf("Run() with an unknown shortcut = %v, want an error listing the shortcuts", err)
+	}
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// name of the maven settings among the files of --registry-auth-file
+const mavenSettingsName = "settings.xml"
+
+// where provider containers find the files of --registry-auth-file
+var RegistryAuthMountPath = path.Join(InputPath, "registry-auth")
+
+// environment variables pointing package managers to their credentials, by
+// name of the credentials file. Maven settings are passed to the java
+// provider instead.
+var registryAuthEnv = map[string]string{
+	".npmrc":   "NPM_CONFIG_USERCONFIG",
+	"npmrc":    "NPM_CONFIG_USERCONFIG",
+	"pip.conf": "PIP_CONFIG_FILE",
+	"pip.ini":  "PIP_CONFIG_FILE",
+}
+
+// registryAuthTempDir returns the dir holding copies of the credentials,
+// the runtime dir of the user is a tmpfs on most linux hosts
+func registryAuthTempDir() string {
+	return os.Getenv("XDG_RUNTIME_DIR")
+}
+
+// writeRegistryAuth copies the registry credentials of --registry-auth-file
+// into a private dir, mounted read-only into the provider containers
+func (a *analyzeCommand) writeRegistryAuth() error {
+	if len(a.registryAuthFiles) == 0 {
+		return nil
+	}
+	seen := map[string]string{}
+	for _, file := range a.registryAuthFiles {
+		name := filepath.Base(file)
+		kind, ok := registryAuthEnv[name]
+		if name == mavenSettingsName {
+			if a.mavenSettingsFile != "" {
+				return fmt.Errorf("must not specify maven settings in both registry-auth-file and maven-settings or maven-mirror")
+			}
+			kind, ok = mavenSettingsName, true
+		}
+		if !ok {
+			return fmt.Errorf("unsupported registry auth file %s, must be one of settings.xml, .npmrc or pip.conf", file)
+		}
+		if other, ok := seen[kind]; ok {
+			return fmt.Errorf("registry auth files %s and %s configure the same package manager", other, file)
+		}
+		seen[kind] = file
+		if _, err := os.Stat(file); err != nil {
+			return fmt.Errorf("%w failed to stat registry auth file at path %s", err, file)
+		}
+	}
+	dir, err := os.MkdirTemp(registryAuthTempDir(), "registry-auth-")
+	if err != nil {
+		return err
+	}
+	a.registryAuthDir = dir
+	for _, file := range a.registryAuthFiles {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(dir, filepath.Base(file))
+		if err := os.WriteFile(dst, content, 0600); err != nil {
+			return err
+		}
+		if filepath.Base(file) == mavenSettingsName {
+			a.mavenSettingsFile = dst
+		}
+	}
+	a.log.V(1).Info("copied registry credentials", "dir", dir)
+	if a.runLocal {
+		// providers run on the host and inherit the environment
+		for name, file := range a.registryAuthEnvVars() {
+			if err := os.Setenv(name, filepath.Join(dir, file)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// registryAuthEnvVars returns the environment variables pointing package
+// managers to their credentials file
+func (a *analyzeCommand) registryAuthEnvVars() map[string]string {
+	env := map[string]string{}
+	for _, file := range a.registryAuthFiles {
+		name := filepath.Base(file)
+		if v, ok := registryAuthEnv[name]; ok {
+			env[v] = name
+		}
+	}
+	return env
+}
+
+// registryAuthVolumes returns the volume and environment of the registry
+// credentials in provider containers
+func (a *analyzeCommand) registryAuthVolumes() (map[string]string, map[string]string) {
+	if a.registryAuthDir == "" {
+		return nil, nil
+	}
+	env := map[string]string{}
+	for name, file := range a.registryAuthEnvVars() {
+		env[name] = path.Join(RegistryAuthMountPath, file)
+	}
+	return map[string]string{a.registryAuthDir: RegistryAuthMountPath}, env
+}
+
+// isRegistryAuthMavenSettings returns whether the maven settings are the
+// credentials of --registry-auth-file, which are mounted rather than copied
+func (a *analyzeCommand) isRegistryAuthMavenSettings() bool {
+	return a.registryAuthDir != "" && filepath.Dir(a.mavenSettingsFile) == a.registryAuthDir
+}
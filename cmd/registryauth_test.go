@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_analyzeCommand_writeRegistryAuth(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	npmrc := filepath.Join(dir, ".npmrc")
+	writeTestFile(t, npmrc, "//npm.example.com/:_authToken=secret\n")
+	settings := filepath.Join(dir, "settings.xml")
+	writeTestFile(t, settings, "<settings></settings>\n")
+
+	a := &analyzeCommand{log: logr.Discard(), registryAuthFiles: []string{npmrc, settings}}
+	if err := a.writeRegistryAuth(); err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(a.registryAuthDir) != os.Getenv("XDG_RUNTIME_DIR") {
+		t.Errorf("writeRegistryAuth() dir = %s, want it in the runtime dir", a.registryAuthDir)
+	}
+	info, err := os.Stat(filepath.Join(a.registryAuthDir, ".npmrc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("writeRegistryAuth() mode = %v, want 0600", info.Mode().Perm())
+	}
+	if !a.isRegistryAuthMavenSettings() {
+		t.Errorf("writeRegistryAuth() maven settings = %s, want the copy in %s", a.mavenSettingsFile, a.registryAuthDir)
+	}
+	volumes, env := a.registryAuthVolumes()
+	if volumes[a.registryAuthDir] != RegistryAuthMountPath {
+		t.Errorf("registryAuthVolumes() volumes = %v", volumes)
+	}
+	if want := path.Join(RegistryAuthMountPath, ".npmrc"); env["NPM_CONFIG_USERCONFIG"] != want || len(env) != 1 {
+		t.Errorf("registryAuthVolumes() env = %v, want NPM_CONFIG_USERCONFIG=%s", env, want)
+	}
+
+	for name, a := range map[string]*analyzeCommand{
+		"unsupported file":   {registryAuthFiles: []string{filepath.Join(dir, "credentials")}},
+		"duplicate manager":  {registryAuthFiles: []string{npmrc, filepath.Join(dir, "npmrc")}},
+		"maven settings set": {registryAuthFiles: []string{settings}, mavenSettingsFile: settings},
+		"missing file":       {registryAuthFiles: []string{filepath.Join(dir, "pip.conf")}},
+	} {
+		a.log = logr.Discard()
+		if err := a.writeRegistryAuth(); err == nil {
+			t.Errorf("writeRegistryAuth() with %s must fail", name)
+		}
+	}
+}
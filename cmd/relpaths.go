@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+)
+
+// inputRoots returns the paths the input is known by in incident URIs, on
+// the host and in the provider containers
+func (a *analyzeCommand) inputRoots() []string {
+	inputs := []string{a.input}
+	// incidents in a snapshot are rewritten to the input, see unsnapshotURIs
+	if a.originalInput != "" {
+		inputs = append(inputs, a.originalInput)
+	}
+	mountPath := a.sourceMountPath()
+	if a.isFileInput {
+		mountPath = path.Dir(mountPath)
+	}
+	roots := []string{}
+	for _, input := range inputs {
+		if a.isFileInput {
+			input = filepath.Dir(input)
+		}
+		roots = append(roots, filepath.ToSlash(input))
+		if real, err := filepath.EvalSymlinks(input); err == nil && real != input {
+			roots = append(roots, filepath.ToSlash(real))
+		}
+	}
+	if !a.runLocal {
+		roots = append(roots, mountPath)
+	}
+	return roots
+}
+
+// relativeURI returns the path of a file URI relative to one of roots,
+// URIs outside of them are returned unchanged
+func relativeURI(u uri.URI, roots []string) uri.URI {
+	if !strings.HasPrefix(string(u), "file:") {
+		return u
+	}
+	p := filepath.ToSlash(u.Filename())
+	for _, root := range roots {
+		root = strings.TrimSuffix(root, "/")
+		if p == root {
+			return "."
+		}
+		if rel, ok := strings.CutPrefix(p, root+"/"); ok {
+			return uri.URI(rel)
+		}
+	}
+	return u
+}
+
+// relativizeURIs rewrites URIs of incidents in the input relative to it
+func (a *analyzeCommand) relativizeURIs(rulesets []outputv1.RuleSet) {
+	roots := a.inputRoots()
+	for i := range rulesets {
+		for ruleID, violation := range rulesets[i].Violations {
+			for j := range violation.Incidents {
+				violation.Incidents[j].URI = relativeURI(violation.Incidents[j].URI, roots)
+			}
+			rulesets[i].Violations[ruleID] = violation
+		}
+	}
+}
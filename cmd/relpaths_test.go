@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+)
+
+func Test_analyzeCommand_relativizeURIs(t *testing.T) {
+	input := t.TempDir()
+	a := &analyzeCommand{input: input}
+	rulesets := []outputv1.RuleSet{
+		{
+			Name: "test",
+			Violations: map[string]outputv1.Violation{
+				"rule-00010": {
+					Incidents: []outputv1.Incident{
+						{URI: uri.File(filepath.Join(input, "src", "Foo.java"))},
+						{URI: uri.File(filepath.Join(filepath.Dir(input), "other", "Bar.java"))},
+						{URI: "konveyor-jdt://contents/lib.jar/Baz.class"},
+					},
+				},
+			},
+		},
+	}
+	a.relativizeURIs(rulesets)
+	incidents := rulesets[0].Violations["rule-00010"].Incidents
+	if incidents[0].URI != "src/Foo.java" {
+		t.Errorf("URI of incident in the input = %s, want src/Foo.java", incidents[0].URI)
+	}
+	if incidents[1].URI != uri.File(filepath.Join(filepath.Dir(input), "other", "Bar.java")) {
+		t.Errorf("URI of incident outside of the input was changed to %s", incidents[1].URI)
+	}
+	if incidents[2].URI != "konveyor-jdt://contents/lib.jar/Baz.class" {
+		t.Errorf("URI that is not a file was changed to %s", incidents[2].URI)
+	}
+
+	// container mode reports files under the mount path
+	a = &analyzeCommand{input: input}
+	got := relativeURI(uri.File(SourceMountPath+"/pom.xml"), a.inputRoots())
+	if got != "pom.xml" {
+		t.Errorf("relativeURI() of container path = %s, want pom.xml", got)
+	}
+}
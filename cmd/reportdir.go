@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.lsp.dev/uri"
+)
+
+// page of the static report, loading its data from output.js
+const defaultReportName = "index.html"
+
+func validateReportName(name string) error {
+	if name == "" || filepath.Base(name) != name || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("report name %s must be a file name, e.g. coolstore.html", name)
+	}
+	if filepath.Ext(name) != ".html" {
+		return fmt.Errorf("report name %s must end with .html", name)
+	}
+	return nil
+}
+
+// validateReportLocation checks the flags of the location of the static report
+func (a *analyzeCommand) validateReportLocation() error {
+	if a.skipStaticReport && (a.staticReportDir != "" || a.reportName != defaultReportName) {
+		return fmt.Errorf("must not specify both skip-static-report and static-report-dir or report-name")
+	}
+	if err := validateReportName(a.reportName); err != nil {
+		return err
+	}
+	if a.staticReportDir == "" {
+		return nil
+	}
+	stat, err := os.Stat(a.staticReportDir)
+	if err == nil && !stat.IsDir() {
+		return fmt.Errorf("static report dir %s is not a directory", a.staticReportDir)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w failed to stat static report dir %s", err, a.staticReportDir)
+	}
+	if absPath, err := filepath.Abs(a.staticReportDir); err == nil {
+		a.staticReportDir = absPath
+	}
+	return nil
+}
+
+// reportDir returns the dir of the static report once published
+func (a *analyzeCommand) reportDir() string {
+	if a.staticReportDir != "" {
+		return a.staticReportDir
+	}
+	return filepath.Join(a.output, "static-report")
+}
+
+// publishStaticReport moves the static report generated in the output dir
+// to the static report dir, with its page named by the report name. The
+// data of a renamed report is named after it, so that reports of several
+// analyses can share a dir, e.g. of a docs site.
+func (a *analyzeCommand) publishStaticReport() error {
+	if a.skipStaticReport {
+		return nil
+	}
+	generated := filepath.Join(a.output, "static-report")
+	dir := a.reportDir()
+	if dir != generated {
+		if err := copyFolderContents(generated, dir); err != nil {
+			return fmt.Errorf("%w failed to copy static report to %s", err, dir)
+		}
+		if err := os.RemoveAll(generated); err != nil {
+			return err
+		}
+	}
+	if a.reportName != defaultReportName {
+		if err := renameReport(dir, a.reportName); err != nil {
+			return fmt.Errorf("%w failed to rename static report to %s", err, a.reportName)
+		}
+	}
+	uri := uri.File(filepath.Join(dir, a.reportName))
+	a.log.Info("Static report created. Access it at this URL:", "URL", string(uri))
+	return nil
+}
+
+// renameReport renames the page of the report in dir and its data files,
+// e.g. output.js to coolstore.js for a report named coolstore.html
+func renameReport(dir string, name string) error {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	page, err := os.ReadFile(filepath.Join(dir, defaultReportName))
+	if err != nil {
+		return err
+	}
+	for data, renamed := range map[string]string{
+		"output.js":     base + ".js",
+		searchIndexFile: base + "-" + searchIndexFile,
+		depsTreePage:    base + "-" + depsTreePage,
+		licensesPage:    base + "-" + licensesPage,
+	} {
+		err := os.Rename(filepath.Join(dir, data), filepath.Join(dir, renamed))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		page = bytes.ReplaceAll(page, []byte(fmt.Sprintf(`src="%s"`, data)), []byte(fmt.Sprintf(`src="%s"`, renamed)))
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), page, 0644); err != nil {
+		return err
+	}
+	return os.Remove(filepath.Join(dir, defaultReportName))
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_publishStaticReport(t *testing.T) {
+	output := t.TempDir()
+	docs := filepath.Join(t.TempDir(), "docs")
+	generated := filepath.Join(output, "static-report")
+	writeTestFile(t, filepath.Join(generated, "index.html"), `<script src="output.js"></script><script src="search-index.js"></script>`)
+	writeTestFile(t, filepath.Join(generated, "output.js"), `window["apps"] = []`)
+	writeTestFile(t, filepath.Join(generated, searchIndexFile), `window["searchIndex"] = {}`)
+	writeTestFile(t, filepath.Join(generated, "static", "js", "main.js"), "")
+	// report of another analysis in the docs
+	writeTestFile(t, filepath.Join(docs, "inventory.html"), "")
+
+	a := &analyzeCommand{log: logr.Discard(), output: output, staticReportDir: docs, reportName: "coolstore.html"}
+	if err := a.publishStaticReport(); err != nil {
+		t.Fatal(err)
+	}
+	page, err := os.ReadFile(filepath.Join(docs, "coolstore.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `<script src="coolstore.js"></script><script src="coolstore-search-index.js"></script>`; string(page) != want {
+		t.Errorf("publishStaticReport() page = %s, want %s", page, want)
+	}
+	for _, file := range []string{"coolstore.js", "coolstore-search-index.js", "static/js/main.js", "inventory.html"} {
+		if _, err := os.Stat(filepath.Join(docs, file)); err != nil {
+			t.Errorf("publishStaticReport() %s: %v", file, err)
+		}
+	}
+	for _, file := range []string{filepath.Join(docs, "index.html"), filepath.Join(docs, "output.js"), generated} {
+		if _, err := os.Stat(file); !os.IsNotExist(err) {
+			t.Errorf("publishStaticReport() left %s", file)
+		}
+	}
+}
+
+func Test_validateReportName(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"coolstore.html":    true,
+		"index.html":        true,
+		"":                  false,
+		"reports/app.html":  false,
+		"coolstore.json":    false,
+		`..\coolstore.html`: false,
+	} {
+		if err := validateReportName(name); (err == nil) != valid {
+			t.Errorf("validateReportName(%q) error = %v, want valid %v", name, err, valid)
+		}
+	}
+}
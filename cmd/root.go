@@ -5,10 +5,12 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
 
 	"github.com/bombsimon/logrusr/v3"
+	"github.com/konveyor-ecosystem/kantra/cmd/internal/i18n"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -48,6 +50,20 @@ func init() {
 	rootCmd.AddCommand(NewAnalyzeCmd(logger))
 	rootCmd.AddCommand(NewTestCommand(logger))
 	rootCmd.AddCommand(NewVersionCommand())
+	rootCmd.AddCommand(NewCapabilitiesCommand())
+	rootCmd.AddCommand(NewCacheCommand(logger))
+	rootCmd.AddCommand(NewBootstrapCommand(logger))
+	rootCmd.AddCommand(NewDependenciesCommand(logger))
+	rootCmd.AddCommand(NewQueryCommand(logger))
+	rootCmd.AddCommand(NewExplainCommand(logger))
+	rootCmd.AddCommand(NewFeedbackCommand(logger))
+	rootCmd.AddCommand(NewExportCommand(logger))
+	rootCmd.AddCommand(NewGenerateCommand(logger))
+	rootCmd.AddCommand(NewDiscoverCommand(logger))
+	rootCmd.AddCommand(NewRulesCommand(logger))
+	rootCmd.AddCommand(NewProfileCommand(logger))
+	rootCmd.AddCommand(NewSelfTestCommand(logger))
+	rootCmd.AddCommand(NewVerifyOutputCommand(logger))
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -58,13 +74,32 @@ func Execute() {
 		log.Fatal(err, "failed to load global settings")
 		os.Exit(1)
 	}
+	i18n.SetLanguage(Settings.Language)
 
 	ctx, cancelFunc := context.WithCancel(context.Background())
 	defer cancelFunc()
 
 	rootCmd.Use = Settings.RootCommandName
 	err = rootCmd.ExecuteContext(ctx)
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.code)
+	}
 	if err != nil {
 		os.Exit(1)
 	}
 }
+
+// exitError is an error exiting kantra with a specific code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
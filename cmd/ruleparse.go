@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/konveyor/analyzer-lsp/engine"
+	"github.com/konveyor/analyzer-lsp/parser"
+	"github.com/konveyor/analyzer-lsp/provider"
+)
+
+// rulesets parsed at once, parsing is mostly yaml decoding and regex
+// compilation
+var ruleParseWorkers = min(runtime.NumCPU(), 8)
+
+// ruleParseJobs splits the rules paths into the paths parsed in parallel.
+// Dirs without ruleset.yaml only hold rulesets in subdirs, the parser
+// ignores their files, so each subdir is parsed on its own.
+func ruleParseJobs(paths []string) []string {
+	jobs := []string{}
+	for _, p := range paths {
+		entries, err := os.ReadDir(p)
+		// files and unreadable dirs are reported by the parser
+		if err != nil {
+			jobs = append(jobs, p)
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(p, parser.RULE_SET_GOLDEN_FILE_NAME)); err == nil {
+			jobs = append(jobs, p)
+			continue
+		}
+		subdirs := []string{}
+		for _, entry := range entries {
+			if stat, err := os.Stat(filepath.Join(p, entry.Name())); err == nil && stat.IsDir() {
+				subdirs = append(subdirs, filepath.Join(p, entry.Name()))
+			}
+		}
+		jobs = append(jobs, ruleParseJobs(subdirs)...)
+	}
+	return jobs
+}
+
+// parseRules parses the rulesets of the rules paths with a bounded number of
+// goroutines. Rulesets are returned in the order of the paths and of their
+// subdirs, as when parsed one after the other, so that the output is the
+// same across runs. Paths failing to parse are logged and their rulesets
+// parsed without errors are kept.
+func parseRules(log logr.Logger, ruleParser *parser.RuleParser, paths []string) ([]engine.RuleSet, map[string]provider.InternalProviderClient) {
+	jobs := ruleParseJobs(paths)
+	results := make([][]engine.RuleSet, len(jobs))
+	clients := make([]map[string]provider.InternalProviderClient, len(jobs))
+	// the parser shares the ruleset of rule files without ruleset.yaml
+	fileLock := sync.Mutex{}
+	sem := make(chan struct{}, max(ruleParseWorkers, 1))
+	wg := sync.WaitGroup{}
+	for i, job := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, job string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if stat, err := os.Stat(job); err == nil && !stat.IsDir() {
+				fileLock.Lock()
+				defer fileLock.Unlock()
+			}
+			ruleSets, needProviders, err := ruleParser.LoadRules(job)
+			if err != nil {
+				log.Error(err, "unable to parse all the rules for ruleset", "file", job)
+			}
+			results[i], clients[i] = ruleSets, needProviders
+		}(i, job)
+	}
+	wg.Wait()
+	ruleSets := []engine.RuleSet{}
+	needProviders := map[string]provider.InternalProviderClient{}
+	for i := range jobs {
+		ruleSets = append(ruleSets, results[i]...)
+		for k, v := range clients[i] {
+			needProviders[k] = v
+		}
+	}
+	return ruleSets, needProviders
+}
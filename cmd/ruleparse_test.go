@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_ruleParseJobs(t *testing.T) {
+	dir := t.TempDir()
+	defaults := filepath.Join(dir, "rulesets")
+	writeTestFile(t, filepath.Join(defaults, "eap8", "ruleset.yaml"), "name: eap8\n")
+	writeTestFile(t, filepath.Join(defaults, "eap8", "nested", "rules.yaml"), "[]\n")
+	writeTestFile(t, filepath.Join(defaults, "group", "quarkus", "ruleset.yaml"), "name: quarkus\n")
+	writeTestFile(t, filepath.Join(defaults, "group", "spring", "ruleset.yaml"), "name: spring\n")
+	// files next to rulesets without ruleset.yaml are not parsed
+	writeTestFile(t, filepath.Join(defaults, "README.yaml"), "[]\n")
+	custom := filepath.Join(dir, "custom.yaml")
+	writeTestFile(t, custom, "[]\n")
+
+	got := ruleParseJobs([]string{defaults, custom})
+	want := []string{
+		filepath.Join(defaults, "eap8"),
+		filepath.Join(defaults, "group", "quarkus"),
+		filepath.Join(defaults, "group", "spring"),
+		custom,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ruleParseJobs() = %v, want %v", got, want)
+	}
+}
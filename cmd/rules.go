@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// providers with the dependency capability the generated conditions need
+var dependencyConditionProviders = []string{javaProvider, goProvider}
+
+type rulesFromDepsCommand struct {
+	input   string
+	matches []string
+	output  string
+	prefix  string
+	log     logr.Logger
+}
+
+// dependencyRule is the skeleton of a rule matching a dependency, in the
+// format of the rule files of the analyzer
+type dependencyRule struct {
+	RuleID      string                       `yaml:"ruleID"`
+	Description string                       `yaml:"description"`
+	Category    string                       `yaml:"category"`
+	Effort      int                          `yaml:"effort"`
+	Message     string                       `yaml:"message"`
+	When        map[string]dependencyMatcher `yaml:"when"`
+}
+
+// dependencyMatcher is a dependency condition, without upperbound and
+// lowerbound it matches any version of the dependency
+type dependencyMatcher struct {
+	Name string `yaml:"name"`
+}
+
+func NewRulesCommand(log logr.Logger) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Author custom rules",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+	cmd.AddCommand(newRulesFromDepsCommand(log))
+	cmd.AddCommand(newRulesDebugCommand(log))
+	return cmd
+}
+
+func newRulesFromDepsCommand(log logr.Logger) *cobra.Command {
+	fromDepsCmd := &rulesFromDepsCommand{log: log}
+	cmd := &cobra.Command{
+		Use:   "from-deps",
+		Short: "Generate rules matching the dependencies of an analysis",
+		Long: "Generate the skeletons of rules with a dependency condition for each dependency of the dependencies.yaml of an\n" +
+			"analysis matching --match, e.g. to ban dependencies. Messages, categories and efforts of the rules are to be completed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := fromDepsCmd.Run(os.Stdout); err != nil {
+				log.Error(err, "failed to generate rules from dependencies")
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&fromDepsCmd.input, "input", "i", "", "path to the dependencies.yaml of an analysis")
+	cmd.Flags().StringArrayVar(&fromDepsCmd.matches, "match", []string{}, "glob pattern of the names of the dependencies to generate rules for, e.g. 'javax.*', all dependencies when not set. Use multiple times for additional patterns")
+	cmd.Flags().StringVarP(&fromDepsCmd.output, "output", "o", "", "path to the rules file to write, stdout when not set")
+	cmd.Flags().StringVar(&fromDepsCmd.prefix, "rule-prefix", "dependency", "prefix of the IDs of the generated rules")
+	cmd.MarkFlagRequired("input")
+	return cmd
+}
+
+func (r *rulesFromDepsCommand) Run(stdout io.Writer) error {
+	for _, match := range r.matches {
+		if _, err := path.Match(match, ""); err != nil {
+			return fmt.Errorf("%w invalid pattern %s", err, match)
+		}
+	}
+	data, err := readOutputFile(r.input)
+	if err != nil {
+		return err
+	}
+	deps := []konveyor.DepsFlatItem{}
+	if err := yaml.Unmarshal(data, &deps); err != nil {
+		return fmt.Errorf("%w failed to parse dependencies %s", err, r.input)
+	}
+	rules := dependencyRules(deps, r.matches, r.prefix)
+	if len(rules) == 0 {
+		return fmt.Errorf("no dependency of %s matches", r.input)
+	}
+	out, err := yaml.Marshal(rules)
+	if err != nil {
+		return err
+	}
+	if r.output == "" {
+		_, err := stdout.Write(out)
+		return err
+	}
+	if err := os.WriteFile(r.output, out, 0644); err != nil {
+		return err
+	}
+	r.log.Info("wrote rules", "file", r.output, "rules", len(rules))
+	return nil
+}
+
+func matchesAny(name string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// dependencyRules returns a rule for each dependency matching the patterns,
+// sorted by provider and name. Dependencies found in several modules or
+// versions get a single rule matching all their versions.
+func dependencyRules(deps []konveyor.DepsFlatItem, patterns []string, prefix string) []dependencyRule {
+	// versions of each dependency by provider and name
+	versions := map[string]map[string][]string{}
+	for _, item := range deps {
+		if !slices.Contains(dependencyConditionProviders, item.Provider) {
+			continue
+		}
+		for _, dep := range item.Dependencies {
+			if !matchesAny(dep.Name, patterns) {
+				continue
+			}
+			if versions[item.Provider] == nil {
+				versions[item.Provider] = map[string][]string{}
+			}
+			versions[item.Provider][dep.Name] = append(versions[item.Provider][dep.Name], dep.Version)
+		}
+	}
+	rules := []dependencyRule{}
+	for _, provider := range sortedMapKeys(versions) {
+		for _, name := range sortedMapKeys(versions[provider]) {
+			found := slices.DeleteFunc(uniqueStrings(versions[provider][name]), func(v string) bool { return v == "" })
+			message := fmt.Sprintf("Replace the dependency %s.", name)
+			if len(found) > 0 {
+				message = fmt.Sprintf("Replace the dependency %s, found in versions %s.", name, strings.Join(found, ", "))
+			}
+			rules = append(rules, dependencyRule{
+				RuleID:      fmt.Sprintf("%s-%05d", prefix, (len(rules)+1)*10),
+				Description: fmt.Sprintf("Dependency %s", name),
+				Category:    "mandatory",
+				Effort:      1,
+				Message:     message,
+				When: map[string]dependencyMatcher{
+					fmt.Sprintf("%s.dependency", provider): {Name: name},
+				},
+			})
+		}
+	}
+	return rules
+}
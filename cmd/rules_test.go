@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_rulesFromDepsCommand_Run(t *testing.T) {
+	deps := filepath.Join(t.TempDir(), "dependencies.yaml")
+	writeTestFile(t, deps, `- fileURI: file:///opt/input/source/pom.xml
+  provider: java
+  dependencies:
+  - name: javax.servlet.servlet-api
+    version: 3.0.1
+  - name: org.hibernate.hibernate-core
+    version: 5.4.32.Final
+- fileURI: file:///opt/input/source/web/pom.xml
+  provider: java
+  dependencies:
+  - name: javax.servlet.servlet-api
+    version: 2.5
+  - name: javax.ejb.ejb-api
+    version: 3.0
+- fileURI: file:///opt/input/source/ui/package.json
+  provider: nodejs
+  dependencies:
+  - name: javax.js
+    version: 1.0.0
+`)
+	out := &bytes.Buffer{}
+	r := &rulesFromDepsCommand{input: deps, matches: []string{"javax.*"}, prefix: "ban", log: logr.Discard()}
+	if err := r.Run(out); err != nil {
+		t.Fatal(err)
+	}
+	want := `- ruleID: ban-00010
+  description: Dependency javax.ejb.ejb-api
+  category: mandatory
+  effort: 1
+  message: Replace the dependency javax.ejb.ejb-api, found in versions 3.0.
+  when:
+    java.dependency:
+      name: javax.ejb.ejb-api
+- ruleID: ban-00020
+  description: Dependency javax.servlet.servlet-api
+  category: mandatory
+  effort: 1
+  message: Replace the dependency javax.servlet.servlet-api, found in versions 2.5,
+    3.0.1.
+  when:
+    java.dependency:
+      name: javax.servlet.servlet-api
+`
+	if out.String() != want {
+		t.Errorf("Run() rules =\n%s\nwant\n%s", out, want)
+	}
+
+	r.matches = []string{"com.acme.*"}
+	if err := r.Run(out); err == nil || !strings.Contains(err.Error(), "no dependency") {
+		t.Errorf("Run() without matching dependencies = %v", err)
+	}
+}
@@ -0,0 +1,371 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/konveyor/analyzer-lsp/engine"
+	"github.com/konveyor/analyzer-lsp/engine/labels"
+	"github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"github.com/konveyor/analyzer-lsp/parser"
+	"github.com/konveyor/analyzer-lsp/provider"
+	"github.com/spf13/cobra"
+)
+
+// incidents listed per condition in the trace of a rule
+const traceIncidents = 5
+
+type rulesDebugCommand struct {
+	analyze *analyzeCommand
+	rule    string
+	ruleID  string
+}
+
+func newRulesDebugCommand(log logr.Logger) *cobra.Command {
+	debugCmd := &rulesDebugCommand{
+		analyze: &analyzeCommand{
+			log:                 log,
+			cleanup:             true,
+			reuseJdtlsWorkspace: true,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Evaluate a rule on an application and trace its conditions",
+		Long: "Evaluate a single rule on an application in containerless mode and print the tree of its conditions with,\n" +
+			"for each of them, the queries sent to the providers, the results they found, the incidents kept after\n" +
+			"filtering and whether the condition matched.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+			if err := debugCmd.Run(ctx, os.Stdout); err != nil {
+				log.Error(err, "failed to debug rule")
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&debugCmd.rule, "rule", "", "path to the rules file of the rule")
+	cmd.Flags().StringVar(&debugCmd.ruleID, "rule-id", "", "ID of the rule to evaluate, required when the file has several rules")
+	cmd.Flags().StringVarP(&debugCmd.analyze.input, "input", "i", "", "path to the application source code")
+	cmd.Flags().StringVarP(&debugCmd.analyze.mode, "mode", "m", string(provider.SourceOnlyAnalysisMode), "analysis mode. Must be one of 'full' or 'source-only', dependency conditions need 'full'")
+	cmd.Flags().StringVar(&debugCmd.analyze.mavenSettingsFile, "maven-settings", "", "path to a custom maven settings file to use")
+	cmd.Flags().BoolVar(&debugCmd.analyze.analyzeKnownLibraries, "analyze-known-libraries", false, "keep incidents in known open-source libraries")
+	cmd.Flags().IntVar(&debugCmd.analyze.contextLines, "context-lines", 10, "number of lines of source code to include in the output for each incident")
+	cmd.MarkFlagRequired("rule")
+	cmd.MarkFlagRequired("input")
+	return cmd
+}
+
+// conditionTrace records the evaluation of a condition of a rule
+type conditionTrace struct {
+	// and, or or <provider>.<capability>
+	Condition string
+	From      string
+	As        string
+	Not       bool
+	Ignorable bool
+	// provider conditions have queries, and and or children
+	Queries   []providerQuery
+	Children  []*conditionTrace
+	Evaluated bool
+	Matched   bool
+	Incidents []engine.IncidentContext
+	Err       error
+	Duration  time.Duration
+}
+
+// providerQuery is a query sent to a provider and the number of results
+// it found, before the condition filters them
+type providerQuery struct {
+	Query string
+	Found int
+	Err   error
+}
+
+// queryRecorder adds the queries of providers to the provider condition
+// being evaluated
+type queryRecorder struct {
+	current *conditionTrace
+}
+
+// tracingProviderClient names a provider client and records the queries
+// the conditions of the rule send to it
+type tracingProviderClient struct {
+	provider.InternalProviderClient
+	name     string
+	recorder *queryRecorder
+}
+
+func (c tracingProviderClient) Evaluate(ctx context.Context, capability string, conditionInfo []byte) (provider.ProviderEvaluateResponse, error) {
+	resp, err := c.InternalProviderClient.Evaluate(ctx, capability, conditionInfo)
+	if c.recorder.current != nil {
+		c.recorder.current.Queries = append(c.recorder.current.Queries, providerQuery{
+			Query: strings.TrimSpace(string(conditionInfo)),
+			Found: len(resp.Incidents),
+			Err:   err,
+		})
+	}
+	return resp, err
+}
+
+// providerClientName returns the name of the provider of a condition
+func providerClientName(client interface{}) string {
+	if traced, ok := client.(tracingProviderClient); ok {
+		return traced.name
+	}
+	return "provider"
+}
+
+// tracedCondition records the evaluation of the condition it wraps
+type tracedCondition struct {
+	engine.Conditional
+	trace    *conditionTrace
+	recorder *queryRecorder
+}
+
+func (c tracedCondition) Evaluate(ctx context.Context, log logr.Logger, condCtx engine.ConditionContext) (engine.ConditionResponse, error) {
+	if len(c.trace.Children) == 0 {
+		c.recorder.current = c.trace
+		defer func() { c.recorder.current = nil }()
+	}
+	started := time.Now()
+	resp, err := c.Conditional.Evaluate(ctx, log, condCtx)
+	c.trace.Duration = time.Since(started)
+	c.trace.Evaluated = true
+	c.trace.Matched = resp.Matched
+	c.trace.Incidents = resp.Incidents
+	c.trace.Err = err
+	return resp, err
+}
+
+// traceCondition wraps the conditions of a rule to record their evaluation
+func traceCondition(cond engine.Conditional, recorder *queryRecorder) (engine.Conditional, *conditionTrace) {
+	trace := &conditionTrace{}
+	switch c := cond.(type) {
+	case engine.AndCondition:
+		trace.Condition = "and"
+		c.Conditions = traceConditionEntries(c.Conditions, trace, recorder)
+		cond = c
+	case engine.OrCondition:
+		trace.Condition = "or"
+		c.Conditions = traceConditionEntries(c.Conditions, trace, recorder)
+		cond = c
+	case engine.ConditionEntry:
+		wrapped, child := traceCondition(c.ProviderSpecificConfig, recorder)
+		child.From, child.As, child.Not, child.Ignorable = c.From, c.As, c.Not, c.Ignorable
+		c.ProviderSpecificConfig = wrapped
+		return c, child
+	case provider.ProviderCondition:
+		trace.Condition = fmt.Sprintf("%s.%s", providerClientName(c.Client), c.Capability)
+	case *provider.DependencyCondition:
+		trace.Condition = fmt.Sprintf("%s.dependency", providerClientName(c.Client))
+	default:
+		trace.Condition = fmt.Sprintf("%T", cond)
+	}
+	return tracedCondition{Conditional: cond, trace: trace, recorder: recorder}, trace
+}
+
+func traceConditionEntries(entries []engine.ConditionEntry, parent *conditionTrace, recorder *queryRecorder) []engine.ConditionEntry {
+	traced := make([]engine.ConditionEntry, len(entries))
+	for i, entry := range entries {
+		wrapped, child := traceCondition(entry.ProviderSpecificConfig, recorder)
+		child.From, child.As, child.Not, child.Ignorable = entry.From, entry.As, entry.Not, entry.Ignorable
+		entry.ProviderSpecificConfig = wrapped
+		traced[i] = entry
+		parent.Children = append(parent.Children, child)
+	}
+	return traced
+}
+
+// result describes the outcome of the condition, negated conditions match
+// when their provider condition does not
+func (t *conditionTrace) result() string {
+	if t.Err != nil {
+		return fmt.Sprintf("error: %v", t.Err)
+	}
+	if !t.Evaluated {
+		return "not evaluated"
+	}
+	matched := t.Matched != t.Not
+	result := "not matched"
+	if matched {
+		result = "matched"
+	}
+	return fmt.Sprintf("%s, %d incidents in %s", result, len(t.Incidents), t.Duration.Round(time.Millisecond))
+}
+
+func (t *conditionTrace) title() string {
+	title := t.Condition
+	if t.Not {
+		title = "not " + title
+	}
+	if t.From != "" {
+		title += " from " + t.From
+	}
+	if t.As != "" {
+		title += " as " + t.As
+	}
+	if t.Ignorable {
+		title += " (ignorable, incidents not reported)"
+	}
+	return title
+}
+
+// writeConditionTrace prints the decision tree of the conditions of a rule
+func writeConditionTrace(out io.Writer, t *conditionTrace, prefix string, last bool) {
+	branch, indent := "├── ", "│   "
+	if last {
+		branch, indent = "└── ", "    "
+	}
+	fmt.Fprintf(out, "%s%s%s: %s\n", prefix, branch, t.title(), t.result())
+	details := prefix + indent
+	for _, query := range t.Queries {
+		found := fmt.Sprintf("%d results", query.Found)
+		if query.Err != nil {
+			found = fmt.Sprintf("error: %v", query.Err)
+		}
+		fmt.Fprintf(out, "%squery, %s:\n%s\n", details, found, indentLines(query.Query, details+"  "))
+		if query.Err == nil && len(t.Queries) == 1 && query.Found > len(t.Incidents) {
+			fmt.Fprintf(out, "%s%d results filtered out by the dependency label selector\n", details, query.Found-len(t.Incidents))
+		}
+	}
+	if len(t.Children) == 0 {
+		for i, incident := range t.Incidents {
+			if i == traceIncidents {
+				fmt.Fprintf(out, "%s... and %d more\n", details, len(t.Incidents)-traceIncidents)
+				break
+			}
+			location := string(incident.FileURI)
+			if incident.LineNumber != nil {
+				location = fmt.Sprintf("%s:%d", location, *incident.LineNumber)
+			}
+			fmt.Fprintf(out, "%s%s\n", details, location)
+		}
+	}
+	for i, child := range t.Children {
+		writeConditionTrace(out, child, details, i == len(t.Children)-1)
+	}
+}
+
+// debugRule returns the rule to debug of the rulesets of a rules file
+func debugRule(ruleSets []engine.RuleSet, ruleID string) (engine.Rule, error) {
+	ids := []string{}
+	for _, rs := range ruleSets {
+		for _, rule := range rs.Rules {
+			if rule.RuleID == ruleID {
+				return rule, nil
+			}
+			ids = append(ids, rule.RuleID)
+		}
+	}
+	if ruleID != "" {
+		return engine.Rule{}, fmt.Errorf("rule %s not found, the file has rules %s", ruleID, strings.Join(ids, ", "))
+	}
+	switch len(ids) {
+	case 0:
+		return engine.Rule{}, fmt.Errorf("no rule found, rules with conditions of providers not available are skipped")
+	case 1:
+		return ruleSets[0].Rules[0], nil
+	}
+	return engine.Rule{}, fmt.Errorf("the file has rules %s, select one with --rule-id", strings.Join(ids, ", "))
+}
+
+func (r *rulesDebugCommand) Run(ctx context.Context, out io.Writer) error {
+	a := r.analyze
+	if a.mode != string(provider.FullAnalysisMode) && a.mode != string(provider.SourceOnlyAnalysisMode) {
+		return fmt.Errorf("mode must be one of 'full' or 'source-only'")
+	}
+	if _, err := os.Stat(r.rule); err != nil {
+		return fmt.Errorf("%w failed to stat rule %s", err, r.rule)
+	}
+	if _, err := os.Stat(a.input); err != nil {
+		return fmt.Errorf("%w failed to stat input path %s", err, a.input)
+	}
+	var err error
+	if a.input, err = filepath.Abs(a.input); err != nil {
+		return err
+	}
+	if err := a.setKantraDir(); err != nil {
+		return err
+	}
+	// provider settings and logs are not kept
+	a.output, err = os.MkdirTemp("", "rules-debug-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(a.output)
+	defer func() {
+		if err := a.cleanlsDirs(); err != nil {
+			a.log.Error(err, "failed to clean language server directories")
+		}
+	}()
+
+	a.reqMap = map[string]string{}
+	if err := a.setBinMapContainerless(); err != nil {
+		a.log.Info("java provider is not installed, evaluating builtin conditions only", "error", err.Error())
+		a.provider = []string{builtinProvider}
+	}
+	configs, err := a.createProviderConfigsContainerless()
+	if err != nil {
+		return err
+	}
+	providerLog := a.log.V(7).WithName("provider")
+	providers, _ := a.setInternalProviders(configs, providerLog)
+	recorder := &queryRecorder{}
+	tracing := map[string]provider.InternalProviderClient{}
+	for name, client := range providers {
+		tracing[name] = tracingProviderClient{InternalProviderClient: client, name: name, recorder: recorder}
+	}
+	ruleParser := parser.RuleParser{
+		ProviderNameToClient: tracing,
+		Log:                  providerLog.WithName("parser"),
+	}
+	// incidents in known open-source libraries are filtered as in analyze
+	if !a.analyzeKnownLibraries {
+		ruleParser.DepLabelSelector, err = labels.NewLabelSelector[*konveyor.Dep](fmt.Sprintf("!%v=open-source", provider.DepSourceLabel), nil)
+		if err != nil {
+			return err
+		}
+	}
+	ruleSets, needProviders, err := ruleParser.LoadRules(r.rule)
+	if err != nil {
+		return fmt.Errorf("%w failed to parse rule %s", err, r.rule)
+	}
+	rule, err := debugRule(ruleSets, r.ruleID)
+	if err != nil {
+		return err
+	}
+	if err := a.startProvidersContainerless(ctx, needProviders); err != nil {
+		return err
+	}
+	defer func() {
+		for _, client := range needProviders {
+			client.Stop()
+		}
+	}()
+
+	when, trace := traceCondition(rule.When, recorder)
+	condCtx := engine.ConditionContext{
+		Tags:     map[string]interface{}{},
+		Template: map[string]engine.ChainTemplate{},
+	}
+	response, evalErr := when.Evaluate(ctx, providerLog, condCtx)
+	result := "not matched"
+	if response.Matched {
+		result = "matched"
+	}
+	if evalErr != nil {
+		result = fmt.Sprintf("error: %v", evalErr)
+	}
+	fmt.Fprintf(out, "rule %s: %s, %d incidents\n", rule.RuleID, result, len(response.Incidents))
+	writeConditionTrace(out, trace, "", true)
+	return evalErr
+}
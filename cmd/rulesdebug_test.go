@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/konveyor/analyzer-lsp/engine"
+	"github.com/konveyor/analyzer-lsp/provider"
+	"go.lsp.dev/uri"
+)
+
+// fakeProviderClient finds an incident in pom.xml for file conditions
+type fakeProviderClient struct {
+	provider.InternalProviderClient
+}
+
+func (fakeProviderClient) Evaluate(ctx context.Context, capability string, conditionInfo []byte) (provider.ProviderEvaluateResponse, error) {
+	if capability != "file" {
+		return provider.ProviderEvaluateResponse{}, nil
+	}
+	return provider.ProviderEvaluateResponse{
+		Matched:   true,
+		Incidents: []provider.IncidentContext{{FileURI: uri.File("/app/pom.xml")}},
+	}, nil
+}
+
+func Test_traceCondition(t *testing.T) {
+	recorder := &queryRecorder{}
+	client := tracingProviderClient{InternalProviderClient: fakeProviderClient{}, name: builtinProvider, recorder: recorder}
+	when := engine.AndCondition{
+		Conditions: []engine.ConditionEntry{
+			{
+				As: "poms",
+				ProviderSpecificConfig: provider.ProviderCondition{
+					Client:        client,
+					Capability:    "file",
+					ConditionInfo: map[string]interface{}{"pattern": "pom.xml"},
+				},
+			},
+			{
+				Not: true,
+				ProviderSpecificConfig: provider.ProviderCondition{
+					Client:        client,
+					Capability:    "filecontent",
+					ConditionInfo: map[string]interface{}{"pattern": "quarkus"},
+				},
+			},
+		},
+	}
+	traced, trace := traceCondition(when, recorder)
+	condCtx := engine.ConditionContext{Tags: map[string]interface{}{}, Template: map[string]engine.ChainTemplate{}}
+	response, err := traced.Evaluate(context.Background(), logr.Discard(), condCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.Matched || len(response.Incidents) != 1 {
+		t.Errorf("Evaluate() = %v, want a match with 1 incident", response)
+	}
+
+	out := &bytes.Buffer{}
+	writeConditionTrace(out, trace, "", true)
+	got := out.String()
+	for _, want := range []string{
+		"└── and: matched, 1 incidents",
+		"    ├── builtin.file as poms: matched, 1 incidents",
+		"query, 1 results:",
+		"pattern: pom.xml",
+		"file:///app/pom.xml",
+		"    └── not builtin.filecontent: matched, 0 incidents",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("trace does not contain %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// resolveDefaultRulesets sets the dir of the default rulesets used instead
+// of the ones shipped with kantra. The location is a local dir or a URL of
+// a .tar.gz or .zip bundle, a channel selects a subdir of it.
+func (a *analyzeCommand) resolveDefaultRulesets() error {
+	location := a.defaultRulesetsPath
+	if location == "" {
+		location = Settings.DefaultRulesetsPath
+	}
+	channel := a.rulesetsChannel
+	if channel == "" {
+		channel = Settings.RulesetsChannel
+	}
+	if location == "" {
+		if channel != "" {
+			return fmt.Errorf("rulesets channel requires a default rulesets path")
+		}
+		return nil
+	}
+	dir := location
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		cached, err := cachedRulesetsBundle(a.log, location)
+		if err != nil {
+			return fmt.Errorf("%w failed to download default rulesets from %s", err, location)
+		}
+		dir = cached
+	}
+	if channel != "" {
+		dir = filepath.Join(dir, channel)
+	}
+	stat, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("%w failed to stat default rulesets %s", err, dir)
+	}
+	if !stat.IsDir() {
+		return fmt.Errorf("default rulesets %s must be a directory", dir)
+	}
+	a.defaultRulesetsDir, err = filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	a.log.V(1).Info("using default rulesets", "path", a.defaultRulesetsDir, "channel", channel)
+	return nil
+}
+
+// defaultRulesetsContainerless returns the dir of the default rulesets on the host
+func (a *analyzeCommand) defaultRulesetsContainerless() string {
+	if a.defaultRulesetsDir != "" {
+		return a.defaultRulesetsDir
+	}
+	return filepath.Join(a.kantraDir, RulesetsLocation)
+}
+
+// getDefaultRulesetsVolume returns a volume replacing the rulesets of the
+// runner image. The rulesets are copied so that mounts nested in the
+// rulesets dir do not create dirs in the user provided location.
+func (a *analyzeCommand) getDefaultRulesetsVolume() (map[string]string, error) {
+	if a.defaultRulesetsDir == "" {
+		return map[string]string{}, nil
+	}
+	tempDir, err := os.MkdirTemp("", "default-rulesets-vol-")
+	if err != nil {
+		return nil, err
+	}
+	a.tempDirs = append(a.tempDirs, tempDir)
+	err = copyFolderContents(a.defaultRulesetsDir, tempDir)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{tempDir: RulesetPath}, nil
+}
+
+func downloadRulesetsBundle(log logr.Logger, url string, dest string) error {
+	bundle, err := os.CreateTemp("", "rulesets-*"+archiveExt(url))
+	if err != nil {
+		return err
+	}
+	bundle.Close()
+	defer os.Remove(bundle.Name())
+	err = downloadFile(log, url, bundle.Name())
+	if err != nil {
+		return err
+	}
+	return extractArchive(bundle.Name(), dest)
+}
+
+func archiveExt(name string) string {
+	for _, ext := range []string{".tar.gz", ".tgz", ".zip"} {
+		if strings.HasSuffix(name, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// extractArchive extracts a .tar.gz, .tgz or .zip file into dest
+func extractArchive(path string, dest string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	switch archiveExt(path) {
+	case ".zip":
+		stat, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		return extractZip(file, stat.Size(), dest)
+	case ".tar.gz", ".tgz":
+		_, err := extractTarGz(file, dest)
+		return err
+	}
+	return fmt.Errorf("archive %s must be a .tar.gz, .tgz or .zip file", filepath.Base(path))
+}
+
+// extractPath returns where an archive entry is extracted to, rejecting
+// entries that would end up outside of dest
+func extractPath(dest string, name string) (string, error) {
+	target := filepath.Join(dest, filepath.FromSlash(name))
+	if target != dest && !strings.HasPrefix(target, dest+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid path %s in bundle", name)
+	}
+	return target, nil
+}
+
+func extractTarGz(r io.Reader, dest string) ([]tarSymlink, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return extractTar(gz, dest)
+}
+
+// tarSymlink is a symlink entry of a tar stream
+type tarSymlink struct {
+	name   string
+	target string
+}
+
+// extractTar extracts the dirs, regular files and links of a tar stream into
+// dest. Hard links are extracted as copies of their target. Symlinks are
+// created once all other entries are extracted, so that no entry is written
+// through them, and symlinks resolving outside of dest are removed again and
+// returned. Other entries, e.g. devices, are skipped.
+func extractTar(r io.Reader, dest string) ([]tarSymlink, error) {
+	tr := tar.NewReader(r)
+	symlinks := []tarSymlink{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		target, err := extractPath(dest, header.Name)
+		if err != nil {
+			return nil, err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+		case tar.TypeReg:
+			err = writeExtractedFile(target, tr, header.FileInfo().Mode())
+		case tar.TypeLink:
+			err = extractHardLink(dest, target, header)
+		case tar.TypeSymlink:
+			symlinks = append(symlinks, tarSymlink{name: header.Name, target: header.Linkname})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return extractSymlinks(dest, symlinks)
+}
+
+// extractHardLink copies the extracted file a hard link entry points to
+func extractHardLink(dest string, target string, header *tar.Header) error {
+	source, err := extractPath(dest, header.Linkname)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("%w failed to extract hard link %s", err, header.Name)
+	}
+	defer file.Close()
+	return writeExtractedFile(target, file, header.FileInfo().Mode())
+}
+
+// extractSymlinks creates the symlinks of a tar stream and returns the ones
+// resolving outside of dest, which are not kept. The dirs of all links are
+// created before any link, so that no link is created through another one.
+func extractSymlinks(dest string, symlinks []tarSymlink) ([]tarSymlink, error) {
+	for _, link := range symlinks {
+		target, err := extractPath(dest, link.name)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+	}
+	for _, link := range symlinks {
+		target, _ := extractPath(dest, link.name)
+		if err := os.Symlink(link.target, target); err != nil {
+			return nil, fmt.Errorf("%w failed to extract symlink %s", err, link.name)
+		}
+	}
+	resolvedDest, err := filepath.EvalSymlinks(dest)
+	if err != nil {
+		return nil, err
+	}
+	escaping := []tarSymlink{}
+	for _, link := range symlinks {
+		target, _ := extractPath(dest, link.name)
+		resolved, err := filepath.EvalSymlinks(target)
+		if err != nil {
+			// broken links are left to the symlink policy of the analysis
+			continue
+		}
+		if rel, err := filepath.Rel(resolvedDest, resolved); err == nil && rel != ".." &&
+			!strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		escaping = append(escaping, link)
+	}
+	for _, link := range escaping {
+		target, _ := extractPath(dest, link.name)
+		if err := os.Remove(target); err != nil {
+			return nil, err
+		}
+	}
+	return escaping, nil
+}
+
+func extractZip(r io.ReaderAt, size int64, dest string) error {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	for _, f := range reader.File {
+		target, err := extractPath(dest, f.Name)
+		if err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = writeExtractedFile(target, rc, f.Mode())
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeExtractedFile(target string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	// keep executable bits, e.g. of the jdtls launcher
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(file, r)
+	return err
+}
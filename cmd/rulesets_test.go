@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func Test_analyzeCommand_resolveDefaultRulesets(t *testing.T) {
+	bundle := tarGz(t, map[string]string{
+		"community/00-discovery/ruleset.yaml": "name: discovery\n",
+		"certified/00-discovery/ruleset.yaml": "name: discovery\n",
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bundle)
+	}))
+	defer server.Close()
+
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("HOME", home)
+	a := &analyzeCommand{log: logr.Discard(), defaultRulesetsPath: server.URL + "/rulesets.tar.gz", rulesetsChannel: "community"}
+	if err := a.resolveDefaultRulesets(); err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(a.defaultRulesetsDir) != "community" {
+		t.Errorf("defaultRulesetsDir = %s, want community channel", a.defaultRulesetsDir)
+	}
+	if _, err := os.Stat(filepath.Join(a.defaultRulesetsContainerless(), "00-discovery", "ruleset.yaml")); err != nil {
+		t.Error(err)
+	}
+
+	a = &analyzeCommand{log: logr.Discard(), defaultRulesetsPath: filepath.Dir(a.defaultRulesetsDir), rulesetsChannel: "missing"}
+	if err := a.resolveDefaultRulesets(); err == nil {
+		t.Errorf("expected error for unknown channel")
+	}
+}
+
+func Test_extractTarGz_rejectsPathTraversal(t *testing.T) {
+	bundle := tarGz(t, map[string]string{"../escape.yaml": "name: escape\n"})
+	if _, err := extractTarGz(bytes.NewReader(bundle), t.TempDir()); err == nil {
+		t.Errorf("expected error for entry outside of destination")
+	}
+}
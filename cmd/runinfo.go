@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/konveyor-ecosystem/kantra/pkg/container"
+	"github.com/phayes/freeport"
+)
+
+// runInfoFile in the output dir lists the ports and containers of the
+// analysis, e.g. to find the containers of a run on a shared host, and the
+// git ref analyzed with --git-ref
+const runInfoFile = "run-info.json"
+
+// runInfo is the content of the run info file
+type runInfo struct {
+	RunID      string         `json:"runID"`
+	Ports      map[string]int `json:"ports,omitempty"`
+	Containers []string       `json:"containers,omitempty"`
+	Network    string         `json:"network,omitempty"`
+	Volume     string         `json:"volume,omitempty"`
+	GitRef     string         `json:"gitRef,omitempty"`
+	GitCommit  string         `json:"gitCommit,omitempty"`
+}
+
+// portRange is an inclusive range of ports, e.g. 30000-30999
+type portRange struct {
+	first int
+	last  int
+}
+
+func parsePortRange(s string) (portRange, error) {
+	first, last, ok := strings.Cut(s, "-")
+	r := portRange{}
+	var err error
+	if r.first, err = strconv.Atoi(strings.TrimSpace(first)); err != nil || !ok {
+		return r, fmt.Errorf("port range %s must be <first>-<last>, e.g. 30000-30999", s)
+	}
+	if r.last, err = strconv.Atoi(strings.TrimSpace(last)); err != nil {
+		return r, fmt.Errorf("port range %s must be <first>-<last>, e.g. 30000-30999", s)
+	}
+	if r.first < 1024 || r.last > 65535 || r.first > r.last {
+		return r, fmt.Errorf("port range %s must be within 1024-65535", s)
+	}
+	return r, nil
+}
+
+// allocate returns n ports of the range for which free is true. The search
+// starts at an offset derived from the run ID, so that concurrent runs
+// sharing a range rarely try the same ports.
+func (r portRange) allocate(runID string, n int, free func(port int) bool) ([]int, error) {
+	size := r.last - r.first + 1
+	h := fnv.New32a()
+	h.Write([]byte(runID))
+	offset := int(h.Sum32() % uint32(size))
+	ports := []int{}
+	for i := 0; i < size && len(ports) < n; i++ {
+		port := r.first + (offset+i)%size
+		if free(port) {
+			ports = append(ports, port)
+		}
+	}
+	if len(ports) < n {
+		return nil, fmt.Errorf("only %d of %d ports free in range %d-%d", len(ports), n, r.first, r.last)
+	}
+	return ports, nil
+}
+
+// portFree reports whether a port of the host can be listened on
+func portFree(port int) bool {
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	l.Close()
+	return true
+}
+
+// validateProviderPorts checks the port range of --provider-ports, or of
+// KANTRA_PROVIDER_PORTS set by the admin of a shared host
+func (a *analyzeCommand) validateProviderPorts() error {
+	if ports := a.providerPortRange(); ports != "" {
+		_, err := parsePortRange(ports)
+		return err
+	}
+	return nil
+}
+
+func (a *analyzeCommand) providerPortRange() string {
+	if a.providerPorts != "" {
+		return a.providerPorts
+	}
+	return Settings.ProviderPorts
+}
+
+// allocateProviderPorts returns a port for each of n providers, from the
+// port range of the run or any free port without one
+func (a *analyzeCommand) allocateProviderPorts(n int) ([]int, error) {
+	ports := a.providerPortRange()
+	if ports == "" {
+		return freeport.GetFreePorts(n)
+	}
+	r, err := parsePortRange(ports)
+	if err != nil {
+		return nil, err
+	}
+	return r.allocate(a.runID, n, portFree)
+}
+
+// containerName names a container, network or volume of the analysis after
+// its run, so that the ones of concurrent runs on a host can be told apart
+func (a *analyzeCommand) containerName(kind string) string {
+	if a.runID == "" {
+		return fmt.Sprintf("%s-%v", kind, container.RandomName())
+	}
+	return fmt.Sprintf("%s-%s-%v", kind, a.runID, container.RandomName())
+}
+
+// providerPortMap returns the ports of the providers by name
+func (a *analyzeCommand) providerPortMap() map[string]int {
+	ports := map[string]int{}
+	for prov, init := range a.providersMap {
+		ports[prov] = init.port
+	}
+	return ports
+}
+
+// writeRunInfo writes the ports and containers of the providers of the run
+// to the output dir
+func (a *analyzeCommand) writeRunInfo(ports map[string]int) error {
+	info := runInfo{
+		RunID:      a.runID,
+		Ports:      ports,
+		Containers: a.providerContainerNames,
+		Network:    a.networkName,
+		Volume:     a.volumeName,
+	}
+	if a.gitWorktree != nil {
+		info.GitRef = a.gitRef
+		info.GitCommit = a.gitWorktree.commit
+	}
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(a.output, runInfoFile), data, 0644)
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_parsePortRange(t *testing.T) {
+	tests := []struct {
+		ports   string
+		want    portRange
+		wantErr bool
+	}{
+		{ports: "30000-30999", want: portRange{first: 30000, last: 30999}},
+		{ports: "30000 - 30000", want: portRange{first: 30000, last: 30000}},
+		{ports: "30000", wantErr: true},
+		{ports: "a-b", wantErr: true},
+		{ports: "80-90", wantErr: true},
+		{ports: "30999-30000", wantErr: true},
+		{ports: "60000-70000", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ports, func(t *testing.T) {
+			got, err := parsePortRange(tt.ports)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePortRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("parsePortRange() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_portRange_allocate(t *testing.T) {
+	r := portRange{first: 30000, last: 30009}
+	all := func(int) bool { return true }
+	ports, err := r.allocate("run", 3, all)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ports) != 3 {
+		t.Fatalf("allocate() = %v, want 3 ports", ports)
+	}
+	for i, port := range ports {
+		if port < r.first || port > r.last {
+			t.Errorf("port %d is out of range", port)
+		}
+		// ports wrap around to the start of the range
+		if i > 0 && port != ports[i-1]+1 && !(ports[i-1] == r.last && port == r.first) {
+			t.Errorf("allocate() = %v, want consecutive ports", ports)
+		}
+	}
+	again, err := r.allocate("run", 3, all)
+	if err != nil || !reflect.DeepEqual(again, ports) {
+		t.Errorf("allocate() = %v, want the same ports %v for the same run", again, ports)
+	}
+
+	used := map[int]bool{ports[0]: true}
+	free, err := r.allocate("run", 3, func(port int) bool { return !used[port] })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, port := range free {
+		if used[port] {
+			t.Errorf("allocate() = %v, want port %d skipped", free, port)
+		}
+	}
+
+	if _, err := r.allocate("run", 3, func(port int) bool { return port == r.first }); err == nil {
+		t.Error("allocate() with too few free ports must fail")
+	}
+}
+
+func Test_containerName(t *testing.T) {
+	a := &analyzeCommand{runID: "abc123"}
+	name := a.containerName("provider")
+	if !strings.HasPrefix(name, "provider-abc123-") {
+		t.Errorf("containerName() = %s, want the run ID", name)
+	}
+	if other := a.containerName("provider"); other == name {
+		t.Errorf("containerName() = %s twice, want unique names", name)
+	}
+}
+
+func Test_writeRunInfo(t *testing.T) {
+	a := &analyzeCommand{
+		runID:                  "abc123",
+		networkName:            "network-abc123-1",
+		volumeName:             "volume-abc123-1",
+		providerContainerNames: []string{"provider-abc123-1"},
+	}
+	a.output = t.TempDir()
+	if err := a.writeRunInfo(map[string]int{"java": 30001}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(a.output, runInfoFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := runInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		t.Fatal(err)
+	}
+	want := runInfo{
+		RunID:      "abc123",
+		Ports:      map[string]int{"java": 30001},
+		Containers: []string{"provider-abc123-1"},
+		Network:    "network-abc123-1",
+		Volume:     "volume-abc123-1",
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("run info = %+v, want %+v", info, want)
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// --provider-network value running providers without network access
+const networkNone = "none"
+
+var (
+	mavenOfflineElement  = regexp.MustCompile(`<offline>\s*\w*\s*</offline>`)
+	mavenSettingsElement = regexp.MustCompile(`<settings(\s[^>]*)?>`)
+)
+
+// validateSandbox checks the options restricting provider containers
+func (a *analyzeCommand) validateSandbox() error {
+	if a.providerNetwork == "" && len(a.providerSecurityOpts) == 0 {
+		return nil
+	}
+	if a.runLocal {
+		return fmt.Errorf("provider-network and provider-security-opt require container mode, set --run-local=false")
+	}
+	if a.providerNetwork == networkNone && (a.httpProxy != "" || a.httpsProxy != "") {
+		return fmt.Errorf("must not specify a proxy with provider-network none")
+	}
+	return nil
+}
+
+// offlineMavenSettings enables the offline mode of maven in settings, so
+// that it only resolves dependencies from the local repository
+func offlineMavenSettings(settings []byte) ([]byte, error) {
+	if mavenOfflineElement.Match(settings) {
+		return mavenOfflineElement.ReplaceAll(settings, []byte("<offline>true</offline>")), nil
+	}
+	loc := mavenSettingsElement.FindIndex(settings)
+	if loc == nil {
+		return nil, fmt.Errorf("maven settings have no settings element")
+	}
+	offline := append([]byte{}, settings[:loc[1]]...)
+	offline = append(offline, []byte("\n  <offline>true</offline>")...)
+	return append(offline, settings[loc[1]:]...), nil
+}
+
+// writeOfflineMavenSettings enables the offline mode in the maven settings
+// at path, which are created when they do not exist
+func writeOfflineMavenSettings(path string) error {
+	settings, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		settings = []byte("<settings>\n</settings>\n")
+	} else if err != nil {
+		return err
+	}
+	settings, err = offlineMavenSettings(settings)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, settings, 0644)
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_offlineMavenSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings string
+		want     string
+	}{
+		{
+			name:     "no offline element",
+			settings: `<settings xmlns="http://maven.apache.org/SETTINGS/1.0.0"><localRepository>/m2</localRepository></settings>`,
+			want:     "<settings xmlns=\"http://maven.apache.org/SETTINGS/1.0.0\">\n  <offline>true</offline><localRepository>/m2</localRepository></settings>",
+		},
+		{
+			name:     "offline disabled",
+			settings: "<settings>\n  <offline> false </offline>\n</settings>",
+			want:     "<settings>\n  <offline>true</offline>\n</settings>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := offlineMavenSettings([]byte(tt.settings))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("offlineMavenSettings() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if _, err := offlineMavenSettings([]byte("<project/>")); err == nil {
+		t.Errorf("offlineMavenSettings() must fail without settings element")
+	}
+
+	path := filepath.Join(t.TempDir(), "settings.xml")
+	if err := writeOfflineMavenSettings(path); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "<offline>true</offline>") {
+		t.Errorf("writeOfflineMavenSettings() wrote %s", content)
+	}
+}
+
+func Test_analyzeCommand_validateSandbox(t *testing.T) {
+	a := &analyzeCommand{runLocal: true, providerNetwork: networkNone}
+	if err := a.validateSandbox(); err == nil {
+		t.Errorf("validateSandbox() must fail in containerless mode")
+	}
+	a = &analyzeCommand{providerNetwork: networkNone, httpsProxy: "http://proxy:3128"}
+	if err := a.validateSandbox(); err == nil {
+		t.Errorf("validateSandbox() must fail with a proxy and no network")
+	}
+	a = &analyzeCommand{providerNetwork: networkNone, providerSecurityOpts: []string{"no-new-privileges"}}
+	if err := a.validateSandbox(); err != nil {
+		t.Errorf("validateSandbox() = %v", err)
+	}
+}
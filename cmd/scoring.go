@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"gopkg.in/yaml.v2"
+)
+
+// scoringModel weighs incidents to compute their priority and the
+// migration readiness score of an application
+type scoringModel struct {
+	// CategoryWeights by violation category, categories not listed weigh 1
+	CategoryWeights map[string]float64 `yaml:"categoryWeights"`
+	// EffortWeight multiplies the effort of a violation
+	EffortWeight float64 `yaml:"effortWeight"`
+	// DependencyWeight multiplies the priority of incidents found in
+	// dependencies rather than in the application source
+	DependencyWeight float64 `yaml:"dependencyWeight"`
+	// Scale is the total priority at which the readiness score drops to 50
+	Scale float64 `yaml:"scale"`
+}
+
+func defaultScoringModel() scoringModel {
+	return scoringModel{
+		CategoryWeights: map[string]float64{
+			string(outputv1.Mandatory): 3,
+			string(outputv1.Potential): 2,
+			string(outputv1.Optional):  1,
+		},
+		EffortWeight:     1,
+		DependencyWeight: 0.5,
+		Scale:            100,
+	}
+}
+
+// loadScoringModel reads a model from path, unset values keep their defaults
+func loadScoringModel(path string) (scoringModel, error) {
+	model := defaultScoringModel()
+	if path == "" {
+		return model, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return model, err
+	}
+	err = yaml.UnmarshalStrict(data, &model)
+	if err != nil {
+		return model, err
+	}
+	if model.Scale <= 0 {
+		return model, fmt.Errorf("scale of scoring model must be greater than 0")
+	}
+	return model, nil
+}
+
+// rulePriority is the priority of the incidents of a violated rule
+type rulePriority struct {
+	RuleSet   string  `yaml:"ruleset" json:"ruleset"`
+	Rule      string  `yaml:"rule" json:"rule"`
+	Incidents int     `yaml:"incidents" json:"incidents"`
+	Priority  float64 `yaml:"priority" json:"priority"`
+}
+
+// incidentPriority returns the priority of a single incident
+func (m scoringModel) incidentPriority(violation outputv1.Violation, inDependency bool) float64 {
+	priority := 1.0
+	if violation.Category != nil {
+		if weight, ok := m.CategoryWeights[string(*violation.Category)]; ok {
+			priority = weight
+		}
+	}
+	if violation.Effort != nil {
+		priority *= 1 + float64(*violation.Effort)*m.EffortWeight
+	}
+	if inDependency {
+		priority *= m.DependencyWeight
+	}
+	return priority
+}
+
+// score returns the readiness score from 0 to 100 of the application,
+// 100 meaning nothing to migrate, and the violated rules by priority
+func (m scoringModel) score(rulesets []outputv1.RuleSet, inDependency func(incident outputv1.Incident) bool) (int, []rulePriority) {
+	priorities := []rulePriority{}
+	total := 0.0
+	for _, rs := range rulesets {
+		for ruleID, violation := range rs.Violations {
+			p := rulePriority{RuleSet: rs.Name, Rule: ruleID, Incidents: len(violation.Incidents)}
+			for _, incident := range violation.Incidents {
+				p.Priority += m.incidentPriority(violation, inDependency(incident))
+			}
+			p.Priority = math.Round(p.Priority*100) / 100
+			total += p.Priority
+			priorities = append(priorities, p)
+		}
+	}
+	sort.SliceStable(priorities, func(i, j int) bool {
+		if priorities[i].Priority != priorities[j].Priority {
+			return priorities[i].Priority > priorities[j].Priority
+		}
+		if priorities[i].RuleSet != priorities[j].RuleSet {
+			return priorities[i].RuleSet < priorities[j].RuleSet
+		}
+		return priorities[i].Rule < priorities[j].Rule
+	})
+	score := int(math.Round(100 * m.Scale / (m.Scale + total)))
+	return score, priorities
+}
+
+// incidentInDependency returns true for incidents outside of the input
+func (a *analyzeCommand) incidentInDependency(incident outputv1.Incident) bool {
+	if !strings.HasPrefix(string(incident.URI), "file:") {
+		return false
+	}
+	p := incident.URI.Filename()
+	for _, input := range []string{a.input, a.originalInput, a.sourceMountPath()} {
+		if input == "" {
+			continue
+		}
+		input = filepath.Clean(input)
+		if p == input || strings.HasPrefix(p, input+string(filepath.Separator)) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkScore fails when the readiness score is below --fail-on-score
+func (a *analyzeCommand) checkScore(summary *analysisSummary) error {
+	if a.failOnScore <= 0 {
+		return nil
+	}
+	if summary == nil {
+		return fmt.Errorf("unable to compute readiness score for --fail-on-score")
+	}
+	if summary.Score < a.failOnScore {
+		return fmt.Errorf("readiness score %d is below %d", summary.Score, a.failOnScore)
+	}
+	return nil
+}
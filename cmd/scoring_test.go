@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+)
+
+func Test_scoringModel_score(t *testing.T) {
+	mandatory := outputv1.Mandatory
+	optional := outputv1.Optional
+	effort := 3
+	rulesets := []outputv1.RuleSet{
+		{
+			Name: "ruleset",
+			Violations: map[string]outputv1.Violation{
+				"rule-000": {
+					Category: &mandatory,
+					Effort:   &effort,
+					Incidents: []outputv1.Incident{
+						{URI: uri.File("/opt/input/source/App.java")},
+						{URI: uri.File("/root/.m2/repository/lib.jar")},
+					},
+				},
+				"rule-001": {
+					Category:  &optional,
+					Incidents: []outputv1.Incident{{URI: uri.File("/opt/input/source/pom.xml")}},
+				},
+			},
+		},
+	}
+	a := &analyzeCommand{input: "/opt/input/source"}
+	score, priorities := defaultScoringModel().score(rulesets, a.incidentInDependency)
+	// 3 * (1 + 3) for the source incident, half of it for the dependency and 1
+	want := []rulePriority{
+		{RuleSet: "ruleset", Rule: "rule-000", Incidents: 2, Priority: 18},
+		{RuleSet: "ruleset", Rule: "rule-001", Incidents: 1, Priority: 1},
+	}
+	if !reflect.DeepEqual(priorities, want) {
+		t.Errorf("score() priorities = %v, want %v", priorities, want)
+	}
+	if score != 84 {
+		t.Errorf("score() = %v, want 84", score)
+	}
+	if score, _ := defaultScoringModel().score(nil, a.incidentInDependency); score != 100 {
+		t.Errorf("score() without violations = %v, want 100", score)
+	}
+
+	a.failOnScore = 90
+	if err := a.checkScore(&analysisSummary{Score: score}); err == nil {
+		t.Errorf("checkScore() expected error for score %d below %d", score, a.failOnScore)
+	}
+}
+
+func Test_analyzeCommand_incidentInDependency(t *testing.T) {
+	a := &analyzeCommand{input: "/src/app"}
+	tests := map[string]bool{
+		"/src/app/App.java":        false,
+		"/src/app-libs/lib.jar":    true,
+		"/root/.m2/repository/lib": true,
+	}
+	for path, want := range tests {
+		if got := a.incidentInDependency(outputv1.Incident{URI: uri.File(path)}); got != want {
+			t.Errorf("incidentInDependency(%s) = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// detail levels of the search index of the static report, each level also
+// indexes the text of the previous ones
+const (
+	reportIndexNone  = "none"
+	reportIndexRules = "rules"
+	reportIndexFiles = "files"
+	reportIndexFull  = "full"
+)
+
+var reportIndexLevels = []string{reportIndexNone, reportIndexRules, reportIndexFiles, reportIndexFull}
+
+// prefix of the search index file of the static report
+const searchIndexPrefix = `window["searchIndex"] = `
+
+const searchIndexFile = "search-index.js"
+
+// minimum length of an indexed term
+const minSearchTermLength = 2
+
+type searchIndexRule struct {
+	App     string `json:"app"`
+	RuleSet string `json:"ruleset"`
+	Rule    string `json:"rule"`
+}
+
+// searchIndex is a prebuilt index of the incidents of the static report,
+// so that the client side search of large reports does not scan them.
+// Incidents are listed in the order of the report, used for keyboard
+// navigation, as [rule, file, line, message] indexes into the other lists.
+type searchIndex struct {
+	Level     string            `json:"level"`
+	Rules     []searchIndexRule `json:"rules"`
+	Files     []string          `json:"files"`
+	Messages  []string          `json:"messages,omitempty"`
+	Incidents [][4]int          `json:"incidents"`
+	// incidents by lower case term of their text
+	Terms map[string][]int `json:"terms"`
+}
+
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// indexer dedups the strings of the index
+type indexer struct {
+	index    *searchIndex
+	files    map[string]int
+	messages map[string]int
+}
+
+func (i *indexer) file(name string) int {
+	if id, ok := i.files[name]; ok {
+		return id
+	}
+	i.files[name] = len(i.index.Files)
+	i.index.Files = append(i.index.Files, name)
+	return i.files[name]
+}
+
+func (i *indexer) message(text string) int {
+	if id, ok := i.messages[text]; ok {
+		return id
+	}
+	i.messages[text] = len(i.index.Messages)
+	i.index.Messages = append(i.index.Messages, text)
+	return i.messages[text]
+}
+
+func (i *indexer) addTerms(incident int, texts ...string) {
+	for _, text := range texts {
+		for _, term := range searchTerms(text) {
+			if len(term) < minSearchTermLength {
+				continue
+			}
+			postings := i.index.Terms[term]
+			// incidents are added in order, a term repeated in an incident is indexed once
+			if len(postings) > 0 && postings[len(postings)-1] == incident {
+				continue
+			}
+			i.index.Terms[term] = append(postings, incident)
+		}
+	}
+}
+
+func sortedKeys(m map[string]konveyor.Violation) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// buildSearchIndex indexes the incidents of the applications with the
+// text of the level
+func buildSearchIndex(apps []Application, level string) *searchIndex {
+	index := &searchIndex{Level: level, Rules: []searchIndexRule{}, Files: []string{},
+		Incidents: [][4]int{}, Terms: map[string][]int{}}
+	i := &indexer{index: index, files: map[string]int{}, messages: map[string]int{}}
+	for _, app := range apps {
+		for _, rs := range app.Rulesets {
+			for _, ruleID := range sortedKeys(rs.Violations) {
+				violation := rs.Violations[ruleID]
+				rule := len(index.Rules)
+				index.Rules = append(index.Rules, searchIndexRule{App: app.Id, RuleSet: rs.Name, Rule: ruleID})
+				for _, incident := range violation.Incidents {
+					id := len(index.Incidents)
+					file := normalizeIncidentURI(incident.URI)
+					entry := [4]int{rule, i.file(file), 0, -1}
+					if incident.LineNumber != nil {
+						entry[2] = *incident.LineNumber
+					}
+					i.addTerms(id, rs.Name, ruleID, violation.Description, strings.Join(violation.Labels, " "))
+					if level == reportIndexFiles || level == reportIndexFull {
+						i.addTerms(id, file)
+					}
+					if level == reportIndexFull {
+						entry[3] = i.message(incident.Message)
+						i.addTerms(id, incident.Message)
+					}
+					index.Incidents = append(index.Incidents, entry)
+				}
+			}
+		}
+	}
+	return index
+}
+
+// writeSearchIndex writes the search index of the applications of the
+// static report next to its data and loads it in the report
+func (a *analyzeCommand) writeSearchIndex() error {
+	if a.skipStaticReport || a.reportIndex == reportIndexNone {
+		return nil
+	}
+	reportDir := filepath.Join(a.output, "static-report")
+	content, err := os.ReadFile(filepath.Join(reportDir, "output.js"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	content = bytes.TrimSpace(content)
+	if !bytes.HasPrefix(content, []byte(staticReportAppsPrefix)) {
+		return fmt.Errorf("unexpected static report data in %s", reportDir)
+	}
+	apps := []Application{}
+	if err := json.Unmarshal(bytes.TrimPrefix(content, []byte(staticReportAppsPrefix)), &apps); err != nil {
+		return err
+	}
+	index, err := json.Marshal(buildSearchIndex(apps, a.reportIndex))
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(filepath.Join(reportDir, searchIndexFile), []byte(fmt.Sprintf("\n%s%s\n", searchIndexPrefix, index)), 0644)
+	if err != nil {
+		return err
+	}
+	a.log.V(1).Info("wrote static report search index", "level", a.reportIndex, "size", len(index))
+	return loadSearchIndex(filepath.Join(reportDir, "index.html"))
+}
+
+// loadSearchIndex adds the search index script to the report page, after
+// the report data
+func loadSearchIndex(page string) error {
+	content, err := os.ReadFile(page)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	script := fmt.Sprintf(`<script src="%s"></script>`, searchIndexFile)
+	if bytes.Contains(content, []byte(script)) {
+		return nil
+	}
+	data := []byte(`<script src="output.js"></script>`)
+	loc := bytes.Index(content, data)
+	if loc < 0 {
+		return nil
+	}
+	loc += len(data)
+	updated := append([]byte{}, content[:loc]...)
+	updated = append(updated, []byte(script)...)
+	return os.WriteFile(page, append(updated, content[loc:]...), 0644)
+}
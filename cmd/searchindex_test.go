@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func Test_buildSearchIndex(t *testing.T) {
+	line := 12
+	apps := []Application{{Id: "0000", Name: "app", Rulesets: []konveyor.RuleSet{{
+		Name: "eap8",
+		Violations: map[string]konveyor.Violation{
+			"session-00010": {Description: "Stateful session EJB", Incidents: []konveyor.Incident{
+				{URI: "file:///opt/input/source/src/Cart.java", LineNumber: &line, Message: "Replace the stateful EJB"},
+			}},
+			"jms-00001": {Description: "JMS queue", Labels: []string{"konveyor.io/target=eap8"}, Incidents: []konveyor.Incident{
+				{URI: "file:///opt/input/source/src/Queue.java", Message: "Use a managed JMS connection"},
+				{URI: "file:///opt/input/source/src/Cart.java", Message: "Use a managed JMS connection"},
+			}},
+		},
+	}}}}
+
+	index := buildSearchIndex(apps, reportIndexFull)
+	wantRules := []searchIndexRule{{App: "0000", RuleSet: "eap8", Rule: "jms-00001"}, {App: "0000", RuleSet: "eap8", Rule: "session-00010"}}
+	if !reflect.DeepEqual(index.Rules, wantRules) {
+		t.Errorf("buildSearchIndex() rules = %v, want %v", index.Rules, wantRules)
+	}
+	wantIncidents := [][4]int{{0, 0, 0, 0}, {0, 1, 0, 0}, {1, 1, 12, 1}}
+	if !reflect.DeepEqual(index.Incidents, wantIncidents) {
+		t.Errorf("buildSearchIndex() incidents = %v, want %v", index.Incidents, wantIncidents)
+	}
+	for term, want := range map[string][]int{"jms": {0, 1}, "cart": {1, 2}, "stateful": {2}, "eap8": {0, 1, 2}} {
+		if got := index.Terms[term]; !reflect.DeepEqual(got, want) {
+			t.Errorf("buildSearchIndex() term %s = %v, want %v", term, got, want)
+		}
+	}
+
+	rules := buildSearchIndex(apps, reportIndexRules)
+	if len(rules.Messages) != 0 || rules.Terms["cart"] != nil || rules.Incidents[2][3] != -1 {
+		t.Errorf("buildSearchIndex() rules level indexed files or messages: %v", rules)
+	}
+}
+
+func Test_analyzeCommand_writeSearchIndex(t *testing.T) {
+	output := t.TempDir()
+	reportDir := filepath.Join(output, "static-report")
+	writeTestFile(t, filepath.Join(reportDir, "output.js"), "\n"+staticReportAppsPrefix+
+		`[{"id":"0000","name":"app","rulesets":[{"name":"eap8","violations":{"jms-00001":{"description":"JMS queue","incidents":[{"uri":"file:///src/Queue.java","message":"Use JMS"}]}}}]}]`+"\n")
+	writeTestFile(t, filepath.Join(reportDir, "index.html"), `<html><head><script src="output.js"></script></head></html>`)
+
+	a := &analyzeCommand{output: output, reportIndex: reportIndexFiles, log: logr.Discard()}
+	if err := a.writeSearchIndex(); err != nil {
+		t.Fatal(err)
+	}
+	// the page loads the index once
+	if err := a.writeSearchIndex(); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(reportDir, searchIndexFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := searchIndex{}
+	data := strings.TrimPrefix(strings.TrimSpace(string(content)), searchIndexPrefix)
+	if err := json.Unmarshal([]byte(data), &index); err != nil {
+		t.Fatal(err)
+	}
+	if index.Level != reportIndexFiles || !reflect.DeepEqual(index.Files, []string{"/src/Queue.java"}) {
+		t.Errorf("writeSearchIndex() index = %v", index)
+	}
+	page, err := os.ReadFile(filepath.Join(reportDir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<script src="output.js"></script><script src="search-index.js"></script></head>`
+	if !strings.Contains(string(page), want) || strings.Count(string(page), searchIndexFile) != 1 {
+		t.Errorf("writeSearchIndex() page = %s, want it to load the index once", page)
+	}
+}
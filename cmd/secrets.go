@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// time to resolve the secrets of the settings
+const secretsTimeout = 30 * time.Second
+
+// secretsProvider reads secrets from a secret store, so that credentials
+// are not kept in env variables or files, e.g. as required by security
+// policies. Settings and flags taking credentials accept a reference to a
+// secret, <provider>:<ref>, instead of the credential.
+type secretsProvider interface {
+	// Secret returns the value of the secret at ref
+	Secret(ctx context.Context, ref string) (string, error)
+}
+
+// secretsProviders by the prefix of their references
+var secretsProviders = map[string]secretsProvider{
+	"vault": vaultSecrets{},
+	"file":  fileSecrets{},
+	"exec":  execSecrets{},
+}
+
+// resolveSecret returns the secret value references, or value when it is
+// not a reference
+func resolveSecret(ctx context.Context, value string) (string, error) {
+	name, ref, ok := strings.Cut(value, ":")
+	if !ok {
+		return value, nil
+	}
+	provider, ok := secretsProviders[name]
+	if !ok {
+		return value, nil
+	}
+	secret, err := provider.Secret(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("%w failed to read %s secret %s", err, name, ref)
+	}
+	return secret, nil
+}
+
+// vaultSecrets reads secrets of HashiCorp Vault, referenced as
+// vault:<path>#<key>, e.g. vault:secret/data/kantra#maven-password, from
+// the server of VAULT_ADDR with the token of VAULT_TOKEN or ~/.vault-token
+type vaultSecrets struct{}
+
+func vaultToken() (string, error) {
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		return token, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	token, err := os.ReadFile(filepath.Join(home, ".vault-token"))
+	if err != nil {
+		return "", fmt.Errorf("%w VAULT_TOKEN or ~/.vault-token is required", err)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+func (vaultSecrets) Secret(ctx context.Context, ref string) (string, error) {
+	secretPath, key, ok := strings.Cut(ref, "#")
+	if !ok || key == "" {
+		return "", fmt.Errorf("vault secret must be referenced as vault:<path>#<key>")
+	}
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return "", fmt.Errorf("VAULT_ADDR is required")
+	}
+	token, err := vaultToken()
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s/v1/%s", strings.TrimSuffix(addr, "/"), strings.TrimPrefix(secretPath, "/")), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if namespace := os.Getenv("VAULT_NAMESPACE"); namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	secret := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return "", err
+	}
+	data := secret.Data
+	// version 2 of the kv secrets engine nests the secret in data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found", key)
+	}
+	return fmt.Sprint(value), nil
+}
+
+// fileSecrets reads secrets from files, referenced as file:<path>, e.g.
+// mounted by a secrets store CSI driver
+type fileSecrets struct{}
+
+func (fileSecrets) Secret(ctx context.Context, ref string) (string, error) {
+	data, err := os.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// execSecrets reads secrets from the output of a command, referenced as
+// exec:<command> <args>, e.g. a script decrypting the secret with a KMS
+type execSecrets struct{}
+
+func (execSecrets) Secret(ctx context.Context, ref string) (string, error) {
+	args := strings.Fields(ref)
+	if len(args) == 0 {
+		return "", fmt.Errorf("secret command must not be empty")
+	}
+	out, err := exec.CommandContext(ctx, args[0], args[1:]...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// settingSecrets resolves the credentials of settings referencing secrets
+// where they are used, so that only commands using them read the secret
+// stores
+func settingSecrets(values ...string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), secretsTimeout)
+	defer cancel()
+	secrets := []string{}
+	for _, value := range values {
+		secret, err := resolveSecret(ctx, value)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
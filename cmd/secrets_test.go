@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func Test_resolveSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/kantra":
+			w.Write([]byte(`{"data":{"data":{"maven-password":"kv2-secret"},"metadata":{"version":1}}}`))
+		case "/v1/kv/kantra":
+			w.Write([]byte(`{"data":{"token":"kv1-secret"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "s.token")
+	secretFile := filepath.Join(t.TempDir(), "password")
+	writeTestFile(t, secretFile, "file-secret\n")
+
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "plain-password", want: "plain-password"},
+		{value: "http://proxy:3128", want: "http://proxy:3128"},
+		{value: "vault:secret/data/kantra#maven-password", want: "kv2-secret"},
+		{value: "vault:kv/kantra#token", want: "kv1-secret"},
+		{value: "vault:kv/kantra#missing", wantErr: true},
+		{value: "vault:kv/missing#token", wantErr: true},
+		{value: "vault:kv/kantra", wantErr: true},
+		{value: "file:" + secretFile, want: "file-secret"},
+		{value: "exec:echo exec-secret", want: "exec-secret"},
+	}
+	for _, tt := range tests {
+		got, err := resolveSecret(context.Background(), tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolveSecret(%s) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("resolveSecret(%s) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+)
+
+// selftest holds a sample application and the rules matching it
+//
+//go:embed selftest
+var selftestFS embed.FS
+
+// selftestIncidents are the incidents the selftest rules must find in the
+// sample application, by rule
+var selftestIncidents = map[string]int{
+	// builtin provider
+	"selftest-00010": 1,
+	"selftest-00020": 1,
+	// java provider
+	"selftest-00030": 1,
+}
+
+type selfTestCommand struct {
+	log       logr.Logger
+	container bool
+	output    string
+}
+
+func NewSelfTestCommand(log logr.Logger) *cobra.Command {
+	selfTestCmd := &selfTestCommand{
+		log: log,
+	}
+	selfTestCommand := &cobra.Command{
+		Use:   "selftest",
+		Short: "Verify the installation by analyzing a sample application",
+		Long: "Analyze a sample application shipped with kantra with rules of the builtin and java providers " +
+			"in containerless mode, and in container mode with --container, and verify the expected incidents are found. " +
+			"No network access is needed once the requirements are installed, e.g. to validate air-gapped installs.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := selfTestCmd.Run(cmd.Context(), os.Stdout); err != nil {
+				log.Error(err, "self test failed")
+				return err
+			}
+			return nil
+		},
+	}
+	selfTestCommand.Flags().BoolVar(&selfTestCmd.container, "container", false, "also analyze the sample application in container mode")
+	selfTestCommand.Flags().StringVar(&selfTestCmd.output, "output", "", "dir to keep the sample application and the analysis output in, a temp dir removed afterwards by default")
+	return selfTestCommand
+}
+
+// writeSelfTestFiles extracts the sample application and its rules into dir
+func writeSelfTestFiles(dir string) error {
+	return fs.WalkDir(selftestFS, "selftest", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel("selftest", filepath.FromSlash(p))
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dir, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		content, err := selftestFS.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, content, 0644)
+	})
+}
+
+// checkSelfTestOutput returns the differences between the incidents of the
+// output and the expected ones
+func checkSelfTestOutput(outputPath string) ([]string, error) {
+	rulesets, err := readRuleSetsOutput(outputPath)
+	if err != nil {
+		return nil, err
+	}
+	found := map[string]int{}
+	for _, rs := range rulesets {
+		for ruleID, violation := range rs.Violations {
+			found[ruleID] += len(violation.Incidents)
+		}
+	}
+	problems := []string{}
+	for _, ruleID := range sortedMapKeys(selftestIncidents) {
+		if found[ruleID] != selftestIncidents[ruleID] {
+			problems = append(problems, fmt.Sprintf("rule %s found %d incidents, expected %d", ruleID, found[ruleID], selftestIncidents[ruleID]))
+		}
+	}
+	for _, rs := range rulesets {
+		for ruleID, reason := range rs.Errors {
+			problems = append(problems, fmt.Sprintf("rule %s failed: %s", ruleID, reason))
+		}
+	}
+	return problems, nil
+}
+
+// analyze runs kantra analyze on the sample application and checks its output
+func (s *selfTestCommand) analyze(ctx context.Context, executable string, dir string, runLocal bool, log io.Writer) ([]string, error) {
+	mode := "containerless"
+	if !runLocal {
+		mode = "container"
+	}
+	output := filepath.Join(dir, "output-"+mode)
+	args := []string{
+		"analyze",
+		"--input", filepath.Join(dir, "app"),
+		"--output", output,
+		"--rules", filepath.Join(dir, "rules"),
+		"--enable-default-rulesets=false",
+		"--skip-static-report",
+		"--overwrite",
+		fmt.Sprintf("--run-local=%t", runLocal),
+	}
+	s.log.Info("analyzing sample application", "mode", mode, "output", output)
+	cmd := exec.CommandContext(ctx, executable, args...)
+	cmd.Stdout = log
+	cmd.Stderr = log
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%w failed to analyze sample application in %s mode, see %s", err, mode, filepath.Join(dir, "selftest.log"))
+	}
+	return checkSelfTestOutput(filepath.Join(output, "output.yaml"))
+}
+
+func (s *selfTestCommand) Run(ctx context.Context, out io.Writer) (err error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	dir := s.output
+	if dir == "" {
+		dir, err = os.MkdirTemp("", "kantra-selftest-")
+		if err != nil {
+			return err
+		}
+		// the output of failed tests is kept to troubleshoot them
+		defer func() {
+			if err == nil {
+				os.RemoveAll(dir)
+			}
+		}()
+	}
+	if err := writeSelfTestFiles(dir); err != nil {
+		return fmt.Errorf("%w failed to write sample application", err)
+	}
+	logFile, err := os.Create(filepath.Join(dir, "selftest.log"))
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	modes := []bool{true}
+	if s.container {
+		modes = append(modes, false)
+	}
+	failed := []string{}
+	for _, runLocal := range modes {
+		mode := "containerless"
+		if !runLocal {
+			mode = "container"
+		}
+		problems, err := s.analyze(ctx, executable, dir, runLocal, logFile)
+		if err != nil {
+			fmt.Fprintf(out, "%s: failed: %v\n", mode, err)
+			failed = append(failed, mode)
+			continue
+		}
+		if len(problems) > 0 {
+			fmt.Fprintf(out, "%s: failed\n", mode)
+			for _, problem := range problems {
+				fmt.Fprintf(out, "  %s\n", problem)
+			}
+			failed = append(failed, mode)
+			continue
+		}
+		fmt.Fprintf(out, "%s: passed, %d rules found their expected incidents\n", mode, len(selftestIncidents))
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("self test failed in %s mode, output kept in %s", strings.Join(failed, " and "), dir)
+	}
+	return nil
+}
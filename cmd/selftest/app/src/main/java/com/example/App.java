@@ -0,0 +1,11 @@
+package com.example;
+
+import java.util.logging.Logger;
+
+public class App {
+    private static final Logger LOG = Logger.getLogger(App.class.getName());
+
+    public static void main(String[] args) {
+        LOG.info("kantra selftest");
+    }
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_selfTest(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeSelfTestFiles(dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"app/pom.xml", "app/src/main/java/com/example/App.java", "rules/ruleset.yaml"} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("sample file %s not written: %v", file, err)
+		}
+	}
+
+	output := filepath.Join(dir, "output.yaml")
+	writeTestFile(t, output, `- name: selftest
+  violations:
+    selftest-00010:
+      incidents:
+      - uri: file:///app/pom.xml
+    selftest-00020:
+      incidents:
+      - uri: file:///app/src/main/resources/application.properties
+      - uri: file:///app/src/main/resources/application.properties
+  unmatched:
+  - selftest-00030
+`)
+	problems, err := checkSelfTestOutput(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"rule selftest-00020 found 2 incidents, expected 1",
+		"rule selftest-00030 found 0 incidents, expected 1",
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("checkSelfTestOutput() = %v, want %v", problems, want)
+	}
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// values of --selinux-label
+const (
+	selinuxLabelAuto    = "auto"
+	selinuxLabelShared  = "shared"
+	selinuxLabelPrivate = "private"
+	selinuxLabelNone    = "none"
+)
+
+var selinuxLabels = []string{selinuxLabelAuto, selinuxLabelShared, selinuxLabelPrivate, selinuxLabelNone}
+
+// values of --provider-userns besides the user namespace modes of the
+// container tool
+const (
+	usernsAuto = "auto"
+	usernsNone = "none"
+	usernsKeep = "keep-id"
+)
+
+// selinuxEnforceFile exists on hosts with SELinux enabled, permissive or
+// enforcing
+var selinuxEnforceFile = "/sys/fs/selinux/enforce"
+
+func selinuxEnabled() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	_, err := os.Stat(selinuxEnforceFile)
+	return err == nil
+}
+
+// rootlessPodman returns whether containers run in the user namespace of
+// rootless podman, where users of the image other than root are mapped to
+// sub UIDs of the host user without access to its files
+func rootlessPodman() bool {
+	return runtime.GOOS == "linux" && isPodman() && os.Geteuid() != 0
+}
+
+// validateSELinux resolves the SELinux label of the volumes and the user
+// namespace of the provider and analyzer containers from the host
+func (a *analyzeCommand) validateSELinux() error {
+	if !slices.Contains(selinuxLabels, a.selinuxLabel) {
+		return fmt.Errorf("selinux-label must be one of %s", strings.Join(selinuxLabels, ", "))
+	}
+	if a.runLocal {
+		if a.selinuxLabel != selinuxLabelAuto || a.providerUserNS != usernsAuto {
+			return fmt.Errorf("selinux-label and provider-userns require container mode, set --run-local=false")
+		}
+		return nil
+	}
+	switch a.selinuxLabel {
+	case selinuxLabelAuto:
+		if selinuxEnabled() {
+			a.volumeLabel = "z"
+		}
+	case selinuxLabelShared:
+		a.volumeLabel = "z"
+	case selinuxLabelPrivate:
+		// containers share the privately labeled volumes with a common level
+		a.volumeLabel = "Z"
+		if !slices.ContainsFunc(a.providerSecurityOpts, func(opt string) bool { return strings.HasPrefix(opt, "label=level:") }) {
+			a.providerSecurityOpts = append(a.providerSecurityOpts, randomSELinuxLevel())
+		}
+	}
+	switch a.providerUserNS {
+	case usernsAuto:
+		a.containerUserNS = ""
+		if rootlessPodman() && selinuxEnabled() {
+			a.containerUserNS = usernsKeep
+		}
+	case usernsNone, "":
+		a.containerUserNS = ""
+	default:
+		if strings.HasPrefix(a.providerUserNS, usernsKeep) && !isPodman() {
+			return fmt.Errorf("provider-userns %s requires podman", a.providerUserNS)
+		}
+		a.containerUserNS = a.providerUserNS
+	}
+	if a.volumeLabel != "" || a.containerUserNS != "" {
+		a.log.V(1).Info("running provider containers", "selinuxLabel", a.volumeLabel, "userns", a.containerUserNS)
+	}
+	return nil
+}
+
+// randomSELinuxLevel returns a security option running a container with a
+// random MCS level, as podman does for each container
+func randomSELinuxLevel() string {
+	c1 := rand.Intn(1024)
+	c2 := rand.Intn(1023)
+	if c2 >= c1 {
+		c2++
+	} else {
+		c1, c2 = c2, c1
+	}
+	return fmt.Sprintf("label=level:s0:c%d,c%d", c1, c2)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_analyzeCommand_validateSELinux(t *testing.T) {
+	defer func(file, bin string) { selinuxEnforceFile, Settings.ContainerBinary = file, bin }(selinuxEnforceFile, Settings.ContainerBinary)
+	Settings.ContainerBinary = "/usr/bin/podman"
+	enforce := filepath.Join(t.TempDir(), "enforce")
+	writeTestFile(t, enforce, "1\n")
+
+	newCommand := func(label, userns string) *analyzeCommand {
+		return &analyzeCommand{selinuxLabel: label, providerUserNS: userns, log: logr.Discard()}
+	}
+	selinuxEnforceFile = filepath.Join(t.TempDir(), "enforce")
+	a := newCommand(selinuxLabelAuto, usernsAuto)
+	if err := a.validateSELinux(); err != nil || a.volumeLabel != "" {
+		t.Errorf("validateSELinux() without SELinux = %q, %v, want no label", a.volumeLabel, err)
+	}
+	selinuxEnforceFile = enforce
+	a = newCommand(selinuxLabelAuto, usernsNone)
+	if err := a.validateSELinux(); err != nil || a.volumeLabel != "z" || a.containerUserNS != "" {
+		t.Errorf("validateSELinux() with SELinux = %q, %q, %v, want z", a.volumeLabel, a.containerUserNS, err)
+	}
+	a = newCommand(selinuxLabelPrivate, "keep-id:uid=1001")
+	if err := a.validateSELinux(); err != nil || a.volumeLabel != "Z" || a.containerUserNS != "keep-id:uid=1001" {
+		t.Errorf("validateSELinux() private = %q, %q, %v, want Z and keep-id:uid=1001", a.volumeLabel, a.containerUserNS, err)
+	}
+	if len(a.providerSecurityOpts) != 1 || !strings.HasPrefix(a.providerSecurityOpts[0], "label=level:s0:c") {
+		t.Errorf("validateSELinux() private security opts = %v, want a common level", a.providerSecurityOpts)
+	}
+
+	Settings.ContainerBinary = "/usr/bin/docker"
+	if err := newCommand(selinuxLabelAuto, usernsKeep).validateSELinux(); err == nil {
+		t.Errorf("validateSELinux() must fail for keep-id with docker")
+	}
+	if err := newCommand("relabel", usernsAuto).validateSELinux(); err == nil {
+		t.Errorf("validateSELinux() must fail for an unknown label")
+	}
+	a = newCommand(selinuxLabelShared, usernsAuto)
+	a.runLocal = true
+	if err := a.validateSELinux(); err == nil {
+		t.Errorf("validateSELinux() must fail in containerless mode")
+	}
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"embed"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"gopkg.in/yaml.v2"
+)
+
+// serverConfigLabel is set on the rules inventorying server configurations,
+// its value is the kind of resource, e.g. datasource
+const serverConfigLabel = "konveyor.io/server-config"
+
+// report of the resources of the server configurations in the output dir
+const serverConfigFile = "server-config.yaml"
+
+//go:embed server-config-rules/*.yaml
+var serverConfigRules embed.FS
+
+// kinds of resources of the server configuration report
+const (
+	serverConfigDatasource     = "datasource"
+	serverConfigJMSQueue       = "jms-queue"
+	serverConfigSecurityDomain = "security-domain"
+)
+
+// properties of a resource holding its name and JNDI name, in the order
+// they are looked up, across the configuration formats of the servers
+var (
+	serverResourceNameKeys = []string{"name", "pool-name", "id"}
+	serverResourceJNDIKeys = []string{"jndi-name", "jndiName", "entries"}
+)
+
+// serverResource is a datasource, JMS queue or security domain of a
+// server configuration
+type serverResource struct {
+	Name     string `yaml:"name,omitempty" json:"name,omitempty"`
+	JNDIName string `yaml:"jndiName,omitempty" json:"jndiName,omitempty"`
+	// attributes and values of the resource, without credentials
+	Properties map[string]string `yaml:"properties,omitempty" json:"properties,omitempty"`
+}
+
+// serverConfig is the inventory of a server configuration file
+type serverConfig struct {
+	File            string           `yaml:"file" json:"file"`
+	Server          string           `yaml:"server" json:"server"`
+	Datasources     []serverResource `yaml:"datasources,omitempty" json:"datasources,omitempty"`
+	JMSQueues       []serverResource `yaml:"jmsQueues,omitempty" json:"jmsQueues,omitempty"`
+	SecurityDomains []serverResource `yaml:"securityDomains,omitempty" json:"securityDomains,omitempty"`
+}
+
+// addServerConfigRules writes the server configuration rules bundled with
+// kantra into a temp dir and adds it to the rules of the analysis
+func (a *analyzeCommand) addServerConfigRules() error {
+	tempDir, err := os.MkdirTemp("", "server-config-rules-")
+	if err != nil {
+		return err
+	}
+	a.bundledRulesDirs = append(a.bundledRulesDirs, tempDir)
+	entries, err := serverConfigRules.ReadDir("server-config-rules")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		content, err := serverConfigRules.ReadFile(path.Join("server-config-rules", entry.Name()))
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(filepath.Join(tempDir, entry.Name()), content, 0644)
+		if err != nil {
+			return err
+		}
+	}
+	a.log.V(1).Info("adding server config rules", "path", tempDir)
+	a.rules = append(a.rules, tempDir)
+	return nil
+}
+
+// isCredential reports whether a property of a resource holds a secret,
+// which is not copied to the report
+func isCredential(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range []string{"password", "credential", "secret"} {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseServerResource reads the properties of a resource from the XML of
+// its element: the attributes of the element and of its children, and the
+// text of its children without children, by local name
+func parseServerResource(matchingXML string) (serverResource, error) {
+	resource := serverResource{Properties: map[string]string{}}
+	decoder := xml.NewDecoder(strings.NewReader(matchingXML))
+	// names of the open elements
+	open := []string{}
+	text := ""
+	set := func(key, value string) {
+		value = strings.TrimSpace(value)
+		if _, ok := resource.Properties[key]; ok || value == "" || isCredential(key) {
+			return
+		}
+		resource.Properties[key] = value
+	}
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return resource, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			for _, attr := range t.Attr {
+				// skip namespace declarations and XMI ids and types of websphere
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" || attr.Name.Space == "xmi" {
+					continue
+				}
+				set(attr.Name.Local, attr.Value)
+			}
+			open = append(open, t.Name.Local)
+			text = ""
+		case xml.CharData:
+			text += string(t)
+		case xml.EndElement:
+			if len(open) > 1 && text != "" {
+				set(open[len(open)-1], text)
+			}
+			open = open[:len(open)-1]
+			text = ""
+		}
+	}
+	for _, key := range serverResourceNameKeys {
+		if v, ok := resource.Properties[key]; ok {
+			resource.Name = v
+			break
+		}
+	}
+	for _, key := range serverResourceJNDIKeys {
+		if v, ok := resource.Properties[key]; ok {
+			resource.JNDIName = v
+			break
+		}
+	}
+	return resource, nil
+}
+
+// serverConfigRule is what the report needs of a server config rule
+type serverConfigRule struct {
+	RuleID string   `yaml:"ruleID"`
+	Labels []string `yaml:"labels"`
+	When   struct {
+		XML struct {
+			XPath string `yaml:"xpath"`
+		} `yaml:"builtin.xml"`
+	} `yaml:"when"`
+}
+
+func loadServerConfigRules() ([]serverConfigRule, error) {
+	data, err := serverConfigRules.ReadFile("server-config-rules/server-config.yaml")
+	if err != nil {
+		return nil, err
+	}
+	rules := []serverConfigRule{}
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ruleLabel returns the value of a label of a rule
+func ruleLabel(labels []string, label string) string {
+	for _, l := range labels {
+		if v, ok := strings.CutPrefix(l, label+"="); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// buildServerConfigReport lists the resources of the configuration files
+// found by the server config rules. The files are read again on the host,
+// the XML of the incidents holds the children of the matched elements only
+// and not their attributes.
+func buildServerConfigReport(rulesets []outputv1.RuleSet, input string, roots []string) ([]serverConfig, error) {
+	rules, err := loadServerConfigRules()
+	if err != nil {
+		return nil, err
+	}
+	// IDs of the rules matching each file, relative to the input
+	matched := map[string]map[string]bool{}
+	for _, rs := range rulesets {
+		// the rules inventory the configuration, they report no effort
+		for _, violations := range []map[string]outputv1.Violation{rs.Insights, rs.Violations} {
+			for ruleID, violation := range violations {
+				if ruleLabel(violation.Labels, serverConfigLabel) == "" {
+					continue
+				}
+				for _, incident := range violation.Incidents {
+					file := string(relativeURI(incident.URI, roots))
+					if matched[file] == nil {
+						matched[file] = map[string]bool{}
+					}
+					matched[file][ruleID] = true
+				}
+			}
+		}
+	}
+	report := []serverConfig{}
+	for _, file := range sortedMapKeys(matched) {
+		f, err := os.Open(filepath.Join(input, filepath.FromSlash(file)))
+		if err != nil {
+			// e.g. files of binaries decompiled in the provider container
+			continue
+		}
+		doc, err := xmlquery.Parse(f)
+		f.Close()
+		if err != nil {
+			continue
+		}
+		config := serverConfig{File: file}
+		for _, rule := range rules {
+			if !matched[file][rule.RuleID] {
+				continue
+			}
+			query, err := xpath.Compile(rule.When.XML.XPath)
+			if err != nil {
+				return nil, fmt.Errorf("%w invalid xpath of rule %s", err, rule.RuleID)
+			}
+			config.Server = ruleLabel(rule.Labels, outputv1.SourceTechnologyLabel)
+			for _, node := range xmlquery.QuerySelectorAll(doc, query) {
+				resource, err := parseServerResource(node.OutputXML(true))
+				if err != nil {
+					continue
+				}
+				switch ruleLabel(rule.Labels, serverConfigLabel) {
+				case serverConfigDatasource:
+					config.Datasources = append(config.Datasources, resource)
+				case serverConfigJMSQueue:
+					config.JMSQueues = append(config.JMSQueues, resource)
+				case serverConfigSecurityDomain:
+					config.SecurityDomains = append(config.SecurityDomains, resource)
+				}
+			}
+		}
+		report = append(report, config)
+	}
+	return report, nil
+}
+
+// writeServerConfigReport writes the datasources, JMS queues and security
+// domains of the server configurations of the input to server-config.yaml
+func (a *analyzeCommand) writeServerConfigReport() error {
+	if !a.serverConfig {
+		return nil
+	}
+	outputPath := filepath.Join(a.output, "output.yaml")
+	reportPath := filepath.Join(a.output, serverConfigFile)
+	// bulk analysis moves results to the application dir
+	if existingFile(outputFile(outputPath)) == "" && a.bulk {
+		outputPath = a.bulkResultPath("output.yaml")
+		reportPath = a.bulkResultPath(serverConfigFile)
+	}
+	rulesets, err := readRuleSetsOutput(outputPath)
+	if err != nil {
+		return err
+	}
+	input := a.input
+	if a.isFileInput {
+		input = filepath.Dir(input)
+	}
+	report, err := buildServerConfigReport(rulesets, input, a.inputRoots())
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(report)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(reportPath, data, 0644); err != nil {
+		return fmt.Errorf("%w failed to write server config report", err)
+	}
+	a.log.Info("wrote server config report", "file", reportPath, "configs", len(report))
+	return nil
+}
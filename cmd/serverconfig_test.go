@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+	"github.com/go-logr/logr"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+)
+
+const testStandaloneXML = `<?xml version="1.0" encoding="UTF-8"?>
+<server xmlns="urn:jboss:domain:20.0">
+  <profile>
+    <subsystem xmlns="urn:jboss:domain:datasources:7.0">
+      <datasources>
+        <datasource jndi-name="java:jboss/datasources/OrdersDS" pool-name="OrdersDS">
+          <connection-url>jdbc:postgresql://db:5432/orders</connection-url>
+          <driver>postgresql</driver>
+          <security>
+            <user-name>orders</user-name>
+            <password>s3cret</password>
+          </security>
+        </datasource>
+      </datasources>
+    </subsystem>
+    <subsystem xmlns="urn:jboss:domain:messaging-activemq:16.0">
+      <server name="default">
+        <jms-queue name="OrderQueue" entries="java:/jms/queue/orders"/>
+      </server>
+    </subsystem>
+    <subsystem xmlns="urn:jboss:domain:security:2.0">
+      <security-domains>
+        <security-domain name="orders-domain" cache-type="default"/>
+      </security-domains>
+    </subsystem>
+  </profile>
+</server>
+`
+
+const testLibertyServerXML = `<server description="orders">
+  <dataSource id="OrdersDS" jndiName="jdbc/orders">
+    <properties.db2.jcc databaseName="ORDERS" serverName="db" password="{xor}Lz4sLCgwLTs="/>
+  </dataSource>
+  <jmsQueue id="OrderQueue" jndiName="jms/orders"/>
+  <basicRegistry id="basic" realm="orders"/>
+</server>
+`
+
+const testWebLogicJDBCXML = `<?xml version="1.0" encoding="UTF-8"?>
+<jdbc-data-source xmlns="http://xmlns.oracle.com/weblogic/jdbc-data-source">
+  <name>OrdersDS</name>
+  <jdbc-driver-params>
+    <url>jdbc:oracle:thin:@db:1521/ORDERS</url>
+    <driver-name>oracle.jdbc.OracleDriver</driver-name>
+    <password-encrypted>{AES256}xyz</password-encrypted>
+  </jdbc-driver-params>
+  <jdbc-data-source-params>
+    <jndi-name>jdbc/orders</jndi-name>
+  </jdbc-data-source-params>
+</jdbc-data-source>
+`
+
+// evaluateServerConfigRules runs the xml conditions of the server config
+// rules on a file of the input the way the builtin provider does
+func evaluateServerConfigRules(t *testing.T, input string, file string) []outputv1.RuleSet {
+	rules, err := loadServerConfigRules()
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(input, file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := xmlquery.Parse(bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs := outputv1.RuleSet{Name: "server-config", Insights: map[string]outputv1.Violation{}}
+	for _, rule := range rules {
+		query, err := xpath.Compile(rule.When.XML.XPath)
+		if err != nil {
+			t.Fatalf("rule %s: %v", rule.RuleID, err)
+		}
+		violation := outputv1.Violation{Labels: rule.Labels}
+		for _, node := range xmlquery.QuerySelectorAll(doc, query) {
+			violation.Incidents = append(violation.Incidents, outputv1.Incident{
+				URI:       uri.File(filepath.Join(input, file)),
+				Variables: map[string]interface{}{"matchingXML": node.OutputXML(false)},
+			})
+		}
+		if len(violation.Incidents) > 0 {
+			rs.Insights[rule.RuleID] = violation
+		}
+	}
+	return []outputv1.RuleSet{rs}
+}
+
+func Test_buildServerConfigReport(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		xml  string
+		want serverConfig
+	}{
+		{
+			name: "jboss",
+			file: "configuration/standalone.xml",
+			xml:  testStandaloneXML,
+			want: serverConfig{
+				File:   "configuration/standalone.xml",
+				Server: "eap",
+				Datasources: []serverResource{{Name: "OrdersDS", JNDIName: "java:jboss/datasources/OrdersDS", Properties: map[string]string{
+					"jndi-name": "java:jboss/datasources/OrdersDS", "pool-name": "OrdersDS",
+					"connection-url": "jdbc:postgresql://db:5432/orders", "driver": "postgresql", "user-name": "orders",
+				}}},
+				JMSQueues:       []serverResource{{Name: "OrderQueue", JNDIName: "java:/jms/queue/orders", Properties: map[string]string{"name": "OrderQueue", "entries": "java:/jms/queue/orders"}}},
+				SecurityDomains: []serverResource{{Name: "orders-domain", Properties: map[string]string{"name": "orders-domain", "cache-type": "default"}}},
+			},
+		},
+		{
+			name: "liberty",
+			file: "src/main/liberty/config/server.xml",
+			xml:  testLibertyServerXML,
+			want: serverConfig{
+				File:   "src/main/liberty/config/server.xml",
+				Server: "openliberty",
+				Datasources: []serverResource{{Name: "OrdersDS", JNDIName: "jdbc/orders", Properties: map[string]string{
+					"id": "OrdersDS", "jndiName": "jdbc/orders", "databaseName": "ORDERS", "serverName": "db",
+				}}},
+				JMSQueues:       []serverResource{{Name: "OrderQueue", JNDIName: "jms/orders", Properties: map[string]string{"id": "OrderQueue", "jndiName": "jms/orders"}}},
+				SecurityDomains: []serverResource{{Name: "basic", Properties: map[string]string{"id": "basic", "realm": "orders"}}},
+			},
+		},
+		{
+			name: "weblogic",
+			file: "config/jdbc/orders-jdbc.xml",
+			xml:  testWebLogicJDBCXML,
+			want: serverConfig{
+				File:   "config/jdbc/orders-jdbc.xml",
+				Server: "weblogic",
+				Datasources: []serverResource{{Name: "OrdersDS", JNDIName: "jdbc/orders", Properties: map[string]string{
+					"name": "OrdersDS", "url": "jdbc:oracle:thin:@db:1521/ORDERS", "driver-name": "oracle.jdbc.OracleDriver", "jndi-name": "jdbc/orders",
+				}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := t.TempDir()
+			writeTestFile(t, filepath.Join(input, tt.file), tt.xml)
+			report, err := buildServerConfigReport(evaluateServerConfigRules(t, input, tt.file), input, []string{input})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(report) != 1 || !reflect.DeepEqual(report[0], tt.want) {
+				t.Errorf("buildServerConfigReport() = %+v, want %+v", report, tt.want)
+			}
+		})
+	}
+}
+
+func Test_analyzeCommand_addServerConfigRules(t *testing.T) {
+	a := &analyzeCommand{log: logr.Discard(), cleanup: true}
+	if err := a.addServerConfigRules(); err != nil {
+		t.Fatal(err)
+	}
+	if len(a.rules) != 1 || a.hasUserRules() {
+		t.Fatalf("server config rules %v are not tracked as bundled rules", a.rules)
+	}
+	// containerless analyses do not clean tempDirs
+	a.removeValidateResources()
+	if _, err := os.Stat(a.rules[0]); !os.IsNotExist(err) {
+		t.Errorf("server config rules dir %s was not removed", a.rules[0])
+	}
+}
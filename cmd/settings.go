@@ -21,6 +21,23 @@ type Config struct {
 	JavaProviderImage    string `env:"JAVA_PROVIDER_IMG" default:"quay.io/konveyor/java-external-provider:latest"`
 	GenericProviderImage string `env:"GENERIC_PROVIDER_IMG" default:"quay.io/konveyor/generic-external-provider:latest"`
 	DotnetProviderImage  string `env:"DOTNET_PROVIDER_IMG" default:"quay.io/konveyor/dotnet-external-provider:latest"`
+	Language             string `env:"KANTRA_LANG" default:"en"`
+	DefaultRulesetsPath  string `env:"KANTRA_DEFAULT_RULESETS_PATH" default:""`
+	RulesetsChannel      string `env:"KANTRA_RULESETS_CHANNEL" default:""`
+	ImagePlatform        string `env:"KANTRA_IMAGE_PLATFORM" default:""`
+	MavenMirror          string `env:"KANTRA_MAVEN_MIRROR" default:""`
+	MavenUsername        string `env:"KANTRA_MAVEN_USERNAME" default:""`
+	MavenPassword        string `env:"KANTRA_MAVEN_PASSWORD" default:""`
+	FeedbackURL          string `env:"KANTRA_FEEDBACK_URL" default:""`
+	FeedbackToken        string `env:"KANTRA_FEEDBACK_TOKEN" default:""`
+	FeedbackIssuesURL    string `env:"KANTRA_FEEDBACK_ISSUES_URL" default:"https://github.com/konveyor/rulesets/issues/new"`
+	HubURL               string `env:"KANTRA_HUB_URL" default:""`
+	HubToken             string `env:"KANTRA_HUB_TOKEN" default:""`
+	Kubectl              string `env:"KANTRA_KUBECTL" default:"kubectl"`
+	KubeImage            string `env:"KANTRA_KUBE_IMG" default:"quay.io/konveyor/kantra-kube:latest"`
+	KubeGitImage         string `env:"KANTRA_KUBE_GIT_IMG" default:"docker.io/alpine/git:latest"`
+	ProviderPorts        string `env:"KANTRA_PROVIDER_PORTS" default:""`
+	Cosign               string `env:"KANTRA_COSIGN" default:"cosign"`
 }
 
 func (c *Config) Load() error {
@@ -30,6 +47,9 @@ func (c *Config) Load() error {
 	if err := c.loadDefaultPodmanBin(); err != nil {
 		return err
 	}
+	if err := c.loadArchOverrides(); err != nil {
+		return err
+	}
 	if err := c.loadRunnerImg(); err != nil {
 		return err
 	}
@@ -80,6 +100,19 @@ func (c *Config) trySetDefaultPodmanBin(file string) (found bool, err error) {
 	return false, nil
 }
 
+// loadArchOverrides sets images from their override for the host
+// architecture, e.g. JAVA_PROVIDER_IMG_ARM64 on arm64 hosts
+func (c *Config) loadArchOverrides() error {
+	for _, name := range imageVariables {
+		if img := os.Getenv(archOverrideVariable(name)); img != "" {
+			if err := os.Setenv(name, img); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (c *Config) loadRunnerImg() error {
 	// TODO(maufart): ensure Config struct works/parses it values from ENV and defaults correctly
 	// Respect existing RUNNER_IMG setting
@@ -23,3 +23,13 @@ func TestRunnerImgCustom(t *testing.T) {
 		t.Errorf("Unexpected RUNNER_IMG: %s", s.RunnerImage)
 	}
 }
+
+func TestProviderImgArchOverride(t *testing.T) {
+	t.Setenv("JAVA_PROVIDER_IMG", "quay.io/konveyor/java-external-provider:latest")
+	t.Setenv(archOverrideVariable("JAVA_PROVIDER_IMG"), "quay.io/some-contributor/java-provider-native")
+	s := &Config{}
+	s.Load()
+	if s.JavaProviderImage != "quay.io/some-contributor/java-provider-native" {
+		t.Errorf("Unexpected JAVA_PROVIDER_IMG: %s", s.JavaProviderImage)
+	}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// outputManifestFile lists the SHA-256 digests of the analysis outputs in
+// the format of sha256sum, so that `sha256sum -c` verifies them
+const outputManifestFile = "output.sha256"
+
+// outputManifestBundle is the cosign bundle of the signature of the manifest
+const outputManifestBundle = outputManifestFile + ".bundle"
+
+// signKeyless signs the manifest with the OIDC identity of the user or CI
+// job through Sigstore instead of a key
+const signKeyless = "keyless"
+
+// validateSignOutput checks --sign-output and --sign-with
+func (a *analyzeCommand) validateSignOutput() error {
+	if a.signWith != "" {
+		a.signOutput = true
+	}
+	if !a.signOutput {
+		return nil
+	}
+	if a.bulk || len(a.bulkInputs) > 0 || a.kube {
+		return fmt.Errorf("sign-output is not supported with bulk and kube analyses")
+	}
+	if a.signWith != "" {
+		if _, err := exec.LookPath(Settings.Cosign); err != nil {
+			return fmt.Errorf("%w cannot find cosign to sign the output with; ensure cosign is installed or set KANTRA_COSIGN", err)
+		}
+	}
+	return nil
+}
+
+// fileDigest returns the hex SHA-256 digest of a file
+func fileDigest(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	digest := sha256.New()
+	if _, err := io.Copy(digest, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+// outputManifest returns the digests of the outputs of the output dir by
+// path relative to it, files of the report dirs one by one. Logs are left
+// out, they are not results of the analysis.
+func outputManifest(output string) (map[string]string, error) {
+	digests := map[string]string{}
+	for _, name := range prefixedOutputs {
+		if strings.HasSuffix(name, ".log") || name == outputManifestFile || name == outputManifestBundle {
+			continue
+		}
+		for _, file := range []string{name, name + gzipExt} {
+			root := filepath.Join(output, file)
+			if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				rel, err := filepath.Rel(output, path)
+				if err != nil {
+					return err
+				}
+				digest, err := fileDigest(path)
+				if err != nil {
+					return err
+				}
+				digests[filepath.ToSlash(rel)] = digest
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return digests, nil
+}
+
+// formatManifest formats the digests as sha256sum does, sorted by path.
+// Paths are named with the prefix the outputs get with --output-prefix.
+func formatManifest(digests map[string]string, prefix string) []byte {
+	paths := make([]string, 0, len(digests))
+	for path := range digests {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	manifest := bytes.Buffer{}
+	for _, path := range paths {
+		fmt.Fprintf(&manifest, "%s  %s%s\n", digests[path], prefix, path)
+	}
+	return manifest.Bytes()
+}
+
+// cosignArgs returns the arguments of cosign signing the manifest into a
+// bundle with the key of --sign-with, or keyless
+func cosignArgs(signWith string, manifest string, bundle string) []string {
+	args := []string{"sign-blob", "--yes", "--bundle", bundle}
+	if signWith != signKeyless {
+		args = append(args, "--key", signWith)
+	}
+	return append(args, manifest)
+}
+
+// signOutputs writes the digests of the analysis outputs with --sign-output
+// and signs them with cosign with --sign-with, so that consumers of the
+// results can verify they were not altered after the analysis
+func (a *analyzeCommand) signOutputs() error {
+	if !a.signOutput {
+		return nil
+	}
+	digests, err := outputManifest(a.output)
+	if err != nil {
+		return fmt.Errorf("%w failed to compute digests of the output", err)
+	}
+	manifestPath := filepath.Join(a.output, outputManifestFile)
+	if err := os.WriteFile(manifestPath, formatManifest(digests, a.outputPrefix), 0644); err != nil {
+		return fmt.Errorf("%w failed to write output manifest", err)
+	}
+	a.log.Info("wrote output manifest", "file", manifestPath, "files", len(digests))
+	if a.signWith == "" {
+		return nil
+	}
+	bundlePath := filepath.Join(a.output, outputManifestBundle)
+	cmd := exec.Command(Settings.Cosign, cosignArgs(a.signWith, manifestPath, bundlePath)...)
+	// keyless signing prints the URL to authenticate with
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%w failed to sign output manifest with cosign", err)
+	}
+	a.log.Info("signed output manifest", "bundle", bundlePath)
+	return nil
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func Test_signOutputs(t *testing.T) {
+	output := t.TempDir()
+	writeTestFile(t, filepath.Join(output, "output.yaml"), "- name: ruleset\n")
+	writeTestFile(t, filepath.Join(output, "dependencies.yaml.gz"), "deps")
+	writeTestFile(t, filepath.Join(output, "static-report", "index.html"), "<html></html>")
+	writeTestFile(t, filepath.Join(output, "analysis.log"), "log")
+	writeTestFile(t, filepath.Join(output, "other.txt"), "not an output")
+
+	a := &analyzeCommand{log: logr.Discard(), output: output, signOutput: true}
+	if err := a.signOutputs(); err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := os.ReadFile(filepath.Join(output, outputManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(manifest)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || len(fields[0]) != 64 {
+			t.Fatalf("manifest line %q is not in sha256sum format", line)
+		}
+		paths = append(paths, fields[1])
+	}
+	want := []string{"dependencies.yaml.gz", "output.yaml", "static-report/index.html"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("manifest paths = %v, want %v", paths, want)
+	}
+
+	// the manifest is verified by sha256sum where available
+	if _, err := exec.LookPath("sha256sum"); err == nil {
+		cmd := exec.Command("sha256sum", "-c", outputManifestFile)
+		cmd.Dir = output
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("sha256sum -c failed: %v\n%s", err, out)
+		}
+	}
+
+	// the manifest of a second run does not list the manifest itself
+	if err := a.signOutputs(); err != nil {
+		t.Fatal(err)
+	}
+	again, err := os.ReadFile(filepath.Join(output, outputManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(manifest) {
+		t.Errorf("manifest changed to %s, want %s", again, manifest)
+	}
+}
+
+func Test_formatManifest(t *testing.T) {
+	got := string(formatManifest(map[string]string{"output.yaml": "aa", "static-report/index.html": "bb"}, "app1-"))
+	want := "aa  app1-output.yaml\nbb  app1-static-report/index.html\n"
+	if got != want {
+		t.Errorf("formatManifest() = %q, want %q", got, want)
+	}
+}
+
+func Test_cosignArgs(t *testing.T) {
+	tests := []struct {
+		signWith string
+		want     []string
+	}{
+		{signWith: "cosign.key", want: []string{"sign-blob", "--yes", "--bundle", "m.bundle", "--key", "cosign.key", "m"}},
+		{signWith: signKeyless, want: []string{"sign-blob", "--yes", "--bundle", "m.bundle", "m"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.signWith, func(t *testing.T) {
+			if got := cosignArgs(tt.signWith, "m", "m.bundle"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cosignArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// report of the files of the input not analyzed, or not fully, in the
+// output dir
+const skippedReportFile = "skipped.yaml"
+
+// reasons of skipped files
+const (
+	// excluded by .kantraignore or --exclude-dir
+	skipReasonExcluded        = "excluded"
+	skipReasonBrokenSymlink   = "broken-symlink"
+	skipReasonExternalSymlink = "external-symlink"
+	// larger than --max-file-size
+	skipReasonSize = "size"
+	// binary with --skip-binary-files
+	skipReasonBinary = "binary"
+	// text the patterns of the builtin provider do not match
+	skipReasonEncoding = "encoding"
+)
+
+// skippedAllProviders is the provider of files no provider analyzes
+const skippedAllProviders = "all"
+
+// byte order marks of UTF-16 text, the patterns of the builtin provider do
+// not match it
+var utf16BOMs = [][]byte{{0xFF, 0xFE}, {0xFE, 0xFF}}
+
+// skippedFile is a file or dir of the input skipped by providers
+type skippedFile struct {
+	// slash separated relative to the input, dirs end with a slash
+	Path     string `yaml:"path" json:"path"`
+	Provider string `yaml:"provider" json:"provider"`
+	Reason   string `yaml:"reason" json:"reason"`
+	Detail   string `yaml:"detail,omitempty" json:"detail,omitempty"`
+}
+
+// hasUTF16BOM returns true when the file starts with a UTF-16 byte order mark
+func hasUTF16BOM(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	buf := make([]byte, 2)
+	if _, err := io.ReadFull(file, buf); err != nil {
+		return false, nil
+	}
+	for _, bom := range utf16BOMs {
+		if bytes.Equal(buf, bom) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// findCoverageGaps returns the paths of the input excluded by the patterns
+// of ignore, the topmost ones only, and the files in encodings the builtin
+// provider does not search
+func findCoverageGaps(root string, ignore *kantraIgnore) ([]skippedFile, error) {
+	gaps := []skippedFile{}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if ignore.Ignored(rel, d.IsDir()) {
+			// explicitly excluded paths are reported with their reason
+			if !ignore.excluded[rel] {
+				if d.IsDir() {
+					rel += "/"
+				}
+				gaps = append(gaps, skippedFile{Path: rel, Provider: skippedAllProviders, Reason: skipReasonExcluded})
+			}
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		utf16, err := hasUTF16BOM(p)
+		if err != nil {
+			return err
+		}
+		if utf16 {
+			gaps = append(gaps, skippedFile{Path: rel, Provider: builtinProvider, Reason: skipReasonEncoding, Detail: "UTF-16"})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return gaps, nil
+}
+
+// addSkipped records files skipped by providers for the skipped files report
+func (a *analyzeCommand) addSkipped(files ...skippedFile) {
+	a.skipped = append(a.skipped, files...)
+}
+
+// writeSkippedReport writes the files of the input skipped by providers,
+// with the reason, to skipped.yaml
+func (a *analyzeCommand) writeSkippedReport() error {
+	if len(a.skipped) == 0 {
+		return nil
+	}
+	reportPath := filepath.Join(a.output, skippedReportFile)
+	// bulk analysis moves results to the application dir
+	if _, err := os.Stat(filepath.Join(a.output, "output.yaml")); errors.Is(err, os.ErrNotExist) && a.bulk {
+		reportPath = a.bulkResultPath(skippedReportFile)
+	}
+	sort.SliceStable(a.skipped, func(i, j int) bool {
+		return a.skipped[i].Path < a.skipped[j].Path
+	})
+	data, err := yaml.Marshal(a.skipped)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(reportPath, data, 0644); err != nil {
+		return fmt.Errorf("%w failed to write skipped files report", err)
+	}
+	a.log.Info("wrote skipped files report", "file", reportPath, "files", len(a.skipped))
+	return nil
+}
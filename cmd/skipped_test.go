@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"gopkg.in/yaml.v2"
+)
+
+func Test_findCoverageGaps(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, "src", "App.java"), "class App {}")
+	writeTestFile(t, filepath.Join(root, "src", "messages.properties"), "\xff\xfek\x00e\x00y\x00")
+	writeTestFile(t, filepath.Join(root, "target", "App.class"), "")
+	writeTestFile(t, filepath.Join(root, "logs", "app.log"), "")
+	writeTestFile(t, filepath.Join(root, "logs", "keep.txt"), "")
+	ignore := excludeDirs(&kantraIgnore{patterns: []ignorePattern{{pattern: "*.log"}}}, []string{"target"})
+	ignore.exclude("src/link")
+
+	got, err := findCoverageGaps(root, ignore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []skippedFile{
+		{Path: "logs/app.log", Provider: skippedAllProviders, Reason: skipReasonExcluded},
+		{Path: "src/messages.properties", Provider: builtinProvider, Reason: skipReasonEncoding, Detail: "UTF-16"},
+		{Path: "target/", Provider: skippedAllProviders, Reason: skipReasonExcluded},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findCoverageGaps() = %v, want %v", got, want)
+	}
+}
+
+func Test_analyzeCommand_writeSkippedReport(t *testing.T) {
+	a := &analyzeCommand{output: t.TempDir(), log: logr.Discard()}
+	if err := a.writeSkippedReport(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(a.output, skippedReportFile)); err == nil {
+		t.Errorf("writeSkippedReport() must not write a report without skipped files")
+	}
+	a.addSkipped(skippedFile{Path: "vendor/blob.bin", Provider: builtinProvider, Reason: skipReasonBinary})
+	a.addSkipped(skippedFile{Path: "docs/link", Provider: skippedAllProviders, Reason: skipReasonBrokenSymlink, Detail: "../missing"})
+	if err := a.writeSkippedReport(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(a.output, skippedReportFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []skippedFile{}
+	if err := yaml.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Path != "docs/link" || got[1].Reason != skipReasonBinary {
+		t.Errorf("writeSkippedReport() = %v, want the files sorted by path", got)
+	}
+}
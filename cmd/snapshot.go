@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+)
+
+// prefix of snapshot dirs, followed by the content hash of the input
+const snapshotDirPrefix = "kantra-snapshot-"
+
+// snapshotInput copies the input into a temp dir named after the hash of
+// its content and analyzes the copy instead, so edits made while the
+// analysis runs do not affect it. Each run gets its own snapshot, which it
+// removes when done. Files in the snapshot are read-only and keep their
+// modification times, directories stay writable for tools that create
+// build output next to the sources.
+func (a *analyzeCommand) snapshotInput() error {
+	staging, err := os.MkdirTemp("", snapshotDirPrefix+"staging-")
+	if err != nil {
+		return err
+	}
+	defer removeSnapshot(staging)
+	// keep the name of the input, it is used in output file and report names
+	name := filepath.Base(a.input)
+	digest := sha256.New()
+	if a.isFileInput {
+		err = copySnapshotFile(a.input, filepath.Join(staging, name), digest)
+	} else {
+		err = copySnapshotDir(a.input, filepath.Join(staging, name), digest)
+	}
+	if err != nil {
+		return fmt.Errorf("%w failed to snapshot input %s", err, a.input)
+	}
+
+	snapshotDir, err := os.MkdirTemp("", snapshotDirPrefix+hex.EncodeToString(digest.Sum(nil))[:16]+"-")
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(staging, name), filepath.Join(snapshotDir, name)); err != nil {
+		removeSnapshot(snapshotDir)
+		return fmt.Errorf("%w failed to snapshot input %s", err, a.input)
+	}
+	a.log.Info("analyzing snapshot of input", "input", a.input, "snapshot", snapshotDir)
+	a.snapshotDir = snapshotDir
+	a.originalInput = a.input
+	a.input = filepath.Join(snapshotDir, name)
+	return nil
+}
+
+// unsnapshotURIs rewrites URIs of incidents in the snapshot of the input to
+// the paths of the input, the snapshot is removed after the analysis
+func (a *analyzeCommand) unsnapshotURIs(rulesets []outputv1.RuleSet) {
+	if a.originalInput == "" {
+		return
+	}
+	snapshot, input := a.input, a.originalInput
+	// the dir of a file input is known as its root, see inputRoots
+	if a.isFileInput {
+		snapshot, input = filepath.Dir(snapshot), filepath.Dir(input)
+	}
+	prefixes := []string{filepath.ToSlash(snapshot)}
+	if real, err := filepath.EvalSymlinks(snapshot); err == nil && real != snapshot {
+		prefixes = append(prefixes, filepath.ToSlash(real))
+	}
+	for i := range rulesets {
+		for ruleID, violation := range rulesets[i].Violations {
+			for j, incident := range violation.Incidents {
+				if !strings.HasPrefix(string(incident.URI), "file:") {
+					continue
+				}
+				file := filepath.ToSlash(incident.URI.Filename())
+				for _, prefix := range prefixes {
+					rel, ok := strings.CutPrefix(file, prefix)
+					if ok && (rel == "" || strings.HasPrefix(rel, "/")) {
+						violation.Incidents[j].URI = uri.File(input + filepath.FromSlash(rel))
+						break
+					}
+				}
+			}
+			rulesets[i].Violations[ruleID] = violation
+		}
+	}
+}
+
+func copySnapshotDir(src string, dst string, digest hash.Hash) error {
+	dirs := []string{}
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		fmt.Fprintf(digest, "%s\x00%v\x00", filepath.ToSlash(rel), d.Type())
+		switch {
+		case d.IsDir():
+			dirs = append(dirs, rel)
+			return os.MkdirAll(target, 0755)
+		case d.Type()&fs.ModeSymlink != 0:
+			return copySnapshotSymlink(src, p, target, digest)
+		case d.Type().IsRegular():
+			return copySnapshotFile(p, target, digest)
+		}
+		// sockets, devices and such are not analyzed
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	// set directory times last, creating their content changes them
+	for i := len(dirs) - 1; i >= 0; i-- {
+		info, err := os.Stat(filepath.Join(src, dirs[i]))
+		if err != nil {
+			return err
+		}
+		err = os.Chtimes(filepath.Join(dst, dirs[i]), info.ModTime(), info.ModTime())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copySnapshotFile(src string, dst string, digest hash.Hash) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	source, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	destination, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(io.MultiWriter(destination, digest), source)
+	if closeErr := destination.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	err = os.Chtimes(dst, info.ModTime(), info.ModTime())
+	if err != nil {
+		return err
+	}
+	return os.Chmod(dst, info.Mode().Perm()&^0222)
+}
+
+// copySnapshotSymlink recreates a symlink, links pointing outside of the
+// input are made absolute so that they still resolve from the snapshot
+func copySnapshotSymlink(root string, src string, dst string, digest hash.Hash) error {
+	linkTarget, err := os.Readlink(src)
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(linkTarget) {
+		resolved := filepath.Join(filepath.Dir(src), linkTarget)
+		rel, err := filepath.Rel(root, resolved)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			linkTarget = resolved
+		}
+	}
+	fmt.Fprintf(digest, "%s\x00", linkTarget)
+	return os.Symlink(linkTarget, dst)
+}
+
+// removeSnapshot removes a snapshot dir including its read-only files
+func removeSnapshot(dir string) error {
+	filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			os.Chmod(p, 0644)
+		}
+		return nil
+	})
+	err := os.RemoveAll(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+)
+
+func Test_analyzeCommand_snapshotInput(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "app")
+	if err := os.MkdirAll(filepath.Join(input, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(input, "src", "App.java")
+	if err := os.WriteFile(file, []byte("class App {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(file, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := func() *analyzeCommand {
+		a := &analyzeCommand{input: input, log: logr.Discard()}
+		if err := a.snapshotInput(); err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+	a := snapshot()
+	defer removeSnapshot(a.snapshotDir)
+	if !strings.HasPrefix(filepath.Base(a.snapshotDir), snapshotDirPrefix) || filepath.Base(a.input) != "app" {
+		t.Fatalf("unexpected snapshot location %s", a.input)
+	}
+	info, err := os.Stat(filepath.Join(a.input, "src", "App.java"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(mtime) {
+		t.Errorf("snapshot mtime = %v, want %v", info.ModTime(), mtime)
+	}
+	if info.Mode().Perm()&0222 != 0 {
+		t.Errorf("snapshot file is writable, mode %v", info.Mode())
+	}
+
+	// runs get their own snapshot named after the hash of the content
+	again := snapshot()
+	if again.snapshotDir == a.snapshotDir || snapshotHash(again.snapshotDir) != snapshotHash(a.snapshotDir) {
+		t.Errorf("snapshot of unchanged input = %s, want a new snapshot of the content of %s", again.snapshotDir, a.snapshotDir)
+	}
+	// removing a snapshot when a run finishes leaves the others intact
+	if err := removeSnapshot(again.snapshotDir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(a.input, "src", "App.java")); err != nil {
+		t.Errorf("removing a snapshot removed the snapshot of another run: %v", err)
+	}
+	if err := os.WriteFile(file, []byte("class App { int x; }"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	changed := snapshot()
+	defer removeSnapshot(changed.snapshotDir)
+	if snapshotHash(changed.snapshotDir) == snapshotHash(a.snapshotDir) {
+		t.Errorf("snapshot of changed input %s has the hash of %s", changed.snapshotDir, a.snapshotDir)
+	}
+}
+
+func Test_analyzeCommand_unsnapshotURIs(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "app")
+	if err := os.MkdirAll(filepath.Join(input, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(input, "src", "App.java"), []byte("class App {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	a := &analyzeCommand{input: input, log: logr.Discard(), runLocal: true}
+	if err := a.snapshotInput(); err != nil {
+		t.Fatal(err)
+	}
+	defer removeSnapshot(a.snapshotDir)
+
+	// incidents as reported by providers analyzing the snapshot
+	line := 1
+	rulesets := []outputv1.RuleSet{{
+		Name: "test",
+		Violations: map[string]outputv1.Violation{
+			"rule-00010": {Incidents: []outputv1.Incident{
+				{URI: uri.File(filepath.Join(a.input, "src", "App.java")), LineNumber: &line},
+				{URI: uri.File(a.input)},
+				{URI: uri.File(filepath.Join(a.snapshotDir, "m2", "lib.jar"))},
+			}},
+		},
+	}}
+	rulesets, err := a.postProcessRuleSets(rulesets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	incidents := rulesets[0].Violations["rule-00010"].Incidents
+	want := []uri.URI{
+		uri.File(filepath.Join(input, "src", "App.java")),
+		uri.File(input),
+		uri.File(filepath.Join(a.snapshotDir, "m2", "lib.jar")),
+	}
+	for i, incident := range incidents {
+		if incident.URI != want[i] {
+			t.Errorf("incident URI = %s, want %s", incident.URI, want[i])
+		}
+	}
+	if a.incidentInDependency(incidents[0]) {
+		t.Errorf("incident in the input %s is counted as dependency", incidents[0].URI)
+	}
+
+	a.relativePaths = true
+	rulesets, err = a.postProcessRuleSets(rulesets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rulesets[0].Violations["rule-00010"].Incidents[0].URI; got != "src/App.java" {
+		t.Errorf("relative incident URI = %s, want src/App.java", got)
+	}
+}
+
+func Test_analyzeCommand_validateErrorRemovesSnapshot(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	input := filepath.Join(t.TempDir(), "app")
+	if err := os.MkdirAll(input, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cmd := NewAnalyzeCmd(logr.Discard())
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	// the score is checked after the snapshot is taken
+	cmd.SetArgs([]string{"--input", input, "--output", filepath.Join(t.TempDir(), "output"),
+		"--rules", t.TempDir(), "--snapshot-input", "--fail-on-score", "200"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected validation error")
+	}
+	entries, err := os.ReadDir(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		t.Errorf("failed validation left %s in the temp dir", entry.Name())
+	}
+}
+
+// snapshotHash returns the content hash in the name of a snapshot dir
+func snapshotHash(dir string) string {
+	hash, _, _ := strings.Cut(strings.TrimPrefix(filepath.Base(dir), snapshotDirPrefix), "-")
+	return hash
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+const (
+	// --source-url-template value deriving the template from the git remote
+	sourceURLAuto = "auto"
+	// incident variable with the link to the incident on the source hosting
+	sourceURLVariable = "kantra.io/sourceURL"
+)
+
+// matches scp-like git remotes, e.g. git@github.com:org/app.git
+var scpRemote = regexp.MustCompile(`^[\w.-]+@([\w.-]+):(.+)$`)
+
+// sourceLinks builds links to files of the input on the source hosting
+type sourceLinks struct {
+	template string
+	ref      string
+	// path of the input in the repository
+	prefix string
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// remoteBaseURL converts a git remote to the https URL of the repository
+func remoteBaseURL(remote string) (string, string, error) {
+	remote = strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+	var host, repo string
+	if m := scpRemote.FindStringSubmatch(remote); m != nil {
+		host, repo = m[1], m[2]
+	} else {
+		_, rest, ok := strings.Cut(remote, "://")
+		if !ok {
+			return "", "", fmt.Errorf("unsupported git remote %s", remote)
+		}
+		// drop user info, e.g. of ssh://git@github.com/org/app
+		if _, after, ok := strings.Cut(rest, "@"); ok {
+			rest = after
+		}
+		host, repo, ok = strings.Cut(rest, "/")
+		if !ok {
+			return "", "", fmt.Errorf("unsupported git remote %s", remote)
+		}
+		// drop ssh ports
+		host, _, _ = strings.Cut(host, ":")
+	}
+	return host, fmt.Sprintf("https://%s/%s", host, strings.Trim(repo, "/")), nil
+}
+
+// sourceURLTemplate returns the link template of the hosting a git remote points to
+func sourceURLTemplate(remote string) (string, error) {
+	host, base, err := remoteBaseURL(remote)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case strings.Contains(host, "gitlab"):
+		return base + "/-/blob/{ref}/{path}#L{line}", nil
+	case strings.Contains(host, "bitbucket"):
+		return base + "/src/{ref}/{path}#lines-{line}", nil
+	}
+	return base + "/blob/{ref}/{path}#L{line}", nil
+}
+
+// resolveSourceLinks sets up links for --source-url-template, the ref and
+// the path of the input in the repository are read from git when it is one
+func (a *analyzeCommand) resolveSourceLinks() error {
+	if a.sourceURLTemplate == "" || a.isFileInput {
+		return nil
+	}
+	links := &sourceLinks{template: a.sourceURLTemplate, ref: "HEAD"}
+	top, err := gitOutput(a.input, "rev-parse", "--show-toplevel")
+	if err == nil {
+		if ref, err := gitOutput(a.input, "rev-parse", "HEAD"); err == nil {
+			links.ref = ref
+		}
+		if real, err := filepath.EvalSymlinks(a.input); err == nil {
+			if rel, err := filepath.Rel(top, real); err == nil && rel != "." {
+				links.prefix = filepath.ToSlash(rel)
+			}
+		}
+	}
+	if a.sourceURLTemplate == sourceURLAuto {
+		if err != nil {
+			return fmt.Errorf("%w input is not a git repository, source-url-template cannot be derived", err)
+		}
+		remote, err := gitOutput(a.input, "remote", "get-url", "origin")
+		if err != nil {
+			return fmt.Errorf("%w failed to get git remote of input", err)
+		}
+		links.template, err = sourceURLTemplate(remote)
+		if err != nil {
+			return err
+		}
+	}
+	if !strings.Contains(links.template, "{path}") {
+		return fmt.Errorf("source-url-template must contain {path}")
+	}
+	a.log.V(1).Info("linking incidents to source hosting", "template", links.template, "ref", links.ref)
+	a.sourceLinks = links
+	return nil
+}
+
+// url returns the link to a file of the input, the fragment holding the
+// line is dropped for incidents without one
+func (l *sourceLinks) url(relPath string, line *int) string {
+	template := l.template
+	if line == nil {
+		if i := strings.LastIndex(template, "#"); i >= 0 && strings.Contains(template[i:], "{line}") {
+			template = template[:i]
+		}
+	}
+	lineNumber := ""
+	if line != nil {
+		lineNumber = strconv.Itoa(*line)
+	}
+	return strings.NewReplacer(
+		"{ref}", l.ref,
+		"{path}", path.Join(l.prefix, relPath),
+		"{line}", lineNumber,
+	).Replace(template)
+}
+
+// addSourceLinks adds links to the incidents in the input
+func (a *analyzeCommand) addSourceLinks(rulesets []outputv1.RuleSet) {
+	if a.sourceLinks == nil {
+		return
+	}
+	roots := a.inputRoots()
+	for i := range rulesets {
+		for ruleID, violation := range rulesets[i].Violations {
+			for j, incident := range violation.Incidents {
+				rel := relativeURI(incident.URI, roots)
+				if rel == incident.URI || rel == "." {
+					continue
+				}
+				variables := map[string]interface{}{}
+				for k, v := range incident.Variables {
+					variables[k] = v
+				}
+				variables[sourceURLVariable] = a.sourceLinks.url(string(rel), incident.LineNumber)
+				violation.Incidents[j].Variables = variables
+			}
+			rulesets[i].Violations[ruleID] = violation
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+)
+
+func Test_sourceURLTemplate(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   string
+	}{
+		{"git@github.com:org/app.git", "https://github.com/org/app/blob/{ref}/{path}#L{line}"},
+		{"https://github.com/org/app.git", "https://github.com/org/app/blob/{ref}/{path}#L{line}"},
+		{"ssh://git@gitlab.example.com:2222/group/sub/app.git", "https://gitlab.example.com/group/sub/app/-/blob/{ref}/{path}#L{line}"},
+		{"https://user@bitbucket.org/org/app", "https://bitbucket.org/org/app/src/{ref}/{path}#lines-{line}"},
+	}
+	for _, tt := range tests {
+		got, err := sourceURLTemplate(tt.remote)
+		if err != nil {
+			t.Errorf("sourceURLTemplate(%s) error = %v", tt.remote, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("sourceURLTemplate(%s) = %s, want %s", tt.remote, got, tt.want)
+		}
+	}
+	if _, err := sourceURLTemplate("/srv/git/app"); err == nil {
+		t.Errorf("sourceURLTemplate() of a local path must fail")
+	}
+}
+
+func Test_analyzeCommand_addSourceLinks(t *testing.T) {
+	input := t.TempDir()
+	line := 12
+	a := &analyzeCommand{
+		input: input,
+		sourceLinks: &sourceLinks{
+			template: "https://github.com/org/app/blob/{ref}/{path}#L{line}",
+			ref:      "abc123",
+			prefix:   "services/app",
+		},
+	}
+	rulesets := []outputv1.RuleSet{
+		{
+			Name: "test",
+			Violations: map[string]outputv1.Violation{
+				"rule-00010": {
+					Incidents: []outputv1.Incident{
+						{URI: uri.File(filepath.Join(input, "src", "Foo.java")), LineNumber: &line},
+						{URI: uri.File(filepath.Join(input, "pom.xml"))},
+						{URI: uri.File(filepath.Join(filepath.Dir(input), "lib", "Bar.java")), LineNumber: &line},
+					},
+				},
+			},
+		},
+	}
+	a.addSourceLinks(rulesets)
+	incidents := rulesets[0].Violations["rule-00010"].Incidents
+	if got := incidents[0].Variables[sourceURLVariable]; got != "https://github.com/org/app/blob/abc123/services/app/src/Foo.java#L12" {
+		t.Errorf("link of incident = %v", got)
+	}
+	if got := incidents[1].Variables[sourceURLVariable]; got != "https://github.com/org/app/blob/abc123/services/app/pom.xml" {
+		t.Errorf("link of incident without line = %v", got)
+	}
+	if _, ok := incidents[2].Variables[sourceURLVariable]; ok {
+		t.Errorf("incident outside of the input must not be linked")
+	}
+}
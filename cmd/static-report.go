@@ -54,7 +54,7 @@ func validateFlags(analysisOutputPaths []string, appNames []string, depsOutputs
 // loadApplications loads applications from provider config
 func loadApplications(apps []*Application) error {
 	for _, app := range apps {
-		analysisReport, err := os.ReadFile(app.analysisPath)
+		analysisReport, err := readOutputFile(app.analysisPath)
 		if err != nil {
 			return err
 		}
@@ -63,7 +63,7 @@ func loadApplications(apps []*Application) error {
 			return err
 		}
 		if app.depsPath != "" {
-			depsReport, err := os.ReadFile(app.depsPath)
+			depsReport, err := readOutputFile(app.depsPath)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// stdinInput as --input reads the input as a tar stream from stdin
+const stdinInput = "-"
+
+// extractInputTar extracts a tar stream, gzip compressed or not, into a new
+// dir of parent and returns the input to analyze, the only top level dir of
+// the stream when it has one, e.g. with git archive --prefix. Symlinks of
+// the stream pointing outside of it are not extracted, they are returned as
+// skipped files of the input.
+func extractInputTar(r io.Reader, parent string) (string, []skippedFile, error) {
+	reader := bufio.NewReader(r)
+	magic, _ := reader.Peek(2)
+	dest := filepath.Join(parent, "stdin")
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return "", nil, err
+	}
+	var symlinks []tarSymlink
+	var err error
+	if bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		symlinks, err = extractTarGz(reader, dest)
+	} else {
+		symlinks, err = extractTar(reader, dest)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("%w failed to extract input tar stream", err)
+	}
+	entries, err := os.ReadDir(dest)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(entries) == 0 {
+		return "", nil, fmt.Errorf("input tar stream is empty")
+	}
+	input := dest
+	if len(entries) == 1 && entries[0].IsDir() {
+		input = filepath.Join(dest, entries[0].Name())
+		// skipped symlinks next to the top level dir are part of the input too
+		for _, link := range symlinks {
+			if !strings.HasPrefix(path.Clean(link.name), entries[0].Name()+"/") {
+				input = dest
+			}
+		}
+	}
+	skipped := []skippedFile{}
+	for _, link := range symlinks {
+		target, _ := extractPath(dest, link.name)
+		rel, err := filepath.Rel(input, target)
+		if err != nil {
+			return "", nil, err
+		}
+		skipped = append(skipped, skippedFile{Path: filepath.ToSlash(rel), Provider: skippedAllProviders,
+			Reason: skipReasonExternalSymlink, Detail: link.target})
+	}
+	return input, skipped, nil
+}
+
+// readStdinInput extracts the tar stream of stdin to a temp dir and
+// analyzes it as the input
+func (a *analyzeCommand) readStdinInput() error {
+	if stat, err := os.Stdin.Stat(); err == nil && stat.Mode()&os.ModeCharDevice != 0 {
+		return fmt.Errorf("input - reads a tar stream from stdin, e.g. git archive HEAD | kantra analyze --input -")
+	}
+	dir, err := os.MkdirTemp("", "kantra-input-")
+	if err != nil {
+		return err
+	}
+	a.stdinDir = dir
+	input, skipped, err := extractInputTar(os.Stdin, dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range skipped {
+		a.log.Info("skipping symlink of input stream pointing outside of it", "path", file.Path, "target", file.Detail)
+	}
+	a.addSkipped(skipped...)
+	a.log.Info("extracted input from stdin", "dir", input)
+	a.input = input
+	return nil
+}
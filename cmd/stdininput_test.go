@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func Test_extractInputTar(t *testing.T) {
+	files := map[string]string{"app/pom.xml": "<project/>", "app/src/App.java": "class App {}"}
+	compressed := tarGz(t, files)
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, stream := range map[string][]byte{"gzip": compressed, "plain": plain} {
+		t.Run(name, func(t *testing.T) {
+			parent := t.TempDir()
+			input, _, err := extractInputTar(bytes.NewReader(stream), parent)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the only top level dir is the input, e.g. of git archive --prefix
+			if want := filepath.Join(parent, "stdin", "app"); input != want {
+				t.Errorf("extractInputTar() = %s, want %s", input, want)
+			}
+			if _, err := os.Stat(filepath.Join(input, "src", "App.java")); err != nil {
+				t.Errorf("extractInputTar() did not extract App.java: %v", err)
+			}
+		})
+	}
+
+	parent := t.TempDir()
+	input, _, err := extractInputTar(bytes.NewReader(tarGz(t, map[string]string{"pom.xml": "<project/>", "README.md": ""})), parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(parent, "stdin"); input != want {
+		t.Errorf("extractInputTar() = %s, want %s", input, want)
+	}
+	if _, _, err := extractInputTar(bytes.NewReader(tarGz(t, map[string]string{"../escape": ""})), t.TempDir()); err == nil {
+		t.Errorf("extractInputTar() expected error for entry outside of the input")
+	}
+}
+
+func Test_extractInputTar_links(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on windows")
+	}
+	var stream bytes.Buffer
+	tw := tar.NewWriter(&stream)
+	for _, header := range []*tar.Header{
+		// links come before their targets and the entries below them
+		{Name: "app/link", Typeflag: tar.TypeSymlink, Linkname: "src"},
+		{Name: "app/src/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "app/src/App.java", Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len("class App {}"))},
+		{Name: "app/src/Copy.java", Typeflag: tar.TypeLink, Linkname: "app/src/App.java", Mode: 0644},
+		{Name: "app/etc", Typeflag: tar.TypeSymlink, Linkname: "/etc"},
+		{Name: "app/src/up", Typeflag: tar.TypeSymlink, Linkname: "../../.."},
+		{Name: "app/missing", Typeflag: tar.TypeSymlink, Linkname: "missing.txt"},
+	} {
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Size > 0 {
+			if _, err := tw.Write([]byte("class App {}")); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	input, skipped, err := extractInputTar(&stream, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(input) != "app" {
+		t.Errorf("extractInputTar() = %s, want the app dir", input)
+	}
+	for _, name := range []string{"link/App.java", "src/Copy.java"} {
+		if content, err := os.ReadFile(filepath.Join(input, name)); err != nil || string(content) != "class App {}" {
+			t.Errorf("extractInputTar() did not extract %s: %v", name, err)
+		}
+	}
+	// broken links are left to the symlink policy of the analysis
+	if _, err := os.Lstat(filepath.Join(input, "missing")); err != nil {
+		t.Errorf("extractInputTar() did not extract broken symlink: %v", err)
+	}
+	for _, name := range []string{"etc", "src/up"} {
+		if _, err := os.Lstat(filepath.Join(input, name)); !os.IsNotExist(err) {
+			t.Errorf("extractInputTar() extracted symlink %s pointing outside of the stream", name)
+		}
+	}
+	want := []skippedFile{
+		{Path: "etc", Provider: skippedAllProviders, Reason: skipReasonExternalSymlink, Detail: "/etc"},
+		{Path: "src/up", Provider: skippedAllProviders, Reason: skipReasonExternalSymlink, Detail: "../../.."},
+	}
+	if !reflect.DeepEqual(skipped, want) {
+		t.Errorf("extractInputTar() skipped = %v, want %v", skipped, want)
+	}
+}
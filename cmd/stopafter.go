@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/konveyor/analyzer-lsp/engine"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// validateStopAfter checks the category of --stop-after, the rules are run
+// in two passes by kantra which needs containerless mode
+func (a *analyzeCommand) validateStopAfter() error {
+	if a.stopAfter == "" {
+		if a.stopAfterIncidents != 0 {
+			return fmt.Errorf("stop-after-incidents requires a category, set --stop-after")
+		}
+		return nil
+	}
+	switch outputv1.Category(a.stopAfter) {
+	case outputv1.Mandatory, outputv1.Optional, outputv1.Potential:
+	default:
+		return fmt.Errorf("stop-after must be one of 'mandatory', 'optional' or 'potential'")
+	}
+	if a.stopAfterIncidents < 0 {
+		return fmt.Errorf("stop-after-incidents must not be negative")
+	}
+	if !a.runLocal {
+		return fmt.Errorf("stop-after requires containerless mode, set --run-local")
+	}
+	return nil
+}
+
+// splitRuleSets returns the rules of the category and the other rules.
+// Tagging rules are in both, rules of either may depend on the tags.
+func splitRuleSets(ruleSets []engine.RuleSet, category outputv1.Category) ([]engine.RuleSet, []engine.RuleSet) {
+	first := []engine.RuleSet{}
+	rest := []engine.RuleSet{}
+	for _, rs := range ruleSets {
+		firstRules := []engine.Rule{}
+		restRules := []engine.Rule{}
+		for _, rule := range rs.Rules {
+			tagging := rule.Perform.Tag != nil
+			inCategory := rule.Category != nil && *rule.Category == category
+			if inCategory || tagging {
+				firstRules = append(firstRules, rule)
+			}
+			if !inCategory || tagging {
+				restRules = append(restRules, rule)
+			}
+		}
+		firstSet, restSet := rs, rs
+		firstSet.Rules, restSet.Rules = firstRules, restRules
+		first = append(first, firstSet)
+		rest = append(rest, restSet)
+	}
+	return first, rest
+}
+
+// categoryIncidents returns the incidents of the violations of the category
+func categoryIncidents(rulesets []outputv1.RuleSet, category outputv1.Category) int {
+	incidents := 0
+	for _, rs := range rulesets {
+		for _, violation := range rs.Violations {
+			if violation.Category != nil && *violation.Category == category {
+				incidents += len(violation.Incidents)
+			}
+		}
+	}
+	return incidents
+}
+
+// mergeRuleSets adds the results of the rulesets of the second pass to the
+// ones of the first pass, by ruleset name
+func mergeRuleSets(first []outputv1.RuleSet, second []outputv1.RuleSet) []outputv1.RuleSet {
+	merged := map[string]*outputv1.RuleSet{}
+	for i := range first {
+		merged[first[i].Name] = &first[i]
+	}
+	for _, rs := range second {
+		existing, ok := merged[rs.Name]
+		if !ok {
+			rs := rs
+			merged[rs.Name] = &rs
+			continue
+		}
+		existing.Tags = uniqueStrings(append(existing.Tags, rs.Tags...))
+		existing.Unmatched = uniqueStrings(append(existing.Unmatched, rs.Unmatched...))
+		existing.Skipped = uniqueStrings(append(existing.Skipped, rs.Skipped...))
+		if existing.Violations == nil {
+			existing.Violations = map[string]outputv1.Violation{}
+		}
+		for ruleID, violation := range rs.Violations {
+			existing.Violations[ruleID] = violation
+		}
+		if existing.Insights == nil {
+			existing.Insights = map[string]outputv1.Violation{}
+		}
+		for ruleID, insight := range rs.Insights {
+			existing.Insights[ruleID] = insight
+		}
+		if existing.Errors == nil {
+			existing.Errors = map[string]string{}
+		}
+		for ruleID, reason := range rs.Errors {
+			existing.Errors[ruleID] = reason
+		}
+	}
+	rulesets := []outputv1.RuleSet{}
+	for _, name := range sortedMapKeys(merged) {
+		rulesets = append(rulesets, *merged[name])
+	}
+	return rulesets
+}
+
+// skipRuleSets marks the rules that were not run as skipped, tagging rules
+// already ran in the first pass
+func skipRuleSets(rulesets []outputv1.RuleSet, notRun []engine.RuleSet) {
+	byName := map[string]*outputv1.RuleSet{}
+	for i := range rulesets {
+		byName[rulesets[i].Name] = &rulesets[i]
+	}
+	for _, rs := range notRun {
+		output, ok := byName[rs.Name]
+		if !ok {
+			continue
+		}
+		for _, rule := range rs.Rules {
+			if rule.Perform.Tag == nil {
+				output.Skipped = append(output.Skipped, rule.RuleID)
+			}
+		}
+		sort.Strings(output.Skipped)
+	}
+}
+
+// runRules evaluates the rules with the engine. With --stop-after the rules
+// of the category run first and the others only when their incidents do not
+// exceed --stop-after-incidents.
+func (a *analyzeCommand) runRules(ctx context.Context, eng analysisEngine, ruleSets []engine.RuleSet, selectors ...engine.RuleSelector) []outputv1.RuleSet {
+	if a.stopAfter == "" {
+		return eng.RunRules(ctx, ruleSets, selectors...)
+	}
+	category := outputv1.Category(a.stopAfter)
+	first, rest := splitRuleSets(ruleSets, category)
+	a.log.Info("evaluating rules of category first", "category", category)
+	rulesets := eng.RunRules(ctx, first, selectors...)
+	if incidents := categoryIncidents(rulesets, category); incidents > a.stopAfterIncidents {
+		a.log.Info("stopping analysis, rules of category exceed incidents", "category", category,
+			"incidents", incidents, "limit", a.stopAfterIncidents)
+		skipRuleSets(rulesets, rest)
+		return rulesets
+	}
+	return mergeRuleSets(rulesets, eng.RunRules(ctx, rest, selectors...))
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/konveyor/analyzer-lsp/engine"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// matchingEngine finds one incident for each rule that is not a tagging rule
+type matchingEngine struct {
+	runs [][]string
+}
+
+func (m *matchingEngine) RunRules(ctx context.Context, ruleSets []engine.RuleSet, selectors ...engine.RuleSelector) []outputv1.RuleSet {
+	rulesets := []outputv1.RuleSet{}
+	ruleIDs := []string{}
+	for _, rs := range ruleSets {
+		output := outputv1.RuleSet{Name: rs.Name, Violations: map[string]outputv1.Violation{}}
+		for _, rule := range rs.Rules {
+			ruleIDs = append(ruleIDs, rule.RuleID)
+			if rule.Perform.Tag != nil {
+				output.Tags = append(output.Tags, rule.Perform.Tag...)
+				continue
+			}
+			output.Violations[rule.RuleID] = outputv1.Violation{Category: rule.Category, Incidents: make([]outputv1.Incident, 1)}
+		}
+		rulesets = append(rulesets, output)
+	}
+	m.runs = append(m.runs, ruleIDs)
+	return rulesets
+}
+
+func (m *matchingEngine) Stop() {}
+
+func Test_analyzeCommand_runRules(t *testing.T) {
+	mandatory, optional := outputv1.Mandatory, outputv1.Optional
+	ruleSets := []engine.RuleSet{
+		{
+			Name: "eap8",
+			Rules: []engine.Rule{
+				{RuleMeta: engine.RuleMeta{RuleID: "tag-000"}, Perform: engine.Perform{Tag: []string{"Java EE"}}},
+				{RuleMeta: engine.RuleMeta{RuleID: "rule-000", Category: &mandatory}},
+				{RuleMeta: engine.RuleMeta{RuleID: "rule-001", Category: &optional}},
+				{RuleMeta: engine.RuleMeta{RuleID: "rule-002"}},
+			},
+		},
+	}
+	tests := []struct {
+		name        string
+		incidents   int
+		wantRuns    [][]string
+		wantRules   []string
+		wantSkipped []string
+	}{
+		{
+			name:        "stops",
+			wantRuns:    [][]string{{"tag-000", "rule-000"}},
+			wantRules:   []string{"rule-000"},
+			wantSkipped: []string{"rule-001", "rule-002"},
+		},
+		{
+			name:      "continues within incidents",
+			incidents: 1,
+			wantRuns:  [][]string{{"tag-000", "rule-000"}, {"tag-000", "rule-001", "rule-002"}},
+			wantRules: []string{"rule-000", "rule-001", "rule-002"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eng := &matchingEngine{}
+			a := &analyzeCommand{stopAfter: "mandatory", stopAfterIncidents: tt.incidents, log: logr.Discard()}
+			rulesets := a.runRules(context.Background(), eng, ruleSets)
+			if !reflect.DeepEqual(eng.runs, tt.wantRuns) {
+				t.Errorf("runRules() ran %v, want %v", eng.runs, tt.wantRuns)
+			}
+			if len(rulesets) != 1 {
+				t.Fatalf("runRules() = %d rulesets, want 1", len(rulesets))
+			}
+			rules := sortedMapKeys(rulesets[0].Violations)
+			sort.Strings(rules)
+			if !reflect.DeepEqual(rules, tt.wantRules) {
+				t.Errorf("runRules() violations = %v, want %v", rules, tt.wantRules)
+			}
+			if (len(rulesets[0].Skipped) > 0 || len(tt.wantSkipped) > 0) && !reflect.DeepEqual(rulesets[0].Skipped, tt.wantSkipped) {
+				t.Errorf("runRules() skipped = %v, want %v", rulesets[0].Skipped, tt.wantSkipped)
+			}
+			if !reflect.DeepEqual(rulesets[0].Tags, []string{"Java EE"}) {
+				t.Errorf("runRules() tags = %v", rulesets[0].Tags)
+			}
+		})
+	}
+
+	for _, a := range []*analyzeCommand{
+		{stopAfter: "blocker", runLocal: true},
+		{stopAfter: "mandatory"},
+		{stopAfterIncidents: 3, runLocal: true},
+	} {
+		if err := a.validateStopAfter(); err == nil {
+			t.Errorf("validateStopAfter() expected error for %+v", a)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/konveyor-ecosystem/kantra/pkg/container"
+)
+
+// where the helper container populating a streamed volume mounts it
+const streamVolumePath = "/volume"
+
+// writeInputTar writes the files of root not excluded by ignore as a tar
+// archive, only limits it to a single file of root
+func writeInputTar(w io.Writer, root string, ignore *kantraIgnore, only string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if (only != "" && rel != only) || ignore.Ignored(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		link := ""
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, filepath.ToSlash(link))
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+		if d.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		file, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.Copy(tw, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// createStreamedVolume creates a volume holding a copy of the input, which
+// is streamed into it as a tar archive. Unlike bind mounts of the input,
+// providers read the volume at native speed in podman machines, and paths
+// excluded by .kantraignore are left out.
+func (a *analyzeCommand) createStreamedVolume(ctx context.Context, volName string) error {
+	cmd := exec.CommandContext(ctx, Settings.ContainerBinary, "volume", "create", volName)
+	cmd.Stdout = a.consoleWriter()
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+	// for cleanup
+	a.volumeName = volName
+
+	root, only := a.input, ""
+	if a.isFileInput {
+		root, only = filepath.Dir(a.input), filepath.Base(a.input)
+	}
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeInputTar(writer, root, a.inputIgnore, only))
+	}()
+	defer reader.Close()
+	a.log.Info("streaming input into container volume", "input", a.input, "volume", volName)
+	err := container.NewContainer().Run(
+		ctx,
+		container.WithImage(Settings.RunnerImage),
+		container.WithPlatform(a.imagePlatform(ctx, Settings.RunnerImage)),
+		container.WithLog(a.log.V(1)),
+		container.WithVolumes(map[string]string{volName: streamVolumePath}),
+		container.WithStdin(reader),
+		container.WithStdout(a.consoleWriter()),
+		container.WithEntrypointBin("tar"),
+		container.WithEntrypointArgs("-x", "-f", "-", "-C", streamVolumePath),
+		container.WithContainerToolBin(Settings.ContainerBinary),
+		container.WithCleanup(true),
+	)
+	if err != nil {
+		return fmt.Errorf("%w failed to stream input into volume %s", err, volName)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func tarNames(t *testing.T, data []byte) []string {
+	names := []string{}
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, header.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func Test_writeInputTar(t *testing.T) {
+	root := t.TempDir()
+	writeTestFile(t, filepath.Join(root, kantraIgnoreFile), "build/\n*.log\n")
+	writeTestFile(t, filepath.Join(root, "pom.xml"), "<project/>")
+	writeTestFile(t, filepath.Join(root, "src", "App.java"), "class App {}")
+	writeTestFile(t, filepath.Join(root, "build", "App.class"), "")
+	writeTestFile(t, filepath.Join(root, "debug.log"), "")
+	if err := os.Symlink("pom.xml", filepath.Join(root, "link.xml")); err != nil {
+		t.Fatal(err)
+	}
+	ignore, err := loadKantraIgnore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := writeInputTar(buf, root, ignore, ""); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{kantraIgnoreFile, "link.xml", "pom.xml", "src/", "src/App.java"}
+	if got := tarNames(t, buf.Bytes()); !reflect.DeepEqual(got, want) {
+		t.Errorf("writeInputTar() = %v, want %v", got, want)
+	}
+
+	buf.Reset()
+	if err := writeInputTar(buf, root, nil, "pom.xml"); err != nil {
+		t.Fatal(err)
+	}
+	if got := tarNames(t, buf.Bytes()); !reflect.DeepEqual(got, []string{"pom.xml"}) {
+		t.Errorf("writeInputTar() of a single file = %v", got)
+	}
+}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// keys of rule conditions that are not provider capabilities
+var conditionKeywords = []string{"and", "or", "from", "as", "ignore", "not"}
+
+// matches the <provider>.<capability> keys of rule conditions
+var capabilityKey = regexp.MustCompile(`^[\w-]+\.[\w-]+$`)
+
+var ruleCategories = []string{"mandatory", "optional", "potential"}
+
+// ruleProblem is a reason a rule file fails to load, printed as
+// <file>:<rule ID>: <problem>
+type ruleProblem struct {
+	File    string
+	RuleID  string
+	Problem string
+}
+
+func (p ruleProblem) String() string {
+	if p.RuleID == "" {
+		return fmt.Sprintf("%s: %s", p.File, p.Problem)
+	}
+	return fmt.Sprintf("%s:%s: %s", p.File, p.RuleID, p.Problem)
+}
+
+func isRuleTestFile(path string) bool {
+	return strings.HasSuffix(path, ".test.yaml") || strings.HasSuffix(path, ".test.yml")
+}
+
+// checkRuleFiles returns the problems of the yaml rule files in paths. The
+// rule parser skips files that are not valid yaml and drops rules it fails
+// to parse, which silently skews the results.
+func checkRuleFiles(paths []string) ([]ruleProblem, error) {
+	problems := []ruleProblem{}
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := filepath.Ext(path)
+			if d.IsDir() || (ext != ".yaml" && ext != ".yml") || isRuleTestFile(path) {
+				return nil
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if filepath.Base(path) == "ruleset.yaml" {
+				problems = append(problems, checkRuleSetFile(path, content)...)
+			} else {
+				problems = append(problems, checkRuleFile(path, content)...)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return problems, nil
+}
+
+func checkRuleSetFile(path string, content []byte) []ruleProblem {
+	ruleset := map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &ruleset); err != nil {
+		return []ruleProblem{{File: path, Problem: fmt.Sprintf("invalid ruleset: %v", err)}}
+	}
+	if _, ok := ruleset["name"].(string); !ok {
+		return []ruleProblem{{File: path, Problem: "ruleset must have a name"}}
+	}
+	if _, ok := ruleset["rules"]; ok {
+		return []ruleProblem{{File: path, Problem: "ruleset must not have rules, rules go in other files of its dir"}}
+	}
+	return nil
+}
+
+func checkRuleFile(path string, content []byte) []ruleProblem {
+	rules := []map[string]interface{}{}
+	if err := yaml.Unmarshal(content, &rules); err != nil {
+		return []ruleProblem{{File: path, Problem: fmt.Sprintf("not a list of rules: %v", err)}}
+	}
+	problems := []ruleProblem{}
+	ruleIDs := map[string]bool{}
+	for i, rule := range rules {
+		ruleID, ok := rule["ruleID"].(string)
+		if !ok || ruleID == "" {
+			problems = append(problems, ruleProblem{File: path, Problem: fmt.Sprintf("rule %d has no ruleID", i+1)})
+			continue
+		}
+		if ruleIDs[ruleID] {
+			problems = append(problems, ruleProblem{File: path, RuleID: ruleID, Problem: "duplicate ruleID"})
+		}
+		ruleIDs[ruleID] = true
+		for _, problem := range checkRule(ruleID, rule) {
+			problems = append(problems, ruleProblem{File: path, RuleID: ruleID, Problem: problem})
+		}
+	}
+	return problems
+}
+
+func checkRule(ruleID string, rule map[string]interface{}) []string {
+	problems := []string{}
+	if strings.ContainsAny(ruleID, "\n;") {
+		problems = append(problems, "ruleID must not contain new lines or semicolons")
+	}
+	message, hasMessage := rule["message"]
+	if _, ok := message.(string); hasMessage && !ok {
+		problems = append(problems, "message must be a string")
+	}
+	tag, hasTag := rule["tag"]
+	if hasTag && !isStringList(tag) {
+		problems = append(problems, "tag must be a list of strings")
+	}
+	if !hasMessage && !hasTag {
+		problems = append(problems, "rule must have a message or a tag")
+	}
+	if labels, ok := rule["labels"]; ok && !isStringList(labels) {
+		problems = append(problems, "labels must be a list of strings")
+	}
+	if category, ok := rule["category"]; ok {
+		if c, ok := category.(string); !ok || !slices.Contains(ruleCategories, c) {
+			problems = append(problems, fmt.Sprintf("category must be one of %s", strings.Join(ruleCategories, ", ")))
+		}
+	}
+	if effort, ok := rule["effort"]; ok {
+		if _, ok := effort.(int); !ok {
+			problems = append(problems, "effort must be an integer")
+		}
+	}
+	when, ok := rule["when"]
+	if !ok {
+		return append(problems, "rule must have a when condition")
+	}
+	return append(problems, checkCondition(when)...)
+}
+
+func checkCondition(condition interface{}) []string {
+	conditionMap, ok := condition.(map[interface{}]interface{})
+	if !ok {
+		return []string{"condition must be an object"}
+	}
+	problems := []string{}
+	capabilities := 0
+	keys := []string{}
+	for k := range conditionMap {
+		keys = append(keys, fmt.Sprint(k))
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := conditionMap[key]
+		switch {
+		case key == "and" || key == "or":
+			conditions, ok := value.([]interface{})
+			if !ok || len(conditions) == 0 {
+				problems = append(problems, fmt.Sprintf("%s must be a list of conditions", key))
+				continue
+			}
+			for _, c := range conditions {
+				problems = append(problems, checkCondition(c)...)
+			}
+			capabilities++
+		case key == "from" || key == "as":
+			if _, ok := value.(string); !ok {
+				problems = append(problems, fmt.Sprintf("%s must be a string", key))
+			}
+		case key == "ignore" || key == "not":
+			if _, ok := value.(bool); !ok {
+				problems = append(problems, fmt.Sprintf("%s must be a boolean", key))
+			}
+		case capabilityKey.MatchString(key):
+			capabilities++
+		default:
+			problems = append(problems, fmt.Sprintf("unknown condition %s, must be one of %s or <provider>.<capability>",
+				key, strings.Join(conditionKeywords, ", ")))
+		}
+	}
+	if capabilities == 0 {
+		problems = append(problems, "condition must have a <provider>.<capability>, and or or")
+	}
+	return problems
+}
+
+func isStringList(v interface{}) bool {
+	values, ok := v.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, value := range values {
+		if _, ok := value.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// checkStrictRules fails when rule files of --rules fail to load, listing
+// all their problems, before the providers are started
+func (a *analyzeCommand) checkStrictRules() error {
+	if !a.strictRules {
+		return nil
+	}
+	problems, err := checkRuleFiles(a.rules)
+	if err != nil {
+		return err
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	lines := []string{}
+	for _, p := range problems {
+		lines = append(lines, p.String())
+	}
+	return fmt.Errorf("%d problems in rules:\n%s", len(problems), strings.Join(lines, "\n"))
+}
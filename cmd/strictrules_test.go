@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_checkRuleFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, filepath.Join(dir, "ruleset.yaml"), "name: custom\n")
+	writeTestFile(t, filepath.Join(dir, "valid.yaml"), `- ruleID: valid-00001
+  category: mandatory
+  effort: 1
+  message: Replace the EJB
+  when:
+    or:
+    - java.referenced:
+        pattern: javax.ejb.Stateful
+    - builtin.file:
+        pattern: ejb-jar.xml
+`)
+	writeTestFile(t, filepath.Join(dir, "invalid.yaml"), `- ruleID: invalid-00001
+  category: blocker
+  message: [not a string]
+  when:
+    java.referenced:
+      pattern: javax.ejb.Stateful
+    unknown: true
+- ruleID: invalid-00001
+  tag: Spring
+- message: no rule ID
+  when:
+    builtin.file:
+      pattern: pom.xml
+`)
+	writeTestFile(t, filepath.Join(dir, "nested", "broken.yml"), "- ruleID: [unclosed\n")
+	// tests of the rules are not rules
+	writeTestFile(t, filepath.Join(dir, "valid.test.yaml"), "rulesPath: valid.yaml\n")
+
+	problems, err := checkRuleFiles([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+	invalid := filepath.Join(dir, "invalid.yaml")
+	want := []string{
+		invalid + ":invalid-00001: message must be a string",
+		invalid + ":invalid-00001: category must be one of mandatory, optional, potential",
+		invalid + ":invalid-00001: unknown condition unknown, must be one of and, or, from, as, ignore, not or <provider>.<capability>",
+		invalid + ":invalid-00001: duplicate ruleID",
+		invalid + ":invalid-00001: tag must be a list of strings",
+		invalid + ":invalid-00001: rule must have a when condition",
+		invalid + ": rule 3 has no ruleID",
+	}
+	if len(got) != len(want)+1 || !reflect.DeepEqual(got[:len(want)], want) {
+		t.Errorf("checkRuleFiles() = %q, want %q and a yaml problem of broken.yml", got, want)
+	}
+}
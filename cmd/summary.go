@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/konveyor-ecosystem/kantra/cmd/internal/i18n"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"gopkg.in/yaml.v2"
+)
+
+// analysisSummary holds the totals of an analysis output
+type analysisSummary struct {
+	RuleSets  int `yaml:"rulesets" json:"rulesets"`
+	Rules     int `yaml:"rules" json:"rules"`
+	Incidents int `yaml:"incidents" json:"incidents"`
+	// migration readiness from 0 to 100 computed by the scoring model
+	Score int `yaml:"score" json:"score"`
+	// violated rules ordered by priority
+	Priorities []rulePriority `yaml:"priorities,omitempty" json:"priorities,omitempty"`
+	// files the builtin provider skipped due to size or binary content
+	SkippedFiles int `yaml:"skippedFiles,omitempty" json:"skippedFiles,omitempty"`
+	// incompatibilities between the input and the target runtimes
+	Compatibility []string `yaml:"compatibility,omitempty" json:"compatibility,omitempty"`
+	// violations of the rules of --platform-checks
+	Platform []platformIssue `yaml:"platform,omitempty" json:"platform,omitempty"`
+	// business metadata of the application given by --app-metadata
+	Metadata map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	// incidents by author of the last change of their line, set by --git-blame
+	Authors []authorSummary `yaml:"authors,omitempty" json:"authors,omitempty"`
+	// dependencies with denied or not allowed licenses, see licenses.json
+	LicenseViolations int `yaml:"licenseViolations,omitempty" json:"licenseViolations,omitempty"`
+	// lines of code and complexity of the input by language and module
+	Code *codeStats `yaml:"code,omitempty" json:"code,omitempty"`
+	// violations of the output checked by the quality gate
+	rulesets []outputv1.RuleSet
+}
+
+func summarizeRuleSets(rulesets []outputv1.RuleSet) analysisSummary {
+	summary := analysisSummary{}
+	for _, rs := range rulesets {
+		if len(rs.Violations) == 0 {
+			continue
+		}
+		summary.RuleSets++
+		for _, violation := range rs.Violations {
+			summary.Rules++
+			summary.Incidents += len(violation.Incidents)
+		}
+	}
+	return summary
+}
+
+func (s analysisSummary) String() string {
+	summary := i18n.Sprintf("%d rules matched with %d incidents in %d rulesets",
+		s.Rules, s.Incidents, s.RuleSets)
+	if s.SkippedFiles > 0 {
+		summary = i18n.Sprintf("%s, %d files skipped", summary, s.SkippedFiles)
+	}
+	return i18n.Sprintf("%s, readiness score %d", summary, s.Score)
+}
+
+func readRuleSetsOutput(path string) ([]outputv1.RuleSet, error) {
+	data, err := readOutputFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rulesets := []outputv1.RuleSet{}
+	err = yaml.Unmarshal(data, &rulesets)
+	if err != nil {
+		return nil, err
+	}
+	return rulesets, nil
+}
+
+// consoleWriter returns where operational output of the analysis goes
+func (a *analyzeCommand) consoleWriter() io.Writer {
+	if a.quiet || a.summaryOnly {
+		return io.Discard
+	}
+	return os.Stdout
+}
+
+// printSummary writes summary.json to the output dir and prints a
+// one-line summary of the analysis output with the summary tables
+func (a *analyzeCommand) printSummary() (*analysisSummary, error) {
+	outputPath := filepath.Join(a.output, "output.yaml")
+	summaryPath := filepath.Join(a.output, "summary.json")
+	// bulk analysis moves results to the application dir
+	if _, err := os.Stat(outputPath); errors.Is(err, os.ErrNotExist) && a.bulk {
+		outputPath = a.bulkResultPath("output.yaml")
+		summaryPath = a.bulkResultPath("summary.json")
+	}
+	rulesets, err := readRuleSetsOutput(outputPath)
+	if err != nil {
+		return nil, err
+	}
+	if a.problems != nil {
+		a.problems.addRuleErrors(rulesets)
+	}
+	summary := summarizeRuleSets(rulesets)
+	summary.SkippedFiles = a.skippedFiles
+	summary.Metadata = a.appMetadata
+	summary.Compatibility = a.compatibilityIssues
+	if a.platformChecks {
+		summary.Platform = summarizePlatform(rulesets)
+	}
+	if a.gitBlame {
+		summary.Authors = summarizeAuthors(rulesets)
+	}
+	summary.LicenseViolations = a.licenseViolations
+	summary.Code = a.countInputCode()
+	summary.Score, summary.Priorities = a.scoringModel.score(rulesets, a.incidentInDependency)
+	summary.rulesets = rulesets
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	err = os.WriteFile(summaryPath, data, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if a.quiet {
+		return &summary, nil
+	}
+	fmt.Fprintln(os.Stdout, i18n.Sprintf("analysis complete: %s, results written to %s",
+		summary, a.output))
+	for _, issue := range summary.Compatibility {
+		fmt.Fprintln(os.Stdout, i18n.Sprintf("incompatible with target runtime: %s", issue))
+	}
+	for _, issue := range summary.Platform {
+		fmt.Fprintln(os.Stdout, i18n.Sprintf("%s platform check failed: %s (%d incidents)",
+			issue.Platform, issue.Description, issue.Incidents))
+	}
+	if !a.summaryOnly {
+		if err := writeSummaryTables(os.Stdout, rulesets, a.summaryColumns); err != nil {
+			return nil, err
+		}
+	}
+	return &summary, nil
+}
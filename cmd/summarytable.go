@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/konveyor-ecosystem/kantra/cmd/internal/i18n"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+// tables of the console summary of the analysis, a label=<key> table counts
+// the incidents by the values of the label key
+const (
+	summaryRules      = "rules"
+	summaryCategories = "categories"
+	summaryFiles      = "files"
+	summaryPackages   = "packages"
+	summaryLabel      = "label="
+)
+
+var defaultSummaryColumns = []string{summaryCategories, summaryFiles}
+
+// rows of the summary tables
+const summaryTableRows = 10
+
+type summaryRow struct {
+	name      string
+	incidents int
+}
+
+func validateSummaryColumns(columns []string) error {
+	for _, column := range columns {
+		switch {
+		case column == summaryRules, column == summaryCategories, column == summaryFiles, column == summaryPackages:
+		case strings.HasPrefix(column, summaryLabel) && len(column) > len(summaryLabel):
+		default:
+			return fmt.Errorf("summary column %s must be one of %s, %s, %s, %s or %s<key>",
+				column, summaryRules, summaryCategories, summaryFiles, summaryPackages, summaryLabel)
+		}
+	}
+	return nil
+}
+
+// countSummaryRows counts the incidents of the violations by the names
+// given by rowNames, and returns the rows with the most incidents first
+func countSummaryRows(rulesets []outputv1.RuleSet, rowNames func(ruleID string, v outputv1.Violation, incident outputv1.Incident) []string) []summaryRow {
+	counts := map[string]int{}
+	for _, rs := range rulesets {
+		for ruleID, v := range rs.Violations {
+			for _, incident := range v.Incidents {
+				for _, name := range rowNames(ruleID, v, incident) {
+					counts[name]++
+				}
+			}
+		}
+	}
+	rows := []summaryRow{}
+	for name, incidents := range counts {
+		rows = append(rows, summaryRow{name: name, incidents: incidents})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].incidents != rows[j].incidents {
+			return rows[i].incidents > rows[j].incidents
+		}
+		return rows[i].name < rows[j].name
+	})
+	return rows
+}
+
+func summaryColumnRows(rulesets []outputv1.RuleSet, column string) (string, []summaryRow) {
+	switch {
+	case column == summaryRules:
+		return i18n.Sprintf("RULE"), countSummaryRows(rulesets, func(ruleID string, _ outputv1.Violation, _ outputv1.Incident) []string {
+			return []string{ruleID}
+		})
+	case column == summaryCategories:
+		return i18n.Sprintf("CATEGORY"), countSummaryRows(rulesets, func(_ string, v outputv1.Violation, _ outputv1.Incident) []string {
+			if v.Category == nil {
+				return []string{"none"}
+			}
+			return []string{string(*v.Category)}
+		})
+	case column == summaryFiles:
+		return i18n.Sprintf("FILE"), countSummaryRows(rulesets, func(_ string, _ outputv1.Violation, incident outputv1.Incident) []string {
+			return []string{normalizeIncidentURI(incident.URI)}
+		})
+	case column == summaryPackages:
+		return i18n.Sprintf("PACKAGE"), countSummaryRows(rulesets, func(_ string, _ outputv1.Violation, incident outputv1.Incident) []string {
+			return []string{incidentPackage(incident)}
+		})
+	default:
+		key := strings.TrimPrefix(column, summaryLabel)
+		return strings.ToUpper(key), countSummaryRows(rulesets, func(_ string, v outputv1.Violation, _ outputv1.Incident) []string {
+			values := []string{}
+			for _, label := range v.Labels {
+				if k, value, ok := strings.Cut(label, "="); ok && k == key {
+					values = append(values, value)
+				}
+			}
+			return uniqueStrings(values)
+		})
+	}
+}
+
+// writeSummaryTables prints a table of incidents per column, e.g. the
+// categories or files with the most incidents
+func writeSummaryTables(out io.Writer, rulesets []outputv1.RuleSet, columns []string) error {
+	for _, column := range columns {
+		header, rows := summaryColumnRows(rulesets, column)
+		if len(rows) == 0 {
+			continue
+		}
+		fmt.Fprintln(out)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\t%s\n", header, i18n.Sprintf("INCIDENTS"))
+		for i, row := range rows {
+			if i == summaryTableRows {
+				fmt.Fprintln(w, i18n.Sprintf("%d more", len(rows)-summaryTableRows))
+				break
+			}
+			fmt.Fprintf(w, "%s\t%d\n", row.name, row.incidents)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
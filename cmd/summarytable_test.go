@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func Test_writeSummaryTables(t *testing.T) {
+	mandatory := outputv1.Category("mandatory")
+	rulesets := []outputv1.RuleSet{{
+		Name: "eap8",
+		Violations: map[string]outputv1.Violation{
+			"jms-00001": {Category: &mandatory, Labels: []string{"konveyor.io/target=eap8", "konveyor.io/target=quarkus"}, Incidents: []outputv1.Incident{
+				{URI: "file:///src/Queue.java"}, {URI: "file:///src/Cart.java"},
+			}},
+			"session-00010": {Labels: []string{"konveyor.io/target=eap8"}, Incidents: []outputv1.Incident{
+				{URI: "file:///src/Cart.java"},
+			}},
+		},
+	}}
+	out := &bytes.Buffer{}
+	err := writeSummaryTables(out, rulesets, []string{summaryCategories, summaryFiles, "label=konveyor.io/target"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"CATEGORY   INCIDENTS\nmandatory  2\nnone       1\n",
+		"FILE             INCIDENTS\n/src/Cart.java   2\n/src/Queue.java  1\n",
+		"KONVEYOR.IO/TARGET  INCIDENTS\neap8                3\nquarkus             2\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("writeSummaryTables() = %q, want table %q", out.String(), want)
+		}
+	}
+}
+
+func Test_validateSummaryColumns(t *testing.T) {
+	if err := validateSummaryColumns([]string{"rules", "label=konveyor.io/source"}); err != nil {
+		t.Errorf("validateSummaryColumns() error = %v", err)
+	}
+	for _, columns := range [][]string{{"effort"}, {"label="}} {
+		if err := validateSummaryColumns(columns); err == nil {
+			t.Errorf("validateSummaryColumns(%v) accepted an unknown column", columns)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+)
+
+// dir of the input the targets of followed symlinks pointing outside of it
+// are mounted to in containers, so that they do not shadow dirs of images
+const symlinkMountDir = ".kantra-links"
+
+type symlink struct {
+	// path of the link relative to the input root, slash separated
+	relPath string
+	// raw target of the link
+	linkTarget string
+	// resolved absolute target, empty when the link is broken
+	target string
+	broken bool
+	// escapes is set when the target is outside of the input root
+	escapes bool
+}
+
+// findSymlinks walks root and returns all symlinks that are not ignored
+func findSymlinks(root string, ignore *kantraIgnore) ([]symlink, error) {
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, err
+	}
+	links := []symlink{}
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		isSymlink := d.Type()&fs.ModeSymlink != 0
+		if ignore.Ignored(rel, d.IsDir() && !isSymlink) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isSymlink {
+			return nil
+		}
+		link := symlink{relPath: rel}
+		link.linkTarget, err = os.Readlink(p)
+		if err != nil {
+			return err
+		}
+		link.target, err = filepath.EvalSymlinks(p)
+		if err != nil {
+			link.broken = true
+			link.target = ""
+		} else {
+			relTarget, err := filepath.Rel(resolvedRoot, link.target)
+			link.escapes = err != nil || relTarget == ".." ||
+				strings.HasPrefix(relTarget, ".."+string(filepath.Separator))
+		}
+		links = append(links, link)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// mountPath returns the path relative to the input the target of the link
+// is mounted to in containers, named after the hash of the target
+func (s symlink) mountPath() string {
+	hash := sha256.Sum256([]byte(s.target))
+	return path.Join(symlinkMountDir, hex.EncodeToString(hash[:])[:16])
+}
+
+// applySymlinkPolicy validates symlinks found in the input when a symlink
+// policy is set, otherwise symlinks are left to the providers and the input
+// is not walked. Broken links and links escaping the input are excluded
+// from analysis, unless following them is requested, in which case their
+// targets are mounted into the containers under symlinkMountDir.
+func (a *analyzeCommand) applySymlinkPolicy(ignore *kantraIgnore) (*kantraIgnore, error) {
+	if !a.followSymlinks && !a.ignoreBrokenSymlinks {
+		return ignore, nil
+	}
+	links, err := findSymlinks(a.input, ignore)
+	if err != nil {
+		return nil, fmt.Errorf("%w failed to check input for symlinks", err)
+	}
+	if len(links) == 0 {
+		return ignore, nil
+	}
+	if ignore == nil {
+		ignore = &kantraIgnore{}
+	}
+	broken := []string{}
+	for _, link := range links {
+		switch {
+		case link.broken:
+			if !a.ignoreBrokenSymlinks {
+				broken = append(broken, link.relPath)
+				continue
+			}
+			a.log.Info("excluding broken symlink from analysis", "path", link.relPath, "target", link.linkTarget)
+			ignore.exclude(link.relPath)
+			a.addSkipped(skippedFile{Path: link.relPath, Provider: skippedAllProviders, Reason: skipReasonBrokenSymlink, Detail: link.linkTarget})
+		case link.escapes && a.followSymlinks:
+			if !a.runLocal {
+				if a.symlinkVolumes == nil {
+					a.symlinkVolumes = map[string]string{}
+					a.symlinkMounts = map[string]string{}
+				}
+				mount := link.mountPath()
+				a.symlinkVolumes[link.target] = path.Join(a.sourceMountPath(), mount)
+				a.symlinkMounts[mount] = link.relPath
+				// the link does not resolve in the containers, its target is
+				// analyzed at the mount and reported at the link
+				ignore.exclude(link.relPath)
+			}
+			a.log.V(1).Info("following symlink outside of input", "path", link.relPath, "target", link.target)
+		case link.escapes:
+			a.log.Info("excluding symlink pointing outside of input from analysis, use --follow-symlinks to analyze it",
+				"path", link.relPath, "target", link.target)
+			ignore.exclude(link.relPath)
+			a.addSkipped(skippedFile{Path: link.relPath, Provider: skippedAllProviders, Reason: skipReasonExternalSymlink, Detail: link.target})
+		}
+	}
+	if len(broken) > 0 {
+		return nil, fmt.Errorf("input contains broken symlinks %s, use --ignore-broken-symlinks to skip them",
+			strings.Join(broken, ", "))
+	}
+	return ignore, nil
+}
+
+// symlinkMountPaths returns the mounts of the targets of followed symlinks
+// relative to the input
+func (a *analyzeCommand) symlinkMountPaths() []string {
+	return sortedMapKeys(a.symlinkMounts)
+}
+
+// relinkURIs rewrites URIs of incidents in the targets of followed symlinks
+// to the paths of the links
+func (a *analyzeCommand) relinkURIs(rulesets []outputv1.RuleSet) {
+	if len(a.symlinkMounts) == 0 {
+		return
+	}
+	for i := range rulesets {
+		for ruleID, violation := range rulesets[i].Violations {
+			for j, incident := range violation.Incidents {
+				if !strings.HasPrefix(string(incident.URI), "file:") {
+					continue
+				}
+				file := filepath.ToSlash(incident.URI.Filename())
+				for mount, link := range a.symlinkMounts {
+					rel, ok := strings.CutPrefix(file, path.Join(a.sourceMountPath(), mount))
+					if ok && (rel == "" || strings.HasPrefix(rel, "/")) {
+						violation.Incidents[j].URI = uri.File(path.Join(a.sourceMountPath(), link) + rel)
+						break
+					}
+				}
+			}
+			rulesets[i].Violations[ruleID] = violation
+		}
+	}
+}
+
+// removeSymlinkMounts removes the empty dirs created in the input for the
+// mounts of the targets of followed symlinks
+func (a *analyzeCommand) removeSymlinkMounts() {
+	if len(a.symlinkMounts) == 0 || a.streamInput {
+		return
+	}
+	for _, mount := range a.symlinkMountPaths() {
+		os.Remove(filepath.Join(a.input, filepath.FromSlash(mount)))
+	}
+	os.Remove(filepath.Join(a.input, symlinkMountDir))
+}
+
+// resolveSymlinkPath returns the real path of p so that it can be bind mounted
+func resolveSymlinkPath(p string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		return "", fmt.Errorf("%w failed to resolve symlinks in path %s", err, p)
+	}
+	return resolved, nil
+}
+
+// copyRulesSymlink copies the target of a symlink found in a rules
+// directory, symlinked directories are only copied with --follow-symlinks
+func (a *analyzeCommand) copyRulesSymlink(p string, tempDir string, rulesDir string) error {
+	target, err := filepath.EvalSymlinks(p)
+	if err != nil {
+		if a.ignoreBrokenSymlinks {
+			a.log.Info("skipping broken symlink in rules", "path", p)
+			return nil
+		}
+		return fmt.Errorf("%w rules contain broken symlink %s, use --ignore-broken-symlinks to skip it", err, p)
+	}
+	stat, err := os.Stat(target)
+	if err != nil {
+		return err
+	}
+	relpath, err := filepath.Rel(rulesDir, p)
+	if err != nil {
+		return err
+	}
+	destPath := filepath.Join(tempDir, relpath)
+	if !stat.IsDir() {
+		a.log.V(5).Info("copying symlinked rules file", "source", target, "dest", destPath)
+		return copyFileContents(target, destPath)
+	}
+	if !a.followSymlinks {
+		a.log.Info("skipping symlinked directory in rules, use --follow-symlinks to include it", "path", p)
+		return nil
+	}
+	a.log.V(5).Info("copying symlinked rules dir", "source", target, "dest", destPath)
+	return copyFolderContents(target, destPath)
+}
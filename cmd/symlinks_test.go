@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"go.lsp.dev/uri"
+)
+
+func Test_findSymlinks(t *testing.T) {
+	outside := t.TempDir()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "src", "App.java"), []byte("class App {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	links := map[string]string{
+		"src/inside.java": "App.java",
+		"lib":             outside,
+		"src/broken.java": "missing.java",
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, filepath.FromSlash(link))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found, err := findSymlinks(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]symlink{}
+	for _, link := range found {
+		got[link.relPath] = link
+	}
+	if len(got) != len(links) {
+		t.Fatalf("findSymlinks() found %v, want %d links", found, len(links))
+	}
+	if l := got["src/inside.java"]; l.broken || l.escapes {
+		t.Errorf("link inside of input reported as %+v", l)
+	}
+	if l := got["lib"]; l.broken || !l.escapes {
+		t.Errorf("link outside of input reported as %+v", l)
+	}
+	if l := got["src/broken.java"]; !l.broken {
+		t.Errorf("broken link reported as %+v", l)
+	}
+}
+
+func Test_analyzeCommand_applySymlinkPolicy(t *testing.T) {
+	outside := t.TempDir()
+	input := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "Lib.java"), []byte("class Lib {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for link, target := range map[string]string{"lib": outside, "broken.java": "missing.java"} {
+		if err := os.Symlink(target, filepath.Join(input, link)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the input is not checked for symlinks without a symlink policy
+	a := &analyzeCommand{input: input, log: logr.Discard()}
+	ignore, err := a.applySymlinkPolicy(nil)
+	if err != nil || ignore != nil || len(a.skipped) != 0 {
+		t.Fatalf("applySymlinkPolicy() without policy = %v, %v, skipped %v", ignore, err, a.skipped)
+	}
+
+	a = &analyzeCommand{input: input, followSymlinks: true, ignoreBrokenSymlinks: true, log: logr.Discard()}
+	ignore, err = a.applySymlinkPolicy(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target, err := filepath.EvalSymlinks(outside)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mount := a.symlinkVolumes[target]
+	if !strings.HasPrefix(mount, path.Join(SourceMountPath, symlinkMountDir)+"/") {
+		t.Errorf("target of followed symlink mounted at %s, want a dir of %s", mount, symlinkMountDir)
+	}
+	if !ignore.Ignored("lib", false) || !ignore.Ignored("broken.java", false) {
+		t.Errorf("followed and broken symlinks must be excluded from analysis")
+	}
+
+	rulesets := []outputv1.RuleSet{{Violations: map[string]outputv1.Violation{
+		"rule": {Incidents: []outputv1.Incident{
+			{URI: uri.File(path.Join(mount, "Lib.java"))},
+			{URI: uri.File(path.Join(SourceMountPath, "App.java"))},
+		}},
+	}}}
+	a.relinkURIs(rulesets)
+	want := []uri.URI{uri.File(path.Join(SourceMountPath, "lib", "Lib.java")), uri.File(path.Join(SourceMountPath, "App.java"))}
+	for i, incident := range rulesets[0].Violations["rule"].Incidents {
+		if incident.URI != want[i] {
+			t.Errorf("relinkURIs() = %s, want %s", incident.URI, want[i])
+		}
+	}
+}
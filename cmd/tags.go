@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/konveyor/analyzer-lsp/engine"
+)
+
+// validateTagsOnly checks the options of a tags only analysis
+func (a *analyzeCommand) validateTagsOnly() error {
+	if !a.tagsOnly {
+		return nil
+	}
+	if !a.runLocal {
+		return fmt.Errorf("tags-only requires containerless mode, set --run-local=true")
+	}
+	return nil
+}
+
+// tagRules returns the rules of the rulesets tagging the input, e.g. with
+// the technologies it uses, without their messages so that they report no
+// violations. Rulesets without such rules are dropped.
+func tagRules(ruleSets []engine.RuleSet) []engine.RuleSet {
+	tagged := []engine.RuleSet{}
+	for _, rs := range ruleSets {
+		rules := []engine.Rule{}
+		for _, rule := range rs.Rules {
+			if len(rule.Perform.Tag) == 0 {
+				continue
+			}
+			rule.Perform.Message = engine.Message{}
+			rules = append(rules, rule)
+		}
+		if len(rules) == 0 {
+			continue
+		}
+		rs.Rules = rules
+		tagged = append(tagged, rs)
+	}
+	return tagged
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/konveyor/analyzer-lsp/engine"
+)
+
+func Test_tagRules(t *testing.T) {
+	message := "Replace the EJB"
+	ruleSets := []engine.RuleSet{
+		{Name: "technology-usage", Rules: []engine.Rule{
+			{RuleMeta: engine.RuleMeta{RuleID: "tech-00001"}, Perform: engine.Perform{Tag: []string{"EJB"}}},
+			{RuleMeta: engine.RuleMeta{RuleID: "tech-00002"}, Perform: engine.Perform{Tag: []string{"JPA"}, Message: engine.Message{Text: &message}}},
+			{RuleMeta: engine.RuleMeta{RuleID: "eap-00001"}, Perform: engine.Perform{Message: engine.Message{Text: &message}}},
+		}},
+		{Name: "eap8", Rules: []engine.Rule{
+			{RuleMeta: engine.RuleMeta{RuleID: "eap-00002"}, Perform: engine.Perform{Message: engine.Message{Text: &message}}},
+		}},
+	}
+	tagged := tagRules(ruleSets)
+	if len(tagged) != 1 || tagged[0].Name != "technology-usage" || len(tagged[0].Rules) != 2 {
+		t.Fatalf("tagRules() = %v, want the 2 tagging rules of technology-usage", tagged)
+	}
+	for _, rule := range tagged[0].Rules {
+		if rule.Perform.Message.Text != nil {
+			t.Errorf("tagRules() kept the message of %s", rule.RuleID)
+		}
+	}
+	if ruleSets[0].Rules[1].Perform.Message.Text == nil {
+		t.Error("tagRules() modified the rules it was given")
+	}
+}
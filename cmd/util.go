@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/fs"
@@ -11,6 +12,7 @@ import (
 	"strings"
 
 	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"gopkg.in/yaml.v2"
 )
 
 var (
@@ -74,8 +76,16 @@ func getSourceOrTargetLabel(text string, label string) string {
 	return ""
 }
 
-func listOptionsFromLabels(sl []string, label string, out io.Writer) {
-	var newSl []string
+// output formats of the list options
+const (
+	listFormatText = "text"
+	listFormatJSON = "json"
+	listFormatYAML = "yaml"
+)
+
+// labelOptions returns the sorted values of a label, without version suffixes
+func labelOptions(sl []string, label string) []string {
+	newSl := []string{}
 	l := label + "="
 
 	for _, label := range sl {
@@ -92,7 +102,43 @@ func listOptionsFromLabels(sl []string, label string, out io.Writer) {
 		}
 	}
 	sort.Strings(newSl)
+	return newSl
+}
+
+// writeListOutput writes the value of a list option as json or yaml
+func writeListOutput(out io.Writer, format string, v interface{}) error {
+	switch format {
+	case listFormatJSON:
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	case listFormatYAML:
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	}
+	return fmt.Errorf("unsupported list format %s", format)
+}
 
+func listOptionsFromLabels(sl []string, label string, format string, out io.Writer) error {
+	newSl := labelOptions(sl, label)
+
+	if format != "" && format != listFormatText {
+		if label == outputv1.SourceTechnologyLabel {
+			return writeListOutput(out, format, struct {
+				Sources []string `json:"sources" yaml:"sources"`
+			}{newSl})
+		}
+		return writeListOutput(out, format, struct {
+			Targets []string `json:"targets" yaml:"targets"`
+		}{newSl})
+	}
 	if label == outputv1.SourceTechnologyLabel {
 		fmt.Fprintln(out, "available source technologies:")
 	} else {
@@ -101,6 +147,7 @@ func listOptionsFromLabels(sl []string, label string, out io.Writer) {
 	for _, tech := range newSl {
 		fmt.Fprintln(out, tech)
 	}
+	return nil
 }
 
 func IsXMLDirEmpty(dir string) (bool, error) {
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+)
+
+func Test_listOptionsFromLabels(t *testing.T) {
+	labels := []string{
+		outputv1.TargetTechnologyLabel + "=quarkus",
+		outputv1.TargetTechnologyLabel + "=eap8+",
+		outputv1.TargetTechnologyLabel + "=eap8",
+		outputv1.SourceTechnologyLabel + "=eap7",
+	}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{listFormatText, "available target technologies:\neap8\nquarkus\n"},
+		{listFormatJSON, "{\n  \"targets\": [\n    \"eap8\",\n    \"quarkus\"\n  ]\n}\n"},
+		{listFormatYAML, "targets:\n- eap8\n- quarkus\n"},
+	}
+	for _, tt := range tests {
+		out := &bytes.Buffer{}
+		if err := listOptionsFromLabels(labels, outputv1.TargetTechnologyLabel, tt.format, out); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("listOptionsFromLabels() with format %s = %q, want %q", tt.format, out.String(), tt.want)
+		}
+	}
+
+	out := &bytes.Buffer{}
+	a := &analyzeCommand{listFormat: listFormatJSON}
+	if err := a.ListAllProviders(out); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out.Bytes(), []byte(`"containerless": [`)) {
+		t.Errorf("ListAllProviders() json output = %s", out.String())
+	}
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+)
+
+// outputSchemasFS holds the schemas of the analysis output by version of
+// the output format, e.g. v1 for konveyor output/v1 of analyzer-lsp
+//
+//go:embed output-schemas
+var outputSchemasFS embed.FS
+
+const defaultOutputSchemaVersion = "v1"
+
+// schemas of the output files in the schema documents
+const (
+	ruleSetsSchema     = "RuleSets"
+	dependenciesSchema = "Dependencies"
+)
+
+type verifyOutputCommand struct {
+	output        string
+	schemaVersion string
+	log           logr.Logger
+}
+
+// outputProblem is a part of an output file not matching the schema
+type outputProblem struct {
+	File   string
+	Path   string
+	Reason string
+}
+
+func (p outputProblem) String() string {
+	if p.Path == "" {
+		return fmt.Sprintf("%s: %s", p.File, p.Reason)
+	}
+	return fmt.Sprintf("%s: %s: %s", p.File, p.Path, p.Reason)
+}
+
+func NewVerifyOutputCommand(log logr.Logger) *cobra.Command {
+	verifyCmd := &verifyOutputCommand{log: log}
+	cmd := &cobra.Command{
+		Use:   "verify-output",
+		Short: "Verify analysis output matches the schema of the output format",
+		Long: "Verify output.yaml and dependencies.yaml of an analysis output match the schema of the konveyor output format,\n" +
+			"e.g. after editing them by hand or in CI to catch changes of the output across kantra versions. Compressed\n" +
+			"outputs are verified too. The command fails when a file does not match the schema.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := verifyCmd.Run(os.Stdout); err != nil {
+				log.Error(err, "failed to verify output")
+				return err
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&verifyCmd.output, "output", "o", "", "path to the output dir of an analysis, its output.yaml or dependencies.yaml")
+	cmd.Flags().StringVar(&verifyCmd.schemaVersion, "schema-version", defaultOutputSchemaVersion, fmt.Sprintf("version of the output format, one of %s", strings.Join(outputSchemaVersions(), ", ")))
+	cmd.MarkFlagRequired("output")
+	return cmd
+}
+
+// outputSchemaVersions returns the versions of the output format with a schema
+func outputSchemaVersions() []string {
+	entries, _ := outputSchemasFS.ReadDir("output-schemas")
+	versions := []string{}
+	for _, entry := range entries {
+		versions = append(versions, strings.TrimSuffix(entry.Name(), path.Ext(entry.Name())))
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// loadOutputSchemas returns the schemas of the output files of a version
+func loadOutputSchemas(version string) (openapi3.Schemas, error) {
+	data, err := outputSchemasFS.ReadFile(path.Join("output-schemas", version+".yaml"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("unknown schema version %s, must be one of %s", version, strings.Join(outputSchemaVersions(), ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w failed to load output schema %s", err, version)
+	}
+	return doc.Components.Schemas, nil
+}
+
+// schemaProblems flattens the errors of a schema validation into problems
+// at the paths of the values not matching it
+func schemaProblems(file string, err error) []outputProblem {
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		problems := []outputProblem{}
+		for _, e := range multi {
+			problems = append(problems, schemaProblems(file, e)...)
+		}
+		return problems
+	}
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		reason := schemaErr.Reason
+		if reason == "" {
+			reason = fmt.Sprintf("does not match %s of the schema", schemaErr.SchemaField)
+		}
+		return []outputProblem{{File: file, Path: "/" + strings.Join(schemaErr.JSONPointer(), "/"), Reason: reason}}
+	}
+	return []outputProblem{{File: file, Reason: err.Error()}}
+}
+
+// verifyOutputFile returns the problems of an output file, or of its
+// compressed copy, with the schema
+func verifyOutputFile(file string, schema *openapi3.Schema) ([]outputProblem, error) {
+	data, err := readOutputFile(file)
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.Base(outputFile(file))
+	content, err := yamlToJSON(data)
+	if err != nil {
+		return []outputProblem{{File: name, Reason: fmt.Sprintf("invalid yaml: %v", err)}}, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(content, &value); err != nil {
+		return nil, err
+	}
+	if err := schema.VisitJSON(value, openapi3.MultiErrors()); err != nil {
+		return schemaProblems(name, err), nil
+	}
+	return nil, nil
+}
+
+// outputFiles returns the output files to verify by the schema they must
+// match, the files of the output dir or the given file
+func (v *verifyOutputCommand) outputFiles() (map[string]string, error) {
+	stat, err := os.Stat(v.output)
+	if err != nil {
+		return nil, fmt.Errorf("%w failed to stat output %s", err, v.output)
+	}
+	if !stat.IsDir() {
+		schema := ruleSetsSchema
+		if strings.HasPrefix(filepath.Base(v.output), "dependencies") {
+			schema = dependenciesSchema
+		}
+		return map[string]string{v.output: schema}, nil
+	}
+	files := map[string]string{}
+	for file, schema := range map[string]string{"output.yaml": ruleSetsSchema, "dependencies.yaml": dependenciesSchema} {
+		file = filepath.Join(v.output, file)
+		if _, err := os.Stat(outputFile(file)); err == nil {
+			files[file] = schema
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no output.yaml or dependencies.yaml in %s", v.output)
+	}
+	return files, nil
+}
+
+func (v *verifyOutputCommand) Run(out io.Writer) error {
+	schemas, err := loadOutputSchemas(v.schemaVersion)
+	if err != nil {
+		return err
+	}
+	files, err := v.outputFiles()
+	if err != nil {
+		return err
+	}
+	problems := []outputProblem{}
+	for _, file := range sortedMapKeys(files) {
+		fileProblems, err := verifyOutputFile(file, schemas[files[file]].Value)
+		if err != nil {
+			return fmt.Errorf("%w failed to read %s", err, file)
+		}
+		if len(fileProblems) == 0 {
+			fmt.Fprintf(out, "%s matches the %s schema\n", filepath.Base(outputFile(file)), v.schemaVersion)
+		}
+		problems = append(problems, fileProblems...)
+	}
+	for _, problem := range problems {
+		fmt.Fprintln(out, problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("found %d problems with the %s schema", len(problems), v.schemaVersion)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	outputv1 "github.com/konveyor/analyzer-lsp/output/v1/konveyor"
+	"gopkg.in/yaml.v2"
+)
+
+func Test_verifyOutputCommand_Run(t *testing.T) {
+	line := 12
+	effort := 3
+	mandatory := outputv1.Mandatory
+	rulesets := []outputv1.RuleSet{{
+		Name: "eap8",
+		Tags: []string{"EJB"},
+		Violations: map[string]outputv1.Violation{
+			"session-00010": {
+				Description: "Stateful session EJB",
+				Category:    &mandatory,
+				Effort:      &effort,
+				Labels:      []string{"konveyor.io/target=eap8"},
+				Links:       []outputv1.Link{{URL: "https://example.com/ejb", Title: "EJB"}},
+				Incidents: []outputv1.Incident{{
+					URI: "file:///src/Cart.java", Message: "Replace the stateful EJB", CodeSnip: "12  @Stateful",
+					LineNumber: &line, Variables: map[string]interface{}{"package": "com.example"},
+				}},
+			},
+		},
+		Errors:    map[string]string{"jms-00001": "failed"},
+		Unmatched: []string{"jaxrs-00001"},
+	}}
+	deps := []outputv1.DepsFlatItem{{
+		FileURI:  "file:///src/pom.xml",
+		Provider: "java",
+		Dependencies: []*outputv1.Dep{{
+			Name: "org.hibernate.hibernate-core", Version: "5.4.0", Indirect: true,
+			Labels: []string{"konveyor.io/dep-source=open-source"}, Extras: map[string]interface{}{"groupId": "org.hibernate"},
+		}},
+	}}
+	output := t.TempDir()
+	for file, v := range map[string]interface{}{"output.yaml": rulesets, "dependencies.yaml": deps} {
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeTestFile(t, filepath.Join(output, file), string(data))
+	}
+	out := &bytes.Buffer{}
+	v := &verifyOutputCommand{output: output, schemaVersion: defaultOutputSchemaVersion}
+	if err := v.Run(out); err != nil {
+		t.Fatalf("Run() error = %v, output %s", err, out)
+	}
+
+	edited := filepath.Join(t.TempDir(), "output.yaml")
+	writeTestFile(t, edited, `- name: eap8
+  violations:
+    session-00010:
+      description: Stateful session EJB
+      category: blocker
+      incidents:
+      - uri: file:///src/Cart.java
+        message: Replace the stateful EJB
+        lineNumer: 12
+`)
+	out.Reset()
+	v = &verifyOutputCommand{output: edited, schemaVersion: defaultOutputSchemaVersion}
+	if err := v.Run(out); err == nil {
+		t.Fatalf("Run() of an edited output succeeded, want problems")
+	}
+	want := []string{
+		"output.yaml: /0/violations/session-00010/category: value is not one of the allowed values",
+		`output.yaml: /0/violations/session-00010/incidents/0: property "lineNumer" is unsupported`,
+	}
+	if got := strings.Split(strings.TrimSpace(out.String()), "\n"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Run() problems = %q, want %q", got, want)
+	}
+
+	v = &verifyOutputCommand{output: output, schemaVersion: "v0"}
+	if err := v.Run(out); err == nil || !strings.Contains(err.Error(), "v1") {
+		t.Errorf("Run() with unknown schema version error = %v, want one listing the versions", err)
+	}
+}
@@ -2,7 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strings"
 
+	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
 )
 
@@ -12,19 +19,152 @@ var (
 	RunnerImage = "quay.io/konveyor/kantra"
 )
 
+const analyzerModule = "github.com/konveyor/analyzer-lsp"
+
+// condition types of the providers of the embedded analyzer-lsp
+var providerCapabilities = map[string][]string{
+	"builtin":               {"file", "filecontent", "hasTags", "json", "xml", "xmlPublicID"},
+	javaProvider:            {"dependency", "referenced"},
+	goProvider:              {"dependency", "referenced"},
+	pythonProvider:          {"referenced"},
+	nodeJSProvider:          {"referenced"},
+	dotnetProvider:          {"referenced"},
+	dotnetFrameworkProvider: {"referenced"},
+}
+
+type versionInfo struct {
+	Version     string `json:"version" yaml:"version"`
+	BuildCommit string `json:"buildCommit" yaml:"buildCommit"`
+	RunnerImage string `json:"runnerImage" yaml:"runnerImage"`
+	// set with --verbose
+	AnalyzerVersion string              `json:"analyzerVersion,omitempty" yaml:"analyzerVersion,omitempty"`
+	ProviderImages  map[string]string   `json:"providerImages,omitempty" yaml:"providerImages,omitempty"`
+	Rulesets        string              `json:"rulesets,omitempty" yaml:"rulesets,omitempty"`
+	RulesetsDigest  string              `json:"rulesetsDigest,omitempty" yaml:"rulesetsDigest,omitempty"`
+	Capabilities    map[string][]string `json:"capabilities,omitempty" yaml:"capabilities,omitempty"`
+}
+
+// analyzerVersion returns the version of analyzer-lsp built into kantra
+func analyzerVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != analyzerModule {
+			continue
+		}
+		if dep.Replace != nil {
+			return fmt.Sprintf("%s => %s %s", dep.Version, dep.Replace.Path, dep.Replace.Version)
+		}
+		return dep.Version
+	}
+	return "unknown"
+}
+
+// defaultRulesetsDigest returns the location and checksum of the default
+// rulesets used in containerless mode
+func defaultRulesetsDigest() (string, string) {
+	a := &analyzeCommand{log: logr.Discard()}
+	if err := a.setKantraDir(); err != nil {
+		return "", ""
+	}
+	dir := filepath.Join(a.kantraDir, RulesetsLocation)
+	if _, err := os.Stat(dir); err != nil {
+		return dir, "not installed"
+	}
+	digest, err := checksumDir(dir)
+	if err != nil {
+		return dir, "unknown"
+	}
+	return dir, "sha256:" + digest
+}
+
+func newVersionInfo(verbose bool) versionInfo {
+	info := versionInfo{Version: Version, BuildCommit: BuildCommit, RunnerImage: RunnerImage}
+	if !verbose {
+		return info
+	}
+	info.AnalyzerVersion = analyzerVersion()
+	info.ProviderImages = map[string]string{
+		javaProvider:   Settings.JavaProviderImage,
+		"generic":      Settings.GenericProviderImage,
+		dotnetProvider: Settings.DotnetProviderImage,
+	}
+	info.Rulesets, info.RulesetsDigest = defaultRulesetsDigest()
+	info.Capabilities = providerCapabilities
+	return info
+}
+
+func writeCapabilities(out io.Writer, capabilities map[string][]string) {
+	names := []string{}
+	for name := range capabilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "  %s: %s\n", name, strings.Join(capabilities[name], ", "))
+	}
+}
+
+func writeVersionInfo(out io.Writer, info versionInfo) {
+	fmt.Fprintf(out, "version: %s\n", info.Version)
+	fmt.Fprintf(out, "SHA: %s\n", info.BuildCommit)
+	fmt.Fprintf(out, "image: %s\n", info.RunnerImage)
+	if info.AnalyzerVersion == "" {
+		return
+	}
+	fmt.Fprintf(out, "analyzer-lsp: %s\n", info.AnalyzerVersion)
+	fmt.Fprintf(out, "java provider image: %s\n", info.ProviderImages[javaProvider])
+	fmt.Fprintf(out, "generic provider image: %s\n", info.ProviderImages["generic"])
+	fmt.Fprintf(out, "dotnet provider image: %s\n", info.ProviderImages[dotnetProvider])
+	if info.Rulesets != "" {
+		fmt.Fprintf(out, "rulesets: %s (%s)\n", info.Rulesets, info.RulesetsDigest)
+	}
+	fmt.Fprintln(out, "capabilities:")
+	writeCapabilities(out, info.Capabilities)
+}
+
 // Use build flags to set correct Version and BuildCommit
 // e.g.:
 // --ldflags="-X 'github.com/konveyor-ecosystem/kantra/cmd.Version=1.2.3' -X 'github.com/konveyor-ecosystem/kantra/cmd.BuildCommit=$(git rev-parse HEAD)'"
 func NewVersionCommand() *cobra.Command {
+	var verbose bool
+	var format string
 	versionCmd := &cobra.Command{
 		Use:   "version",
 		Short: "Print the tool version",
 		Long:  "Print this tool version number",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Printf("version: %s\n", Version)
-			fmt.Printf("SHA: %s\n", BuildCommit)
-			fmt.Printf("image: %s\n", RunnerImage)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := newVersionInfo(verbose)
+			if format != listFormatText {
+				return writeListOutput(os.Stdout, format, info)
+			}
+			writeVersionInfo(os.Stdout, info)
+			return nil
 		},
 	}
+	versionCmd.Flags().BoolVar(&verbose, "verbose", false, "also print the analyzer-lsp version, provider images, default rulesets digest and provider capabilities")
+	versionCmd.Flags().StringVar(&format, "format", listFormatText, "output format. Must be one of 'text', 'json' or 'yaml'")
 	return versionCmd
 }
+
+// NewCapabilitiesCommand prints the condition types supported by each provider
+func NewCapabilitiesCommand() *cobra.Command {
+	var format string
+	capabilitiesCmd := &cobra.Command{
+		Use:   "capabilities",
+		Short: "Print the condition types supported by each provider",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != listFormatText {
+				return writeListOutput(os.Stdout, format, providerCapabilities)
+			}
+			fmt.Fprintf(os.Stdout, "analyzer-lsp: %s\n", analyzerVersion())
+			fmt.Fprintln(os.Stdout, "capabilities:")
+			writeCapabilities(os.Stdout, providerCapabilities)
+			return nil
+		},
+	}
+	capabilitiesCmd.Flags().StringVar(&format, "format", listFormatText, "output format. Must be one of 'text', 'json' or 'yaml'")
+	return capabilitiesCmd
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/konveyor/analyzer-lsp/provider"
+	"github.com/konveyor/analyzer-lsp/provider/lib"
+)
+
+func Test_providerCapabilities_builtin(t *testing.T) {
+	config := provider.Config{
+		Name:       "builtin",
+		InitConfig: []provider.InitConfig{{Location: t.TempDir()}},
+	}
+	client, err := lib.GetProviderClient(config, logr.Discard())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, c := range client.Capabilities() {
+		got = append(got, c.Name)
+	}
+	sort.Strings(got)
+	want := providerCapabilities["builtin"]
+	if len(got) != len(want) {
+		t.Fatalf("builtin capabilities = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("builtin capabilities = %v, want %v", got, want)
+		}
+	}
+}
+
+func Test_analyzerVersion(t *testing.T) {
+	if v := analyzerVersion(); v == "" || v == "unknown" {
+		t.Errorf("analyzerVersion() = %q", v)
+	}
+}
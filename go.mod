@@ -3,9 +3,12 @@ module github.com/konveyor-ecosystem/kantra
 go 1.21
 
 require (
+	github.com/antchfx/xmlquery v1.4.1
+	github.com/antchfx/xpath v1.3.1
 	github.com/devfile/alizer v1.6.1
 	github.com/getkin/kin-openapi v0.108.0
 	github.com/go-logr/logr v1.4.2
+	github.com/itchyny/gojq v0.12.7
 	github.com/phayes/freeport v0.0.0-20220201140144-74d24b5ae9f5
 	github.com/spf13/cobra v1.8.1
 	go.lsp.dev/uri v0.3.0
@@ -13,13 +16,11 @@ require (
 	go.opentelemetry.io/otel/trace v1.22.0
 	gopkg.in/yaml.v2 v2.4.0
 	gopkg.in/yaml.v3 v3.0.1
-
 )
 
 require (
 	cloud.google.com/go/compute v1.24.0 // indirect
 	cloud.google.com/go/compute/metadata v0.2.3 // indirect
-	github.com/antchfx/xpath v1.3.1 // indirect
 	github.com/bufbuild/protocompile v0.10.0 // indirect
 	github.com/cbroglie/mustache v1.4.0 // indirect
 	github.com/containerd/typeurl/v2 v2.1.1 // indirect
@@ -34,6 +35,7 @@ require (
 	github.com/golang/protobuf v1.5.4 // indirect
 	github.com/hashicorp/go-version v1.7.0 // indirect
 	github.com/invopop/yaml v0.2.0 // indirect
+	github.com/itchyny/timefmt-go v0.1.3 // indirect
 	github.com/jhump/protoreflect v1.16.0 // indirect
 	github.com/josharian/intern v1.0.0 // indirect
 	github.com/mailru/easyjson v0.7.7 // indirect
@@ -66,7 +68,6 @@ require (
 require (
 	github.com/PaesslerAG/gval v1.2.2 // indirect
 	github.com/antchfx/jsonquery v1.3.5 // indirect
-	github.com/antchfx/xmlquery v1.4.1 // indirect
 	github.com/bombsimon/logrusr/v3 v3.1.0
 	github.com/codingconcepts/env v0.0.0-20200821220118-a8fbf8d84482
 	github.com/fabianvf/windup-rulesets-yaml v0.5.3
@@ -75,6 +76,6 @@ require (
 	github.com/konveyor/analyzer-lsp/external-providers/java-external-provider v0.0.0-20241213191020-b49d6a41aa43
 	github.com/shopspring/decimal v1.3.1 // indirect
 	github.com/sirupsen/logrus v1.9.3
-	github.com/spf13/pflag v1.0.5 // indirect
+	github.com/spf13/pflag v1.0.5
 	golang.org/x/exp v0.0.0-20230725093048-515e97ebf090
 )
@@ -18,6 +18,7 @@ import (
 )
 
 type container struct {
+	stdin          io.Reader
 	stdout         []io.Writer
 	stderr         []io.Writer
 	Name           string
@@ -31,12 +32,20 @@ type container struct {
 	// whether to delete container after run()
 	cleanup bool
 	// map of source -> dest paths to mount
-	volumes          map[string]string
+	volumes map[string]string
+	// map of dest path -> mount options, e.g. ro
+	volumeOptions    map[string]string
 	cFlag            bool
 	detached         bool
 	log              logr.Logger
 	containerToolBin string
 	reproducerCmd    *string
+	platform         string
+	securityOpts     []string
+	// SELinux label option of volumes on linux, z (shared), Z (private) or
+	// none when empty
+	selinuxLabel string
+	userns       string
 }
 
 type Option func(c *container)
@@ -95,6 +104,36 @@ func WithVolumes(m map[string]string) Option {
 	}
 }
 
+func WithVolumeOptions(m map[string]string) Option {
+	return func(c *container) {
+		c.volumeOptions = m
+	}
+}
+
+func WithSecurityOpts(opts ...string) Option {
+	return func(c *container) {
+		c.securityOpts = opts
+	}
+}
+
+func WithSELinuxLabel(l string) Option {
+	return func(c *container) {
+		c.selinuxLabel = l
+	}
+}
+
+func WithUserNS(u string) Option {
+	return func(c *container) {
+		c.userns = u
+	}
+}
+
+func WithStdin(i io.Reader) Option {
+	return func(c *container) {
+		c.stdin = i
+	}
+}
+
 func WithStdout(o ...io.Writer) Option {
 	return func(c *container) {
 		c.stdout = o
@@ -131,12 +170,26 @@ func WithEnv(k string, v string) Option {
 	}
 }
 
+func WithEnvs(m map[string]string) Option {
+	return func(c *container) {
+		for k, v := range m {
+			c.env[k] = v
+		}
+	}
+}
+
 func WithLog(l logr.Logger) Option {
 	return func(c *container) {
 		c.log = l
 	}
 }
 
+func WithPlatform(p string) Option {
+	return func(c *container) {
+		c.platform = p
+	}
+}
+
 func WithReproduceCmd(r *string) Option {
 	return func(c *container) {
 		c.reproducerCmd = r
@@ -165,10 +218,11 @@ func NewContainer() *container {
 		Name:             "",
 		NetworkName:      "",
 		// by default, remove the container after run()
-		cleanup:  true,
-		cFlag:    false,
-		detached: false,
-		log:      logr.Discard(),
+		cleanup:      true,
+		cFlag:        false,
+		detached:     false,
+		log:          logr.Discard(),
+		selinuxLabel: "z",
 	}
 }
 
@@ -185,6 +239,9 @@ func (c *container) Run(ctx context.Context, opts ...Option) error {
 	if c.detached {
 		args = append(args, "-d")
 	}
+	if c.stdin != nil {
+		args = append(args, "-i")
+	}
 	if c.cleanup {
 		args = append(args, "--rm")
 	}
@@ -207,15 +264,35 @@ func (c *container) Run(ctx context.Context, opts ...Option) error {
 		args = append(args, "--entrypoint")
 		args = append(args, c.entrypointBin)
 	}
+	for _, opt := range c.securityOpts {
+		args = append(args, "--security-opt")
+		args = append(args, opt)
+	}
+	if c.userns != "" {
+		args = append(args, "--userns")
+		args = append(args, c.userns)
+	}
+	if c.platform != "" {
+		args = append(args, "--platform")
+		args = append(args, c.platform)
+	}
 	if c.workdir != "" {
 		args = append(args, "--workdir")
 		args = append(args, c.workdir)
 	}
 	for sourcePath, destPath := range c.volumes {
 		args = append(args, "-v")
-		if os == "linux" {
-			args = append(args, fmt.Sprintf("%s:%s:z",
-				filepath.Clean(sourcePath), path.Clean(destPath)))
+		options := []string{}
+		if o := c.volumeOptions[destPath]; o != "" {
+			options = append(options, o)
+		}
+		// overlay mounts do not take other options
+		if os == "linux" && c.volumeOptions[destPath] != "O" && c.selinuxLabel != "" {
+			options = append(options, c.selinuxLabel)
+		}
+		if len(options) > 0 {
+			args = append(args, fmt.Sprintf("%s:%s:%s",
+				filepath.Clean(sourcePath), path.Clean(destPath), strings.Join(options, ",")))
 		} else {
 			args = append(args, fmt.Sprintf("%s:%s",
 				filepath.Clean(sourcePath), path.Clean(destPath)))
@@ -238,8 +315,8 @@ func (c *container) Run(ctx context.Context, opts ...Option) error {
 			c.containerToolBin, reproducer)
 	}
 	cmd := exec.CommandContext(ctx, c.containerToolBin, args...)
-	fmt.Printf("%v", cmd.String())
 	errBytes := &bytes.Buffer{}
+	cmd.Stdin = c.stdin
 	cmd.Stdout = nil
 	cmd.Stderr = errBytes
 	if c.stdout != nil {
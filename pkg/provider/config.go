@@ -0,0 +1,243 @@
+// Package provider builds the analyzer-lsp provider configs kantra starts
+// its providers with, so that other tools analyzing applications the way
+// kantra does generate identical configs.
+//
+// The builders only fill in the config from their Options, copying maven
+// settings files or writing settings.json is up to the caller.
+package provider
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/konveyor/analyzer-lsp/provider"
+)
+
+// Names of the providers
+const (
+	Builtin = "builtin"
+	Java    = "java"
+	Go      = "go"
+	Python  = "python"
+	NodeJS  = "nodejs"
+	DotNet  = "dotnet"
+)
+
+// Paths of the language servers in the provider images
+const (
+	ContainerJDTLSPath            = "/jdtls/bin/jdtls"
+	ContainerMavenIndexPath       = "/usr/local/etc/maven.default.index"
+	ContainerGoplsPath            = "/root/go/bin/gopls"
+	ContainerGoDependencyProvider = "/usr/local/bin/golang-dependency-provider"
+	ContainerPylspPath            = "/usr/local/bin/pylsp"
+	ContainerTypeScriptServerPath = "/usr/local/bin/typescript-language-server"
+	ContainerCSharpLanguageServer = "/opt/app-root/.dotnet/tools/csharp-ls"
+)
+
+// Files of the kantra dir used by the java provider in containerless mode
+const (
+	fernflowerJar  = "fernflower.jar"
+	mavenIndexFile = "maven.default.index"
+)
+
+// Mode is where the providers run
+type Mode string
+
+const (
+	// ContainerMode runs the providers in their images, they listen on a port
+	ContainerMode Mode = "container"
+	// ContainerlessMode runs the providers in the kantra process with the
+	// language servers installed on the host
+	ContainerlessMode Mode = "containerless"
+)
+
+// Options are the settings of the analysis the configs are built from
+type Options struct {
+	Mode Mode
+	// Location is the input as seen by the providers, e.g. the mount path of
+	// the input in container mode
+	Location     string
+	AnalysisMode provider.AnalysisMode
+	// Port the provider listens on in container mode
+	Port int
+	// IncludedPaths limit the analysis to these paths of the input
+	IncludedPaths []string
+	// DependencyFolders of python and nodejs inputs
+	DependencyFolders []string
+	// MavenSettingsFile as seen by the java provider
+	MavenSettingsFile string
+	// JVMMaxMemory of jdtls, e.g. 4g
+	JVMMaxMemory string
+	// JDTLSPath is the jdtls binary, ContainerJDTLSPath in container mode
+	JDTLSPath string
+	// JavaBundlePath is the java analyzer bundle jar
+	JavaBundlePath string
+	// KantraDir holds fernflower and the maven index in containerless mode
+	KantraDir string
+	// Workspace of jdtls in containerless mode
+	Workspace string
+}
+
+// address returns the address the provider listens on in container mode
+func (o Options) address() string {
+	if o.Mode != ContainerMode {
+		return ""
+	}
+	return fmt.Sprintf("0.0.0.0:%v", o.Port)
+}
+
+// includedPaths returns the included paths in the form providers expect them
+// in provider specific config
+func includedPaths(paths []string) []interface{} {
+	included := []interface{}{}
+	for _, p := range paths {
+		included = append(included, p)
+	}
+	return included
+}
+
+// BuiltinConfig returns the config of the builtin provider
+func BuiltinConfig(opts Options) provider.Config {
+	config := provider.Config{
+		Name: Builtin,
+		InitConfig: []provider.InitConfig{
+			{
+				Location:     opts.Location,
+				AnalysisMode: opts.AnalysisMode,
+			},
+		},
+	}
+	if len(opts.IncludedPaths) > 0 {
+		config.InitConfig[0].ProviderSpecificConfig = map[string]interface{}{
+			provider.IncludedPathsConfigKey: includedPaths(opts.IncludedPaths),
+		}
+	}
+	return config
+}
+
+// JavaConfig returns the config of the java provider
+func JavaConfig(opts Options) provider.Config {
+	config := provider.Config{
+		Name:    Java,
+		Address: opts.address(),
+		InitConfig: []provider.InitConfig{
+			{
+				Location:     opts.Location,
+				AnalysisMode: opts.AnalysisMode,
+				ProviderSpecificConfig: map[string]interface{}{
+					"lspServerName":                 Java,
+					"bundles":                       opts.JavaBundlePath,
+					"depOpenSourceLabelsFile":       ContainerMavenIndexPath,
+					provider.LspServerPathConfigKey: opts.JDTLSPath,
+				},
+			},
+		},
+	}
+	specific := config.InitConfig[0].ProviderSpecificConfig
+	if opts.Mode == ContainerlessMode {
+		config.BinaryPath = opts.JDTLSPath
+		specific["fernFlowerPath"] = filepath.Join(opts.KantraDir, fernflowerJar)
+		specific["depOpenSourceLabelsFile"] = filepath.Join(opts.KantraDir, mavenIndexFile)
+		if opts.Workspace != "" {
+			specific["workspace"] = opts.Workspace
+		}
+	}
+	if opts.MavenSettingsFile != "" {
+		specific["mavenSettingsFile"] = opts.MavenSettingsFile
+	}
+	if opts.JVMMaxMemory != "" {
+		specific["jvmMaxMem"] = opts.JVMMaxMemory
+	}
+	if len(opts.IncludedPaths) > 0 {
+		specific[provider.IncludedPathsConfigKey] = includedPaths(opts.IncludedPaths)
+	}
+	return config
+}
+
+// GoConfig returns the config of the go provider, it runs in container mode
+// only
+func GoConfig(opts Options) provider.Config {
+	return provider.Config{
+		Name:    Go,
+		Address: opts.address(),
+		InitConfig: []provider.InitConfig{
+			{
+				AnalysisMode: provider.FullAnalysisMode,
+				ProviderSpecificConfig: map[string]interface{}{
+					"lspServerName":                 "generic",
+					"workspaceFolders":              []string{fmt.Sprintf("file://%s", opts.Location)},
+					"dependencyProviderPath":        ContainerGoDependencyProvider,
+					provider.LspServerPathConfigKey: ContainerGoplsPath,
+				},
+			},
+		},
+	}
+}
+
+// PythonConfig returns the config of the python provider, it runs in
+// container mode only
+func PythonConfig(opts Options) provider.Config {
+	return genericSourceConfig(Python, "generic", ContainerPylspPath, opts)
+}
+
+// NodeJSConfig returns the config of the nodejs provider, it runs in
+// container mode only
+func NodeJSConfig(opts Options) provider.Config {
+	return genericSourceConfig(NodeJS, "nodejs", ContainerTypeScriptServerPath, opts)
+}
+
+// genericSourceConfig returns the config of a provider of the generic
+// provider image analyzing the source only
+func genericSourceConfig(name string, lspServerName string, lspServerPath string, opts Options) provider.Config {
+	config := provider.Config{
+		Name:    name,
+		Address: opts.address(),
+		InitConfig: []provider.InitConfig{
+			{
+				AnalysisMode: provider.SourceOnlyAnalysisMode,
+				ProviderSpecificConfig: map[string]interface{}{
+					"lspServerName":                 lspServerName,
+					"workspaceFolders":              []string{fmt.Sprintf("file://%s", opts.Location)},
+					provider.LspServerPathConfigKey: lspServerPath,
+				},
+			},
+		},
+	}
+	if len(opts.DependencyFolders) != 0 {
+		config.InitConfig[0].ProviderSpecificConfig["dependencyFolders"] = opts.DependencyFolders
+	}
+	return config
+}
+
+// DotNetConfig returns the config of the dotnet provider, it runs in
+// container mode only
+func DotNetConfig(opts Options) provider.Config {
+	return provider.Config{
+		Name:    DotNet,
+		Address: opts.address(),
+		InitConfig: []provider.InitConfig{
+			{
+				Location:     opts.Location,
+				AnalysisMode: provider.SourceOnlyAnalysisMode,
+				ProviderSpecificConfig: map[string]interface{}{
+					provider.LspServerPathConfigKey: ContainerCSharpLanguageServer,
+				},
+			},
+		},
+	}
+}
+
+// SetProxy sets the proxy of the configs, configs are left without proxy when
+// neither httpProxy nor httpsProxy is set
+func SetProxy(configs []provider.Config, httpProxy string, httpsProxy string, noProxy string) {
+	if httpProxy == "" && httpsProxy == "" {
+		return
+	}
+	for i := range configs {
+		configs[i].Proxy = &provider.Proxy{
+			HTTPProxy:  httpProxy,
+			HTTPSProxy: httpsProxy,
+			NoProxy:    noProxy,
+		}
+	}
+}
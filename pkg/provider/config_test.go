@@ -0,0 +1,87 @@
+package provider
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/konveyor/analyzer-lsp/provider"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the provider configs")
+
+func TestConfigs(t *testing.T) {
+	container := Options{
+		Mode:              ContainerMode,
+		Location:          "/opt/input/source",
+		AnalysisMode:      provider.FullAnalysisMode,
+		Port:              6734,
+		IncludedPaths:     []string{"src/main"},
+		DependencyFolders: []string{"node_modules"},
+		MavenSettingsFile: "/opt/input/config/settings.xml",
+		JVMMaxMemory:      "4g",
+		JDTLSPath:         ContainerJDTLSPath,
+		JavaBundlePath:    "/jdtls/java-analyzer-bundle/java-analyzer-bundle.core/target/java-analyzer-bundle.core-1.0.0-SNAPSHOT.jar",
+	}
+	containerless := Options{
+		Mode:           ContainerlessMode,
+		Location:       "/home/user/app",
+		AnalysisMode:   provider.SourceOnlyAnalysisMode,
+		JDTLSPath:      "/home/user/.kantra/jdtls/bin/jdtls",
+		JavaBundlePath: "/home/user/.kantra/jdtls/java-analyzer-bundle/java-analyzer-bundle.core/target/java-analyzer-bundle.core-1.0.0-SNAPSHOT.jar",
+		KantraDir:      "/home/user/.kantra",
+		Workspace:      "/home/user/.cache/kantra/jdtls-workspace",
+	}
+	tests := []struct {
+		name    string
+		configs []provider.Config
+	}{
+		{name: "builtin-container", configs: []provider.Config{BuiltinConfig(container)}},
+		{name: "builtin-containerless", configs: []provider.Config{BuiltinConfig(containerless)}},
+		{name: "java-container", configs: []provider.Config{JavaConfig(container)}},
+		{name: "java-containerless", configs: []provider.Config{JavaConfig(containerless)}},
+		{name: "go-container", configs: []provider.Config{GoConfig(container)}},
+		{name: "python-container", configs: []provider.Config{PythonConfig(container)}},
+		{name: "nodejs-container", configs: []provider.Config{NodeJSConfig(container)}},
+		{name: "dotnet-container", configs: []provider.Config{DotNetConfig(container)}},
+		{
+			name: "proxy-container",
+			configs: func() []provider.Config {
+				configs := []provider.Config{BuiltinConfig(container), JavaConfig(container)}
+				SetProxy(configs, "http://proxy:3128", "", "localhost")
+				return configs
+			}(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.MarshalIndent(tt.configs, "", "	")
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("testdata", tt.name+".golden.json")
+			if *update {
+				if err := os.WriteFile(golden, append(got, '\n'), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(append(got, '\n')) != string(want) {
+				t.Errorf("config differs from %s, run go test -update to update it\n%s", golden, got)
+			}
+		})
+	}
+}
+
+func TestSetProxy(t *testing.T) {
+	configs := []provider.Config{BuiltinConfig(Options{})}
+	SetProxy(configs, "", "", "localhost")
+	if configs[0].Proxy != nil {
+		t.Errorf("SetProxy() set a proxy without http or https proxy")
+	}
+}
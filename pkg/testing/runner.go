@@ -437,11 +437,11 @@ func ensureProviderSettings(tempDirPath string, runLocal bool, testsFile TestsFi
 			delete(uniqueTrees, key)
 		}
 		for uniquePath := range uniqueTrees {
-			volumes[filepath.Join(filepath.Dir(testsFile.Path), uniquePath)] = path.Join("/data", uniquePath)
+			volumes[filepath.Join(filepath.Dir(testsFile.Path), uniquePath)] = path.Join("/data", filepath.ToSlash(uniquePath))
 		}
 		for _, override := range testsFile.Providers {
 			// when running in the container, we use the mounted path
-			dataPath := filepath.Join("/data", filepath.Clean(override.DataPath))
+			dataPath := path.Join("/data", filepath.ToSlash(filepath.Clean(override.DataPath)))
 			final = append(final,
 				getMergedProviderConfig(override.Name, baseProviders, params, dataPath, "/shared")...)
 		}